@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/gmtls"
 
 	cfsslapi "github.com/cloudflare/cfssl/api"
 	"github.com/cloudflare/cfssl/csr"
@@ -138,7 +139,21 @@ func (c *Client) Init() error {
 
 func (c *Client) initHTTPClient(serverName string) error {
 	tr := new(http.Transport)
-	if c.Config.TLS.Enabled {
+	if c.Config.TLS.Enabled && c.Config.TLS.GMTLS {
+		log.Info("GM TLS Enabled")
+
+		gmConfig, err2 := tls.GetClientGMTLSConfig(&c.Config.TLS)
+		if err2 != nil {
+			return fmt.Errorf("Failed to get client GM TLS config: %s", err2)
+		}
+		//set the host name override
+		gmConfig.ServerName = serverName
+
+		// net/http only speaks TLS on its own, TLCP connections are established by the dialer
+		tr.DialTLS = func(network, addr string) (net.Conn, error) {
+			return gmtls.Dial(network, addr, gmConfig)
+		}
+	} else if c.Config.TLS.Enabled {
 		log.Info("TLS Enabled")
 
 		tlsConfig, err2 := tls.GetClientTLSConfig(&c.Config.TLS, c.csp)
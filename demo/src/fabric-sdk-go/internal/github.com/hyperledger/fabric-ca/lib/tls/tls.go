@@ -26,6 +26,8 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/gmtls"
+	"github.com/tjfoc/gmsm/sm2"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkinternal/pkg/util"
 	factory "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
@@ -49,12 +51,17 @@ type ClientTLSConfig struct {
 	CertFiles   [][]byte `help:"A list of comma-separated PEM-encoded trusted certificate bytes"`
 	Client      KeyCertFiles
 	TlsCertPool *x509.CertPool
+	GMTLS       bool `help:"Use GM/T TLS (TLCP) with SM2 certificates instead of TLS"`
+	// EncClient is the SM2 encryption certificate and key sent along with Client (GM TLS only)
+	EncClient KeyCertFiles `skip:"true"`
 }
 
 // KeyCertFiles defines the files need for client on TLS
 type KeyCertFiles struct {
 	KeyFile  []byte `help:"PEM-encoded key bytes when mutual authentication is enabled"`
 	CertFile []byte `help:"PEM-encoded certificate bytes when mutual authenticate is enabled"`
+	// KeyPassword decrypts KeyFile if it is encrypted (GM TLS only)
+	KeyPassword []byte `skip:"true"`
 }
 
 // GetClientTLSConfig creates a tls.Config object from certs and roots
@@ -108,6 +115,61 @@ func GetClientTLSConfig(cfg *ClientTLSConfig, csp core.CryptoSuite) (*tls.Config
 	return config, nil
 }
 
+// GetClientGMTLSConfig creates a gmtls.Config object from the SM2 certs and roots
+func GetClientGMTLSConfig(cfg *ClientTLSConfig) (*gmtls.Config, error) {
+	config := &gmtls.Config{RootCAs: sm2.NewCertPool()}
+
+	if cfg.Client.CertFile != nil {
+		clientCert, err := gmKeyPair(&cfg.Client)
+		if err != nil {
+			return nil, err
+		}
+		config.SignCertificate = clientCert
+
+		if cfg.EncClient.CertFile != nil {
+			log.Debug("Loading client GM TLS encryption certificate")
+			config.EncCertificate, err = gmKeyPair(&cfg.EncClient)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		log.Debug("Client GM TLS certificate and/or key file not provided")
+	}
+
+	if len(cfg.CertFiles) == 0 {
+		return nil, errors.New("No trusted root certificates for GM TLS were provided")
+	}
+
+	for _, cacert := range cfg.CertFiles {
+		ok := config.RootCAs.AppendCertsFromPEM(cacert)
+		if !ok {
+			return nil, errors.New("Failed to process certificate")
+		}
+	}
+
+	return config, nil
+}
+
+// gmKeyPair loads an SM2 certificate and its key, and checks the validity dates of the certificate
+func gmKeyPair(files *KeyCertFiles) (*gmtls.Certificate, error) {
+	clientCert, err := gmtls.X509KeyPair(files.CertFile, files.KeyFile, files.KeyPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("Check client GM TLS certificate for valid dates")
+	cert, err := sm2.ParseCertificate(clientCert.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing certificate")
+	}
+	err = checkValidity(cert.NotBefore, cert.NotAfter)
+	if err != nil {
+		return nil, err
+	}
+	return &clientCert, nil
+}
+
 func checkCertDates(certPEM []byte) error {
 	log.Debug("Check client TLS certificate for valid dates")
 
@@ -116,14 +178,16 @@ func checkCertDates(certPEM []byte) error {
 		return err
 	}
 
-	notAfter := cert.NotAfter
+	return checkValidity(cert.NotBefore, cert.NotAfter)
+}
+
+func checkValidity(notBefore, notAfter time.Time) error {
 	currentTime := time.Now().UTC()
 
 	if currentTime.After(notAfter) {
 		return errors.New("Certificate provided has expired")
 	}
 
-	notBefore := cert.NotBefore
 	if currentTime.Before(notBefore) {
 		return errors.New("Certificate provided not valid until later date")
 	}
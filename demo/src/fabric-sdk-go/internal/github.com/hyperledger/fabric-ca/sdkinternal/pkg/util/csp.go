@@ -131,27 +131,43 @@ func BCCSPKeyRequestGenerate(req *csr.CertificateRequest, myCSP core.CryptoSuite
 	return key, cspSigner, nil
 }
 
-// ImportBCCSPKeyFromPEM attempts to create a private BCCSP key from a pem file keyFile
-func ImportBCCSPKeyFromPEM(keyFile string, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
+// ImportBCCSPKeyFromPEM attempts to create a private BCCSP key from a pem file keyFile,
+// decrypting it with the given password (see secret.KeyPassword) if it is encrypted
+func ImportBCCSPKeyFromPEM(keyFile string, password []byte, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
 	keyBuff, err := ioutil.ReadFile(keyFile)
 	if err != nil {
 		return nil, err
 	}
-	key, err := ImportBCCSPKeyFromPEMBytes(keyBuff, myCSP, temporary)
+	key, err := ImportBCCSPKeyFromPEMBytesWithPassword(keyBuff, password, myCSP, temporary)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("Failed parsing private key from key file %s", keyFile))
 	}
 	return key, nil
 }
 
-// ImportBCCSPKeyFromPEMBytes attempts to create a private BCCSP key from a pem byte slice
+// ImportBCCSPKeyFromPEMBytes attempts to create a private BCCSP key from a pem byte slice.
+// Encrypted keys are decrypted with the process-wide hutil.UserPassword.
+//
+// Deprecated: use ImportBCCSPKeyFromPEMBytesWithPassword.
 func ImportBCCSPKeyFromPEMBytes(keyBuff []byte, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
+	return ImportBCCSPKeyFromPEMBytesWithPassword(keyBuff, []byte(hutil.UserPassword), myCSP, temporary)
+}
+
+// ImportBCCSPKeyFromPEMBytesWithPassword attempts to create a private BCCSP key from a pem byte slice,
+// decrypting it with the given password if it is encrypted
+func ImportBCCSPKeyFromPEMBytesWithPassword(keyBuff []byte, password []byte, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
 	keyFile := "pem bytes"
-	userPassword := []byte(hutil.UserPassword)
 	block, _ := pem.Decode(keyBuff)
+	if block == nil {
+		return nil, errors.Errorf("Failed decoding PEM for '%s'", keyFile)
+	}
 	var decrypted []byte
 	if _, ok := block.Headers["DEK-Info"]; ok {
-		decrypted, _ = x509.DecryptPEMBlock(block, userPassword)
+		var err error
+		decrypted, err = x509.DecryptPEMBlock(block, password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed decrypting private key for '%s'", keyFile)
+		}
 	} else {
 		decrypted = block.Bytes
 	}
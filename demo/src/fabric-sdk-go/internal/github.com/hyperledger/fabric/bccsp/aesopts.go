@@ -84,3 +84,21 @@ type AESCBCPKCS7ModeOpts struct {
 	// It is used only if different from nil.
 	PRNG io.Reader
 }
+
+// AESGCMModeOpts contains options for AES encryption in GCM mode.
+// The ciphertext is the nonce followed by the sealed data.
+// Notice that both Nonce and PRNG can be nil. In that case, the BCCSP implementation
+// is supposed to sample the nonce using a cryptographic secure PRNG.
+// Notice also that either Nonce or PRNG can be different from nil.
+type AESGCMModeOpts struct {
+	// Nonce is the nonce to be used by the underlying cipher.
+	// Its length must be the standard GCM nonce size (12 bytes).
+	// It is used only if different from nil.
+	Nonce []byte
+	// AdditionalData is authenticated but not encrypted.
+	// The same value must be passed on decryption.
+	AdditionalData []byte
+	// PRNG is an instance of a PRNG to be used by the underlying cipher.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
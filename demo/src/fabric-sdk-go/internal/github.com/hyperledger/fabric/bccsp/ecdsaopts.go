@@ -20,6 +20,8 @@ Please review third_party pinning scripts and patches for more details.
 
 package bccsp
 
+import "io"
+
 // ECDSAP256KeyGenOpts contains options for ECDSA key generation with curve P-256.
 type ECDSAP256KeyGenOpts struct {
 	Temporary bool
@@ -51,3 +53,13 @@ func (opts *ECDSAP384KeyGenOpts) Algorithm() string {
 func (opts *ECDSAP384KeyGenOpts) Ephemeral() bool {
 	return opts.Temporary
 }
+
+// ECIESOpts contains options for ECIES encryption to an ECDSA public key
+// and decryption with the matching private key.
+// The ciphertext is the ephemeral public key (uncompressed point) followed by
+// the AES-256-GCM sealed plaintext, keyed with HKDF-SHA256 of the ECDH secret.
+type ECIESOpts struct {
+	// PRNG is an instance of a PRNG to be used to generate the ephemeral key.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
@@ -184,6 +184,54 @@ func AESCBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
 	return nil, err
 }
 
+// AESGCMEncrypt encrypts src in GCM mode. The returned ciphertext is prefixed with the nonce.
+// If nonce is nil then a random one is sampled from prng.
+func AESGCMEncrypt(prng io.Reader, nonce []byte, key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) == 0 {
+		nonce = make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(prng, nonce); err != nil {
+			return nil, err
+		}
+	} else if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("Invalid nonce. It must have length [%d]", gcm.NonceSize())
+	}
+
+	ciphertext := make([]byte, len(nonce), len(nonce)+len(src)+gcm.Overhead())
+	copy(ciphertext, nonce)
+
+	return gcm.Seal(ciphertext, nonce, src, additionalData), nil
+}
+
+// AESGCMDecrypt decrypts a ciphertext that was produced by AESGCMEncrypt
+func AESGCMDecrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("Invalid ciphertext. It is too short")
+	}
+
+	nonce := src[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, src[gcm.NonceSize():], additionalData)
+}
+
 type aescbcpkcs7Encryptor struct{}
 
 func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
@@ -206,6 +254,20 @@ func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp
 		return AESCBCPKCS7Encrypt(k.(*aesPrivateKey).privKey, plaintext)
 	case bccsp.AESCBCPKCS7ModeOpts:
 		return e.Encrypt(k, plaintext, &o)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+
+		if len(o.Nonce) != 0 && o.PRNG != nil {
+			return nil, errors.New("Invalid options. Either Nonce or PRNG should be different from nil, or both nil.")
+		}
+
+		prng := o.PRNG
+		if prng == nil {
+			prng = rand.Reader
+		}
+		return AESGCMEncrypt(prng, o.Nonce, k.(*aesPrivateKey).privKey, plaintext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return e.Encrypt(k, plaintext, &o)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
@@ -215,10 +277,15 @@ type aescbcpkcs7Decryptor struct{}
 
 func (*aescbcpkcs7Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
 	// check for mode
-	switch opts.(type) {
+	switch o := opts.(type) {
 	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
 		// AES in CBC mode with PKCS7 padding
 		return AESCBCPKCS7Decrypt(k.(*aesPrivateKey).privKey, ciphertext)
+	case *bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext, o.AdditionalData)
+	case bccsp.AESGCMModeOpts:
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext, o.AdditionalData)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
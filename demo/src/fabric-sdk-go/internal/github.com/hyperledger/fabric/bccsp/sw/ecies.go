@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+// ECIESEncrypt encrypts plaintext to pub. An ephemeral key agrees on a secret with pub (ECDH),
+// from which HKDF-SHA256 derives the AES-256-GCM key sealing plaintext.
+// The ciphertext is the ephemeral public key (uncompressed point) followed by the sealed plaintext.
+func ECIESEncrypt(prng io.Reader, pub *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if prng == nil {
+		prng = rand.Reader
+	}
+	ephemeral, err := ecdsa.GenerateKey(pub.Curve, prng)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed generating ephemeral key")
+	}
+	ephemeralPub := elliptic.Marshal(pub.Curve, ephemeral.X, ephemeral.Y)
+
+	aead, err := eciesAEAD(pub.Curve, pub.X, pub.Y, ephemeral.D, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+
+	// every message has its own key, so the nonce can be fixed
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ephemeralPub, nonce, plaintext, nil), nil
+}
+
+// ECIESDecrypt decrypts ciphertext produced by ECIESEncrypt with priv
+func ECIESDecrypt(priv *ecdsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	pointLength := 1 + 2*((priv.Curve.Params().BitSize+7)/8)
+	if len(ciphertext) < pointLength {
+		return nil, errors.New("Invalid ciphertext. It is too short")
+	}
+
+	ephemeralPub := ciphertext[:pointLength]
+	x, y := elliptic.Unmarshal(priv.Curve, ephemeralPub)
+	if x == nil {
+		return nil, errors.New("Invalid ciphertext. The ephemeral key is not on the curve")
+	}
+
+	aead, err := eciesAEAD(priv.Curve, x, y, priv.D, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	plaintext, err := aead.Open(nil, nonce, ciphertext[pointLength:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed decrypting ciphertext")
+	}
+	return plaintext, nil
+}
+
+// eciesAEAD returns the AES-256-GCM cipher keyed from the ECDH secret of the point (x, y) and d
+func eciesAEAD(curve elliptic.Curve, x, y, d *big.Int, info []byte) (cipher.AEAD, error) {
+	sx, _ := curve.ScalarMult(x, y, d.Bytes())
+	secret := make([]byte, (curve.Params().BitSize+7)/8)
+	sxBytes := sx.Bytes()
+	copy(secret[len(secret)-len(sxBytes):], sxBytes)
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key); err != nil {
+		return nil, errors.Wrap(err, "failed deriving encryption key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type eciesEncryptor struct{}
+
+func (e *eciesEncryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	var pub *ecdsa.PublicKey
+	switch kk := k.(type) {
+	case *ecdsaPublicKey:
+		pub = kk.pubKey
+	case *ecdsaPrivateKey:
+		pub = &kk.privKey.PublicKey
+	default:
+		return nil, errors.Errorf("Invalid key type [%T]", k)
+	}
+
+	switch o := opts.(type) {
+	case *bccsp.ECIESOpts:
+		return ECIESEncrypt(o.PRNG, pub, plaintext)
+	case bccsp.ECIESOpts:
+		return e.Encrypt(k, plaintext, &o)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
+
+type eciesDecryptor struct{}
+
+func (*eciesDecryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	switch opts.(type) {
+	case *bccsp.ECIESOpts, bccsp.ECIESOpts:
+		return ECIESDecrypt(k.(*ecdsaPrivateKey).privKey, ciphertext)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
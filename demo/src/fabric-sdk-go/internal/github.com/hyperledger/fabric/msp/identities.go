@@ -15,8 +15,6 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/hex"
-	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
-	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 
@@ -170,10 +168,10 @@ func (id *identity) Verify(msg []byte, sig []byte) error {
 	}
 
 	digest, err := id.msp.bccsp.Hash(msg, hashOpt)
-	digest = hx509.WrapHashResult(id.msp.bccsp.(*wrapper.CryptoSuite).BCCSP, msg, digest)
 	if err != nil {
 		return errors.WithMessage(err, "failed computing digest")
 	}
+	digest = bccsp.PrepareKeySignatureDigest(id.msp.bccsp, id.pk, msg, digest)
 
 	if mspIdentityLogger.IsEnabledFor(logging.DEBUG) {
 		mspIdentityLogger.Debugf("Verify: digest = %s", hex.Dump(digest))
@@ -257,6 +255,7 @@ func (id *signingidentity) Sign(msg []byte) ([]byte, error) {
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed computing digest")
 	}
+	digest = bccsp.PrepareKeySignatureDigest(id.msp.bccsp, id.pk, msg, digest)
 
 	if len(msg) < 32 {
 		mspIdentityLogger.Debugf("Sign: plaintext: %X \n", msg)
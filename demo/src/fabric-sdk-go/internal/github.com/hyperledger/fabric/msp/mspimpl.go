@@ -90,6 +90,9 @@ type bccspmsp struct {
 	// verification options for MSP members
 	opts *x509.VerifyOptions
 
+	// the certificates of opts and the CRLs, from which the SM chains are built
+	chainOpts hx509.ChainOptions
+
 	// list of certificate revocation lists
 	CRL []*pkix.CertificateList
 
@@ -701,12 +704,12 @@ func (msp *bccspmsp) getCertificationChainForBCCSPIdentity(id *identity) ([]*x50
 	return msp.getValidationChain(id.cert, false)
 }
 
-func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.VerifyOptions) ([]*x509.Certificate, error) {
+func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.VerifyOptions, chainOpts hx509.ChainOptions) ([]*x509.Certificate, error) {
 	// ask golang to validate the cert for us based on the options that we've built at setup time
 	if msp.opts == nil {
 		return nil, errors.New("the supplied identity has no verify options")
 	}
-	validationChains, err := hx509.Verify(cert, opts)
+	validationChains, err := hx509.Verify(cert, opts, chainOpts)
 	if err != nil {
 		return nil, errors.WithMessage(err, "the supplied identity is not valid")
 	}
@@ -722,7 +725,7 @@ func (msp *bccspmsp) getUniqueValidationChain(cert *x509.Certificate, opts x509.
 }
 
 func (msp *bccspmsp) getValidationChain(cert *x509.Certificate, isIntermediateChain bool) ([]*x509.Certificate, error) {
-	validationChain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert))
+	validationChain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert), msp.chainOpts)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed getting validation chain")
 	}
@@ -782,7 +785,7 @@ func (msp *bccspmsp) sanitizeCert(cert *x509.Certificate) (*x509.Certificate, er
 	if isECDSASignedCert(cert) {
 		// Lookup for a parent certificate to perform the sanitization
 		var parentCert *x509.Certificate
-		chain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert))
+		chain, err := msp.getUniqueValidationChain(cert, msp.getValidityOptsForCert(cert), msp.chainOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	m "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	bccsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkpatch/cryptosuitebridge"
 	errors "github.com/pkg/errors"
 )
@@ -116,19 +117,20 @@ func (msp *bccspmsp) setupCAs(conf *m.FabricMSPConfig) error {
 	// CA certificates. After their sanitization is done, the opts
 	// will be recreated using the sanitized certs.
 	msp.opts = &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
+	msp.chainOpts = hx509.ChainOptions{}
 	for _, v := range conf.RootCerts {
 		cert, err := msp.getCertFromPem(v)
 		if err != nil {
 			return err
 		}
-		msp.opts.Roots.AddCert(cert)
+		msp.chainOpts.AddRoot(msp.opts, cert)
 	}
 	for _, v := range conf.IntermediateCerts {
 		cert, err := msp.getCertFromPem(v)
 		if err != nil {
 			return err
 		}
-		msp.opts.Intermediates.AddCert(cert)
+		msp.chainOpts.AddIntermediate(msp.opts, cert)
 	}
 
 	// Load root and intermediate CA identities
@@ -156,11 +158,12 @@ func (msp *bccspmsp) setupCAs(conf *m.FabricMSPConfig) error {
 
 	// root CA and intermediate CA certificates are sanitized, they can be re-imported
 	msp.opts = &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
+	msp.chainOpts = hx509.ChainOptions{}
 	for _, id := range msp.rootCerts {
-		msp.opts.Roots.AddCert(id.(*identity).cert)
+		msp.chainOpts.AddRoot(msp.opts, id.(*identity).cert)
 	}
 	for _, id := range msp.intermediateCerts {
-		msp.opts.Intermediates.AddCert(id.(*identity).cert)
+		msp.chainOpts.AddIntermediate(msp.opts, id.(*identity).cert)
 	}
 
 	return nil
@@ -202,7 +205,7 @@ func (msp *bccspmsp) setupCRLs(conf *m.FabricMSPConfig) error {
 	// setup the CRL (if present)
 	msp.CRL = make([]*pkix.CertificateList, len(conf.RevocationList))
 	for i, crlbytes := range conf.RevocationList {
-		crl, err := x509.ParseCRL(crlbytes)
+		crl, err := hx509.ParseCRL(crlbytes)
 		if err != nil {
 			return errors.Wrap(err, "could not parse RevocationList")
 		}
@@ -214,6 +217,8 @@ func (msp *bccspmsp) setupCRLs(conf *m.FabricMSPConfig) error {
 
 		msp.CRL[i] = crl
 	}
+	// the SM chains are checked against the CRLs while they are built
+	msp.chainOpts.CRLs = msp.CRL
 
 	return nil
 }
@@ -237,7 +242,7 @@ func (msp *bccspmsp) finalizeSetupCAs() error {
 	// certification tree
 	msp.certificationTreeInternalNodesMap = make(map[string]bool)
 	for _, id := range append([]Identity{}, msp.intermediateCerts...) {
-		chain, err := msp.getUniqueValidationChain(id.(*identity).cert, msp.getValidityOptsForCert(id.(*identity).cert))
+		chain, err := msp.getUniqueValidationChain(id.(*identity).cert, msp.getValidityOptsForCert(id.(*identity).cert), msp.chainOpts)
 		if err != nil {
 			return errors.WithMessagef(err, "failed getting validation chain, (SN: %s)", id.(*identity).cert.SerialNumber)
 		}
@@ -425,6 +430,7 @@ func (msp *bccspmsp) setupOUs(conf *m.FabricMSPConfig) error {
 func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 
 	opts := &x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: x509.NewCertPool()}
+	var chainOpts hx509.ChainOptions
 
 	// Load TLS root and intermediate CA identities
 	msp.tlsRootCerts = make([][]byte, len(conf.TlsRootCerts))
@@ -437,7 +443,7 @@ func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 
 		rootCerts[i] = cert
 		msp.tlsRootCerts[i] = trustedCert
-		opts.Roots.AddCert(cert)
+		chainOpts.AddRoot(opts, cert)
 	}
 
 	// make and fill the set of intermediate certs (if present)
@@ -451,7 +457,7 @@ func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 
 		intermediateCerts[i] = cert
 		msp.tlsIntermediateCerts[i] = trustedCert
-		opts.Intermediates.AddCert(cert)
+		chainOpts.AddIntermediate(opts, cert)
 	}
 
 	// ensure that our CAs are properly formed and that they are valid
@@ -467,7 +473,7 @@ func (msp *bccspmsp) setupTLSCAs(conf *m.FabricMSPConfig) error {
 			return errors.WithMessagef(err, "CA Certificate problem with Subject Key Identifier extension, (SN: %x)", cert.SerialNumber)
 		}
 
-		if err := msp.validateTLSCAIdentity(cert, opts); err != nil {
+		if err := msp.validateTLSCAIdentity(cert, opts, chainOpts); err != nil {
 			return errors.WithMessagef(err, "CA Certificate is not valid, (SN: %s)", cert.SerialNumber)
 		}
 	}
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	"github.com/pkg/errors"
 )
 
@@ -59,7 +60,7 @@ func (msp *bccspmsp) validateCAIdentity(id *identity) error {
 		return errors.New("Only CA identities can be validated")
 	}
 
-	validationChain, err := msp.getUniqueValidationChain(id.cert, msp.getValidityOptsForCert(id.cert))
+	validationChain, err := msp.getUniqueValidationChain(id.cert, msp.getValidityOptsForCert(id.cert), msp.chainOpts)
 	if err != nil {
 		return errors.WithMessage(err, "could not obtain certification chain")
 	}
@@ -71,12 +72,12 @@ func (msp *bccspmsp) validateCAIdentity(id *identity) error {
 	return msp.validateIdentityAgainstChain(id, validationChain)
 }
 
-func (msp *bccspmsp) validateTLSCAIdentity(cert *x509.Certificate, opts *x509.VerifyOptions) error {
+func (msp *bccspmsp) validateTLSCAIdentity(cert *x509.Certificate, opts *x509.VerifyOptions, chainOpts hx509.ChainOptions) error {
 	if !cert.IsCA {
 		return errors.New("Only CA identities can be validated")
 	}
 
-	validationChain, err := msp.getUniqueValidationChain(cert, *opts)
+	validationChain, err := msp.getUniqueValidationChain(cert, *opts, chainOpts)
 	if err != nil {
 		return errors.WithMessage(err, "could not obtain certification chain")
 	}
@@ -119,7 +120,7 @@ func (msp *bccspmsp) validateCertAgainstChain(cert *x509.Certificate, validation
 					// certificate that is under validation. As a
 					// precaution, we verify that said CA is also the
 					// signer of this CRL.
-					err = validationChain[1].CheckCRLSignature(crl)
+					err = hx509.CheckCRLSignature(validationChain[1], crl)
 					if err != nil {
 						// the CA cert that signed the certificate
 						// that is under validation did not sign the
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	m "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func TestValidateRevokedSMIdentity(t *testing.T) {
+	ca := newSMTestCA(t)
+	adminPEM, _ := ca.issue(t, "admin", 2)
+	userPEM, _ := ca.issue(t, "user1", 3)
+	revokedPEM, revokedSerial := ca.issue(t, "user2", 4)
+
+	crlDER, err := ca.template.CreateCRL(rand.Reader, ca.key, []pkix.RevokedCertificate{{SerialNumber: revokedSerial, RevocationTime: time.Now()}}, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		crls    [][]byte
+		revoked bool
+	}{
+		{name: "Without CRL"},
+		{name: "With CRL", crls: [][]byte{pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlDER})}, revoked: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msp := newSMTestMSP(t, &m.FabricMSPConfig{
+				Name:           "SampleOrg",
+				RootCerts:      [][]byte{ca.certPEM},
+				Admins:         [][]byte{adminPEM},
+				RevocationList: tc.crls,
+			})
+
+			assert.NoError(t, msp.Validate(deserialize(t, msp, userPEM)))
+
+			err := msp.Validate(deserialize(t, msp, revokedPEM))
+			if !tc.revoked {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "revoked")
+		})
+	}
+}
+
+func newSMTestMSP(t *testing.T, conf *m.FabricMSPConfig) MSP {
+	cryptoSuite, err := sm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	msp, err := New(&BCCSPNewOpts{NewBaseOpts{Version: MSPv1_4_3}}, cryptoSuite)
+	require.NoError(t, err)
+
+	confBytes, err := proto.Marshal(conf)
+	require.NoError(t, err)
+	require.NoError(t, msp.Setup(&m.MSPConfig{Type: int32(FABRIC), Config: confBytes}))
+	return msp
+}
+
+func deserialize(t *testing.T, msp MSP, certPEM []byte) Identity {
+	serialized, err := proto.Marshal(&m.SerializedIdentity{Mspid: "SampleOrg", IdBytes: certPEM})
+	require.NoError(t, err)
+	id, err := msp.DeserializeIdentity(serialized)
+	require.NoError(t, err)
+	return id
+}
+
+// smTestCA is an SM2 CA issuing SM2 certificates
+type smTestCA struct {
+	template *sm2.Certificate
+	key      *sm2.PrivateKey
+	certPEM  []byte
+}
+
+func newSMTestCA(t *testing.T) *smTestCA {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	template := &sm2.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		SubjectKeyId:          []byte("ca.example.com"),
+		KeyUsage:              sm2.KeyUsageCertSign | sm2.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SignatureAlgorithm:    sm2.SM2WithSM3,
+	}
+	certPEM, err := sm2.CreateCertificateToMem(template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &smTestCA{template: template, key: key, certPEM: certPEM}
+}
+
+// issue returns the PEM encoded certificate of a new SM2 key and its serial number
+func (ca *smTestCA) issue(t *testing.T, cn string, serial int64) ([]byte, *big.Int) {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	template := &sm2.Certificate{
+		SerialNumber:       big.NewInt(serial),
+		Subject:            pkix.Name{CommonName: cn},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SubjectKeyId:       []byte(cn),
+		AuthorityKeyId:     ca.template.SubjectKeyId,
+		KeyUsage:           sm2.KeyUsageDigitalSignature,
+		SignatureAlgorithm: sm2.SM2WithSM3,
+	}
+	certPEM, err := sm2.CreateCertificateToMem(template, ca.template, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	return certPEM, template.SerialNumber
+}
@@ -0,0 +1,14 @@
+package hfactory
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+)
+
+// SM2UserID is the default SM2 user ID with which signatures are computed and verified.
+const SM2UserID = hsm.UserID
+
+// SM2KeyGenOpts contains options for SM2 key generation.
+type SM2KeyGenOpts = hsm.SM2KeyGenOpts
+
+// SM2EncryptionOpts contains options for SM2 public key encryption.
+type SM2EncryptionOpts = hsm.SM2EncryptionOpts
@@ -0,0 +1,20 @@
+package hfactory
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+)
+
+// SM4KeyGenOpts contains options for SM4 key generation.
+type SM4KeyGenOpts = hsm.SM4KeyGenOpts
+
+// SM4ImportKeyOpts contains options for importing SM4 keys.
+type SM4ImportKeyOpts = hsm.SM4ImportKeyOpts
+
+// SM4HMACDeriveKeyOpts contains options for HMAC-SM3 derivation of SM4 keys.
+type SM4HMACDeriveKeyOpts = hsm.SM4HMACDeriveKeyOpts
+
+// SM4CBCPKCS7ModeOpts contains options for SM4 encryption in CBC mode with PKCS7 padding.
+type SM4CBCPKCS7ModeOpts = hsm.SM4CBCPKCS7ModeOpts
+
+// SM4GCMModeOpts contains options for SM4 encryption in GCM mode.
+type SM4GCMModeOpts = hsm.SM4GCMModeOpts
@@ -12,6 +12,14 @@ const (
 	SMSoftwareBasedFactoryName = "SM"
 )
 
+const (
+	// LegacyHashMode computes SM3 for every hash opts, including the SHA ones
+	LegacyHashMode = hsm.LegacyHashMode
+	// StrictHashMode computes SHA digests for SHA opts and SM3 only for SM3 opts
+	// or for bccsp.SHAOpts when the hash family is SM3
+	StrictHashMode = hsm.StrictHashMode
+)
+
 // SMFactory is the factory of the software-based BCCSP.
 type SMFactory struct{}
 
@@ -31,7 +39,7 @@ func (f *SMFactory) Get(smOpts *SmOpts) (bccsp.BCCSP, error) {
 	if smOpts.Ephemeral == true {
 		ks = hsm.NewDummyKeyStore()
 	} else if smOpts.FileKeystore != nil {
-		fks, err := hsm.NewFileBasedKeyStore(nil, smOpts.FileKeystore.KeyStorePath, false)
+		fks, err := hsm.NewFileBasedKeyStore(smOpts.FileKeystore.Password, smOpts.FileKeystore.KeyStorePath, false)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to initialize software key store")
 		}
@@ -41,7 +49,7 @@ func (f *SMFactory) Get(smOpts *SmOpts) (bccsp.BCCSP, error) {
 		ks = hsm.NewDummyKeyStore()
 	}
 
-	return hsm.New(smOpts.SecLevel, smOpts.HashFamily, ks)
+	return hsm.NewWithHashMode(smOpts.SecLevel, smOpts.HashFamily, smOpts.HashMode, ks)
 }
 
 // SMOpts contains options for the SMFactory
@@ -49,6 +57,8 @@ type SmOpts struct {
 	// Default algorithms when not specified (Deprecated?)
 	SecLevel   int    `mapstructure:"security" json:"security" yaml:"Security"`
 	HashFamily string `mapstructure:"hash" json:"hash" yaml:"Hash"`
+	// HashMode is either LegacyHashMode (default) or StrictHashMode
+	HashMode string `mapstructure:"hashmode,omitempty" json:"hashmode,omitempty" yaml:"HashMode"`
 
 	// Keystore Options
 	Ephemeral     bool                 `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
@@ -59,6 +69,8 @@ type SmOpts struct {
 // Pluggable Keystores, could add JKS, P12, etc..
 type SMFileKeystoreOpts struct {
 	KeyStorePath string `mapstructure:"keystore" yaml:"KeyStore"`
+	// Password encrypts the keys in the keystore, keys are stored in the clear if it's empty
+	Password []byte `mapstructure:"password,omitempty" json:"-" yaml:"-"`
 }
 
 type SMDummyKeystoreOpts struct{}
@@ -0,0 +1,34 @@
+package smpkcs11
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/smpkcs11"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SMPKCS11BasedFactoryName is the name of the factory of the BCCSP keeping SM2 keys on a PKCS#11 token
+	SMPKCS11BasedFactoryName = "SMPKCS11"
+)
+
+// SMPKCS11Factory is the factory of the PKCS#11 based SM BCCSP.
+// It is separate from hfactory, which doesn't depend on the PKCS#11 library.
+type SMPKCS11Factory struct{}
+
+// Name returns the name of this factory
+func (f *SMPKCS11Factory) Name() string {
+	return SMPKCS11BasedFactoryName
+}
+
+// Get returns an instance of BCCSP using Opts.
+func (f *SMPKCS11Factory) Get(opts *smpkcs11.SMPKCS11Opts) (bccsp.BCCSP, error) {
+	// Validate arguments
+	if opts == nil {
+		return nil, errors.New("Invalid config. It must not be nil.")
+	}
+
+	ks := hsm.NewDummyKeyStore()
+
+	return smpkcs11.New(*opts, ks)
+}
@@ -13,6 +13,10 @@ var logger = flogging.MustGetLogger("securekey")
 
 type SecureUserKey struct{}
 
+// UserPassword is the password of the encrypted private keys, set with apiKey.SetUserPassword.
+// It is only used as a fallback when the secret provider of the SDK has no password.
+//
+// Deprecated: use fabsdk.WithSecretProvider instead.
 var UserPassword = ""
 
 func (suk *SecureUserKey) EncryptKey(PlainMSPKeyFilePath, PlainTLSKeyFilePath, EncryptedMSPKeyFilePath, EncryptedTLSKeyFilePath, password, keyType string) error {
@@ -0,0 +1,63 @@
+package hx509
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/sx509"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+// ChainOptions holds what Verify needs besides x509.VerifyOptions to build the chains that contain
+// SM certificates: the certificates of the root and intermediate pools, since the certificates of a
+// x509.CertPool can not be listed, and the CRLs of the CAs
+type ChainOptions struct {
+	Roots         []*x509.Certificate
+	Intermediates []*x509.Certificate
+	CRLs          []*pkix.CertificateList
+}
+
+// AddRoot adds cert to the roots of opts and of o
+func (o *ChainOptions) AddRoot(opts *x509.VerifyOptions, cert *x509.Certificate) {
+	opts.Roots.AddCert(cert)
+	o.Roots = append(o.Roots, cert)
+}
+
+// AddIntermediate adds cert to the intermediates of opts and of o
+func (o *ChainOptions) AddIntermediate(opts *x509.VerifyOptions, cert *x509.Certificate) {
+	opts.Intermediates.AddCert(cert)
+	o.Intermediates = append(o.Intermediates, cert)
+}
+
+// IsSMKey reports whether key belongs to the SM2 curve. key is an ECDSA or SM2 public
+// or private key, or its DER encoding.
+func IsSMKey(key interface{}) bool {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return isSM2Curve(k.Curve)
+	case *ecdsa.PrivateKey:
+		return isSM2Curve(k.Curve)
+	case *sm2.PublicKey:
+		return isSM2Curve(k.Curve)
+	case *sm2.PrivateKey:
+		return isSM2Curve(k.Curve)
+	case []byte:
+		if sx509.IsSMPublicKey(k) {
+			return true
+		}
+		priv, err := sx509.DERToPrivateKey(k)
+		return err == nil && IsSMKey(priv)
+	}
+	return false
+}
+
+// IsSMCertificate reports whether the public key of cert belongs to the SM2 curve
+func IsSMCertificate(cert *x509.Certificate) bool {
+	return cert != nil && sx509.IsSMPublicKey(cert.RawSubjectPublicKeyInfo)
+}
+
+func isSM2Curve(curve elliptic.Curve) bool {
+	return curve != nil && curve.Params() == sm2.P256Sm2().Params()
+}
@@ -3,6 +3,7 @@ package hx509
 import (
 	"crypto/ecdsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
@@ -12,7 +13,6 @@ import (
 	"github.com/pkg/errors"
 	"reflect"
 	"strings"
-	"unsafe"
 )
 
 var logger = flogging.MustGetLogger("hx509")
@@ -21,17 +21,56 @@ func ParseCertificate(asn1Data []byte) (*x509.Certificate, error) {
 	return sx509.ParseCertificate(asn1Data)
 }
 
-func Verify(cert *x509.Certificate, opts x509.VerifyOptions) (chains [][]*x509.Certificate, err error) {
+// ParseCRL parses a PEM or DER encoded CRL, which may be signed with SM2 and SM3
+func ParseCRL(crlBytes []byte) (*pkix.CertificateList, error) {
+	return sx509.ParseCRL(crlBytes)
+}
+
+// CheckCRLSignature checks that the signature over crl, with SM2 and SM3 or a standard
+// algorithm, is from cert
+func CheckCRLSignature(cert *x509.Certificate, crl *pkix.CertificateList) error {
+	return (*hverify.SMCertificate)(cert).CheckCRLSignature(crl)
+}
+
+// Verify builds the chains of cert like x509.Certificate.Verify. Chains that contain SM
+// certificates, possibly mixed with standard ones, are built from the certificates of
+// chainOpts, and are rejected if a certificate is listed by the CRL of its issuer in chainOpts.
+func Verify(cert *x509.Certificate, opts x509.VerifyOptions, chainOpts ChainOptions) (chains [][]*x509.Certificate, err error) {
 	if val, err := cert.Verify(opts); err == nil {
-		return val, nil
+		if !containsSMCertificate(val) {
+			return val, nil
+		}
+		// SM certificates of standard CAs
+		return hverify.FilterRevokedChains(val, chainOpts.CRLs)
+	}
+	smOpts := hverify.SMVerifyOptions{
+		DNSName:       opts.DNSName,
+		Intermediates: hverify.NewSMCertPool(chainOpts.Intermediates...),
+		CurrentTime:   opts.CurrentTime,
+		KeyUsages:     opts.KeyUsages,
+		CRLs:          chainOpts.CRLs,
+	}
+	if opts.Roots != nil {
+		smOpts.Roots = hverify.NewSMCertPool(chainOpts.Roots...)
 	}
-	validationChains, err := (*hverify.SMCertificate)(unsafe.Pointer(cert)).SMVerify(opts)
+	validationChains, err := (*hverify.SMCertificate)(cert).SMVerify(smOpts)
 	if err != nil {
 		return nil, errors.WithMessage(err, "verify with sm cert fail")
 	}
 	return validationChains, nil
 }
 
+func containsSMCertificate(chains [][]*x509.Certificate) bool {
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if IsSMCertificate(cert) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func WrapHashResult(bsp bccsp.BCCSP, msg []byte, digest []byte) []byte {
 	var val = isBccspOfSM(bsp)
 	if val {
@@ -83,6 +122,11 @@ func PEMtoPrivateKey(raw []byte, pwd []byte) (interface{}, error) {
 	return key, nil
 }
 
+// PrivateKeyToPEM converts an EC or SM2 private key to PEM, encrypted with pwd if it is not empty
+func PrivateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
+	return sx509.PrivateKeyToPEM(privateKey, pwd)
+}
+
 func PrivateKeyToDER(privateKey *ecdsa.PrivateKey) ([]byte, error) {
 	if privateKey == nil {
 		return nil, errors.New("Invalid sm2 private key. It must be different from nil.")
@@ -0,0 +1,253 @@
+package hx509
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+type testCA struct {
+	template *sm2.Certificate
+	key      crypto.Signer
+	cert     *x509.Certificate
+}
+
+func newECDSAKey(t *testing.T) crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func newSM2Key(t *testing.T) crypto.Signer {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func newTemplate(name string, serial int64, isCA bool) *sm2.Certificate {
+	template := &sm2.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		SubjectKeyId:          []byte(name),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage = sm2.KeyUsageCertSign
+	}
+	return template
+}
+
+// issue creates a certificate for key signed by parent, or self-signed if parent is nil
+func issue(t *testing.T, template *sm2.Certificate, key crypto.Signer, parent *testCA) *testCA {
+	parentTemplate, parentKey := template, key
+	if parent != nil {
+		parentTemplate, parentKey = parent.template, parent.key
+	}
+	if _, ok := parentKey.(*sm2.PrivateKey); ok {
+		template.SignatureAlgorithm = sm2.SM2WithSM3
+	}
+
+	der, err := sm2.CreateCertificate(rand.Reader, template, parentTemplate, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{template: template, key: key, cert: cert}
+}
+
+func verifyOpts(roots, intermediates []*x509.Certificate) (x509.VerifyOptions, ChainOptions) {
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	var chainOpts ChainOptions
+	for _, cert := range roots {
+		chainOpts.AddRoot(&opts, cert)
+	}
+	for _, cert := range intermediates {
+		chainOpts.AddIntermediate(&opts, cert)
+	}
+	return opts, chainOpts
+}
+
+func TestVerifyMixedChains(t *testing.T) {
+	swRoot := issue(t, newTemplate("sw-root", 1, true), newECDSAKey(t), nil)
+	smRoot := issue(t, newTemplate("sm-root", 2, true), newSM2Key(t), nil)
+	assert.False(t, IsSMCertificate(swRoot.cert))
+	assert.True(t, IsSMCertificate(smRoot.cert))
+
+	tests := []struct {
+		name    string
+		root    *testCA
+		chain   []bool // SM keys of the intermediates and the leaf
+		invalid bool
+	}{
+		{name: "sm", root: smRoot, chain: []bool{true}},
+		{name: "sw leaf of sm root", root: smRoot, chain: []bool{false}},
+		{name: "sm leaf of sw root", root: swRoot, chain: []bool{true}},
+		{name: "sw leaf of sm intermediate of sw root", root: swRoot, chain: []bool{true, false}},
+		{name: "sm leaf of sw intermediate of sm root", root: smRoot, chain: []bool{false, true}},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var intermediates []*x509.Certificate
+			parent := tc.root
+			for j, sm := range tc.chain {
+				key := newECDSAKey(t)
+				if sm {
+					key = newSM2Key(t)
+				}
+				isCA := j < len(tc.chain)-1
+				parent = issue(t, newTemplate(tc.name+string(rune('a'+j)), int64(10*i+j+10), isCA), key, parent)
+				if isCA {
+					intermediates = append(intermediates, parent.cert)
+				}
+			}
+			leaf := parent.cert
+			assert.Equal(t, tc.chain[len(tc.chain)-1], IsSMCertificate(leaf))
+
+			opts, chainOpts := verifyOpts([]*x509.Certificate{tc.root.cert}, intermediates)
+			chains, err := Verify(leaf, opts, chainOpts)
+			require.NoError(t, err)
+			require.Len(t, chains, 1)
+			assert.Len(t, chains[0], len(tc.chain)+1)
+			assert.Equal(t, tc.root.cert.Raw, chains[0][len(chains[0])-1].Raw)
+
+			// the chain doesn't verify against the root of the other kind
+			other := swRoot
+			if tc.root == swRoot {
+				other = smRoot
+			}
+			opts, chainOpts = verifyOpts([]*x509.Certificate{other.cert}, intermediates)
+			_, err = Verify(leaf, opts, chainOpts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyWithoutChainOptions(t *testing.T) {
+	root := issue(t, newTemplate("root", 1, true), newSM2Key(t), nil)
+	leaf := issue(t, newTemplate("leaf", 2, false), newSM2Key(t), root)
+
+	// certificates that are only in the pool are unknown to the SM verification
+	opts := x509.VerifyOptions{Roots: x509.NewCertPool()}
+	opts.Roots.AddCert(root.cert)
+	_, err := Verify(leaf.cert, opts, ChainOptions{})
+	assert.Error(t, err)
+
+	_, err = Verify(leaf.cert, opts, ChainOptions{Roots: []*x509.Certificate{root.cert}})
+	assert.NoError(t, err)
+}
+
+func TestIsSMKey(t *testing.T) {
+	smKey, err := sm2.GenerateKey()
+	require.NoError(t, err)
+	swKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	assert.True(t, IsSMKey(smKey))
+	assert.True(t, IsSMKey(&smKey.PublicKey))
+	assert.True(t, IsSMKey(&ecdsa.PublicKey{Curve: smKey.Curve, X: smKey.X, Y: smKey.Y}))
+	assert.False(t, IsSMKey(swKey))
+	assert.False(t, IsSMKey(&swKey.PublicKey))
+	assert.False(t, IsSMKey("key"))
+
+	smDER, err := sm2.MarshalSm2PublicKey(&smKey.PublicKey)
+	require.NoError(t, err)
+	assert.True(t, IsSMKey(smDER))
+	swDER, err := x509.MarshalPKIXPublicKey(&swKey.PublicKey)
+	require.NoError(t, err)
+	assert.False(t, IsSMKey(swDER))
+
+	smPrivDER, err := PrivateKeyToDER(&ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: smKey.Curve, X: smKey.X, Y: smKey.Y}, D: smKey.D})
+	require.NoError(t, err)
+	assert.True(t, IsSMKey(smPrivDER))
+	assert.False(t, IsSMKey([]byte("0123456789abcdef")))
+}
+
+// revoke returns a CRL of ca revoking certs
+func revoke(t *testing.T, ca *testCA, certs ...*x509.Certificate) *pkix.CertificateList {
+	var revoked []pkix.RevokedCertificate
+	for _, cert := range certs {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	}
+	der, err := ca.template.CreateCRL(rand.Reader, ca.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	crl, err := ParseCRL(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+	require.NoError(t, err)
+	return crl
+}
+
+func TestCheckCRLSignature(t *testing.T) {
+	smRoot := issue(t, newTemplate("sm-root", 1, true), newSM2Key(t), nil)
+	swRoot := issue(t, newTemplate("sw-root", 2, true), newECDSAKey(t), nil)
+	otherSMRoot := issue(t, newTemplate("other-sm-root", 3, true), newSM2Key(t), nil)
+
+	smCRL := revoke(t, smRoot)
+	swCRL := revoke(t, swRoot)
+
+	assert.NoError(t, CheckCRLSignature(smRoot.cert, smCRL))
+	assert.NoError(t, CheckCRLSignature(swRoot.cert, swCRL))
+	assert.Error(t, CheckCRLSignature(otherSMRoot.cert, smCRL))
+	assert.Error(t, CheckCRLSignature(swRoot.cert, smCRL))
+	assert.Error(t, CheckCRLSignature(smRoot.cert, swCRL))
+
+	tampered := *smCRL
+	tampered.TBSCertList.Raw = append([]byte(nil), smCRL.TBSCertList.Raw...)
+	tampered.TBSCertList.Raw[len(tampered.TBSCertList.Raw)-1] ^= 1
+	assert.Error(t, CheckCRLSignature(smRoot.cert, &tampered))
+}
+
+func TestVerifyRevoked(t *testing.T) {
+	smRoot := issue(t, newTemplate("sm-root", 1, true), newSM2Key(t), nil)
+	swRoot := issue(t, newTemplate("sw-root", 2, true), newECDSAKey(t), nil)
+	intermediate := issue(t, newTemplate("sm-intermediate", 3, true), newSM2Key(t), smRoot)
+	leaf := issue(t, newTemplate("sm-leaf", 4, false), newSM2Key(t), intermediate)
+	swLeaf := issue(t, newTemplate("sm-leaf-of-sw-root", 5, false), newSM2Key(t), swRoot)
+	// an unrelated CA issuing certificates with the same serial numbers
+	otherRoot := issue(t, newTemplate("other-root", 6, true), newSM2Key(t), nil)
+
+	tests := []struct {
+		name    string
+		leaf    *testCA
+		crls    []*pkix.CertificateList
+		revoked bool
+	}{
+		{name: "no CRL", leaf: leaf},
+		{name: "CRL without the leaf", leaf: leaf, crls: []*pkix.CertificateList{revoke(t, intermediate), revoke(t, smRoot)}},
+		{name: "revoked leaf", leaf: leaf, crls: []*pkix.CertificateList{revoke(t, intermediate, leaf.cert)}, revoked: true},
+		{name: "revoked intermediate", leaf: leaf, crls: []*pkix.CertificateList{revoke(t, smRoot, intermediate.cert)}, revoked: true},
+		{name: "leaf in the CRL of another CA", leaf: leaf, crls: []*pkix.CertificateList{revoke(t, otherRoot, leaf.cert), revoke(t, smRoot, leaf.cert)}},
+		{name: "sm leaf revoked by sw root", leaf: swLeaf, crls: []*pkix.CertificateList{revoke(t, swRoot, swLeaf.cert)}, revoked: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, chainOpts := verifyOpts([]*x509.Certificate{smRoot.cert, swRoot.cert}, []*x509.Certificate{intermediate.cert})
+			chainOpts.CRLs = tc.crls
+
+			_, err := Verify(tc.leaf.cert, opts, chainOpts)
+			if tc.revoked {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "revoked")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package hsm
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
 	"hash"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/tjfoc/gmsm/sm3"
+	"golang.org/x/crypto/sha3"
 )
 
 const (
@@ -11,6 +16,17 @@ const (
 	SM3 = "SM3"
 )
 
+const (
+	// LegacyHashMode computes SM3 for every hash opts, including the SHA ones.
+	// This is the default, kept for compatibility with existing SM deployments.
+	LegacyHashMode = "legacy"
+
+	// StrictHashMode computes the digest that the hash opts ask for: SHA opts
+	// yield SHA digests, SM3Opts yields SM3 and bccsp.SHAOpts follows the
+	// configured hash family.
+	StrictHashMode = "strict"
+)
+
 type SM3Opts struct {
 }
 
@@ -32,3 +48,29 @@ func (c *hasher) Hash(msg []byte, opts bccsp.HashOpts) (hash []byte, err error)
 func (c *hasher) GetHash(opts bccsp.HashOpts) (h hash.Hash, err error) {
 	return c.hash(), nil
 }
+
+// hashFamilyFunction returns the hash function and the network hash opts of the
+// given hash family at the given security level
+func hashFamilyFunction(securityLevel int, hashFamily string) (func() hash.Hash, bccsp.HashOpts, error) {
+	switch hashFamily {
+	case SM3:
+		return sm3.New, &SM3Opts{}, nil
+	case "SHA2":
+		switch securityLevel {
+		case 256:
+			return sha256.New, &bccsp.SHA256Opts{}, nil
+		case 384:
+			return sha512.New384, &bccsp.SHA384Opts{}, nil
+		}
+	case "SHA3":
+		switch securityLevel {
+		case 256:
+			return sha3.New256, &bccsp.SHA3_256Opts{}, nil
+		case 384:
+			return sha3.New384, &bccsp.SHA3_384Opts{}, nil
+		}
+	default:
+		return nil, nil, fmt.Errorf("Hash Family not supported [%s]", hashFamily)
+	}
+	return nil, nil, fmt.Errorf("Security level not supported [%d] for hash family [%s]", securityLevel, hashFamily)
+}
@@ -4,7 +4,10 @@
 package hsm
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
 	"github.com/tjfoc/gmsm/sm3"
+	"golang.org/x/crypto/sha3"
 	"hash"
 	"reflect"
 
@@ -36,7 +39,15 @@ func NewDefaultSecurityLevelWithKeystore(keyStore bccsp.KeyStore) (bccsp.BCCSP,
 
 // New returns a new instance of the software-based BCCSP
 // set at the passed security level, hash family and KeyStore.
+// Every hash opts yields SM3 (see LegacyHashMode).
 func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	return NewWithHashMode(securityLevel, hashFamily, LegacyHashMode, keyStore)
+}
+
+// NewWithHashMode returns a new instance of the software-based BCCSP
+// set at the passed security level, hash family, hash mode and KeyStore.
+// An empty hash mode is the same as LegacyHashMode.
+func NewWithHashMode(securityLevel int, hashFamily string, hashMode string, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
 	// Check KeyStore
 	if keyStore == nil {
 		return nil, errors.Errorf("Invalid bccsp.KeyStore instance. It must be different from nil.")
@@ -44,9 +55,14 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 
 	// Set the encryptors
 	encryptors := make(map[reflect.Type]Encryptor)
+	encryptors[reflect.TypeOf(&sm4PrivateKey{})] = &sm4Encryptor{}
+	encryptors[reflect.TypeOf(&sm2PublicKey{})] = &sm2Encryptor{}
+	encryptors[reflect.TypeOf(&sm2PrivateKey{})] = &sm2Encryptor{}
 
 	// Set the decryptors
 	decryptors := make(map[reflect.Type]Decryptor)
+	decryptors[reflect.TypeOf(&sm4PrivateKey{})] = &sm4Decryptor{}
+	decryptors[reflect.TypeOf(&sm2PrivateKey{})] = &sm2Decryptor{}
 
 	// Set the signers
 	signers := make(map[reflect.Type]Signer)
@@ -58,10 +74,29 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 
 	// Set the hashers
 	hashers := make(map[reflect.Type]Hasher)
-	hashers[reflect.TypeOf(&SM3Opts{})] = &hasher{sm3.New}
-	hashers[reflect.TypeOf(&bccsp.SHA256Opts{})] = &hasher{sm3.New}
-	hashers[reflect.TypeOf(&bccsp.SHAOpts{})] = &hasher{sm3.New}
-	hashers[reflect.TypeOf(&bccsp.SHA3_256Opts{})] = &hasher{sm3.New}
+	var networkHashOpts bccsp.HashOpts
+	switch hashMode {
+	case "", LegacyHashMode:
+		hashers[reflect.TypeOf(&SM3Opts{})] = &hasher{sm3.New}
+		hashers[reflect.TypeOf(&bccsp.SHA256Opts{})] = &hasher{sm3.New}
+		hashers[reflect.TypeOf(&bccsp.SHAOpts{})] = &hasher{sm3.New}
+		hashers[reflect.TypeOf(&bccsp.SHA3_256Opts{})] = &hasher{sm3.New}
+		networkHashOpts = &SM3Opts{}
+	case StrictHashMode:
+		familyHash, familyOpts, err := hashFamilyFunction(securityLevel, hashFamily)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed initializing hash mode [%s]", hashMode)
+		}
+		hashers[reflect.TypeOf(&SM3Opts{})] = &hasher{sm3.New}
+		hashers[reflect.TypeOf(&bccsp.SHAOpts{})] = &hasher{familyHash}
+		hashers[reflect.TypeOf(&bccsp.SHA256Opts{})] = &hasher{sha256.New}
+		hashers[reflect.TypeOf(&bccsp.SHA384Opts{})] = &hasher{sha512.New384}
+		hashers[reflect.TypeOf(&bccsp.SHA3_256Opts{})] = &hasher{sha3.New256}
+		hashers[reflect.TypeOf(&bccsp.SHA3_384Opts{})] = &hasher{sha3.New384}
+		networkHashOpts = familyOpts
+	default:
+		return nil, errors.Errorf("Hash mode not supported [%s]", hashMode)
+	}
 
 	impl := &impl{
 		ks:         keyStore,
@@ -69,12 +104,15 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 		decryptors: decryptors,
 		signers:    signers,
 		verifiers:  verifiers,
-		hashers:    hashers}
+		hashers:    hashers,
+
+		networkHashOpts: networkHashOpts}
 
 	// Set the key generators
 	keyGenerators := make(map[reflect.Type]KeyGenerator)
 	keyGenerators[reflect.TypeOf(&bccsp.ECDSAP256KeyGenOpts{})] = &sm2KeyGenerator{}
 	keyGenerators[reflect.TypeOf(&SM2KeyGenOpts{})] = &sm2KeyGenerator{}
+	keyGenerators[reflect.TypeOf(&SM4KeyGenOpts{})] = &sm4KeyGenerator{}
 
 	impl.keyGenerators = keyGenerators
 
@@ -82,6 +120,7 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	keyDerivers := make(map[reflect.Type]KeyDeriver)
 	keyDerivers[reflect.TypeOf(&sm2PrivateKey{})] = &sm2PrivateKeyKeyDeriver{}
 	keyDerivers[reflect.TypeOf(&sm2PublicKey{})] = &sm2PublicKeyKeyDeriver{}
+	keyDerivers[reflect.TypeOf(&sm4PrivateKey{})] = &sm4PrivateKeyKeyDeriver{}
 
 	impl.keyDerivers = keyDerivers
 
@@ -92,6 +131,7 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	keyImporters[reflect.TypeOf(&SM2GoPublicKeyImportOpts{})] = &sm2GoPublicKeyImportOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.X509PublicKeyImportOpts{})] = &x509PublicKeyImportOptsKeyImporter{bccsp: impl}
 	keyImporters[reflect.TypeOf(&bccsp.ECDSAPrivateKeyImportOpts{})] = &sm2PrivateKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&SM4ImportKeyOpts{})] = &sm4ImportKeyOptsKeyImporter{}
 
 	impl.keyImporters = keyImporters
 
@@ -110,6 +150,14 @@ type impl struct {
 	signers       map[reflect.Type]Signer
 	verifiers     map[reflect.Type]Verifier
 	hashers       map[reflect.Type]Hasher
+
+	networkHashOpts bccsp.HashOpts
+}
+
+// NetworkHashOpts returns the hash opts of the hash function used by the network
+// (e.g. for transaction IDs), which is the configured hash family.
+func (csp *impl) NetworkHashOpts() bccsp.HashOpts {
+	return csp.networkHashOpts
 }
 
 // KeyGen generates a key using opts.
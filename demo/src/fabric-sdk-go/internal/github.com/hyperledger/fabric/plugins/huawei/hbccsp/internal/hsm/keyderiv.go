@@ -6,7 +6,10 @@ import (
 	"errors"
 
 	"crypto/ecdsa"
+	"crypto/hmac"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/tjfoc/gmsm/sm3"
+	"github.com/tjfoc/gmsm/sm4"
 	"math/big"
 )
 
@@ -106,3 +109,30 @@ func (kd *sm2PrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts
 		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
 	}
 }
+
+type sm4PrivateKeyKeyDeriver struct{}
+
+func (kd *sm4PrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (dk bccsp.Key, err error) {
+	// Validate opts
+	if opts == nil {
+		return nil, errors.New("Invalid opts parameter. It must not be nil.")
+	}
+
+	sm4K := k.(*sm4PrivateKey)
+
+	switch hmacOpts := opts.(type) {
+	// Derive an SM4 key with HMAC-SM3
+	case *SM4HMACDeriveKeyOpts:
+		mac := hmac.New(sm3.New, sm4K.privKey)
+		mac.Write(hmacOpts.Argument())
+		return &sm4PrivateKey{mac.Sum(nil)[:sm4.BlockSize], false}, nil
+
+	case *bccsp.HMACDeriveKeyOpts:
+		mac := hmac.New(sm3.New, sm4K.privKey)
+		mac.Write(hmacOpts.Argument())
+		return &sm4PrivateKey{mac.Sum(nil)[:sm4.BlockSize], true}, nil
+
+	default:
+		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
+	}
+}
@@ -2,9 +2,11 @@ package hsm
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	"github.com/pkg/errors"
 	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm4"
 )
 
 type sm2KeyGenerator struct {
@@ -25,3 +27,14 @@ func (kg *sm2KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error
 	}
 	return &sm2PrivateKey{privateKey}, nil
 }
+
+type sm4KeyGenerator struct {
+}
+
+func (kg *sm4KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
+	key := make([]byte, sm4.BlockSize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "generate key fail")
+	}
+	return &sm4PrivateKey{key, false}, nil
+}
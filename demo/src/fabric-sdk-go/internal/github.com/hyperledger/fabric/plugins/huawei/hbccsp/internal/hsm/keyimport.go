@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/tjfoc/gmsm/sm4"
 	"reflect"
 )
 
@@ -93,3 +94,25 @@ func (ki *x509PublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bc
 		return nil, errors.New("Certificate's public key type not recognized. Supported keys: [SM2, RSA]")
 	}
 }
+
+type sm4ImportKeyOptsKeyImporter struct{}
+
+func (*sm4ImportKeyOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+	sm4Raw, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("Invalid raw material. Expected byte array.")
+	}
+
+	if sm4Raw == nil {
+		return nil, errors.New("Invalid raw material. It must not be nil.")
+	}
+
+	if len(sm4Raw) != sm4.BlockSize {
+		return nil, fmt.Errorf("Invalid Key Length [%d]. Must be %d bytes", len(sm4Raw), sm4.BlockSize)
+	}
+
+	key := make([]byte, len(sm4Raw))
+	copy(key, sm4Raw)
+
+	return &sm4PrivateKey{key, false}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/asn1"
 	"errors"
+	"fmt"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	"github.com/tjfoc/gmsm/sm2"
 	"math/big"
@@ -17,7 +18,8 @@ func MarshalECDSASignature(r, s *big.Int) ([]byte, error) {
 	return asn1.Marshal(ECDSASignature{r, s})
 }
 
-const userID = "1234567812345678"
+// UserID is the default SM2 user ID with which signatures are computed and verified
+const UserID = "1234567812345678"
 
 type sm2Signer struct{}
 
@@ -31,7 +33,7 @@ func signSM2(k *ecdsa.PrivateKey, digest []byte, opts bccsp.SignerOpts) (signatu
 		},
 		D: k.D,
 	}
-	r, s, err := sm2.Sm2Sign(privateKey, digest, []byte(userID))
+	r, s, err := sm2.Sm2Sign(privateKey, digest, []byte(UserID))
 	if err != nil {
 		return nil, errors.New("Sm2Sign fail")
 	}
@@ -56,6 +58,67 @@ func VerifySM2(k *ecdsa.PublicKey, signature, digest []byte, opts bccsp.SignerOp
 		X:     k.X,
 		Y:     k.Y,
 	}
-	valid = sm2.Sm2Verify(publicKey, digest, []byte(userID), ecdsaSignature.R, ecdsaSignature.S)
+	valid = sm2.Sm2Verify(publicKey, digest, []byte(UserID), ecdsaSignature.R, ecdsaSignature.S)
 	return valid, nil
 }
+
+// sm2CiphertextOverhead is the length of C1 (uncompressed point) and C3 (SM3 digest)
+const sm2CiphertextOverhead = 1 + 2*32 + 32
+
+// EncryptSM2 encrypts plaintext to k with SM2
+func EncryptSM2(k *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if len(plaintext) == 0 {
+		return nil, errors.New("Invalid plaintext. It must not be empty")
+	}
+	return sm2.Encrypt(&sm2.PublicKey{Curve: k.Curve, X: k.X, Y: k.Y}, plaintext)
+}
+
+// DecryptSM2 decrypts ciphertext produced by EncryptSM2 with k
+func DecryptSM2(k *ecdsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) <= sm2CiphertextOverhead || ciphertext[0] != 0x04 {
+		return nil, errors.New("Invalid ciphertext. It is too short or not in uncompressed form")
+	}
+	x := new(big.Int).SetBytes(ciphertext[1:33])
+	y := new(big.Int).SetBytes(ciphertext[33:65])
+	if !k.Curve.IsOnCurve(x, y) {
+		return nil, errors.New("Invalid ciphertext. The ephemeral key is not on the curve")
+	}
+
+	privateKey := &sm2.PrivateKey{
+		PublicKey: sm2.PublicKey{Curve: k.Curve, X: k.X, Y: k.Y},
+		D:         k.D,
+	}
+	return sm2.Decrypt(privateKey, ciphertext)
+}
+
+type sm2Encryptor struct{}
+
+func (e *sm2Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	var pub *ecdsa.PublicKey
+	switch kk := k.(type) {
+	case *sm2PublicKey:
+		pub = kk.pubKey
+	case *sm2PrivateKey:
+		pub = &kk.privKey.PublicKey
+	default:
+		return nil, fmt.Errorf("Invalid key type [%T]", k)
+	}
+
+	switch opts.(type) {
+	case *SM2EncryptionOpts, SM2EncryptionOpts:
+		return EncryptSM2(pub, plaintext)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
+
+type sm2Decryptor struct{}
+
+func (*sm2Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	switch opts.(type) {
+	case *SM2EncryptionOpts, SM2EncryptionOpts:
+		return DecryptSM2(k.(*sm2PrivateKey).privKey, ciphertext)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
@@ -86,3 +86,8 @@ func (opts *SM2ReRandKeyOpts) Ephemeral() bool {
 func (opts *SM2ReRandKeyOpts) ExpansionValue() []byte {
 	return opts.Expansion
 }
+
+// SM2EncryptionOpts contains options for SM2 public key encryption (GB/T 32918.4).
+// The ciphertext is C1 || C3 || C2: the uncompressed ephemeral point, the SM3 digest
+// and the encrypted plaintext.
+type SM2EncryptionOpts struct{}
@@ -0,0 +1,226 @@
+package hsm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/tjfoc/gmsm/sm4"
+)
+
+func sm4PKCS7Padding(src []byte) []byte {
+	padding := sm4.BlockSize - len(src)%sm4.BlockSize
+	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
+	return append(src, padtext...)
+}
+
+func sm4PKCS7UnPadding(src []byte) ([]byte, error) {
+	length := len(src)
+	if length == 0 {
+		return nil, errors.New("Invalid pkcs7 padding (empty plaintext)")
+	}
+	unpadding := int(src[length-1])
+
+	if unpadding > sm4.BlockSize || unpadding == 0 {
+		return nil, errors.New("Invalid pkcs7 padding (unpadding > sm4.BlockSize || unpadding == 0)")
+	}
+
+	pad := src[len(src)-unpadding:]
+	for i := 0; i < unpadding; i++ {
+		if pad[i] != byte(unpadding) {
+			return nil, errors.New("Invalid pkcs7 padding (pad[i] != unpadding)")
+		}
+	}
+
+	return src[:(length - unpadding)], nil
+}
+
+func sm4CBCEncryptWithIV(iv []byte, key, s []byte) ([]byte, error) {
+	if len(s)%sm4.BlockSize != 0 {
+		return nil, errors.New("Invalid plaintext. It must be a multiple of the block size")
+	}
+
+	if len(iv) != sm4.BlockSize {
+		return nil, errors.New("Invalid IV. It must have length the block size")
+	}
+
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, sm4.BlockSize+len(s))
+	copy(ciphertext[:sm4.BlockSize], iv)
+
+	mode := cipher.NewCBCEncrypter(block, iv)
+	mode.CryptBlocks(ciphertext[sm4.BlockSize:], s)
+
+	return ciphertext, nil
+}
+
+func sm4CBCDecrypt(key, src []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < sm4.BlockSize {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+	iv := src[:sm4.BlockSize]
+	src = src[sm4.BlockSize:]
+
+	if len(src)%sm4.BlockSize != 0 {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+
+	pt := make([]byte, len(src))
+	mode := cipher.NewCBCDecrypter(block, iv)
+	mode.CryptBlocks(pt, src)
+
+	return pt, nil
+}
+
+// SM4CBCPKCS7EncryptWithRand combines CBC encryption and PKCS7 padding using as prng the passed to the function
+func SM4CBCPKCS7EncryptWithRand(prng io.Reader, key, src []byte) ([]byte, error) {
+	iv := make([]byte, sm4.BlockSize)
+	if _, err := io.ReadFull(prng, iv); err != nil {
+		return nil, err
+	}
+
+	return SM4CBCPKCS7EncryptWithIV(iv, key, src)
+}
+
+// SM4CBCPKCS7EncryptWithIV combines CBC encryption and PKCS7 padding, the IV used is the one passed to the function
+func SM4CBCPKCS7EncryptWithIV(iv []byte, key, src []byte) ([]byte, error) {
+	// First pad
+	tmp := sm4PKCS7Padding(src)
+
+	// Then encrypt
+	return sm4CBCEncryptWithIV(iv, key, tmp)
+}
+
+// SM4CBCPKCS7Decrypt combines CBC decryption and PKCS7 unpadding
+func SM4CBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
+	// First decrypt
+	pt, err := sm4CBCDecrypt(key, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return sm4PKCS7UnPadding(pt)
+}
+
+// SM4GCMEncrypt encrypts src in GCM mode. The returned ciphertext is prefixed with the nonce.
+// If nonce is nil then a random one is sampled from prng.
+func SM4GCMEncrypt(prng io.Reader, nonce []byte, key, src, additionalData []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) == 0 {
+		nonce = make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(prng, nonce); err != nil {
+			return nil, err
+		}
+	} else if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("Invalid nonce. It must have length [%d]", gcm.NonceSize())
+	}
+
+	ciphertext := make([]byte, len(nonce), len(nonce)+len(src)+gcm.Overhead())
+	copy(ciphertext, nonce)
+
+	return gcm.Seal(ciphertext, nonce, src, additionalData), nil
+}
+
+// SM4GCMDecrypt decrypts a ciphertext that was produced by SM4GCMEncrypt
+func SM4GCMDecrypt(key, src, additionalData []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("Invalid ciphertext. It is too short")
+	}
+
+	nonce := src[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, src[gcm.NonceSize():], additionalData)
+}
+
+type sm4Encryptor struct{}
+
+func (e *sm4Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	key := k.(*sm4PrivateKey).privKey
+
+	switch o := opts.(type) {
+	case *SM4CBCPKCS7ModeOpts:
+		// SM4 in CBC mode with PKCS7 padding
+
+		if len(o.IV) != 0 && o.PRNG != nil {
+			return nil, errors.New("Invalid options. Either IV or PRNG should be different from nil, or both nil.")
+		}
+
+		if len(o.IV) != 0 {
+			// Encrypt with the passed IV
+			return SM4CBCPKCS7EncryptWithIV(o.IV, key, plaintext)
+		} else if o.PRNG != nil {
+			// Encrypt with PRNG
+			return SM4CBCPKCS7EncryptWithRand(o.PRNG, key, plaintext)
+		}
+		return SM4CBCPKCS7EncryptWithRand(rand.Reader, key, plaintext)
+	case SM4CBCPKCS7ModeOpts:
+		return e.Encrypt(k, plaintext, &o)
+	case *SM4GCMModeOpts:
+		// SM4 in GCM mode
+
+		if len(o.Nonce) != 0 && o.PRNG != nil {
+			return nil, errors.New("Invalid options. Either Nonce or PRNG should be different from nil, or both nil.")
+		}
+
+		prng := o.PRNG
+		if prng == nil {
+			prng = rand.Reader
+		}
+		return SM4GCMEncrypt(prng, o.Nonce, key, plaintext, o.AdditionalData)
+	case SM4GCMModeOpts:
+		return e.Encrypt(k, plaintext, &o)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
+
+type sm4Decryptor struct{}
+
+func (*sm4Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	key := k.(*sm4PrivateKey).privKey
+
+	// check for mode
+	switch o := opts.(type) {
+	case *SM4CBCPKCS7ModeOpts, SM4CBCPKCS7ModeOpts:
+		// SM4 in CBC mode with PKCS7 padding
+		return SM4CBCPKCS7Decrypt(key, ciphertext)
+	case *SM4GCMModeOpts:
+		// SM4 in GCM mode
+		return SM4GCMDecrypt(key, ciphertext, o.AdditionalData)
+	case SM4GCMModeOpts:
+		return SM4GCMDecrypt(key, ciphertext, o.AdditionalData)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
@@ -0,0 +1,97 @@
+package hsm
+
+import "io"
+
+const (
+	// SM4 block cipher identifier
+	SM4 = "SM4"
+)
+
+// SM4KeyGenOpts contains options for SM4 key generation.
+type SM4KeyGenOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (opts *SM4KeyGenOpts) Algorithm() string {
+	return SM4
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *SM4KeyGenOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// SM4ImportKeyOpts contains options for importing SM4 keys.
+type SM4ImportKeyOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *SM4ImportKeyOpts) Algorithm() string {
+	return SM4
+}
+
+// Ephemeral returns true if the key generated has to be ephemeral,
+// false otherwise.
+func (opts *SM4ImportKeyOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// SM4HMACDeriveKeyOpts contains options for HMAC-SM3 key derivation.
+// The derived key is truncated to the SM4 key size and can be used
+// for encryption.
+type SM4HMACDeriveKeyOpts struct {
+	Temporary bool
+	Arg       []byte
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *SM4HMACDeriveKeyOpts) Algorithm() string {
+	return "HMAC_SM3_SM4"
+}
+
+// Ephemeral returns true if the key to derive has to be ephemeral,
+// false otherwise.
+func (opts *SM4HMACDeriveKeyOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// Argument returns the argument to be passed to the HMAC
+func (opts *SM4HMACDeriveKeyOpts) Argument() []byte {
+	return opts.Arg
+}
+
+// SM4CBCPKCS7ModeOpts contains options for SM4 encryption in CBC mode
+// with PKCS7 padding.
+// Notice that both IV and PRNG can be nil. In that case, the BCCSP implementation
+// is supposed to sample the IV using a cryptographic secure PRNG.
+// Notice also that either IV or PRNG can be different from nil.
+type SM4CBCPKCS7ModeOpts struct {
+	// IV is the initialization vector to be used by the underlying cipher.
+	// The length of IV must be the same as the Block's block size.
+	// It is used only if different from nil.
+	IV []byte
+	// PRNG is an instance of a PRNG to be used by the underlying cipher.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
+
+// SM4GCMModeOpts contains options for SM4 encryption in GCM mode.
+// The ciphertext is the nonce followed by the sealed data.
+// Notice that both Nonce and PRNG can be nil. In that case, the BCCSP implementation
+// is supposed to sample the nonce using a cryptographic secure PRNG.
+// Notice also that either Nonce or PRNG can be different from nil.
+type SM4GCMModeOpts struct {
+	// Nonce is the nonce to be used by the underlying cipher.
+	// Its length must be the standard GCM nonce size (12 bytes).
+	// It is used only if different from nil.
+	Nonce []byte
+	// AdditionalData is authenticated but not encrypted.
+	// The same value must be passed on decryption.
+	AdditionalData []byte
+	// PRNG is an instance of a PRNG to be used by the underlying cipher.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
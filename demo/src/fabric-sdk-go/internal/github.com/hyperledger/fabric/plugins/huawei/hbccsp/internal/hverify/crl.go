@@ -0,0 +1,89 @@
+package hverify
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/sx509"
+	"github.com/pkg/errors"
+)
+
+// RevokedError is returned by SMVerify when all the chains of the certificate contain a revoked certificate
+type RevokedError struct {
+	// Cert is the revoked certificate
+	Cert *x509.Certificate
+}
+
+func (e RevokedError) Error() string {
+	return "x509: certificate has been revoked (SN: " + e.Cert.SerialNumber.String() + ")"
+}
+
+// CheckCRLSignature checks that the signature over crl is from c. The CRL may be signed
+// with SM2 and SM3 or with a standard algorithm.
+func (c *SMCertificate) CheckCRLSignature(crl *pkix.CertificateList) error {
+	if crl == nil {
+		return errors.New("crl is nil")
+	}
+	algo := sx509.CRLSignatureAlgorithm(crl)
+	if algo == sx509.SM2WithSM3 {
+		return checkSignature(algo, crl.TBSCertList.Raw, crl.SignatureValue.RightAlign(), c.PublicKey)
+	}
+	// a standard CA
+	if sx509.IsSMPublicKey(c.RawSubjectPublicKeyInfo) {
+		return x509.ErrUnsupportedAlgorithm
+	}
+	return (*x509.Certificate)(c).CheckCRLSignature(crl)
+}
+
+// FilterRevokedChains returns the chains without a certificate listed by one of crls signed by its
+// issuer, or the RevokedError of the first chain if all of them contain a revoked certificate
+func FilterRevokedChains(chains [][]*x509.Certificate, crls []*pkix.CertificateList) ([][]*x509.Certificate, error) {
+	if len(crls) == 0 {
+		return chains, nil
+	}
+
+	var valid [][]*x509.Certificate
+	var revokedErr error
+	for _, chain := range chains {
+		if err := checkRevocation(chain, crls); err != nil {
+			if revokedErr == nil {
+				revokedErr = err
+			}
+			continue
+		}
+		valid = append(valid, chain)
+	}
+	if len(valid) == 0 && revokedErr != nil {
+		return nil, revokedErr
+	}
+	return valid, nil
+}
+
+// checkRevocation returns a RevokedError if a certificate of chain, but the root, is listed by one of
+// crls signed by its issuer
+func checkRevocation(chain []*x509.Certificate, crls []*pkix.CertificateList) error {
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], (*SMCertificate)(chain[i+1])
+		for _, crl := range crls {
+			if !listsSerialNumber(crl, cert) {
+				continue
+			}
+			if err := issuer.CheckCRLSignature(crl); err != nil {
+				// the CRL of another CA
+				logger.Debugf("CRL listing the serial number %s is not signed by the issuer: %s", cert.SerialNumber, err)
+				continue
+			}
+			return RevokedError{Cert: cert}
+		}
+	}
+	return nil
+}
+
+func listsSerialNumber(crl *pkix.CertificateList, cert *x509.Certificate) bool {
+	for _, rc := range crl.TBSCertList.RevokedCertificates {
+		if rc.SerialNumber != nil && rc.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true
+		}
+	}
+	return false
+}
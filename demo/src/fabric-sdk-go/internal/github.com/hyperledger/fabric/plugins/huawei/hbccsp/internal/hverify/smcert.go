@@ -6,16 +6,12 @@ import (
 	"crypto/ecdsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/asn1"
 	"fmt"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/sx509"
-	"math/big"
-	"net"
 	"runtime"
 	"strings"
 	"time"
-	"unsafe"
 
 	flogging "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkpatch/logbridge"
 	"github.com/pkg/errors"
@@ -36,70 +32,54 @@ type SMVerifyOptions struct {
 	CurrentTime   time.Time   // if zero, the current time is used
 
 	KeyUsages []x509.ExtKeyUsage
+
+	// CRLs are the revocation lists of the CAs. Chains with a certificate listed by
+	// the CRL of its issuer are rejected.
+	CRLs []*pkix.CertificateList
 }
+
+// SMCertPool is a set of certificates which may mix SM and standard certificates
 type SMCertPool struct {
 	bySubjectKeyId map[string][]int
 	byName         map[string][]int
 	certs          []*SMCertificate
 }
 
-type SMCertificate struct {
-	Raw                     []byte // Complete ASN.1 DER content (certificate, signature algorithm and signature).
-	RawTBSCertificate       []byte // Certificate part of raw ASN.1 DER content.
-	RawSubjectPublicKeyInfo []byte // DER encoded SubjectPublicKeyInfo.
-	RawSubject              []byte // DER encoded Subject
-	RawIssuer               []byte // DER encoded Issuer
-
-	Signature          []byte
-	SignatureAlgorithm x509.SignatureAlgorithm
-
-	PublicKeyAlgorithm x509.PublicKeyAlgorithm
-	PublicKey          interface{}
-
-	Version             int
-	SerialNumber        *big.Int
-	Issuer              pkix.Name
-	Subject             pkix.Name
-	NotBefore, NotAfter time.Time // Validity bounds.
-	KeyUsage            x509.KeyUsage
-
-	Extensions []pkix.Extension
-
-	ExtraExtensions []pkix.Extension
-
-	UnhandledCriticalExtensions []asn1.ObjectIdentifier
-
-	ExtKeyUsage        []x509.ExtKeyUsage      // Sequence of extended key usages.
-	UnknownExtKeyUsage []asn1.ObjectIdentifier // Encountered extended key usages unknown to this package.
-
-	BasicConstraintsValid bool // if true then the next two fields are valid.
-	IsCA                  bool
-	MaxPathLen            int
-
-	MaxPathLenZero bool
-
-	SubjectKeyId   []byte
-	AuthorityKeyId []byte
-
-	// RFC 5280, 4.2.2.1 (Authority Information Access)
-	OCSPServer            []string
-	IssuingCertificateURL []string
-
-	// Subject Alternate Name values
-	DNSNames       []string
-	EmailAddresses []string
-	IPAddresses    []net.IP
+// NewSMCertPool returns a pool holding the given certificates
+func NewSMCertPool(certs ...*x509.Certificate) *SMCertPool {
+	s := &SMCertPool{
+		bySubjectKeyId: make(map[string][]int),
+		byName:         make(map[string][]int),
+	}
+	for _, cert := range certs {
+		s.AddCert(cert)
+	}
+	return s
+}
 
-	// Name constraints
-	PermittedDNSDomainsCritical bool // if true then the name constraints are marked critical.
-	PermittedDNSDomains         []string
+// AddCert adds a certificate to the pool
+func (s *SMCertPool) AddCert(cert *x509.Certificate) {
+	if cert == nil {
+		panic("adding nil Certificate to SMCertPool")
+	}
+	if s.contains(cert) {
+		return
+	}
 
-	// CRL Distribution Points
-	CRLDistributionPoints []string
+	n := len(s.certs)
+	s.certs = append(s.certs, (*SMCertificate)(cert))
 
-	PolicyIdentifiers []asn1.ObjectIdentifier
+	if len(cert.SubjectKeyId) > 0 {
+		keyId := string(cert.SubjectKeyId)
+		s.bySubjectKeyId[keyId] = append(s.bySubjectKeyId[keyId], n)
+	}
+	name := string(cert.RawSubject)
+	s.byName[name] = append(s.byName[name], n)
 }
 
+// SMCertificate is a certificate whose chain may contain SM2 signatures
+type SMCertificate x509.Certificate
+
 type UnknownAuthorityError struct {
 	cert *x509.Certificate
 	// hintErr contains an error that may be helpful in determining why an
@@ -127,9 +107,8 @@ func (e UnknownAuthorityError) Error() string {
 
 var errNotParsed = errors.New("x509: missing ASN.1 contents; use ParseCertificate")
 
-func (smc *SMCertificate) SMVerify(optsx x509.VerifyOptions) (chains [][]*x509.Certificate, err error) {
-	c := (*x509.Certificate)(unsafe.Pointer(smc))
-	opts := *(*SMVerifyOptions)(unsafe.Pointer(&optsx))
+func (smc *SMCertificate) SMVerify(opts SMVerifyOptions) (chains [][]*x509.Certificate, err error) {
+	c := (*x509.Certificate)(smc)
 
 	if len(c.Raw) == 0 {
 		return nil, errNotParsed
@@ -178,6 +157,11 @@ func (smc *SMCertificate) SMVerify(optsx x509.VerifyOptions) (chains [][]*x509.C
 		}
 	}
 
+	candidateChains, err = FilterRevokedChains(candidateChains, opts.CRLs)
+	if err != nil {
+		return nil, err
+	}
+
 	keyUsages := opts.KeyUsages
 	if len(keyUsages) == 0 {
 		keyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
@@ -256,7 +240,7 @@ NextCert:
 }
 
 func (smc *SMCertificate) isValid(certType int, currentChain []*x509.Certificate, opts *SMVerifyOptions) error {
-	c := (*x509.Certificate)(unsafe.Pointer(smc))
+	c := (*x509.Certificate)(smc)
 	now := opts.CurrentTime
 	if now.IsZero() {
 		now = time.Now()
@@ -297,7 +281,7 @@ func (smc *SMCertificate) isValid(certType int, currentChain []*x509.Certificate
 }
 
 func (smc *SMCertificate) buildChains(cache map[int][][]*x509.Certificate, currentChain []*x509.Certificate, opts *SMVerifyOptions) (chains [][]*x509.Certificate, err error) {
-	c := (*x509.Certificate)(unsafe.Pointer(smc))
+	c := (*x509.Certificate)(smc)
 
 	possibleRoots, failedRoot, rootErr := opts.Roots.findVerifiedParents(c)
 	for _, rootNum := range possibleRoots {
@@ -315,7 +299,7 @@ nextIntermediate:
 	for _, intermediateNum := range possibleIntermediates {
 		intermediate := opts.Intermediates.certs[intermediateNum]
 		for _, cert := range currentChain {
-			if cert == (*x509.Certificate)(unsafe.Pointer(intermediate)) {
+			if cert == (*x509.Certificate)(intermediate) {
 				continue nextIntermediate
 			}
 		}
@@ -349,7 +333,7 @@ nextIntermediate:
 }
 
 func (s *SMCertPool) findVerifiedParents(cert *x509.Certificate) (parents []int, errCert *x509.Certificate, err error) {
-	smcert := (*SMCertificate)(unsafe.Pointer(cert))
+	smcert := (*SMCertificate)(cert)
 
 	if s == nil {
 		return
@@ -367,7 +351,7 @@ func (s *SMCertPool) findVerifiedParents(cert *x509.Certificate) (parents []int,
 		if err = smcert.CheckSignatureFrom(s.certs[c]); err == nil {
 			parents = append(parents, c)
 		} else {
-			errCert = (*x509.Certificate)(unsafe.Pointer(s.certs[c]))
+			errCert = (*x509.Certificate)(s.certs[c])
 		}
 	}
 
@@ -377,7 +361,7 @@ func (s *SMCertPool) findVerifiedParents(cert *x509.Certificate) (parents []int,
 func appendToFreshChain(chain []*x509.Certificate, cert *SMCertificate) []*x509.Certificate {
 	n := make([]*x509.Certificate, len(chain)+1)
 	copy(n, chain)
-	n[len(chain)] = (*x509.Certificate)(unsafe.Pointer(cert))
+	n[len(chain)] = (*x509.Certificate)(cert)
 	return n
 }
 
@@ -399,6 +383,13 @@ func (c *SMCertificate) CheckSignatureFrom(parent *SMCertificate) (err error) {
 }
 
 func (c *SMCertificate) CheckSignature(algo x509.SignatureAlgorithm, signed, signature []byte) (err error) {
+	if algo != sx509.SM2WithSM3 {
+		// a standard CA, which may also sign the certificates of an SM CA
+		if sx509.IsSMPublicKey(c.RawSubjectPublicKeyInfo) {
+			return x509.ErrUnsupportedAlgorithm
+		}
+		return (*x509.Certificate)(c).CheckSignature(algo, signed, signature)
+	}
 	return checkSignature(algo, signed, signature, c.PublicKey)
 }
 
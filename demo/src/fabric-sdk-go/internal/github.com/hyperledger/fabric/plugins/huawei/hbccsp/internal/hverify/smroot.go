@@ -1,10 +1,11 @@
 package hverify
 
 import (
-	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"sync"
-	"unsafe"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/sx509"
 )
 
 var (
@@ -29,12 +30,12 @@ var certFiles = []string{
 }
 
 func initSystemRoots() {
-	roots := x509.NewCertPool()
+	roots := NewSMCertPool()
 	for _, file := range certFiles {
 		data, err := ioutil.ReadFile(file)
 		if err == nil {
-			roots.AppendCertsFromPEM(data)
-			systemRoots = (*SMCertPool)(unsafe.Pointer(roots))
+			roots.appendCertsFromPEM(data)
+			systemRoots = roots
 			return
 		}
 	}
@@ -47,13 +48,37 @@ func initSystemRoots() {
 		rootsAdded := false
 		for _, fi := range fis {
 			data, err := ioutil.ReadFile(directory + "/" + fi.Name())
-			if err == nil && roots.AppendCertsFromPEM(data) {
+			if err == nil && roots.appendCertsFromPEM(data) {
 				rootsAdded = true
 			}
 		}
 		if rootsAdded {
-			systemRoots = (*SMCertPool)(unsafe.Pointer(roots))
+			systemRoots = roots
 			return
 		}
 	}
 }
+
+// appendCertsFromPEM adds the SM and standard certificates of pemCerts to the pool
+func (s *SMCertPool) appendCertsFromPEM(pemCerts []byte) (ok bool) {
+	for len(pemCerts) > 0 {
+		var block *pem.Block
+		block, pemCerts = pem.Decode(pemCerts)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" || len(block.Headers) != 0 {
+			continue
+		}
+
+		cert, err := sx509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		s.AddCert(cert)
+		ok = true
+	}
+
+	return
+}
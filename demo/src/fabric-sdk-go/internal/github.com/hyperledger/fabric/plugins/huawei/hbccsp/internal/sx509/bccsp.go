@@ -10,11 +10,23 @@ import (
 
 func ParseCertificate(asn1Data []byte) (*x509.Certificate, error) {
 	if val, err := x509.ParseCertificate(asn1Data); err == nil {
+		if val.SignatureAlgorithm == x509.UnknownSignatureAlgorithm {
+			// a standard certificate may still be issued by an SM CA
+			val.SignatureAlgorithm = parseSignatureAlgorithm(val.Raw)
+		}
 		return val, nil
 	}
 	return parseSMCertificate(asn1Data)
 }
 
+func parseSignatureAlgorithm(asn1Data []byte) x509.SignatureAlgorithm {
+	var cert certificate
+	if _, err := asn1.Unmarshal(asn1Data, &cert); err != nil {
+		return x509.UnknownSignatureAlgorithm
+	}
+	return getSignatureAlgorithmFromOID(cert.SignatureAlgorithm.Algorithm)
+}
+
 func parseSMCertificate(asn1Data []byte) (*x509.Certificate, error) {
 	var cert certificate
 	rest, err := asn1.Unmarshal(asn1Data, &cert)
@@ -0,0 +1,18 @@
+package sx509
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+)
+
+// ParseCRL parses a CRL from the given bytes, which are PEM or DER encoded.
+// The CRL may be signed with a standard or an SM signature algorithm.
+func ParseCRL(crlBytes []byte) (*pkix.CertificateList, error) {
+	return x509.ParseCRL(crlBytes)
+}
+
+// CRLSignatureAlgorithm returns the algorithm of the signature over crl, SM2WithSM3 for
+// SM2 signatures with SM3
+func CRLSignatureAlgorithm(crl *pkix.CertificateList) x509.SignatureAlgorithm {
+	return getSignatureAlgorithmFromOID(crl.SignatureAlgorithm.Algorithm)
+}
@@ -298,6 +298,15 @@ func isSMkey(pki publicKeyInfo) bool {
 	return true
 }
 
+// IsSMPublicKey reports whether the DER encoded SubjectPublicKeyInfo holds a point of the SM2 curve
+func IsSMPublicKey(derBytes []byte) bool {
+	var pki publicKeyInfo
+	if rest, err := asn1.Unmarshal(derBytes, &pki); err != nil || len(rest) != 0 {
+		return false
+	}
+	return isSMkey(pki)
+}
+
 func ParsePKIXPublicKey(derBytes []byte) (pub interface{}, err error) {
 	var pki publicKeyInfo
 	rest, err := asn1.Unmarshal(derBytes, &pki)
@@ -0,0 +1,55 @@
+package smpkcs11
+
+import (
+	"encoding/asn1"
+
+	"github.com/miekg/pkcs11"
+)
+
+// oidNamedCurveSM2 is the OID of the SM2 curve (GM/T 0006), used as CKA_EC_PARAMS of SM2 keys
+var oidNamedCurveSM2 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+
+// SMPKCS11Opts contains options for the SMPKCS11Factory
+type SMPKCS11Opts struct {
+	// Default algorithms when not specified (Deprecated?)
+	SecLevel   int    `mapstructure:"security" json:"security"`
+	HashFamily string `mapstructure:"hash" json:"hash"`
+	// HashMode of the software fallback, see hsm.LegacyHashMode and hsm.StrictHashMode
+	HashMode string `mapstructure:"hashmode,omitempty" json:"hashmode,omitempty"`
+
+	// PKCS11 options
+	Library    string `mapstructure:"library" json:"library"`
+	Label      string `mapstructure:"label" json:"label"`
+	Pin        string `mapstructure:"pin" json:"pin"`
+	SoftVerify bool   `mapstructure:"softwareverify,omitempty" json:"softwareverify,omitempty"`
+
+	// SM2 is not part of the PKCS#11 standard, tokens expose it with vendor defined values.
+	// KeyType is the CKA_KEY_TYPE of SM2 keys, CKK_EC (with the SM2 curve as CKA_EC_PARAMS) if zero.
+	KeyType uint `mapstructure:"keytype,omitempty" json:"keytype,omitempty"`
+	// KeyPairGenMechanism generates SM2 key pairs, CKM_EC_KEY_PAIR_GEN if zero.
+	KeyPairGenMechanism uint `mapstructure:"keypairgenmechanism,omitempty" json:"keypairgenmechanism,omitempty"`
+	// SignMechanism signs messages with SM3-with-SM2 (the token computes Z and the SM3 digest).
+	// It has no default and is required.
+	SignMechanism uint `mapstructure:"signmechanism" json:"signmechanism"`
+}
+
+type config struct {
+	keyType             uint
+	keyPairGenMechanism uint
+	signMechanism       uint
+}
+
+func newConfig(opts SMPKCS11Opts) *config {
+	conf := &config{
+		keyType:             opts.KeyType,
+		keyPairGenMechanism: opts.KeyPairGenMechanism,
+		signMechanism:       opts.SignMechanism,
+	}
+	if conf.keyType == 0 {
+		conf.keyType = pkcs11.CKK_EC
+	}
+	if conf.keyPairGenMechanism == 0 {
+		conf.keyPairGenMechanism = pkcs11.CKM_EC_KEY_PAIR_GEN
+	}
+	return conf
+}
@@ -0,0 +1,172 @@
+package smpkcs11
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+	flogging "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkpatch/logbridge"
+	sdkp11 "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/common/pkcs11"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("bccsp_smp11")
+
+// token is the subset of the PKCS#11 context handle used by the BCCSP
+type token interface {
+	GetSession() pkcs11.SessionHandle
+	ReturnSession(session pkcs11.SessionHandle)
+	CloseSession(session pkcs11.SessionHandle) error
+	FindObjectsInit(session pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
+	FindObjects(session pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
+	FindObjectsFinal(session pkcs11.SessionHandle) error
+	GetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) ([]*pkcs11.Attribute, error)
+	SetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) error
+	GenerateKeyPair(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error)
+	SignInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Sign(session pkcs11.SessionHandle, message []byte) ([]byte, error)
+	VerifyInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, key pkcs11.ObjectHandle) error
+	Verify(session pkcs11.SessionHandle, data []byte, signature []byte) error
+}
+
+// New returns a new instance of the BCCSP keeping SM2 keys on a PKCS#11 token.
+// Other keys and operations are handled by the software SM BCCSP, using keyStore.
+func New(opts SMPKCS11Opts, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	// Check KeyStore
+	if keyStore == nil {
+		return nil, errors.New("Invalid bccsp.KeyStore instance. It must be different from nil")
+	}
+
+	//Load PKCS11 context handle
+	pkcs11Ctx, err := sdkp11.LoadContextAndLogin(opts.Library, opts.Pin, opts.Label)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing PKCS11 context")
+	}
+	return newWithToken(opts, keyStore, pkcs11Ctx)
+}
+
+func newWithToken(opts SMPKCS11Opts, keyStore bccsp.KeyStore, tk token) (bccsp.BCCSP, error) {
+	if opts.SignMechanism == 0 {
+		return nil, errors.New("The SM2 sign mechanism of the token must be configured")
+	}
+
+	smCSP, err := hsm.NewWithHashMode(opts.SecLevel, opts.HashFamily, opts.HashMode, keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing fallback SM BCCSP")
+	}
+
+	return &impl{BCCSP: smCSP, conf: newConfig(opts), softVerify: opts.SoftVerify, token: tk}, nil
+}
+
+type impl struct {
+	bccsp.BCCSP
+
+	conf *config
+
+	token      token
+	softVerify bool
+}
+
+// NetworkHashOpts returns the network hash opts of the software fallback
+func (csp *impl) NetworkHashOpts() bccsp.HashOpts {
+	if p, ok := csp.BCCSP.(interface{ NetworkHashOpts() bccsp.HashOpts }); ok {
+		return p.NetworkHashOpts()
+	}
+	return nil
+}
+
+// KeyGen generates a key using opts. SM2 keys are generated on the token.
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
+	// Validate arguments
+	if opts == nil {
+		return nil, errors.New("Invalid Opts parameter. It must not be nil")
+	}
+
+	// Parse algorithm
+	switch opts.(type) {
+	case *hsm.SM2KeyGenOpts, *bccsp.ECDSAKeyGenOpts, *bccsp.ECDSAP256KeyGenOpts:
+		ski, pub, err := csp.generateSM2Key(opts.Ephemeral())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating SM2 key")
+		}
+		k = &sm2PrivateKey{ski, sm2PublicKey{ski, pub}}
+
+	default:
+		return csp.BCCSP.KeyGen(opts)
+	}
+
+	return k, nil
+}
+
+// GetKey returns the key this CSP associates to
+// the Subject Key Identifier ski.
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	pubKey, isPriv, err := csp.getSM2Key(ski)
+	if err == nil {
+		if isPriv {
+			return &sm2PrivateKey{ski, sm2PublicKey{ski, pubKey}}, nil
+		}
+		return &sm2PublicKey{ski, pubKey}, nil
+	}
+	return csp.BCCSP.GetKey(ski)
+}
+
+// Sign signs msg using key k.
+// SM2 keys of the token sign with SM3-with-SM2: like the software SM BCCSP, msg is
+// the message itself, not its digest.
+func (csp *impl) Sign(k bccsp.Key, msg []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(msg) == 0 {
+		return nil, errors.New("Invalid message. Cannot be empty")
+	}
+
+	// Check key type
+	switch key := k.(type) {
+	case *sm2PrivateKey:
+		r, s, err := csp.signP11SM2(key.ski, msg)
+		if err != nil {
+			return nil, err
+		}
+		return hsm.MarshalECDSASignature(r, s)
+	default:
+		return csp.BCCSP.Sign(key, msg, opts)
+	}
+}
+
+// Verify verifies signature against key k and msg
+func (csp *impl) Verify(k bccsp.Key, signature, msg []byte, opts bccsp.SignerOpts) (bool, error) {
+	// Validate arguments
+	if k == nil {
+		return false, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(signature) == 0 {
+		return false, errors.New("Invalid signature. Cannot be empty")
+	}
+	if len(msg) == 0 {
+		return false, errors.New("Invalid message. Cannot be empty")
+	}
+
+	// Check key type
+	switch key := k.(type) {
+	case *sm2PrivateKey:
+		return csp.verifySM2(key.pub, signature, msg, opts)
+	case *sm2PublicKey:
+		return csp.verifySM2(*key, signature, msg, opts)
+	default:
+		return csp.BCCSP.Verify(k, signature, msg, opts)
+	}
+}
+
+func (csp *impl) verifySM2(k sm2PublicKey, signature, msg []byte, opts bccsp.SignerOpts) (bool, error) {
+	r, s, err := unmarshalSM2Signature(signature)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed unmarshalling signature")
+	}
+
+	if csp.softVerify {
+		return hsm.VerifySM2(k.pub, signature, msg, opts)
+	}
+	return csp.verifyP11SM2(k.ski, msg, r, s)
+}
@@ -0,0 +1,348 @@
+package smpkcs11
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+// vendor defined SM2 mechanisms of the mock token
+const (
+	mockKeyPairGenMechanism = pkcs11.CKM_VENDOR_DEFINED + 0x101
+	mockSignMechanism       = pkcs11.CKM_VENDOR_DEFINED + 0x102
+)
+
+func TestNew(t *testing.T) {
+	_, err := newWithToken(SMPKCS11Opts{SecLevel: 256, HashFamily: "SM3"}, hsm.NewDummyKeyStore(), newMockToken())
+	assert.Error(t, err, "the sign mechanism is required")
+
+	_, err = New(SMPKCS11Opts{SecLevel: 256, HashFamily: "SM3", SignMechanism: mockSignMechanism}, nil)
+	assert.Error(t, err, "the keystore is required")
+}
+
+func TestKeyGenSignVerify(t *testing.T) {
+	tk := newMockToken()
+	csp := newTestCSP(t, tk, false)
+
+	k, err := csp.KeyGen(&hsm.SM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.Equal(t, uint(mockKeyPairGenMechanism), tk.keyPairGenMechanism)
+
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	pub := pk.(*sm2PublicKey).pub
+	assert.Equal(t, sm3.Sm3Sum(elliptic.Marshal(pub.Curve, pub.X, pub.Y)), k.SKI(), "SKI is computed like the one of software SM2 keys")
+	_, err = pk.Bytes()
+	assert.NoError(t, err)
+
+	msg := []byte("message signed on the token")
+	signature, err := csp.Sign(k, msg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(mockSignMechanism), tk.signMechanism)
+
+	// the signature is a standard SM3-with-SM2 signature
+	valid, err := hsm.VerifySM2(pub, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// verification on the token
+	valid, err = csp.Verify(pk, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(k, signature, []byte("another message"), nil)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// software verification
+	csp.(*impl).softVerify = true
+	valid, err = csp.Verify(pk, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(pk, signature, []byte("another message"), nil)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = csp.Verify(pk, []byte("not a signature"), msg, nil)
+	assert.Error(t, err)
+}
+
+func TestGetKey(t *testing.T) {
+	tk := newMockToken()
+	csp := newTestCSP(t, tk, true)
+
+	k, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	// by CKA_ID
+	key, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+	assert.Equal(t, k.SKI(), key.SKI())
+
+	// by CKA_LABEL, for keys provisioned outside of the SDK
+	ski := tk.provision(t, false)
+	key, err = csp.GetKey(ski)
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+
+	msg := []byte("message")
+	signature, err := csp.Sign(key, msg, nil)
+	require.NoError(t, err)
+	pk, err := key.PublicKey()
+	require.NoError(t, err)
+	valid, err := csp.Verify(pk, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// public keys only
+	ski = tk.provision(t, true)
+	key, err = csp.GetKey(ski)
+	require.NoError(t, err)
+	assert.False(t, key.Private())
+
+	// unknown keys are looked up in the software keystore
+	_, err = csp.GetKey([]byte("unknown"))
+	assert.Error(t, err)
+}
+
+func TestFallback(t *testing.T) {
+	csp := newTestCSP(t, newMockToken(), true)
+
+	assert.NotNil(t, csp.(*impl).NetworkHashOpts())
+
+	digest, err := csp.Hash([]byte("message"), &bccsp.SHAOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, sm3.Sm3Sum([]byte("message")), digest)
+
+	// software keys are still handled by the software BCCSP
+	k, err := csp.KeyGen(&hsm.SM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, k.Symmetric())
+}
+
+func newTestCSP(t *testing.T, tk *mockToken, softVerify bool) bccsp.BCCSP {
+	opts := SMPKCS11Opts{
+		SecLevel:            256,
+		HashFamily:          "SM3",
+		SoftVerify:          softVerify,
+		KeyPairGenMechanism: mockKeyPairGenMechanism,
+		SignMechanism:       mockSignMechanism,
+	}
+	csp, err := newWithToken(opts, hsm.NewDummyKeyStore(), tk)
+	require.NoError(t, err)
+	return csp
+}
+
+// mockToken is an in-memory token exposing SM2 like SoftHSM exposes EC keys:
+// CKA_EC_POINT is DER encoded and signatures are r||s
+type mockToken struct {
+	mutex   sync.Mutex
+	objects map[pkcs11.ObjectHandle][]*pkcs11.Attribute
+	keys    map[pkcs11.ObjectHandle]*sm2.PrivateKey
+	next    pkcs11.ObjectHandle
+
+	found []pkcs11.ObjectHandle
+
+	keyPairGenMechanism uint
+	signMechanism       uint
+	signKey             pkcs11.ObjectHandle
+	verifyKey           pkcs11.ObjectHandle
+}
+
+func newMockToken() *mockToken {
+	return &mockToken{
+		objects: make(map[pkcs11.ObjectHandle][]*pkcs11.Attribute),
+		keys:    make(map[pkcs11.ObjectHandle]*sm2.PrivateKey),
+	}
+}
+
+// provision adds a key pair labelled with the hex encoding of its SKI and returns the SKI
+func (m *mockToken) provision(t *testing.T, publicOnly bool) []byte {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+	ski := sm3.Sm3Sum(elliptic.Marshal(key.Curve, key.X, key.Y))
+	label := hex.EncodeToString(ski)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	pub, prv := m.addKeyPair(key,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY), pkcs11.NewAttribute(pkcs11.CKA_LABEL, label)},
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY), pkcs11.NewAttribute(pkcs11.CKA_LABEL, label)})
+	if publicOnly {
+		delete(m.objects, prv)
+		delete(m.keys, prv)
+	}
+	require.NotZero(t, pub)
+	return ski
+}
+
+func (m *mockToken) addKeyPair(key *sm2.PrivateKey, public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle) {
+	point, _ := asn1.Marshal(elliptic.Marshal(key.Curve, key.X, key.Y))
+
+	m.next++
+	pub := m.next
+	m.objects[pub] = append(append([]*pkcs11.Attribute{}, public...), pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, point))
+	m.keys[pub] = key
+
+	m.next++
+	prv := m.next
+	m.objects[prv] = append([]*pkcs11.Attribute{}, private...)
+	m.keys[prv] = key
+	return pub, prv
+}
+
+func (m *mockToken) GetSession() pkcs11.SessionHandle {
+	return 1
+}
+
+func (m *mockToken) ReturnSession(session pkcs11.SessionHandle) {}
+
+func (m *mockToken) CloseSession(session pkcs11.SessionHandle) error {
+	return nil
+}
+
+func (m *mockToken) FindObjectsInit(session pkcs11.SessionHandle, temp []*pkcs11.Attribute) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.found = nil
+	for handle, attrs := range m.objects {
+		if matches(attrs, temp) {
+			m.found = append(m.found, handle)
+		}
+	}
+	return nil
+}
+
+func (m *mockToken) FindObjects(session pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.found) > max {
+		return m.found[:max], false, nil
+	}
+	return m.found, false, nil
+}
+
+func (m *mockToken) FindObjectsFinal(session pkcs11.SessionHandle) error {
+	return nil
+}
+
+func (m *mockToken) GetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) ([]*pkcs11.Attribute, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var values []*pkcs11.Attribute
+	for _, a := range attrs {
+		v := attribute(m.objects[objectHandle], a.Type)
+		if v == nil {
+			return nil, pkcs11.Error(pkcs11.CKR_ATTRIBUTE_TYPE_INVALID)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func (m *mockToken) SetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, a := range attrs {
+		if v := attribute(m.objects[objectHandle], a.Type); v != nil {
+			v.Value = a.Value
+		} else {
+			m.objects[objectHandle] = append(m.objects[objectHandle], a)
+		}
+	}
+	return nil
+}
+
+func (m *mockToken) GenerateKeyPair(session pkcs11.SessionHandle, mech []*pkcs11.Mechanism, public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error) {
+	key, err := sm2.GenerateKey()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.keyPairGenMechanism = mech[0].Mechanism
+	pub, prv := m.addKeyPair(key, public, private)
+	return pub, prv, nil
+}
+
+func (m *mockToken) SignInit(session pkcs11.SessionHandle, mech []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.signMechanism = mech[0].Mechanism
+	m.signKey = o
+	return nil
+}
+
+func (m *mockToken) Sign(session pkcs11.SessionHandle, message []byte) ([]byte, error) {
+	m.mutex.Lock()
+	key := m.keys[m.signKey]
+	m.mutex.Unlock()
+
+	r, s, err := sm2.Sm2Sign(key, message, []byte("1234567812345678"))
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 2*sm2ByteSize)
+	copy(sig[sm2ByteSize-len(r.Bytes()):sm2ByteSize], r.Bytes())
+	copy(sig[2*sm2ByteSize-len(s.Bytes()):], s.Bytes())
+	return sig, nil
+}
+
+func (m *mockToken) VerifyInit(session pkcs11.SessionHandle, mech []*pkcs11.Mechanism, key pkcs11.ObjectHandle) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.verifyKey = key
+	return nil
+}
+
+func (m *mockToken) Verify(session pkcs11.SessionHandle, data []byte, signature []byte) error {
+	m.mutex.Lock()
+	key := m.keys[m.verifyKey]
+	m.mutex.Unlock()
+
+	r := new(big.Int).SetBytes(signature[:sm2ByteSize])
+	s := new(big.Int).SetBytes(signature[sm2ByteSize:])
+	if !sm2.Sm2Verify(&key.PublicKey, data, []byte("1234567812345678"), r, s) {
+		return pkcs11.Error(pkcs11.CKR_SIGNATURE_INVALID)
+	}
+	return nil
+}
+
+func matches(attrs, template []*pkcs11.Attribute) bool {
+	for _, t := range template {
+		a := attribute(attrs, t.Type)
+		if a == nil || !bytes.Equal(a.Value, t.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func attribute(attrs []*pkcs11.Attribute, typ uint) *pkcs11.Attribute {
+	for _, a := range attrs {
+		if a.Type == typ {
+			return a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package smpkcs11
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/internal/hsm"
+	"github.com/miekg/pkcs11"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+// sm2ByteSize is the size of the coordinates of SM2 points and of the r and s signature values
+const sm2ByteSize = 32
+
+var regex = regexp.MustCompile(".*0xB.:\\sCKR.+")
+
+func (csp *impl) handleSessionReturn(err error, session pkcs11.SessionHandle) {
+	if err != nil {
+		if regex.MatchString(err.Error()) {
+			logger.Debugf("PKCS11 session invalidated, closing session: %v, not returning to pool", err)
+			csp.token.CloseSession(session) //nolint
+			return
+		}
+	}
+
+	csp.token.ReturnSession(session)
+}
+
+// Look for an SM2 key by SKI, stored in CKA_ID, or by its hex encoding, stored in CKA_LABEL
+func (csp *impl) getSM2Key(ski []byte) (pubKey *ecdsa.PublicKey, isPriv bool, err error) {
+	session := csp.token.GetSession()
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	isPriv = true
+	if _, err = csp.findKey(session, ski, pkcs11.CKO_PRIVATE_KEY); err != nil {
+		isPriv = false
+		logger.Debugf("Private key not found [%s] for SKI [%s], looking for Public key", err, hex.EncodeToString(ski))
+	}
+
+	publicKey, err := csp.findKey(session, ski, pkcs11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
+	}
+
+	ecpt, err := csp.ecPoint(session, publicKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("Public key not found [%s] for SKI [%s]", err, hex.EncodeToString(ski))
+	}
+
+	pubKey, err = unmarshalSM2Point(ecpt)
+	if err != nil {
+		return nil, false, err
+	}
+	return pubKey, isPriv, nil
+}
+
+// findKey returns the key of class keyClass whose CKA_ID is ski or, for keys provisioned
+// outside of the SDK, whose CKA_LABEL is the hex encoding of ski
+func (csp *impl) findKey(session pkcs11.SessionHandle, ski []byte, keyClass uint) (pkcs11.ObjectHandle, error) {
+	templates := [][]*pkcs11.Attribute{
+		{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, keyClass),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		},
+		{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, keyClass),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+		},
+	}
+
+	for _, template := range templates {
+		handle, found, err := csp.findObject(session, template)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			return handle, nil
+		}
+	}
+	return 0, fmt.Errorf("Key not found [%s]", hex.EncodeToString(ski))
+}
+
+func (csp *impl) findObject(session pkcs11.SessionHandle, template []*pkcs11.Attribute) (pkcs11.ObjectHandle, bool, error) {
+	if err := csp.token.FindObjectsInit(session, template); err != nil {
+		return 0, false, err
+	}
+	handles, _, err := csp.token.FindObjects(session, 1)
+	if err != nil {
+		csp.token.FindObjectsFinal(session) //nolint
+		return 0, false, err
+	}
+	if err := csp.token.FindObjectsFinal(session); err != nil {
+		return 0, false, err
+	}
+
+	if len(handles) == 0 {
+		return 0, false, nil
+	}
+	return handles[0], true, nil
+}
+
+func (csp *impl) generateSM2Key(ephemeral bool) (ski []byte, pubKey *ecdsa.PublicKey, err error) {
+	session := csp.token.GetSession()
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	id, err := tempID()
+	if err != nil {
+		return nil, nil, err
+	}
+	publabel := "BCPUB" + id
+	prvlabel := "BCPRV" + id
+
+	marshaledOID, err := asn1.Marshal(oidNamedCurveSM2)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not marshal OID [%s]", err.Error())
+	}
+
+	pubkeyT := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, csp.conf.keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, marshaledOID),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, publabel),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, publabel),
+	}
+
+	prvkeyT := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, csp.conf.keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, prvlabel),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, prvlabel),
+
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+	}
+
+	pub, prv, err := csp.token.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(csp.conf.keyPairGenMechanism, nil)},
+		pubkeyT, prvkeyT)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: keypair generate failed [%s]", err)
+	}
+
+	ecpt, err := csp.ecPoint(session, pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error querying EC-point: [%s]", err)
+	}
+	pubKey, err = unmarshalSM2Point(ecpt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the SKI is computed like the one of software SM2 keys
+	ski = sm3.Sm3Sum(elliptic.Marshal(pubKey.Curve, pubKey.X, pubKey.Y))
+
+	// set CKA_ID of the both keys to SKI(public key) and CKA_LABEL to hex string of SKI
+	setskiT := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+	}
+
+	logger.Infof("Generated new P11 SM2 key, SKI %x\n", ski)
+	err = csp.token.SetAttributeValue(session, pub, setskiT)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: set-ID-to-SKI[public] failed [%s]", err)
+	}
+
+	err = csp.token.SetAttributeValue(session, prv, setskiT)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: set-ID-to-SKI[private] failed [%s]", err)
+	}
+
+	return ski, pubKey, nil
+}
+
+func (csp *impl) signP11SM2(ski []byte, msg []byte) (R, S *big.Int, err error) {
+	session := csp.token.GetSession()
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	privateKey, err := csp.findKey(session, ski, pkcs11.CKO_PRIVATE_KEY)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Private key not found [%s]", err)
+	}
+
+	err = csp.token.SignInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.conf.signMechanism, nil)}, privateKey)
+	if err != nil {
+		logger.Errorf("Sign-initialize failed [session: %d]: cause: %s", session, err)
+		return nil, nil, fmt.Errorf("Sign-initialize failed [%s]", err)
+	}
+
+	sig, err := csp.token.Sign(session, msg)
+	if err != nil {
+		logger.Errorf("P11: sign failed [session: %d]: cause: %s", session, err)
+		return nil, nil, fmt.Errorf("P11: sign failed [%s]", err)
+	}
+	if len(sig) != 2*sm2ByteSize {
+		return nil, nil, fmt.Errorf("P11: unexpected signature length [%d]", len(sig))
+	}
+
+	R = new(big.Int).SetBytes(sig[:sm2ByteSize])
+	S = new(big.Int).SetBytes(sig[sm2ByteSize:])
+	return R, S, nil
+}
+
+func (csp *impl) verifyP11SM2(ski []byte, msg []byte, R, S *big.Int) (valid bool, err error) {
+	session := csp.token.GetSession()
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	publicKey, err := csp.findKey(session, ski, pkcs11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return false, fmt.Errorf("Public key not found [%s]", err)
+	}
+
+	r := R.Bytes()
+	s := S.Bytes()
+	if len(r) > sm2ByteSize || len(s) > sm2ByteSize {
+		return false, nil
+	}
+
+	// Pad front of R and S with Zeroes if needed
+	sig := make([]byte, 2*sm2ByteSize)
+	copy(sig[sm2ByteSize-len(r):sm2ByteSize], r)
+	copy(sig[2*sm2ByteSize-len(s):], s)
+
+	err = csp.token.VerifyInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.conf.signMechanism, nil)}, publicKey)
+	if err != nil {
+		return false, fmt.Errorf("PKCS11: Verify-initialize [%s]", err)
+	}
+	err = csp.token.Verify(session, msg, sig)
+	if err == pkcs11.Error(pkcs11.CKR_SIGNATURE_INVALID) {
+		err = nil
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("PKCS11: Verify failed [%s]", err)
+	}
+
+	return true, nil
+}
+
+// ecPoint returns the uncompressed EC point of a public key. Like SoftHSM, some tokens
+// return the DER encoding of the point (an OCTET STRING) instead of the point itself.
+func (csp *impl) ecPoint(session pkcs11.SessionHandle, key pkcs11.ObjectHandle) ([]byte, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	}
+
+	attr, err := csp.token.GetAttributeValue(session, key, template)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS11: get(EC point) [%s]", err)
+	}
+
+	for _, a := range attr {
+		if a.Type != pkcs11.CKA_EC_POINT || len(a.Value) == 0 {
+			continue
+		}
+		logger.Debugf("EC point: attr type %d/0x%x, len %d\n%s\n", a.Type, a.Type, len(a.Value), hex.Dump(a.Value))
+
+		var point []byte
+		if rest, err := asn1.Unmarshal(a.Value, &point); err == nil && len(rest) == 0 && len(point) == 1+2*sm2ByteSize {
+			logger.Debugf("Detected DER encoded EC point, unwrapping it")
+			return point, nil
+		}
+		return a.Value, nil
+	}
+	return nil, fmt.Errorf("CKA_EC_POINT not found, perhaps not an EC Key?")
+}
+
+func unmarshalSM2Point(ecpt []byte) (*ecdsa.PublicKey, error) {
+	curve := sm2.P256Sm2()
+	x, y := elliptic.Unmarshal(curve, ecpt)
+	if x == nil {
+		return nil, fmt.Errorf("Failed Unmarshaling SM2 Public Key")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func unmarshalSM2Signature(signature []byte) (R, S *big.Int, err error) {
+	sig := &hsm.ECDSASignature{}
+	rest, err := asn1.Unmarshal(signature, sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, fmt.Errorf("invalid signature, it contains trailing data")
+	}
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("invalid signature, R and S must be positive")
+	}
+	return sig.R, sig.S, nil
+}
+
+// tempID returns a random identifier for the labels of keys being generated, until their SKI is known
+func tempID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("Failed generating key identifier [%s]", err)
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -52,6 +52,16 @@ func GetDefault() core.CryptoSuite {
 	return cryptosuite.GetDefault()
 }
 
+//PrepareSignatureDigest is a bridge for cryptosuite.PrepareSignatureDigest
+func PrepareSignatureDigest(csp core.CryptoSuite, msg, digest []byte) []byte {
+	return cryptosuite.PrepareSignatureDigest(csp, msg, digest)
+}
+
+//PrepareKeySignatureDigest is a bridge for cryptosuite.PrepareKeySignatureDigest
+func PrepareKeySignatureDigest(csp core.CryptoSuite, key core.Key, msg, digest []byte) []byte {
+	return cryptosuite.PrepareKeySignatureDigest(csp, key, msg, digest)
+}
+
 //SignatureToLowS is a bridge for bccsp utils.SignatureToLowS()
 func SignatureToLowS(k *ecdsa.PublicKey, signature []byte) ([]byte, error) {
 	return utils.SignatureToLowS(k, signature)
@@ -5,6 +5,10 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hutil"
 )
 
+// SetUserPassword sets the password of the encrypted private keys
+//
+// Deprecated: the password is shared by all the SDK instances of the process,
+// use fabsdk.WithSecretProvider instead.
 func SetUserPassword(password string) error {
 	hutil.UserPassword = password
 	return nil
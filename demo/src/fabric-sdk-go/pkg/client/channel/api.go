@@ -63,6 +63,11 @@ type Response struct {
 	Payload          []byte
 }
 
+// RWSets returns the decoded read/write sets of the endorsement
+func (r Response) RWSets() ([]*invoke.NsRWSet, error) {
+	return invoke.Response(r).RWSets()
+}
+
 //WithTargets allows overriding of the target peers for the request
 func WithTargets(targets ...fab.Peer) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -43,11 +43,23 @@ type Client struct {
 	eventService fab.EventService
 	greylist     *greylist.Filter
 	metrics      *metrics.ClientMetrics
+	tracker      *invoke.ConflictTracker
 }
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
+// WithConflictTracker sets a conflict tracker which detects in-flight transactions that read or write the same keys.
+// Depending on the mode of the tracker, Execute either waits for conflicting transactions to complete before
+// endorsing again or re-endorses transactions that were invalidated with a read conflict.
+// The same tracker may be shared by multiple channel clients.
+func WithConflictTracker(tracker *invoke.ConflictTracker) ClientOption {
+	return func(cc *Client) error {
+		cc.tracker = tracker
+		return nil
+	}
+}
+
 // New returns a Client instance. Channel client can query chaincode, execute chaincode and register/unregister for chaincode events on specific channel.
 func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
 
@@ -113,6 +125,14 @@ func (cc *Client) Execute(request Request, options ...RequestOption) (Response,
 	return callExecute(cc, request, options...)
 }
 
+// executeHandler returns the handler used by Execute
+func (cc *Client) executeHandler() invoke.Handler {
+	if cc.tracker != nil {
+		return invoke.NewExecuteWithConflictTrackingHandler(cc.tracker)
+	}
+	return invoke.NewExecuteHandler()
+}
+
 // addDefaultTargetFilter adds default target filter if target filter is not specified
 func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -253,6 +273,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	}
 
 	clientContext := &invoke.ClientContext{
+		ChannelID:    cc.context.ChannelID(),
 		Selection:    selection,
 		Discovery:    discovery,
 		Membership:   cc.membership,
@@ -289,12 +310,13 @@ func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestO
 //  Parameters:
 //  chaincodeID is the chaincode ID for which events are to be received
 //  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//  filter is an optional filter on the transaction that emitted the event (see package eventfilter)
 //
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
-func (cc *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+func (cc *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
 	// Register callback for CE
-	return cc.eventService.RegisterChaincodeEvent(chainCodeID, eventFilter)
+	return cc.eventService.RegisterChaincodeEvent(chainCodeID, eventFilter, filter...)
 }
 
 // UnregisterChaincodeEvent removes the given registration and closes the event channel.
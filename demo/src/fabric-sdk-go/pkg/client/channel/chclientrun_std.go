@@ -17,5 +17,5 @@ func callQuery(cc *Client, request Request, options ...RequestOption) (Response,
 }
 
 func callExecute(cc *Client, request Request, options ...RequestOption) (Response, error) {
-	return cc.InvokeHandler(invoke.NewExecuteHandler(), request, options...)
+	return cc.InvokeHandler(cc.executeHandler(), request, options...)
 }
@@ -70,6 +70,7 @@ type Handler interface {
 
 //ClientContext contains context parameters for handler execution
 type ClientContext struct {
+	ChannelID    string
 	CryptoSuite  core.CryptoSuite
 	Discovery    fab.DiscoveryService
 	Selection    fab.SelectionService
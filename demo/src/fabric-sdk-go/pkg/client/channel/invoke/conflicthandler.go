@@ -0,0 +1,280 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"sync"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// ConflictMode determines how the conflict tracker handles transactions that touch the same keys
+type ConflictMode int
+
+const (
+	// SerializeConflicts holds back a transaction whose read/write set conflicts with an in-flight transaction
+	// until the in-flight transaction completes. The transaction is then endorsed again so that it reads the
+	// updated state. Transactions that are invalidated with a read conflict are also re-endorsed.
+	SerializeConflicts ConflictMode = iota
+
+	// RetryConflicts submits transactions without waiting for conflicting in-flight transactions. If a transaction
+	// is invalidated with a read conflict then it's endorsed again (after the conflicting in-flight transactions
+	// have completed) and re-submitted.
+	RetryConflicts
+)
+
+// ConflictTracker keeps track of the keys read and written by in-flight transactions so that transactions
+// which touch the same keys may be detected before they're submitted to the orderer. Conflicts are only
+// detected between transactions submitted through the same tracker, so the tracker should be shared by all
+// channel clients that update the same keys. Range queries are not taken into account.
+type ConflictTracker struct {
+	mode     ConflictMode
+	attempts int
+	mutex    sync.Mutex
+	inFlight map[fab.TransactionID]*inFlightTxn
+}
+
+type inFlightTxn struct {
+	keys *keySet
+	done chan struct{}
+}
+
+// NewConflictTracker returns a new conflict tracker. Attempts is the maximum number of times that
+// a transaction is endorsed again due to a conflict. If attempts is 0 then retry.DefaultAttempts is used.
+func NewConflictTracker(mode ConflictMode, attempts int) *ConflictTracker {
+	if attempts <= 0 {
+		attempts = retry.DefaultAttempts
+	}
+
+	return &ConflictTracker{
+		mode:     mode,
+		attempts: attempts,
+		inFlight: make(map[fab.TransactionID]*inFlightTxn),
+	}
+}
+
+// InFlight returns the number of transactions that are currently being tracked
+func (t *ConflictTracker) InFlight() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return len(t.inFlight)
+}
+
+// register tracks the keys of the given transaction. If failOnConflict is true and the transaction
+// conflicts with in-flight transactions then the transaction is not registered and the completion
+// channels of the conflicting transactions are returned instead.
+func (t *ConflictTracker) register(txnID fab.TransactionID, keys *keySet, failOnConflict bool) ([]<-chan struct{}, func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if failOnConflict {
+		if conflicts := t.conflicts(txnID, keys); len(conflicts) > 0 {
+			return conflicts, nil
+		}
+	}
+
+	txn := &inFlightTxn{keys: keys, done: make(chan struct{})}
+	t.inFlight[txnID] = txn
+
+	return nil, func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+
+		delete(t.inFlight, txnID)
+		close(txn.done)
+	}
+}
+
+// waitForConflicts waits for all in-flight transactions that conflict with the given keys to complete
+func (t *ConflictTracker) waitForConflicts(ctx reqContext.Context, txnID fab.TransactionID, keys *keySet) error {
+	t.mutex.Lock()
+	conflicts := t.conflicts(txnID, keys)
+	t.mutex.Unlock()
+
+	return wait(ctx, conflicts)
+}
+
+// conflicts returns the completion channels of the in-flight transactions that conflict
+// with the given keys. The caller must hold the lock.
+func (t *ConflictTracker) conflicts(txnID fab.TransactionID, keys *keySet) []<-chan struct{} {
+	var conflicts []<-chan struct{}
+	for id, txn := range t.inFlight {
+		if id != txnID && txn.keys.conflictsWith(keys) {
+			conflicts = append(conflicts, txn.done)
+		}
+	}
+	return conflicts
+}
+
+func wait(ctx reqContext.Context, conflicts []<-chan struct{}) error {
+	for _, done := range conflicts {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				"request timed out or been cancelled while waiting for conflicting transactions", nil)
+		}
+	}
+	return nil
+}
+
+// keySet contains the keys that a transaction reads and writes. Keys are qualified by
+// namespace and, for private data, by collection (in which case the key hash is used).
+type keySet struct {
+	reads  map[string]struct{}
+	writes map[string]struct{}
+}
+
+func newKeySet(rwSets []*NsRWSet) *keySet {
+	keys := &keySet{
+		reads:  make(map[string]struct{}),
+		writes: make(map[string]struct{}),
+	}
+
+	for _, rwSet := range rwSets {
+		for _, r := range rwSet.Reads {
+			keys.reads[qualifiedKey(rwSet.Namespace, "", r.Key)] = struct{}{}
+		}
+		for _, w := range rwSet.Writes {
+			keys.writes[qualifiedKey(rwSet.Namespace, "", w.Key)] = struct{}{}
+		}
+		for _, coll := range rwSet.Collections {
+			for _, r := range coll.HashedReads {
+				keys.reads[qualifiedKey(rwSet.Namespace, coll.CollectionName, string(r.KeyHash))] = struct{}{}
+			}
+			for _, w := range coll.HashedWrites {
+				keys.writes[qualifiedKey(rwSet.Namespace, coll.CollectionName, string(w.KeyHash))] = struct{}{}
+			}
+		}
+	}
+
+	return keys
+}
+
+// conflictsWith returns true if either of the key sets writes a key that the other one reads or writes
+func (k *keySet) conflictsWith(other *keySet) bool {
+	return intersects(k.writes, other.reads) || intersects(k.writes, other.writes) || intersects(k.reads, other.writes)
+}
+
+func intersects(m1, m2 map[string]struct{}) bool {
+	if len(m1) > len(m2) {
+		m1, m2 = m2, m1
+	}
+	for k := range m1 {
+		if _, ok := m2[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifiedKey(ns, coll, key string) string {
+	return ns + "\x00" + coll + "\x00" + key
+}
+
+// ConflictTrackingHandler endorses and commits a transaction, using a conflict tracker to either serialise
+// transactions that touch the same keys or re-endorse transactions that were invalidated with a read conflict
+type ConflictTrackingHandler struct {
+	tracker   *ConflictTracker
+	endorser  Handler
+	committer Handler
+	next      Handler
+}
+
+// NewExecuteWithConflictTrackingHandler returns an execute handler that uses the given conflict tracker
+// to detect in-flight transactions that touch the same keys
+func NewExecuteWithConflictTrackingHandler(tracker *ConflictTracker, next ...Handler) *ConflictTrackingHandler {
+	return &ConflictTrackingHandler{
+		tracker: tracker,
+		endorser: NewSelectAndEndorseHandler(
+			NewEndorsementValidationHandler(
+				NewSignatureValidationHandler(),
+			),
+		),
+		committer: NewCommitHandler(),
+		next:      getNext(next),
+	}
+}
+
+// Handle endorses the transaction and commits it once it no longer conflicts with in-flight transactions
+func (h *ConflictTrackingHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	targets := requestContext.Opts.Targets
+
+	for attempt := 0; ; attempt++ {
+		// Reset context parameters for a fresh endorsement
+		requestContext.Opts.Targets = targets
+		requestContext.Error = nil
+		requestContext.Response = Response{}
+
+		h.endorser.Handle(requestContext, clientContext)
+		if requestContext.Error != nil {
+			return
+		}
+
+		rwSets, err := requestContext.Response.RWSets()
+		if err != nil {
+			requestContext.Error = err
+			return
+		}
+
+		txnID := requestContext.Response.TransactionID
+		keys := newKeySet(rwSets)
+		canRetry := attempt < h.tracker.attempts
+
+		conflicts, release := h.tracker.register(txnID, keys, h.tracker.mode == SerializeConflicts && canRetry)
+		if len(conflicts) > 0 {
+			logger.Debugf("Transaction [%s] conflicts with %d in-flight transaction(s) - waiting for them to complete before endorsing again", txnID, len(conflicts))
+			if err := wait(requestContext.Ctx, conflicts); err != nil {
+				requestContext.Error = err
+				return
+			}
+			continue
+		}
+
+		h.committer.Handle(requestContext, clientContext)
+		release()
+
+		if canRetry && isReadConflict(requestContext.Error) {
+			logger.Debugf("Transaction [%s] was invalidated with a read conflict - endorsing again: %s", txnID, requestContext.Error)
+			if err := h.tracker.waitForConflicts(requestContext.Ctx, txnID, keys); err != nil {
+				requestContext.Error = err
+				return
+			}
+			continue
+		}
+
+		break
+	}
+
+	if requestContext.Error != nil {
+		return
+	}
+
+	//Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+func isReadConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	if !ok || s.Group != status.EventServerStatus {
+		return false
+	}
+
+	code := pb.TxValidationCode(s.Code)
+	return code == pb.TxValidationCode_MVCC_READ_CONFLICT || code == pb.TxValidationCode_PHANTOM_READ_CONFLICT
+}
@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRWSets(t *testing.T) {
+	rwSet := newRwSetWithWrites("testCC", true, "coll1")
+	rwSet.KvRwSet.Reads = []*kvrwset.KVRead{{Key: "key", Version: &kvrwset.Version{BlockNum: 10, TxNum: 1}}}
+
+	requestContext, clientContext := newConflictTestContexts(t, rwSet)
+
+	handler := NewProposalProcessorHandler(NewEndorsementHandler())
+	handler.Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+
+	rwSets, err := requestContext.Response.RWSets()
+	require.NoError(t, err)
+	require.Len(t, rwSets, 1)
+
+	nsRWSet := rwSets[0]
+	assert.Equal(t, "testCC", nsRWSet.Namespace)
+	require.Len(t, nsRWSet.Reads, 1)
+	assert.Equal(t, "key", nsRWSet.Reads[0].Key)
+	assert.Equal(t, uint64(10), nsRWSet.Reads[0].Version.BlockNum)
+	require.Len(t, nsRWSet.Writes, 1)
+	assert.Equal(t, []byte("value"), nsRWSet.Writes[0].Value)
+	require.Len(t, nsRWSet.Collections, 1)
+	assert.Equal(t, "coll1", nsRWSet.Collections[0].CollectionName)
+	assert.Len(t, nsRWSet.Collections[0].HashedWrites, 1)
+
+	_, err = Response{}.RWSets()
+	assert.Error(t, err)
+}
+
+func TestConflictTracker(t *testing.T) {
+	tracker := NewConflictTracker(SerializeConflicts, 0)
+	assert.Equal(t, 3, tracker.attempts)
+
+	readA := newKeySet([]*NsRWSet{{Namespace: "cc", Reads: []*kvrwset.KVRead{{Key: "a"}}}})
+	readA2 := newKeySet([]*NsRWSet{{Namespace: "cc", Reads: []*kvrwset.KVRead{{Key: "a"}}}})
+	writeA := newKeySet([]*NsRWSet{{Namespace: "cc", Writes: []*kvrwset.KVWrite{{Key: "a"}}}})
+	writeAOtherCC := newKeySet([]*NsRWSet{{Namespace: "othercc", Writes: []*kvrwset.KVWrite{{Key: "a"}}}})
+
+	conflicts, release1 := tracker.register("txn1", readA, true)
+	require.Empty(t, conflicts)
+
+	// Concurrent reads don't conflict
+	conflicts, release2 := tracker.register("txn2", readA2, true)
+	require.Empty(t, conflicts)
+
+	// Keys of other chaincodes don't conflict
+	conflicts, release3 := tracker.register("txn3", writeAOtherCC, true)
+	require.Empty(t, conflicts)
+	assert.Equal(t, 3, tracker.InFlight())
+
+	conflicts, release := tracker.register("txn4", writeA, true)
+	assert.Len(t, conflicts, 2)
+	assert.Nil(t, release)
+
+	release1()
+	release2()
+	release3()
+	assert.Equal(t, 0, tracker.InFlight())
+
+	for _, done := range conflicts {
+		select {
+		case <-done:
+		default:
+			t.Fatal("expecting conflicting transaction to be done")
+		}
+	}
+}
+
+func TestConflictTrackingHandlerRetry(t *testing.T) {
+	rwSet := newRwSetWithWrites("testCC", true)
+	requestContext, clientContext := newConflictTestContexts(t, rwSet)
+
+	eventService := newTxStatusEventService(pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_VALID)
+	clientContext.EventService = eventService
+
+	tracker := NewConflictTracker(RetryConflicts, 2)
+	NewExecuteWithConflictTrackingHandler(tracker).Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, pb.TxValidationCode_VALID, requestContext.Response.TxValidationCode)
+	assert.Equal(t, int32(2), eventService.registrations())
+	assert.Equal(t, 0, tracker.InFlight())
+
+	t.Run("Attempts exceeded", func(t *testing.T) {
+		requestContext, clientContext := newConflictTestContexts(t, rwSet)
+		eventService := newTxStatusEventService(pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_MVCC_READ_CONFLICT)
+		clientContext.EventService = eventService
+
+		NewExecuteWithConflictTrackingHandler(NewConflictTracker(RetryConflicts, 1)).Handle(requestContext, clientContext)
+		require.Error(t, requestContext.Error)
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.EventServerStatus, s.Group)
+		assert.Equal(t, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), s.Code)
+		assert.Equal(t, int32(2), eventService.registrations())
+	})
+}
+
+func TestConflictTrackingHandlerSerialize(t *testing.T) {
+	rwSet := newRwSetWithWrites("testCC", true)
+	requestContext, clientContext := newConflictTestContexts(t, rwSet)
+
+	eventService := newTxStatusEventService(pb.TxValidationCode_VALID)
+	clientContext.EventService = eventService
+
+	tracker := NewConflictTracker(SerializeConflicts, 0)
+
+	// Simulate an in-flight transaction that writes the same key
+	rwSets, err := GetRWSets(newProposalResponse(t, rwSet))
+	require.NoError(t, err)
+	_, release := tracker.register("inflight", newKeySet(rwSets), true)
+
+	done := make(chan struct{})
+	go func() {
+		NewExecuteWithConflictTrackingHandler(tracker).Handle(requestContext, clientContext)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expecting transaction to wait for the conflicting in-flight transaction")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, int32(0), eventService.registrations())
+
+	release()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for transaction")
+	}
+
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, int32(1), eventService.registrations())
+	assert.Equal(t, 0, tracker.InFlight())
+}
+
+func newConflictTestContexts(t *testing.T, rwSet *rwsetutil.NsRwSet) (*RequestContext, *ClientContext) {
+	peer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	peer.SetRwSets(rwSet)
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "testCC", Fcn: "invoke"}, Opts{Targets: []fab.Peer{peer}}, t)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer}, t)
+
+	return requestContext, clientContext
+}
+
+func newProposalResponse(t *testing.T, rwSet *rwsetutil.NsRwSet) *fab.TransactionProposalResponse {
+	requestContext, clientContext := newConflictTestContexts(t, rwSet)
+	NewProposalProcessorHandler(NewEndorsementHandler()).Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	return requestContext.Response.Responses[0]
+}
+
+// txStatusEventService responds to transaction status registrations with the given validation codes (in order)
+type txStatusEventService struct {
+	*fcmocks.MockEventService
+	codes []pb.TxValidationCode
+	count int32
+}
+
+func newTxStatusEventService(codes ...pb.TxValidationCode) *txStatusEventService {
+	return &txStatusEventService{MockEventService: fcmocks.NewMockEventService(), codes: codes}
+}
+
+func (s *txStatusEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	i := atomic.AddInt32(&s.count, 1) - 1
+
+	eventCh := make(chan *fab.TxStatusEvent, 1)
+	eventCh <- &fab.TxStatusEvent{TxID: txID, TxValidationCode: s.codes[i]}
+
+	return &dispatcher.TxStatusReg{Eventch: eventCh, TxID: txID}, eventCh, nil
+}
+
+func (s *txStatusEventService) registrations() int32 {
+	return atomic.LoadInt32(&s.count)
+}
@@ -0,0 +1,423 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/policy"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/pkg/errors"
+)
+
+const (
+	lifecycleCC                  = "_lifecycle"
+	lifecycleQueryDefinitionFunc = "QueryChaincodeDefinition"
+	lsccCC                       = "lscc"
+	lsccGetChaincodeDataFunc     = "getccdata"
+
+	// DefaultPolicyCacheTimeout is the time for which the EndorsementPolicyHandler created by
+	// NewEndorsementPolicyHandler caches the endorsement policy of a chaincode
+	DefaultPolicyCacheTimeout = time.Minute
+)
+
+// ChaincodePolicy contains the endorsement policy of a chaincode
+type ChaincodePolicy struct {
+	// SignaturePolicy is the endorsement policy of the chaincode. It is nil if the chaincode's
+	// endorsement policy is a reference to a channel config policy.
+	SignaturePolicy *common.SignaturePolicyEnvelope
+	// ChannelConfigPolicy is the name of the channel config policy that the chaincode's endorsement policy references
+	ChannelConfigPolicy string
+	// Collections contains the configuration of the chaincode's private data collections (may be nil)
+	Collections *pb.CollectionConfigPackage
+}
+
+// EndorsementPolicyProvider returns the endorsement policy of the given chaincode
+type EndorsementPolicyProvider func(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error)
+
+// PolicyEvaluationError is returned by the EndorsementPolicyHandler when the endorsements
+// don't satisfy the endorsement policy of a chaincode or private data collection
+type PolicyEvaluationError struct {
+	// ChaincodeID is the chaincode whose policy is not satisfied
+	ChaincodeID string
+	// Collection is the private data collection whose policy is not satisfied (empty for the chaincode policy)
+	Collection string
+	// Result contains the policy and the missing principals
+	Result *policy.Result
+}
+
+func (e *PolicyEvaluationError) Error() string {
+	if e.Collection != "" {
+		return fmt.Sprintf("endorsements do not satisfy the endorsement policy of collection [%s] of chaincode [%s]: %s", e.Collection, e.ChaincodeID, e.Result)
+	}
+	return fmt.Sprintf("endorsements do not satisfy the endorsement policy of chaincode [%s]: %s", e.ChaincodeID, e.Result)
+}
+
+// EndorsementPolicyHandler evaluates the endorsements against the endorsement policies of the chaincodes
+// that were written to by the transaction (and of any private data collection that has its own endorsement
+// policy), so that under-endorsed transactions fail before they're submitted rather than being invalidated
+// with ENDORSEMENT_POLICY_FAILURE. The handler should be placed after the EndorsementValidationHandler
+// and SignatureValidationHandler. Note that key-level (state-based) endorsement policies and endorsement
+// policies that reference a channel config policy are not evaluated.
+type EndorsementPolicyHandler struct {
+	next           Handler
+	policyProvider EndorsementPolicyProvider
+}
+
+// NewEndorsementPolicyHandler returns a handler that evaluates the endorsements against the endorsement policies
+// which are queried from the chaincode definition in _lifecycle (or from LSCC for legacy chaincodes). The policies
+// are cached per channel and chaincode for DefaultPolicyCacheTimeout.
+func NewEndorsementPolicyHandler(next ...Handler) *EndorsementPolicyHandler {
+	return &EndorsementPolicyHandler{next: getNext(next), policyProvider: NewCachingPolicyProvider(QueryEndorsementPolicy, DefaultPolicyCacheTimeout)}
+}
+
+// NewEndorsementPolicyHandlerWithProvider returns a handler that evaluates the endorsements against the
+// endorsement policies returned by the given provider
+func NewEndorsementPolicyHandlerWithProvider(next Handler, provider EndorsementPolicyProvider) *EndorsementPolicyHandler {
+	return &EndorsementPolicyHandler{next: next, policyProvider: provider}
+}
+
+// NewCachingPolicyProvider returns a provider that caches the policies returned by the given provider per channel
+// and chaincode. A cached policy is queried again once the timeout has elapsed, so a policy that was changed by a
+// new chaincode definition may be used for at most timeout after the definition was committed. Errors are not cached.
+func NewCachingPolicyProvider(provider EndorsementPolicyProvider, timeout time.Duration) EndorsementPolicyProvider {
+	cache := &policyCache{provider: provider, timeout: timeout, policies: make(map[policyCacheKey]*cachedPolicy)}
+	return cache.get
+}
+
+type policyCacheKey struct {
+	channelID   string
+	chaincodeID string
+}
+
+type cachedPolicy struct {
+	policy  *ChaincodePolicy
+	expires time.Time
+}
+
+type policyCache struct {
+	provider EndorsementPolicyProvider
+	timeout  time.Duration
+	mutex    sync.RWMutex
+	policies map[policyCacheKey]*cachedPolicy
+}
+
+func (c *policyCache) get(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error) {
+	key := policyCacheKey{channelID: clientContext.ChannelID, chaincodeID: chaincodeID}
+
+	c.mutex.RLock()
+	cached, ok := c.policies[key]
+	c.mutex.RUnlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.policy, nil
+	}
+
+	ccPolicy, err := c.provider(requestContext, clientContext, chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Caching endorsement policy of chaincode [%s] on channel [%s] for %s", chaincodeID, key.channelID, c.timeout)
+
+	c.mutex.Lock()
+	c.policies[key] = &cachedPolicy{policy: ccPolicy, expires: time.Now().Add(c.timeout)}
+	c.mutex.Unlock()
+
+	return ccPolicy, nil
+}
+
+// NewExecuteWithPolicyCheckHandler returns execute handler with chain of SelectAndEndorseHandler, EndorsementValidationHandler,
+// SignatureValidationHandler, EndorsementPolicyHandler and CommitHandler
+func NewExecuteWithPolicyCheckHandler(next ...Handler) Handler {
+	return NewSelectAndEndorseHandler(
+		NewEndorsementValidationHandler(
+			NewSignatureValidationHandler(
+				NewEndorsementPolicyHandler(NewCommitHandler(next...)),
+			),
+		),
+	)
+}
+
+// Handle evaluates the endorsements against the endorsement policies
+func (h *EndorsementPolicyHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if err := h.evaluate(requestContext, clientContext); err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	// Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+func (h *EndorsementPolicyHandler) evaluate(requestContext *RequestContext, clientContext *ClientContext) error {
+	responses := requestContext.Response.Responses
+	if len(responses) == 0 {
+		return nil
+	}
+
+	evaluator, ok := clientContext.Membership.(policy.PrincipalEvaluator)
+	if !ok {
+		return errors.Errorf("channel membership %T does not support principal evaluation", clientContext.Membership)
+	}
+
+	// The responses have already been validated to have the same payload
+	rwSets, err := getRWSetsFromProposalResponse(responses[0].ProposalResponse)
+	if err != nil {
+		return errors.WithMessage(err, "error extracting read-write sets from proposal response")
+	}
+
+	var endorsers [][]byte
+	for _, r := range responses {
+		if r.ProposalResponse.Endorsement != nil {
+			endorsers = append(endorsers, r.ProposalResponse.Endorsement.Endorser)
+		}
+	}
+
+	var failures []*PolicyEvaluationError
+	for _, target := range policyTargets(requestContext.Request.ChaincodeID, rwSets) {
+		ccPolicy, err := h.policyProvider(requestContext, clientContext, target.chaincodeID)
+		if err != nil {
+			return errors.WithMessagef(err, "error retrieving endorsement policy of chaincode [%s]", target.chaincodeID)
+		}
+
+		f, err := evaluatePolicies(target, ccPolicy, endorsers, evaluator)
+		if err != nil {
+			return err
+		}
+		failures = append(failures, f...)
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(failures))
+	details := make([]interface{}, len(failures))
+	for i, f := range failures {
+		msgs[i] = f.Error()
+		details[i] = f
+	}
+
+	return status.New(status.EndorserClientStatus, status.MissingEndorsement.ToInt32(), strings.Join(msgs, "; "), details)
+}
+
+// policyTarget is a chaincode whose endorsement policy must be satisfied along with
+// the collections that were written to
+type policyTarget struct {
+	chaincodeID        string
+	publicWrites       bool
+	writtenCollections []string
+}
+
+// policyTargets returns the invoked chaincode and all other chaincodes that were written to
+func policyTargets(chaincodeID string, rwSets []*rwsetutil.NsRwSet) []*policyTarget {
+	targets := []*policyTarget{{chaincodeID: chaincodeID}}
+
+	for _, rwSet := range rwSets {
+		if !lsccFilter(rwSet.NameSpace) {
+			continue
+		}
+
+		target := &policyTarget{chaincodeID: rwSet.NameSpace}
+		if rwSet.KvRwSet != nil {
+			target.publicWrites = len(rwSet.KvRwSet.Writes) > 0 || len(rwSet.KvRwSet.MetadataWrites) > 0
+		}
+		for _, collRWSet := range rwSet.CollHashedRwSets {
+			if collRWSet.HashedRwSet != nil && (len(collRWSet.HashedRwSet.HashedWrites) > 0 || len(collRWSet.HashedRwSet.MetadataWrites) > 0) {
+				target.writtenCollections = append(target.writtenCollections, collRWSet.CollectionName)
+			}
+		}
+
+		if rwSet.NameSpace == chaincodeID {
+			targets[0] = target
+		} else if target.publicWrites || len(target.writtenCollections) > 0 {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// evaluatePolicies evaluates the endorsers against the policies of the written collections that have their own
+// endorsement policy and against the chaincode policy, unless all writes were to such collections
+func evaluatePolicies(target *policyTarget, ccPolicy *ChaincodePolicy, endorsers [][]byte, evaluator policy.PrincipalEvaluator) ([]*PolicyEvaluationError, error) {
+	var failures []*PolicyEvaluationError
+
+	evaluateCCPolicy := target.publicWrites || len(target.writtenCollections) == 0
+	for _, collection := range target.writtenCollections {
+		collPolicy, channelConfigPolicy := collectionPolicy(ccPolicy.Collections, collection)
+		if collPolicy == nil && channelConfigPolicy == "" {
+			// The collection doesn't have its own policy so the chaincode policy applies
+			evaluateCCPolicy = true
+			continue
+		}
+		if collPolicy == nil {
+			logger.Debugf("Skipping evaluation of the endorsement policy of collection [%s] of chaincode [%s] since it references channel config policy [%s]", collection, target.chaincodeID, channelConfigPolicy)
+			continue
+		}
+
+		result, err := policy.Evaluate(collPolicy, endorsers, evaluator)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error evaluating endorsement policy of collection [%s] of chaincode [%s]", collection, target.chaincodeID)
+		}
+		logger.Debugf("Evaluated endorsement policy of collection [%s] of chaincode [%s]: %s", collection, target.chaincodeID, result)
+		if !result.Satisfied {
+			failures = append(failures, &PolicyEvaluationError{ChaincodeID: target.chaincodeID, Collection: collection, Result: result})
+		}
+	}
+
+	if !evaluateCCPolicy {
+		return failures, nil
+	}
+
+	if ccPolicy.SignaturePolicy == nil {
+		logger.Debugf("Skipping evaluation of the endorsement policy of chaincode [%s] since it references channel config policy [%s]", target.chaincodeID, ccPolicy.ChannelConfigPolicy)
+		return failures, nil
+	}
+
+	result, err := policy.Evaluate(ccPolicy.SignaturePolicy, endorsers, evaluator)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error evaluating endorsement policy of chaincode [%s]", target.chaincodeID)
+	}
+	logger.Debugf("Evaluated endorsement policy of chaincode [%s]: %s", target.chaincodeID, result)
+	if !result.Satisfied {
+		failures = append(failures, &PolicyEvaluationError{ChaincodeID: target.chaincodeID, Result: result})
+	}
+
+	return failures, nil
+}
+
+// collectionPolicy returns the endorsement policy of the given collection (if any)
+func collectionPolicy(collections *pb.CollectionConfigPackage, name string) (*common.SignaturePolicyEnvelope, string) {
+	if collections == nil {
+		return nil, ""
+	}
+
+	for _, config := range collections.Config {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig == nil || staticConfig.Name != name || staticConfig.EndorsementPolicy == nil {
+			continue
+		}
+		return staticConfig.EndorsementPolicy.GetSignaturePolicy(), staticConfig.EndorsementPolicy.GetChannelConfigPolicyReference()
+	}
+
+	return nil, ""
+}
+
+// QueryEndorsementPolicy queries the endorsement policy of the given chaincode from the chaincode definition
+// in _lifecycle. If the chaincode is not defined in _lifecycle then the policy is queried from LSCC.
+// The query is sent to the targets of the request or, if none were specified, to the endorsers.
+func QueryEndorsementPolicy(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error) {
+	targets, err := policyQueryTargets(requestContext, clientContext)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := proto.Marshal(&lb.QueryChaincodeDefinitionArgs{Name: chaincodeID})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling QueryChaincodeDefinitionArgs")
+	}
+
+	payload, lifecycleErr := queryPolicy(clientContext, &Request{ChaincodeID: lifecycleCC, Fcn: lifecycleQueryDefinitionFunc, Args: [][]byte{args}}, targets)
+	if lifecycleErr == nil {
+		return unmarshalChaincodeDefinition(payload)
+	}
+
+	logger.Debugf("Error querying chaincode definition of [%s] from _lifecycle: %s. Querying LSCC ...", chaincodeID, lifecycleErr)
+
+	payload, err = queryPolicy(clientContext, &Request{ChaincodeID: lsccCC, Fcn: lsccGetChaincodeDataFunc, Args: [][]byte{[]byte(clientContext.ChannelID), []byte(chaincodeID)}}, targets)
+	if err != nil {
+		return nil, errors.Errorf("error querying chaincode definition from _lifecycle: %s and from LSCC: %s", lifecycleErr, err)
+	}
+
+	ccData := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(payload, ccData); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode data")
+	}
+
+	sigPolicy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(ccData.Policy, sigPolicy); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling signature policy")
+	}
+
+	return &ChaincodePolicy{SignaturePolicy: sigPolicy}, nil
+}
+
+func unmarshalChaincodeDefinition(payload []byte) (*ChaincodePolicy, error) {
+	definition := &lb.QueryChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, definition); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode definition")
+	}
+
+	sigPolicy, channelConfigPolicy, err := resource.NewLifecycle().UnmarshalApplicationPolicy(definition.ValidationParameter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChaincodePolicy{
+		SignaturePolicy:     sigPolicy,
+		ChannelConfigPolicy: channelConfigPolicy,
+		Collections:         definition.Collections,
+	}, nil
+}
+
+// queryPolicy sends the query to each of the targets until a successful response is received
+func queryPolicy(clientContext *ClientContext, request *Request, targets []fab.ProposalProcessor) ([]byte, error) {
+	var lastErr error
+	for _, target := range targets {
+		responses, _, err := createAndSendTransactionProposal(clientContext.Transactor, request, []fab.ProposalProcessor{target})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return responses[0].ProposalResponse.GetResponse().Payload, nil
+	}
+	return nil, lastErr
+}
+
+func policyQueryTargets(requestContext *RequestContext, clientContext *ClientContext) ([]fab.ProposalProcessor, error) {
+	if len(requestContext.Opts.Targets) > 0 {
+		return peer.PeersToTxnProcessors(requestContext.Opts.Targets), nil
+	}
+
+	peers, err := clientContext.Discovery.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting peers from discovery service")
+	}
+
+	var targets []fab.Peer
+	for _, r := range requestContext.Response.Responses {
+		for _, p := range peers {
+			if p.URL() == r.Endorser {
+				targets = append(targets, p)
+				break
+			}
+		}
+	}
+	if len(targets) == 0 {
+		targets = peers
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no targets available to query the endorsement policy")
+	}
+
+	return peer.PeersToTxnProcessors(targets), nil
+}
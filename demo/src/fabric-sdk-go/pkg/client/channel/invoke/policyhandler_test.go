@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndorsementPolicyHandler(t *testing.T) {
+	ccID := "testCC"
+
+	ccPolicy, err := policydsl.FromString("AND('Org1MSP.peer', 'Org2MSP.peer')")
+	require.NoError(t, err)
+	collPolicy, err := policydsl.FromString("OR('Org3MSP.peer')")
+	require.NoError(t, err)
+
+	policyProvider := func(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error) {
+		assert.Equal(t, ccID, chaincodeID)
+		return &ChaincodePolicy{
+			SignaturePolicy: ccPolicy,
+			Collections: &pb.CollectionConfigPackage{
+				Config: []*pb.CollectionConfig{
+					newCollectionConfig("coll1", collPolicy),
+					newCollectionConfig("coll2", nil),
+				},
+			},
+		}, nil
+	}
+
+	publicWrites := newRwSetWithWrites(ccID, true)
+	coll1Writes := newRwSetWithWrites(ccID, false, "coll1")
+	coll2Writes := newRwSetWithWrites(ccID, false, "coll2")
+
+	t.Run("Satisfied", func(t *testing.T) {
+		err := handlePolicy(t, policyProvider, publicWrites, "Org1MSP", "Org2MSP")
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing principal", func(t *testing.T) {
+		err := handlePolicy(t, policyProvider, publicWrites, "Org1MSP", "Org1MSP")
+		require.Error(t, err)
+
+		s, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.EndorserClientStatus, s.Group)
+		assert.Equal(t, status.MissingEndorsement.ToInt32(), s.Code)
+		assert.Contains(t, s.Message, "endorsement policy of chaincode [testCC]")
+		assert.Contains(t, s.Message, "missing principals [Org2MSP.peer]")
+		require.Len(t, s.Details, 1)
+		assert.Equal(t, []string{"Org2MSP.peer"}, s.Details[0].(*PolicyEvaluationError).Result.Missing)
+	})
+
+	t.Run("Collection policy", func(t *testing.T) {
+		// Only the collection policy applies since there are no public writes
+		err := handlePolicy(t, policyProvider, coll1Writes, "Org3MSP")
+		require.NoError(t, err)
+
+		err = handlePolicy(t, policyProvider, coll1Writes, "Org1MSP", "Org2MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "endorsement policy of collection [coll1] of chaincode [testCC]")
+		assert.Contains(t, err.Error(), "missing principals [Org3MSP.peer]")
+	})
+
+	t.Run("Collection without policy", func(t *testing.T) {
+		// The chaincode policy applies to collections without their own policy
+		err := handlePolicy(t, policyProvider, coll2Writes, "Org3MSP")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing principals [Org1MSP.peer Org2MSP.peer]")
+	})
+
+	t.Run("Channel config policy", func(t *testing.T) {
+		provider := func(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error) {
+			return &ChaincodePolicy{ChannelConfigPolicy: "/Channel/Application/Endorsement"}, nil
+		}
+		err := handlePolicy(t, provider, publicWrites, "Org1MSP")
+		require.NoError(t, err)
+	})
+}
+
+func TestQueryEndorsementPolicy(t *testing.T) {
+	ccPolicy, err := policydsl.FromString("OR('Org1MSP.peer', 'Org2MSP.peer')")
+	require.NoError(t, err)
+
+	validationParameter, err := proto.Marshal(&pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_SignaturePolicy{SignaturePolicy: ccPolicy}})
+	require.NoError(t, err)
+
+	definition, err := proto.Marshal(&lb.QueryChaincodeDefinitionResult{
+		ValidationParameter: validationParameter,
+		Collections: &pb.CollectionConfigPackage{
+			Config: []*pb.CollectionConfig{newCollectionConfig("coll1", nil)},
+		},
+	})
+	require.NoError(t, err)
+
+	mockPeer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: 200, Payload: definition}
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "testCC"}, Opts{Targets: []fab.Peer{mockPeer}}, t)
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	result, err := QueryEndorsementPolicy(requestContext, clientContext, "testCC")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(ccPolicy, result.SignaturePolicy))
+	require.NotNil(t, result.Collections)
+	assert.Len(t, result.Collections.Config, 1)
+}
+
+func TestCachingPolicyProvider(t *testing.T) {
+	calls := 0
+	var providerErr error
+	provider := NewCachingPolicyProvider(func(requestContext *RequestContext, clientContext *ClientContext, chaincodeID string) (*ChaincodePolicy, error) {
+		calls++
+		if providerErr != nil {
+			return nil, providerErr
+		}
+		return &ChaincodePolicy{ChannelConfigPolicy: chaincodeID}, nil
+	}, 50*time.Millisecond)
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "testCC"}, Opts{}, t)
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	for i := 0; i < 2; i++ {
+		ccPolicy, err := provider(requestContext, clientContext, "testCC")
+		require.NoError(t, err)
+		assert.Equal(t, "testCC", ccPolicy.ChannelConfigPolicy)
+	}
+	assert.Equal(t, 1, calls, "the policy should be cached")
+
+	ccPolicy, err := provider(requestContext, clientContext, "otherCC")
+	require.NoError(t, err)
+	assert.Equal(t, "otherCC", ccPolicy.ChannelConfigPolicy)
+	assert.Equal(t, 2, calls, "policies are cached per chaincode")
+
+	otherChannelContext := setupChannelClientContext(nil, nil, nil, t)
+	otherChannelContext.ChannelID = "otherChannel"
+	_, err = provider(requestContext, otherChannelContext, "testCC")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "policies are cached per channel")
+
+	providerErr = fmt.Errorf("query failed")
+	_, err = provider(requestContext, clientContext, "failingCC")
+	require.Error(t, err)
+	_, err = provider(requestContext, clientContext, "failingCC")
+	require.Error(t, err)
+	assert.Equal(t, 5, calls, "errors should not be cached")
+
+	providerErr = nil
+	time.Sleep(100 * time.Millisecond)
+	_, err = provider(requestContext, clientContext, "testCC")
+	require.NoError(t, err)
+	assert.Equal(t, 6, calls, "the policy should be queried again after the timeout")
+}
+
+func handlePolicy(t *testing.T, provider EndorsementPolicyProvider, rwSet *rwsetutil.NsRwSet, endorserMSPIDs ...string) error {
+	var peers []fab.Peer
+	for i, mspID := range endorserMSPIDs {
+		endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte{byte(i)}})
+		require.NoError(t, err)
+
+		peer := &fcmocks.MockPeer{MockName: fmt.Sprintf("Peer%d", i), MockURL: fmt.Sprintf("http://peer%d.com", i), MockMSP: mspID, Status: 200, Payload: []byte("value"), Endorser: endorser}
+		peer.SetRwSets(rwSet)
+		peers = append(peers, peer)
+	}
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "testCC", Fcn: "invoke"}, Opts{Targets: peers}, t)
+	clientContext := setupChannelClientContext(nil, nil, peers, t)
+
+	handler := NewProposalProcessorHandler(
+		NewEndorsementHandler(
+			NewEndorsementPolicyHandlerWithProvider(nil, provider),
+		),
+	)
+	handler.Handle(requestContext, clientContext)
+
+	return requestContext.Error
+}
+
+func newRwSetWithWrites(ccID string, publicWrites bool, collections ...string) *rwsetutil.NsRwSet {
+	rwSet := fcmocks.NewRwSet(ccID)
+	if publicWrites {
+		rwSet.KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}
+	}
+	for _, coll := range collections {
+		rwSet.CollHashedRwSets = append(rwSet.CollHashedRwSets, &rwsetutil.CollHashedRwSet{
+			CollectionName: coll,
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+			},
+		})
+	}
+	return rwSet
+}
+
+func newCollectionConfig(name string, policy *common.SignaturePolicyEnvelope) *pb.CollectionConfig {
+	config := &pb.StaticCollectionConfig{Name: name}
+	if policy != nil {
+		config.EndorsementPolicy = &pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_SignaturePolicy{SignaturePolicy: policy}}
+	}
+	return &pb.CollectionConfig{Payload: &pb.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: config}}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// NsRWSet contains the read/write set that an endorsement produced for a chaincode namespace
+type NsRWSet struct {
+	Namespace        string
+	Reads            []*kvrwset.KVRead
+	RangeQueriesInfo []*kvrwset.RangeQueryInfo
+	Writes           []*kvrwset.KVWrite
+	MetadataWrites   []*kvrwset.KVMetadataWrite
+	Collections      []*CollHashedRWSet
+}
+
+// CollHashedRWSet contains the hashed read/write set of a private data collection
+type CollHashedRWSet struct {
+	CollectionName string
+	HashedReads    []*kvrwset.KVReadHash
+	HashedWrites   []*kvrwset.KVWriteHash
+}
+
+// RWSets returns the decoded read/write sets of the endorsement. The read/write sets are taken
+// from the first proposal response since all responses are expected to be the same.
+func (r Response) RWSets() ([]*NsRWSet, error) {
+	if len(r.Responses) == 0 {
+		return nil, errors.New("no proposal responses")
+	}
+	return GetRWSets(r.Responses[0])
+}
+
+// GetRWSets decodes the read/write sets from the given proposal response
+func GetRWSets(response *fab.TransactionProposalResponse) ([]*NsRWSet, error) {
+	if response == nil {
+		return nil, errors.New("proposal response is nil")
+	}
+
+	rwSets, err := getRWSetsFromProposalResponse(response.ProposalResponse)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error extracting read/write sets from proposal response")
+	}
+
+	nsRWSets := make([]*NsRWSet, len(rwSets))
+	for i, rwSet := range rwSets {
+		nsRWSet := &NsRWSet{Namespace: rwSet.NameSpace}
+		if rwSet.KvRwSet != nil {
+			nsRWSet.Reads = rwSet.KvRwSet.Reads
+			nsRWSet.RangeQueriesInfo = rwSet.KvRwSet.RangeQueriesInfo
+			nsRWSet.Writes = rwSet.KvRwSet.Writes
+			nsRWSet.MetadataWrites = rwSet.KvRwSet.MetadataWrites
+		}
+		for _, collRWSet := range rwSet.CollHashedRwSets {
+			coll := &CollHashedRWSet{CollectionName: collRWSet.CollectionName}
+			if collRWSet.HashedRwSet != nil {
+				coll.HashedReads = collRWSet.HashedRwSet.HashedReads
+				coll.HashedWrites = collRWSet.HashedRwSet.HashedWrites
+			}
+			nsRWSet.Collections = append(nsRWSet.Collections, coll)
+		}
+		nsRWSets[i] = nsRWSet
+	}
+
+	return nsRWSets, nil
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
-	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/pkg/errors"
 
 	"github.com/golang/protobuf/proto"
@@ -72,7 +71,7 @@ func (e *SelectAndEndorseHandler) Handle(requestContext *RequestContext, clientC
 			if len(additionalEndorsers) > 0 {
 				requestContext.Opts.Targets = additionalEndorsers
 				logger.Debugf("...getting additional endorsements from %d target(s)", len(additionalEndorsers))
-				additionalResponses, err := clientContext.Transactor.SendTransactionProposal(requestContext.Response.Proposal, peer.PeersToTxnProcessors(additionalEndorsers))
+				additionalResponses, err := e.sendProposal(clientContext.Transactor, requestContext.Response.Proposal, additionalEndorsers)
 				if err != nil {
 					requestContext.Error = errors.WithMessage(err, "error sending transaction proposal")
 					return
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// SignedProposal contains a transaction proposal along with the signed proposal that's sent to the endorsers.
+// The proposal is signed by an external signer (e.g. an offline signer) rather than by the client context.
+type SignedProposal struct {
+	Proposal       *fab.TransactionProposal
+	SignedProposal *pb.SignedProposal
+}
+
+// SignedTransaction contains a transaction envelope that was signed by an external signer
+type SignedTransaction struct {
+	TxnID    fab.TransactionID
+	Envelope *fab.SignedEnvelope
+}
+
+//NewSignedProposalEndorsementHandler returns a handler that sends a signed proposal to the targets
+func NewSignedProposalEndorsementHandler(proposal *SignedProposal, next ...Handler) *EndorsementHandler {
+	return &EndorsementHandler{next: getNext(next), signedProposal: proposal}
+}
+
+//NewSelectAndEndorseSignedProposalHandler returns a handler that selects endorsers and sends a signed proposal to them
+func NewSelectAndEndorseSignedProposalHandler(proposal *SignedProposal, next ...Handler) Handler {
+	return &SelectAndEndorseHandler{
+		EndorsementHandler: NewSignedProposalEndorsementHandler(proposal),
+		next:               getNext(next),
+	}
+}
+
+//NewSignedCommitHandler returns a handler that sends a signed transaction envelope to the orderer
+//and waits for the transaction to be committed
+func NewSignedCommitHandler(tx *SignedTransaction, next ...Handler) *CommitTxHandler {
+	return &CommitTxHandler{next: getNext(next), signedTxn: tx}
+}
+
+func sendSignedEnvelope(sender fab.Sender, envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	envelopeSender, ok := sender.(fab.SignedEnvelopeSender)
+	if !ok {
+		return nil, errors.New("transactor does not support sending signed envelopes")
+	}
+	return envelopeSender.SendSignedEnvelope(envelope)
+}
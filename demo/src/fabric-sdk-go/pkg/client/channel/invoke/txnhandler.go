@@ -30,6 +30,7 @@ type TxnHeaderOptsProvider func() []fab.TxnHeaderOpt
 type EndorsementHandler struct {
 	next               Handler
 	headerOptsProvider TxnHeaderOptsProvider
+	signedProposal     *SignedProposal
 }
 
 //Handle for endorsing transactions
@@ -46,12 +47,20 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 		TxnHeaderOpts = e.headerOptsProvider()
 	}
 
-	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(
-		clientContext.Transactor,
-		&requestContext.Request,
-		peer.PeersToTxnProcessors(requestContext.Opts.Targets),
-		TxnHeaderOpts...,
-	)
+	var transactionProposalResponses []*fab.TransactionProposalResponse
+	var proposal *fab.TransactionProposal
+	var err error
+	if e.signedProposal != nil {
+		proposal = e.signedProposal.Proposal
+		transactionProposalResponses, err = e.sendProposal(clientContext.Transactor, proposal, requestContext.Opts.Targets)
+	} else {
+		transactionProposalResponses, proposal, err = createAndSendTransactionProposal(
+			clientContext.Transactor,
+			&requestContext.Request,
+			peer.PeersToTxnProcessors(requestContext.Opts.Targets),
+			TxnHeaderOpts...,
+		)
+	}
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
@@ -73,6 +82,20 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	}
 }
 
+// sendProposal sends the given proposal to the targets. If the handler was created with a signed proposal
+// then the signed proposal is sent, otherwise the proposal is signed by the client context.
+func (e *EndorsementHandler) sendProposal(transactor fab.ProposalSender, proposal *fab.TransactionProposal, targets []fab.Peer) ([]*fab.TransactionProposalResponse, error) {
+	if e.signedProposal == nil {
+		return transactor.SendTransactionProposal(proposal, peer.PeersToTxnProcessors(targets))
+	}
+
+	sender, ok := transactor.(fab.SignedProposalSender)
+	if !ok {
+		return nil, errors.New("transactor does not support sending signed proposals")
+	}
+	return sender.SendSignedTransactionProposal(e.signedProposal.SignedProposal, peer.PeersToTxnProcessors(targets))
+}
+
 //ProposalProcessorHandler for selecting proposal processors
 type ProposalProcessorHandler struct {
 	next Handler
@@ -161,11 +184,15 @@ func (f *EndorsementValidationHandler) validate(txProposalResponse []*fab.Transa
 
 //CommitTxHandler for committing transactions
 type CommitTxHandler struct {
-	next Handler
+	next      Handler
+	signedTxn *SignedTransaction
 }
 
 //Handle handles commit tx
 func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if c.signedTxn != nil {
+		requestContext.Response.TransactionID = c.signedTxn.TxnID
+	}
 	txnID := requestContext.Response.TransactionID
 
 	//Register Tx event
@@ -176,7 +203,11 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 	defer clientContext.EventService.Unregister(reg)
 
-	_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	if c.signedTxn != nil {
+		_, err = sendSignedEnvelope(clientContext.Transactor, c.signedTxn.Envelope)
+	} else {
+		_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	}
 	if err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
@@ -466,6 +466,7 @@ func setupChannelClientContext(discErr error, selectionErr error, peers []fab.Pe
 	}
 
 	return &ClientContext{
+		ChannelID:  "testChannel",
 		Membership: membership,
 		Discovery:  txnmocks.NewMockDiscoveryService(discErr),
 		Selection:  txnmocks.NewMockSelectionService(selectionErr, peers...),
@@ -0,0 +1,239 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/pkg/errors"
+)
+
+// UnsignedProposal contains a transaction proposal that is to be signed by an external (e.g. offline) signer.
+// The signer signs Digest with the private key of the proposal's creator using the given signature scheme.
+type UnsignedProposal struct {
+	Request  Request
+	Proposal *fab.TransactionProposal
+	// Bytes contains the marshalled proposal
+	Bytes []byte
+	// Digest contains the digest of Bytes that must be signed
+	Digest []byte
+	// Scheme is the signature scheme of the creator's key (ECDSA or SM2)
+	Scheme txn.SignatureScheme
+}
+
+// UnsignedTransaction contains an endorsed transaction that is to be signed by an external (e.g. offline) signer
+// before it's sent to the orderer. The signer signs Digest with the private key of the transaction's creator.
+type UnsignedTransaction struct {
+	Request       Request
+	TransactionID fab.TransactionID
+	// Bytes contains the marshalled payload of the transaction envelope
+	Bytes []byte
+	// Digest contains the digest of Bytes that must be signed
+	Digest []byte
+	// Scheme is the signature scheme of the creator's key (ECDSA or SM2)
+	Scheme txn.SignatureScheme
+}
+
+// CreateUnsignedProposal creates a transaction proposal for the given request which is to be signed by an external signer.
+// The creator of the proposal is the identity of the client context unless it is overridden with fab.WithCreator.
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  opts holds optional transaction header options
+//
+//  Returns:
+//  the unsigned proposal along with the digest to be signed
+func (cc *Client) CreateUnsignedProposal(request Request, opts ...fab.TxnHeaderOpt) (*UnsignedProposal, error) {
+	if request.ChaincodeID == "" || request.Fcn == "" {
+		return nil, errors.New("ChaincodeID and Fcn are required")
+	}
+
+	txh, err := txn.NewHeader(cc.context, cc.context.ChannelID(), opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction header failed")
+	}
+
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID:  request.ChaincodeID,
+		Fcn:          request.Fcn,
+		Args:         request.Args,
+		TransientMap: request.TransientMap,
+		IsInit:       request.IsInit,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
+	}
+
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal failed")
+	}
+
+	digest, scheme, err := txn.SigningDigest(cc.context.CryptoSuite(), txh.Creator(), proposalBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "computing proposal digest failed")
+	}
+
+	return &UnsignedProposal{
+		Request:  request,
+		Proposal: proposal,
+		Bytes:    proposalBytes,
+		Digest:   digest,
+		Scheme:   scheme,
+	}, nil
+}
+
+// SendSignedProposal sends the proposal, signed by an external signer, to the endorsers. The endorsements are
+// validated and returned in the response, which is then used to create the unsigned transaction.
+//  Parameters:
+//  proposal is the proposal returned by CreateUnsignedProposal
+//  signature is the signature of the proposal digest
+//  options holds optional request options
+//
+//  Returns:
+//  the proposal responses from peer(s)
+func (cc *Client) SendSignedProposal(proposal *UnsignedProposal, signature []byte, options ...RequestOption) (Response, error) {
+	if proposal == nil || proposal.Proposal == nil {
+		return Response{}, errors.New("proposal is required")
+	}
+
+	creator, err := creatorFromHeader(proposal.Proposal.Proposal.Header)
+	if err != nil {
+		return Response{}, err
+	}
+
+	signature, err = txn.NormalizeSignature(creator, signature)
+	if err != nil {
+		return Response{}, errors.WithMessage(err, "invalid proposal signature")
+	}
+
+	signedProposal := &invoke.SignedProposal{
+		Proposal:       proposal.Proposal,
+		SignedProposal: &pb.SignedProposal{ProposalBytes: proposal.Bytes, Signature: signature},
+	}
+
+	options = append(options, addDefaultTimeout(fab.Execute))
+	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+
+	handler := invoke.NewSelectAndEndorseSignedProposalHandler(
+		signedProposal,
+		invoke.NewEndorsementValidationHandler(
+			invoke.NewSignatureValidationHandler(),
+		),
+	)
+
+	return cc.InvokeHandler(handler, proposal.Request, options...)
+}
+
+// CreateUnsignedTransaction creates the transaction envelope payload from the endorsements of a signed proposal.
+// The payload is to be signed by an external signer.
+//  Parameters:
+//  proposal is the proposal returned by CreateUnsignedProposal
+//  response is the response returned by SendSignedProposal
+//
+//  Returns:
+//  the unsigned transaction along with the digest to be signed
+func (cc *Client) CreateUnsignedTransaction(proposal *UnsignedProposal, response Response) (*UnsignedTransaction, error) {
+	if proposal == nil {
+		return nil, errors.New("proposal is required")
+	}
+	if response.Proposal == nil {
+		return nil, errors.New("response does not contain a proposal")
+	}
+
+	tx, err := txn.New(fab.TransactionRequest{Proposal: response.Proposal, ProposalResponses: response.Responses})
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction failed")
+	}
+
+	payload, err := txn.CreateTransactionPayload(tx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction payload failed")
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	creator, err := creatorFromHeader(response.Proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, scheme, err := txn.SigningDigest(cc.context.CryptoSuite(), creator, payloadBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "computing transaction digest failed")
+	}
+
+	return &UnsignedTransaction{
+		Request:       proposal.Request,
+		TransactionID: response.TransactionID,
+		Bytes:         payloadBytes,
+		Digest:        digest,
+		Scheme:        scheme,
+	}, nil
+}
+
+// SendSignedTransaction sends the transaction, signed by an external signer, to the orderer and waits
+// for the transaction to be committed.
+//  Parameters:
+//  tx is the transaction returned by CreateUnsignedTransaction
+//  signature is the signature of the transaction digest
+//  options holds optional request options
+//
+//  Returns:
+//  the response containing the transaction ID and validation code
+func (cc *Client) SendSignedTransaction(tx *UnsignedTransaction, signature []byte, options ...RequestOption) (Response, error) {
+	if tx == nil {
+		return Response{}, errors.New("transaction is required")
+	}
+
+	payload, err := protoutil.UnmarshalPayload(tx.Bytes)
+	if err != nil {
+		return Response{}, errors.Wrap(err, "unmarshal payload failed")
+	}
+
+	creator, err := creatorFromSignatureHeader(payload.GetHeader().GetSignatureHeader())
+	if err != nil {
+		return Response{}, err
+	}
+
+	signature, err = txn.NormalizeSignature(creator, signature)
+	if err != nil {
+		return Response{}, errors.WithMessage(err, "invalid transaction signature")
+	}
+
+	signedTxn := &invoke.SignedTransaction{
+		TxnID:    tx.TransactionID,
+		Envelope: &fab.SignedEnvelope{Payload: tx.Bytes, Signature: signature},
+	}
+
+	options = append(options, addDefaultTimeout(fab.Execute))
+
+	return cc.InvokeHandler(invoke.NewSignedCommitHandler(signedTxn), tx.Request, options...)
+}
+
+func creatorFromHeader(headerBytes []byte) ([]byte, error) {
+	header, err := protoutil.UnmarshalHeader(headerBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	return creatorFromSignatureHeader(header.SignatureHeader)
+}
+
+func creatorFromSignatureHeader(signatureHeaderBytes []byte) ([]byte, error) {
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(signatureHeaderBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal signature header failed")
+	}
+	return signatureHeader.Creator, nil
+}
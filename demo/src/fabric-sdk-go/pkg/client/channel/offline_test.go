@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineSigning(t *testing.T) {
+	key, creator := newOfflineSigner(t)
+
+	testPeer1 := &recordingPeer{MockPeer: fcmocks.NewMockPeer("Peer1", "http://peer1.com")}
+	testPeer1.Payload = []byte("value")
+
+	broadcastListener := make(chan *fab.SignedEnvelope, 1)
+	orderer := fcmocks.NewMockOrderer("", broadcastListener)
+	defer orderer.CloseQueue()
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer1}, []fab.Orderer{orderer}, t)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	proposal, err := chClient.CreateUnsignedProposal(request, fab.WithCreator(creator))
+	require.NoError(t, err)
+	assert.Equal(t, txn.ECDSA, proposal.Scheme)
+	require.NotEmpty(t, proposal.Digest)
+
+	response, err := chClient.SendSignedProposal(proposal, sign(t, key, proposal.Digest))
+	require.NoError(t, err)
+	assert.Equal(t, proposal.Proposal.TxnID, response.TransactionID)
+	assert.Equal(t, []byte("value"), response.Payload)
+
+	signedProposal := testPeer1.signedProposal
+	require.NotNil(t, signedProposal)
+	assert.Equal(t, proposal.Bytes, signedProposal.ProposalBytes)
+	verify(t, key, proposal.Digest, signedProposal.Signature)
+
+	tx, err := chClient.CreateUnsignedTransaction(proposal, response)
+	require.NoError(t, err)
+	assert.Equal(t, response.TransactionID, tx.TransactionID)
+	assert.Equal(t, txn.ECDSA, tx.Scheme)
+
+	response, err = chClient.SendSignedTransaction(tx, sign(t, key, tx.Digest))
+	require.NoError(t, err)
+	assert.Equal(t, tx.TransactionID, response.TransactionID)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+
+	select {
+	case envelope := <-broadcastListener:
+		assert.Equal(t, tx.Bytes, envelope.Payload)
+		verify(t, key, tx.Digest, envelope.Signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the envelope to be broadcast")
+	}
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		_, err := chClient.SendSignedProposal(proposal, []byte("invalid"))
+		assert.Error(t, err)
+
+		_, err = chClient.SendSignedTransaction(tx, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := chClient.CreateUnsignedProposal(Request{ChaincodeID: "testCC"}, fab.WithCreator(creator))
+		assert.Error(t, err)
+
+		_, err = chClient.CreateUnsignedTransaction(proposal, Response{})
+		assert.Error(t, err)
+	})
+}
+
+// recordingPeer records the signed proposal that it receives
+type recordingPeer struct {
+	*fcmocks.MockPeer
+	signedProposal *pb.SignedProposal
+}
+
+func (p *recordingPeer) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	p.signedProposal = request.SignedProposal
+	return p.MockPeer.ProcessTransactionProposal(ctx, request)
+}
+
+func newOfflineSigner(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "offline-user"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	creator, err := proto.Marshal(&mb.SerializedIdentity{
+		Mspid:   "Org1MSP",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+	})
+	require.NoError(t, err)
+
+	return key, creator
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, digest []byte) []byte {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	require.NoError(t, err)
+	signature, err := utils.MarshalECDSASignature(r, s)
+	require.NoError(t, err)
+	return signature
+}
+
+func verify(t *testing.T, key *ecdsa.PrivateKey, digest []byte, signature []byte) {
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	assert.True(t, ecdsa.Verify(&key.PublicKey, digest, r, s), "signature verification failed")
+}
@@ -9,6 +9,7 @@ package mocks
 import (
 	"time"
 
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -56,3 +57,21 @@ func (t *MockTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionR
 	defer cancel()
 	return txn.Send(rqtx, tx, t.Orderers)
 }
+
+// SendSignedTransactionProposal sends a proposal that was signed by an external signer to the target peers.
+func (t *MockTransactor) SendSignedTransactionProposal(signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	if t.Err != nil {
+		return nil, t.Err
+	}
+
+	rqtx, cancel := contextImpl.NewRequest(t.Ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+	return txn.SendSignedProposal(rqtx, signedProposal, targets)
+}
+
+// SendSignedEnvelope sends a transaction envelope that was signed by an external signer to the orderers.
+func (t *MockTransactor) SendSignedEnvelope(envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	rqtx, cancel := contextImpl.NewRequest(t.Ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+	return txn.BroadcastEnvelope(rqtx, envelope, t.Orderers)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package policy evaluates signature policies, such as chaincode endorsement policies, on the client.
+// The evaluation follows the semantics of Fabric's cauthdsl evaluator: a signature policy is compiled
+// into a tree of N-out-of rules and signed-by principals, and each identity may be used to satisfy only
+// one principal of the policy. Unlike cauthdsl, the evaluator also reports which principals are missing
+// so that under-endorsed transactions can be diagnosed before they're submitted.
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
+// PrincipalEvaluator determines whether a serialized identity satisfies an MSP principal.
+// The channel membership implements this interface.
+type PrincipalEvaluator interface {
+	// SatisfiesPrincipal returns nil if the identity satisfies the principal
+	SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error
+}
+
+// Result contains the result of the evaluation of a signature policy
+type Result struct {
+	// Satisfied is true if the identities satisfy the policy
+	Satisfied bool
+	// Policy is a description of the policy, e.g. OutOf(2, 'Org1MSP.peer', 'Org2MSP.peer')
+	Policy string
+	// Missing contains the principals that were not satisfied by any of the identities
+	Missing []string
+}
+
+// String returns a description of the result
+func (r *Result) String() string {
+	if r.Satisfied {
+		return fmt.Sprintf("policy %s is satisfied", r.Policy)
+	}
+	return fmt.Sprintf("policy %s is not satisfied - missing principals %s", r.Policy, r.Missing)
+}
+
+type evaluator func(identities [][]byte, used []bool) (bool, []string)
+
+// Evaluate evaluates the signature policy against the given serialized identities
+func Evaluate(policy *common.SignaturePolicyEnvelope, identities [][]byte, pe PrincipalEvaluator) (*Result, error) {
+	if policy == nil || policy.Rule == nil {
+		return nil, errors.New("signature policy is empty")
+	}
+
+	eval, err := compile(policy.Rule, policy.Identities, pe)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := describe(policy.Rule, policy.Identities)
+	if err != nil {
+		return nil, err
+	}
+
+	identities = deduplicate(identities)
+	satisfied, missing := eval(identities, make([]bool, len(identities)))
+
+	return &Result{
+		Satisfied: satisfied,
+		Policy:    description,
+		Missing:   unique(missing),
+	}, nil
+}
+
+func compile(policy *common.SignaturePolicy, principals []*mb.MSPPrincipal, pe PrincipalEvaluator) (evaluator, error) {
+	switch t := policy.Type.(type) {
+	case *common.SignaturePolicy_NOutOf_:
+		return compileNOutOf(t.NOutOf, principals, pe)
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || t.SignedBy >= int32(len(principals)) {
+			return nil, errors.Errorf("identity index out of range, requested %d, but identities length is %d", t.SignedBy, len(principals))
+		}
+		return signedBy(principals[t.SignedBy], pe)
+	default:
+		return nil, errors.Errorf("unknown signature policy type: %T", t)
+	}
+}
+
+func compileNOutOf(policy *common.SignaturePolicy_NOutOf, principals []*mb.MSPPrincipal, pe PrincipalEvaluator) (evaluator, error) {
+	rules := make([]evaluator, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		eval, err := compile(rule, principals, pe)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = eval
+	}
+
+	return func(identities [][]byte, used []bool) (bool, []string) {
+		var verified int32
+		var missing []string
+
+		// Identities are only marked as used if the rule as a whole is satisfied
+		_used := make([]bool, len(used))
+		copy(_used, used)
+		for _, rule := range rules {
+			ok, m := rule(identities, _used)
+			if ok {
+				verified++
+			} else {
+				missing = append(missing, m...)
+			}
+		}
+
+		if verified >= policy.N {
+			copy(used, _used)
+			return true, nil
+		}
+		return false, missing
+	}, nil
+}
+
+func signedBy(principal *mb.MSPPrincipal, pe PrincipalEvaluator) (evaluator, error) {
+	name, err := principalString(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(identities [][]byte, used []bool) (bool, []string) {
+		for i, id := range identities {
+			if used[i] {
+				continue
+			}
+			if err := pe.SatisfiesPrincipal(id, principal); err == nil {
+				used[i] = true
+				return true, nil
+			}
+		}
+		return false, []string{name}
+	}, nil
+}
+
+func describe(policy *common.SignaturePolicy, principals []*mb.MSPPrincipal) (string, error) {
+	switch t := policy.Type.(type) {
+	case *common.SignaturePolicy_NOutOf_:
+		rules := make([]string, len(t.NOutOf.Rules))
+		for i, rule := range t.NOutOf.Rules {
+			s, err := describe(rule, principals)
+			if err != nil {
+				return "", err
+			}
+			rules[i] = s
+		}
+		return fmt.Sprintf("OutOf(%d, %s)", t.NOutOf.N, strings.Join(rules, ", ")), nil
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || t.SignedBy >= int32(len(principals)) {
+			return "", errors.Errorf("identity index out of range, requested %d, but identities length is %d", t.SignedBy, len(principals))
+		}
+		s, err := principalString(principals[t.SignedBy])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("'%s'", s), nil
+	default:
+		return "", errors.Errorf("unknown signature policy type: %T", t)
+	}
+}
+
+// principalString returns the principal in the notation of the policy DSL, e.g. Org1MSP.peer
+func principalString(principal *mb.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return "", errors.Wrap(err, "error unmarshalling MSP role")
+		}
+		return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return "", errors.Wrap(err, "error unmarshalling organization unit")
+		}
+		return fmt.Sprintf("%s.OU(%s)", ou.MspIdentifier, ou.OrganizationalUnitIdentifier), nil
+	case mb.MSPPrincipal_IDENTITY:
+		id := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, id); err != nil {
+			return "", errors.Wrap(err, "error unmarshalling serialized identity")
+		}
+		return fmt.Sprintf("%s.identity", id.Mspid), nil
+	default:
+		return principal.PrincipalClassification.String(), nil
+	}
+}
+
+// deduplicate removes duplicate identities so that an identity can't satisfy more than one principal
+func deduplicate(identities [][]byte) [][]byte {
+	var result [][]byte
+	for _, id := range identities {
+		duplicate := false
+		for _, existing := range result {
+			if bytes.Equal(id, existing) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func unique(values []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/policydsl"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	pe := &mspIDEvaluator{}
+
+	org1 := newIdentity(t, "Org1MSP", "peer1")
+	org1Peer2 := newIdentity(t, "Org1MSP", "peer2")
+	org2 := newIdentity(t, "Org2MSP", "peer1")
+	org3 := newIdentity(t, "Org3MSP", "peer1")
+
+	policy, err := policydsl.FromString("OR('Org1MSP.peer', AND('Org2MSP.peer', 'Org3MSP.peer'))")
+	require.NoError(t, err)
+
+	t.Run("Satisfied", func(t *testing.T) {
+		result, err := Evaluate(policy, [][]byte{org1}, pe)
+		require.NoError(t, err)
+		assert.True(t, result.Satisfied)
+		assert.Empty(t, result.Missing)
+
+		result, err = Evaluate(policy, [][]byte{org2, org3}, pe)
+		require.NoError(t, err)
+		assert.True(t, result.Satisfied)
+	})
+
+	t.Run("Not satisfied", func(t *testing.T) {
+		result, err := Evaluate(policy, [][]byte{org2}, pe)
+		require.NoError(t, err)
+		assert.False(t, result.Satisfied)
+		assert.Equal(t, []string{"Org1MSP.peer", "Org3MSP.peer"}, result.Missing)
+		assert.Equal(t, "OutOf(1, 'Org1MSP.peer', OutOf(2, 'Org2MSP.peer', 'Org3MSP.peer'))", result.Policy)
+		assert.Contains(t, result.String(), "missing principals [Org1MSP.peer Org3MSP.peer]")
+	})
+
+	t.Run("Identity used once", func(t *testing.T) {
+		policy, err := policydsl.FromString("AND('Org1MSP.peer', 'Org1MSP.peer')")
+		require.NoError(t, err)
+
+		result, err := Evaluate(policy, [][]byte{org1, org1}, pe)
+		require.NoError(t, err)
+		assert.False(t, result.Satisfied, "expecting duplicate endorsements to count once")
+		assert.Equal(t, []string{"Org1MSP.peer"}, result.Missing)
+
+		result, err = Evaluate(policy, [][]byte{org1, org1Peer2}, pe)
+		require.NoError(t, err)
+		assert.True(t, result.Satisfied)
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		_, err := Evaluate(nil, [][]byte{org1}, pe)
+		assert.Error(t, err)
+
+		policy, err := policydsl.FromString("AND('Org1MSP.peer', 'Org2MSP.peer')")
+		require.NoError(t, err)
+		policy.Identities = policy.Identities[:1]
+
+		_, err = Evaluate(policy, [][]byte{org1}, pe)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "identity index out of range")
+	})
+}
+
+// mspIDEvaluator satisfies role principals if the MSP ID of the identity matches
+type mspIDEvaluator struct{}
+
+func (e *mspIDEvaluator) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	id := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, id); err != nil {
+		return err
+	}
+
+	role := &mb.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+
+	if id.Mspid != role.MspIdentifier {
+		return errors.Errorf("identity of MSP [%s] does not satisfy principal of MSP [%s]", id.Mspid, role.MspIdentifier)
+	}
+	return nil
+}
+
+func newIdentity(t *testing.T, mspID, name string) []byte {
+	id, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(name)})
+	require.NoError(t, err)
+	return id
+}
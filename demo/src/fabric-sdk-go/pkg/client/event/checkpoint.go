@@ -0,0 +1,239 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/checkpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// checkpointedReg forwards the events of a registration to the caller and records a checkpoint
+// for each event once it has been processed. An event is considered to be processed when the
+// caller receives the next event from the channel or, in ExactlyOnce mode, as soon as the caller
+// receives the event.
+type checkpointedReg struct {
+	reg         fab.Registration
+	es          fab.EventService
+	resumed     bool // es was created to resume the registration and is closed with it
+	exactlyOnce bool
+	tracker     *checkpoint.Tracker
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// RegisterCheckpointedChaincodeEvent registers for chaincode events and records the position of the last
+// processed event using the client's Checkpointer (see WithCheckpointer). If a checkpoint exists for the
+// registration ID then events are replayed from the checkpoint, otherwise events are received from the
+// client's event service. An event is considered to be processed when the next event is received from
+// the channel, so the last event received before a restart is delivered again, unless the ExactlyOnce
+// delivery mode is used in which case an event is recorded as soon as it is received from the channel.
+// Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  registrationID uniquely identifies the registration on the channel across restarts
+//  ccID is the chaincode ID for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterCheckpointedChaincodeEvent(registrationID, ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	r, err := c.newCheckpointedReg(registrationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reg, eventch, err := r.es.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		r.closeEventService()
+		return nil, nil, err
+	}
+	r.reg = reg
+
+	out := make(chan *fab.CCEvent)
+	go r.forwardCCEvents(eventch, out)
+
+	return r, out, nil
+}
+
+// RegisterCheckpointedBlockEvent registers for block events and records the number of the last processed
+// block using the client's Checkpointer (see WithCheckpointer). If a checkpoint exists for the registration ID
+// then blocks are replayed from the block following the checkpoint. A block is considered to be processed when
+// the next block is received from the channel (or as soon as it is received in ExactlyOnce mode). If the caller does not have permission to register for block
+// events then an error is returned. Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  registrationID uniquely identifies the registration on the channel across restarts
+//  filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterCheckpointedBlockEvent(registrationID string, filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	r, err := c.newCheckpointedReg(registrationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reg, eventch, err := r.es.RegisterBlockEvent(filter...)
+	if err != nil {
+		r.closeEventService()
+		return nil, nil, err
+	}
+	r.reg = reg
+
+	out := make(chan *fab.BlockEvent)
+	go r.forwardBlockEvents(eventch, out)
+
+	return r, out, nil
+}
+
+// newCheckpointedReg loads the registration's checkpoint and selects the event service from which its events are received
+func (c *Client) newCheckpointedReg(registrationID string) (*checkpointedReg, error) {
+	if c.checkpointer == nil {
+		return nil, errors.New("checkpointer not configured")
+	}
+
+	tracker, err := checkpoint.NewTracker(c.checkpointer, c.channelID, registrationID, c.deliveryMode)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &checkpointedReg{
+		es:          c.eventService,
+		exactlyOnce: c.deliveryMode == checkpoint.ExactlyOnce,
+		tracker:     tracker,
+		done:        make(chan struct{}),
+	}
+
+	fromBlock, ok := tracker.ResumeBlock()
+	if !ok {
+		return r, nil
+	}
+
+	logger.Debugf("Resuming registration [%s] from block %d", registrationID, fromBlock)
+
+	var opts []options.Opt
+	if c.permitPvtData {
+		opts = append(opts, client.WithBlockAndPrivateData())
+	} else if c.permitBlockEvents {
+		opts = append(opts, client.WithBlockEvents())
+	}
+	opts = append(opts, deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(fromBlock))
+
+	// The channel service does not cache event services that seek from a given block, so each resumed
+	// registration receives its events on its own deliver stream
+	es, err := c.channelService.EventService(opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "event service creation failed")
+	}
+	r.es = es
+	r.resumed = true
+
+	return r, nil
+}
+
+func (r *checkpointedReg) forwardCCEvents(eventch <-chan *fab.CCEvent, out chan<- *fab.CCEvent) {
+	defer close(out)
+
+	var last *fab.CCEvent
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				return
+			}
+			if r.tracker.TxProcessed(event.BlockNumber, event.TxID) {
+				logger.Debugf("Skipping chaincode event from block %d, transaction %s since it has already been processed", event.BlockNumber, event.TxID)
+				continue
+			}
+			select {
+			case out <- event:
+			case <-r.done:
+				return
+			}
+			if r.exactlyOnce {
+				// The event is recorded as soon as it's delivered so that it isn't delivered again after a restart
+				r.recordTx(event)
+				continue
+			}
+			if last != nil {
+				r.recordTx(last)
+			}
+			last = event
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *checkpointedReg) forwardBlockEvents(eventch <-chan *fab.BlockEvent, out chan<- *fab.BlockEvent) {
+	defer close(out)
+
+	var last *fab.BlockEvent
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				return
+			}
+			if r.tracker.BlockProcessed(event.Block.Header.Number) {
+				logger.Debugf("Skipping block %d since it has already been processed", event.Block.Header.Number)
+				continue
+			}
+			select {
+			case out <- event:
+			case <-r.done:
+				return
+			}
+			if r.exactlyOnce {
+				// The block is recorded as soon as it's delivered so that it isn't delivered again after a restart
+				r.recordBlock(event)
+				continue
+			}
+			if last != nil {
+				r.recordBlock(last)
+			}
+			last = event
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *checkpointedReg) recordTx(event *fab.CCEvent) {
+	if err := r.tracker.RecordTx(event.BlockNumber, event.TxID); err != nil {
+		logger.Warnf("Failed to record checkpoint: %s", err)
+	}
+}
+
+func (r *checkpointedReg) recordBlock(event *fab.BlockEvent) {
+	if err := r.tracker.RecordBlock(event.Block.Header.Number); err != nil {
+		logger.Warnf("Failed to record checkpoint: %s", err)
+	}
+}
+
+func (r *checkpointedReg) close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		r.es.Unregister(r.reg)
+		r.closeEventService()
+	})
+}
+
+// closeEventService closes the event service if it was created to resume the registration
+func (r *checkpointedReg) closeEventService() {
+	if r.resumed {
+		closeEventService(r.es)
+	}
+}
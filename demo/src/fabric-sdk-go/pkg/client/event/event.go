@@ -15,21 +15,36 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/checkpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/overflow"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
 // Client enables access to a channel events on a Fabric network.
 type Client struct {
 	eventService      fab.EventService
+	channelService    fab.ChannelService
 	permitBlockEvents bool
+	permitPvtData     bool
 	fromBlock         uint64
+	stopBlock         uint64
+	hasStopBlock      bool
 	seekType          seek.Type
+	mutex             sync.Mutex
+	replays           map[fab.Registration]fab.EventService
+	channelID         string
+	checkpointer      checkpoint.Checkpointer
+	deliveryMode      checkpoint.DeliveryMode
+	metrics           *metrics.ClientMetrics
 }
 
 // New returns a Client instance. Client receives events such as block, filtered block,
@@ -54,26 +69,34 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.New("channel service not initialized")
 	}
 
-	var es fab.EventService
-	if eventClient.permitBlockEvents {
-		var opts []options.Opt
-		opts = append(opts, client.WithBlockEvents())
-		if eventClient.seekType != "" {
-			opts = append(opts, deliverclient.WithSeekType(eventClient.seekType))
-			if eventClient.seekType == seek.FromBlock {
-				opts = append(opts, deliverclient.WithBlockNum(eventClient.fromBlock))
-			}
+	var esOpts []options.Opt
+	if eventClient.permitPvtData {
+		esOpts = append(esOpts, client.WithBlockAndPrivateData())
+	} else if eventClient.permitBlockEvents {
+		esOpts = append(esOpts, client.WithBlockEvents())
+	}
+	if eventClient.seekType != "" {
+		esOpts = append(esOpts, deliverclient.WithSeekType(eventClient.seekType))
+		if eventClient.seekType == seek.FromBlock {
+			esOpts = append(esOpts, deliverclient.WithBlockNum(eventClient.fromBlock))
 		}
-		es, err = channelContext.ChannelService().EventService(opts...)
-	} else {
-		es, err = channelContext.ChannelService().EventService()
+		if eventClient.hasStopBlock {
+			esOpts = append(esOpts, deliverclient.WithStopBlockNum(eventClient.stopBlock))
+		}
+	} else if eventClient.hasStopBlock {
+		return nil, errors.New("a stop block may only be specified with seek type oldest or from")
 	}
 
+	es, err := channelContext.ChannelService().EventService(esOpts...)
+
 	if err != nil {
 		return nil, errors.WithMessage(err, "event service creation failed")
 	}
 
 	eventClient.eventService = es
+	eventClient.channelService = channelContext.ChannelService()
+	eventClient.channelID = channelContext.ChannelID()
+	eventClient.metrics = channelContext.GetMetrics()
 
 	return &eventClient, nil
 }
@@ -86,25 +109,186 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
-	return c.eventService.RegisterBlockEvent(filter...)
+	return c.registerBlockEvent(&registrationOptions{}, filter...)
+}
+
+func (c *Client) registerBlockEvent(opts *registrationOptions, filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	reg, eventch, err := c.eventService.RegisterBlockEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(c.eventService, reg, blockEventType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, blockEventType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.BlockEvent), nil
+}
+
+// RegisterBlockAndPrivateDataEvent registers for block events which include the private data of each
+// transaction. If the caller does not have permission to register for block and private data events
+// (see WithBlockAndPrivateData) then an error is returned. Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	return c.registerBlockAndPrivateDataEvent(&registrationOptions{}, filter...)
+}
+
+func (c *Client) registerBlockAndPrivateDataEvent(opts *registrationOptions, filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	reg, eventch, err := c.eventService.RegisterBlockAndPrivateDataEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(c.eventService, reg, blockAndPvtDataType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, blockAndPvtDataType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.BlockAndPrivateDataEvent), nil
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  filter is an optional filter that filters out unwanted events, e.g. eventfilter.FilteredBlock(...). (Note: Only one filter may be specified.)
+//
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
-func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
-	return c.eventService.RegisterFilteredBlockEvent()
+func (c *Client) RegisterFilteredBlockEvent(filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return c.registerFilteredBlockEvent(&registrationOptions{}, filter...)
+}
+
+func (c *Client) registerFilteredBlockEvent(opts *registrationOptions, filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	reg, eventch, err := c.eventService.RegisterFilteredBlockEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(c.eventService, reg, filteredBlockEventType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, filteredBlockEventType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.FilteredBlockEvent), nil
 }
 
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
 //  Parameters:
 //  ccID is the chaincode ID for which events are to be received
 //  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//  filter is an optional filter on the transaction that emitted the event (see package eventfilter). (Note: Only one filter may be specified.)
 //
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
-func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
-	return c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return c.registerChaincodeEvent(&registrationOptions{}, ccID, eventFilter, filter...)
+}
+
+func (c *Client) registerChaincodeEvent(opts *registrationOptions, ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
+	reg, eventch, err := c.eventService.RegisterChaincodeEvent(ccID, eventFilter, filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(c.eventService, reg, chaincodeEventType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, chaincodeEventType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.CCEvent), nil
+}
+
+// RegisterChaincodeEventRange replays the chaincode events that were committed in the given range of blocks.
+// The events are received from a dedicated event service which is independent of the client's seek options.
+// After the events of the last block in the range have been delivered the event channel is closed.
+// Unregister must be called when the registration is no longer needed (including after the channel has been closed).
+//  Parameters:
+//  ccID is the chaincode ID for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//  fromBlock is the first block of the range
+//  toBlock is the last block of the range (inclusive)
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed after the events of
+//  block toBlock have been delivered or when Unregister is called.
+func (c *Client) RegisterChaincodeEventRange(ccID, eventFilter string, fromBlock, toBlock uint64) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return c.registerChaincodeEventRange(&registrationOptions{}, ccID, eventFilter, fromBlock, toBlock)
+}
+
+func (c *Client) registerChaincodeEventRange(opts *registrationOptions, ccID, eventFilter string, fromBlock, toBlock uint64) (fab.Registration, <-chan *fab.CCEvent, error) {
+	if toBlock < fromBlock {
+		return nil, nil, errors.Errorf("toBlock %d must not be less than fromBlock %d", toBlock, fromBlock)
+	}
+	if c.channelService == nil {
+		return nil, nil, errors.New("channel service not initialized")
+	}
+
+	var esOpts []options.Opt
+	if c.permitPvtData {
+		esOpts = append(esOpts, client.WithBlockAndPrivateData())
+	} else if c.permitBlockEvents {
+		esOpts = append(esOpts, client.WithBlockEvents())
+	}
+	esOpts = append(esOpts, deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(fromBlock), deliverclient.WithStopBlockNum(toBlock))
+
+	es, err := c.channelService.EventService(esOpts...)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "event service creation failed")
+	}
+
+	reg, eventch, err := es.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		closeEventService(es)
+		return nil, nil, err
+	}
+
+	c.mutex.Lock()
+	if c.replays == nil {
+		c.replays = make(map[fab.Registration]fab.EventService)
+	}
+	c.replays[reg] = es
+	c.mutex.Unlock()
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(es, reg, chaincodeEventType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, chaincodeEventType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.CCEvent), nil
 }
 
 // RegisterTxStatusEvent registers for transaction status events. Unregister must be called when the registration is no longer needed.
@@ -114,12 +298,97 @@ func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registrat
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
-	return c.eventService.RegisterTxStatusEvent(txID)
+	return c.registerTxStatusEvent(&registrationOptions{}, txID)
+}
+
+func (c *Client) registerTxStatusEvent(opts *registrationOptions, txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	reg, eventch, err := c.eventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.overflowPolicy == overflow.Block {
+		if err := c.setDropHandler(c.eventService, reg, txStatusEventType, opts); err != nil {
+			return nil, nil, err
+		}
+		return reg, eventch, nil
+	}
+
+	r, out, err := c.newOverflowReg(reg, eventch, txStatusEventType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, out.(chan *fab.TxStatusEvent), nil
 }
 
 // Unregister removes the given registration and closes the event channel.
 //  Parameters:
 //  reg is the registration handle that was returned from one of the Register functions
 func (c *Client) Unregister(reg fab.Registration) {
+	if r, ok := reg.(*checkpointedReg); ok {
+		r.close()
+		return
+	}
+
+	if r, ok := reg.(*overflowReg); ok {
+		// Close the forwarder first so that the dispatcher isn't blocked by undelivered events
+		r.forwarder.Close()
+		reg = r.reg
+	}
+
+	c.mutex.Lock()
+	es, ok := c.replays[reg]
+	delete(c.replays, reg)
+	c.mutex.Unlock()
+
+	if ok {
+		es.Unregister(reg)
+		closeEventService(es)
+		return
+	}
+
 	c.eventService.Unregister(reg)
 }
+
+// RegisterConnectionLifecycleEvent registers for connection lifecycle events of the channel's event client,
+// i.e. when the event client connects to, disconnects from or switches peers, or when the connected peer
+// lags behind the other peers in the channel. Each event includes the peer resolver's reasoning.
+// The lag is checked with the event service's PeerMonitorPeriod (or its default if the peer monitor is disabled,
+// e.g. for the Balanced strategy) only while there are registrations, and is not checked if BlockHeightLagThreshold
+// is negative.
+// Unregister must be called when the registration is no longer needed.
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterConnectionLifecycleEvent() (fab.Registration, <-chan *fab.ConnectionLifecycleEvent, error) {
+	es, ok := c.eventService.(lifecycleEventService)
+	if !ok {
+		return nil, nil, errors.New("event service does not support connection lifecycle events")
+	}
+	return es.RegisterConnectionLifecycleEvent()
+}
+
+// Status returns the health of the channel's event client, including the connected peer,
+// the number of blocks by which the client is lagging and the peer resolver's reasoning.
+func (c *Client) Status() (*fab.EventClientStatus, error) {
+	es, ok := c.eventService.(lifecycleEventService)
+	if !ok {
+		return nil, errors.New("event service does not report status")
+	}
+	return es.Status()
+}
+
+type lifecycleEventService interface {
+	RegisterConnectionLifecycleEvent() (fab.Registration, <-chan *fab.ConnectionLifecycleEvent, error)
+	Status() (*fab.EventClientStatus, error)
+}
+
+// closeEventService closes an event service that was created for a single registration
+func closeEventService(es fab.EventService) {
+	if c, ok := es.(closable); ok {
+		c.Close()
+	}
+}
+
+type closable interface {
+	Close()
+}
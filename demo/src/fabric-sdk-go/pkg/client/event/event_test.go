@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
+	"io/ioutil"
 	"math"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,7 +23,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/checkpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/overflow"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
@@ -55,6 +60,48 @@ func TestNewEventClient(t *testing.T) {
 	}
 }
 
+func TestNewEventClientWithStopBlock(t *testing.T) {
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	_, err := New(ctx, WithStopBlockNum(10))
+	assert.Error(t, err, "expecting error with stop block and no seek type")
+
+	_, err = New(ctx, WithSeekType(seek.FromBlock), WithBlockNum(5), WithStopBlockNum(10))
+	assert.NoError(t, err)
+}
+
+func TestChaincodeEventRange(t *testing.T) {
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	_, _, err = client.RegisterChaincodeEventRange("mycc", ".*", 20, 10)
+	assert.Error(t, err, "expecting error with invalid block range")
+
+	reg, eventch, err := client.RegisterChaincodeEventRange("mycc", ".*", 10, 20)
+	if err != nil {
+		t.Fatalf("error registering for chaincode event range: %s", err)
+	}
+	assert.NotNil(t, eventch)
+
+	client.mutex.Lock()
+	_, ok := client.replays[reg]
+	client.mutex.Unlock()
+	assert.True(t, ok, "expecting registration to be tracked as a replay")
+
+	client.Unregister(reg)
+
+	client.mutex.Lock()
+	_, ok = client.replays[reg]
+	client.mutex.Unlock()
+	assert.False(t, ok, "expecting replay registration to be removed")
+}
+
 func TestBlockEvents(t *testing.T) {
 
 	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
@@ -92,6 +139,179 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+func TestOverflowPolicy(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	client.eventService = eventService
+
+	_, err = client.WithRegistrationOptions(WithOverflowPolicy(overflow.DropOldest, 0))
+	assert.Error(t, err, "expecting error for zero buffer size")
+
+	var mutex sync.Mutex
+	var dropped []uint64
+	registrar, err := client.WithRegistrationOptions(WithOverflowPolicy(overflow.DropOldest, 2), WithDropHandler(func(event interface{}, reason error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		dropped = append(dropped, event.(*fab.BlockEvent).Block.Header.Number)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create registrar: %s", err)
+	}
+
+	registration, eventch, err := registrar.RegisterBlockEvent()
+	if err != nil {
+		t.Fatalf("error registering for block events: %s", err)
+	}
+
+	// The consumer doesn't receive any events until all of the blocks have been produced
+	const numBlocks = 5
+	for i := 0; i < numBlocks; i++ {
+		eventProducer.Ledger().NewBlock(channelID)
+	}
+
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(dropped) == numBlocks-2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{0, 1, 2}, dropped)
+
+	for _, expected := range []uint64{3, 4} {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				t.Fatalf("unexpected closed channel")
+			}
+			assert.Equal(t, expected, event.Block.Header.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for block event")
+		}
+	}
+
+	client.Unregister(registration)
+
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting channel to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to be closed")
+	}
+}
+
+func TestBlockPolicyDrops(t *testing.T) {
+	opts := []options.Opt{dispatcher.WithEventConsumerBufferSize(1), dispatcher.WithEventConsumerTimeout(10 * time.Millisecond)}
+	eventService, eventProducer, err := newServiceWithMockProducer(opts, withBlockLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	client.eventService = eventService
+
+	var mutex sync.Mutex
+	var dropped []uint64
+	registrar, err := client.WithRegistrationOptions(WithDropHandler(func(event interface{}, reason error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.Equal(t, dispatcher.ErrEventNotDelivered, reason)
+		dropped = append(dropped, event.(*fab.BlockEvent).Block.Header.Number)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create registrar: %s", err)
+	}
+
+	registration, eventch, err := registrar.RegisterBlockEvent()
+	if err != nil {
+		t.Fatalf("error registering for block events: %s", err)
+	}
+	defer registrar.Unregister(registration)
+
+	// The first event is buffered by the event service and the others time out
+	const numBlocks = 4
+	for i := 0; i < numBlocks; i++ {
+		eventProducer.Ledger().NewBlock(channelID)
+	}
+
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(dropped) == numBlocks-1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{1, 2, 3}, dropped)
+
+	select {
+	case event, ok := <-eventch:
+		if !ok {
+			t.Fatalf("unexpected closed channel")
+		}
+		assert.Equal(t, uint64(0), event.Block.Header.Number)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for block event")
+	}
+}
+
+func TestBlockAndPrivateDataEvents(t *testing.T) {
+
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockAndPrivateDataLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	client, err := New(ctx, WithBlockAndPrivateData())
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+	assert.True(t, client.permitBlockEvents, "block events should be permitted with private data")
+
+	client.eventService = eventService
+
+	registration, eventch, err := client.RegisterBlockAndPrivateDataEvent()
+	if err != nil {
+		t.Fatalf("error registering for block and private data events: %s", err)
+	}
+	defer client.Unregister(registration)
+
+	eventProducer.Ledger().NewBlock(channelID)
+
+	select {
+	case event, ok := <-eventch:
+		if !ok {
+			t.Fatalf("unexpected closed channel")
+		}
+		assert.NotNil(t, event.Block)
+		assert.NotNil(t, event.PrivateDataMap)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for block and private data event")
+	}
+}
+
 func TestFilteredBlockEvents(t *testing.T) {
 
 	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
@@ -266,6 +486,128 @@ func TestCCEvents(t *testing.T) {
 
 }
 
+func TestCheckpointedCCEvents(t *testing.T) {
+	chanID := "mychannel"
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	checkpointer, err := checkpoint.NewFileCheckpointer(dir)
+	if err != nil {
+		t.Fatalf("error creating checkpointer: %s", err)
+	}
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, chanID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+	_, _, err = client.RegisterCheckpointedChaincodeEvent("reg1", "mycc", ".*")
+	assert.Error(t, err, "expecting error registering without a checkpointer")
+
+	client, err = New(ctx, WithCheckpointer(checkpointer, checkpoint.ExactlyOnce))
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+	client.eventService = eventService
+
+	reg, eventch, err := client.RegisterCheckpointedChaincodeEvent("reg1", "mycc", ".*")
+	if err != nil {
+		t.Fatalf("error registering for chaincode events: %s", err)
+	}
+
+	eventProducer.Ledger().NewFilteredBlock(
+		chanID,
+		servicemocks.NewFilteredTxWithCCEvent("txid1", "mycc", "event1"),
+		servicemocks.NewFilteredTxWithCCEvent("txid2", "mycc", "event2"),
+		servicemocks.NewFilteredTxWithCCEvent("txid3", "mycc", "event3"),
+	)
+
+	for _, txID := range []string{"txid1", "txid2", "txid3"} {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				t.Fatal("unexpected closed channel")
+			}
+			assert.Equal(t, txID, event.TxID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for CC event for %s", txID)
+		}
+	}
+
+	// In ExactlyOnce mode the last event that was received is recorded without waiting for the next event
+	assert.Eventually(t, func() bool {
+		cp, err := checkpointer.Load(chanID, "reg1")
+		return err == nil && cp != nil && len(cp.TransactionIDs) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cp, err := checkpointer.Load(chanID, "reg1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"txid1", "txid2", "txid3"}, cp.TransactionIDs)
+	assert.Equal(t, 2, cp.EventIndex)
+
+	client.Unregister(reg)
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting channel to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+
+	// Registering again resumes from the checkpoint using a new event service, which is closed on unregister
+	resumeService := &closableEventService{EventService: fcmocks.NewMockEventService()}
+	client.channelService = &eventServiceProvider{ChannelService: client.channelService, es: resumeService}
+
+	reg, _, err = client.RegisterCheckpointedChaincodeEvent("reg1", "mycc", ".*")
+	if err != nil {
+		t.Fatalf("error registering for chaincode events: %s", err)
+	}
+	assert.Equal(t, resumeService, reg.(*checkpointedReg).es)
+	assert.False(t, resumeService.isClosed())
+
+	client.Unregister(reg)
+	assert.True(t, resumeService.isClosed(), "expecting resume event service to be closed")
+}
+
+// eventServiceProvider is a channel service which provides the given event service
+type eventServiceProvider struct {
+	fab.ChannelService
+	es fab.EventService
+}
+
+func (p *eventServiceProvider) EventService(opts ...options.Opt) (fab.EventService, error) {
+	return p.es, nil
+}
+
+type closableEventService struct {
+	fab.EventService
+	mutex  sync.Mutex
+	closed bool
+}
+
+func (s *closableEventService) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+}
+
+func (s *closableEventService) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
+
 func validateCCEvents(t *testing.T, eventProducer *servicemocks.MockProducer, eventch1 <-chan *fab.CCEvent, eventch2 <-chan *fab.CCEvent, chanID string, ccID1 string, ccID2 string) {
 	event1 := "event1"
 	event2 := "event2"
@@ -406,6 +748,12 @@ func withBlockLedger(source string) producerOpt {
 	}
 }
 
+func withBlockAndPrivateDataLedger(source string) producerOpt {
+	return func(opts *producerOpts) {
+		opts.ledger = servicemocks.NewMockLedger(servicemocks.BlockAndPrivateDataEventFactory, source)
+	}
+}
+
 func withFilteredBlockLedger(source string) producerOpt {
 	return func(opts *producerOpts) {
 		opts.ledger = servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, source)
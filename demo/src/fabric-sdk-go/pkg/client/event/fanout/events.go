@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fanout
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/eventfilter"
+)
+
+// Event types
+const (
+	// BlockEventType is the type of an event which summarizes a committed block
+	BlockEventType = "block"
+	// TxStatusEventType is the type of an event which contains the status of a committed transaction
+	TxStatusEventType = "txstatus"
+	// ChaincodeEventType is the type of an event which contains a chaincode event
+	ChaincodeEventType = "chaincode"
+)
+
+// Event is the JSON representation of an event that is published to subscribers
+type Event struct {
+	Type           string          `json:"type"`
+	ChannelID      string          `json:"channelId"`
+	BlockNumber    uint64          `json:"blockNumber"`
+	SourceURL      string          `json:"sourceUrl,omitempty"`
+	Block          *Block          `json:"block,omitempty"`
+	Transaction    *Transaction    `json:"transaction,omitempty"`
+	ChaincodeEvent *ChaincodeEvent `json:"chaincodeEvent,omitempty"`
+
+	// The decoded transactions are used for filtering
+	txs []*fab.TransactionInfo
+}
+
+// Block summarizes a committed block
+type Block struct {
+	Number       uint64         `json:"number"`
+	DataHash     string         `json:"dataHash"`
+	PreviousHash string         `json:"previousHash"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// Transaction contains the attributes of a committed transaction
+type Transaction struct {
+	TxID           string     `json:"txId"`
+	ValidationCode string     `json:"validationCode"`
+	CreatorMSPID   string     `json:"creatorMspId,omitempty"`
+	ChaincodeID    string     `json:"chaincodeId,omitempty"`
+	FunctionName   string     `json:"functionName,omitempty"`
+	Timestamp      *time.Time `json:"timestamp,omitempty"`
+}
+
+// ChaincodeEvent contains an event that was set by a chaincode. The payload is base64 encoded.
+type ChaincodeEvent struct {
+	ChaincodeID string `json:"chaincodeId"`
+	EventName   string `json:"eventName"`
+	Payload     []byte `json:"payload,omitempty"`
+}
+
+// eventsFromBlock returns the block event followed by the transaction status
+// and chaincode events of each transaction in the block
+func eventsFromBlock(channelID string, blockEvent *fab.BlockEvent) []*Event {
+	block := blockEvent.Block
+	if block == nil || block.Header == nil {
+		return nil
+	}
+
+	txs := eventfilter.TransactionsFromBlock(block)
+
+	summary := &Block{
+		Number:       block.Header.Number,
+		DataHash:     hex.EncodeToString(block.Header.DataHash),
+		PreviousHash: hex.EncodeToString(block.Header.PreviousHash),
+		Transactions: make([]*Transaction, 0, len(txs)),
+	}
+
+	events := []*Event{{
+		Type:        BlockEventType,
+		ChannelID:   channelID,
+		BlockNumber: block.Header.Number,
+		SourceURL:   blockEvent.SourceURL,
+		Block:       summary,
+		txs:         txs,
+	}}
+
+	for _, tx := range txs {
+		transaction := newTransaction(tx)
+		summary.Transactions = append(summary.Transactions, transaction)
+
+		events = append(events, &Event{
+			Type:        TxStatusEventType,
+			ChannelID:   channelID,
+			BlockNumber: block.Header.Number,
+			SourceURL:   blockEvent.SourceURL,
+			Transaction: transaction,
+			txs:         []*fab.TransactionInfo{tx},
+		})
+
+		if tx.ChaincodeEvent != nil {
+			events = append(events, &Event{
+				Type:        ChaincodeEventType,
+				ChannelID:   channelID,
+				BlockNumber: block.Header.Number,
+				SourceURL:   blockEvent.SourceURL,
+				Transaction: transaction,
+				ChaincodeEvent: &ChaincodeEvent{
+					ChaincodeID: tx.ChaincodeEvent.ChaincodeId,
+					EventName:   tx.ChaincodeEvent.EventName,
+					Payload:     tx.ChaincodeEvent.Payload,
+				},
+				txs: []*fab.TransactionInfo{tx},
+			})
+		}
+	}
+
+	return events
+}
+
+func newTransaction(tx *fab.TransactionInfo) *Transaction {
+	transaction := &Transaction{
+		TxID:           tx.TxID,
+		ValidationCode: tx.ValidationCode.String(),
+		CreatorMSPID:   tx.CreatorMSPID,
+		ChaincodeID:    tx.ChaincodeID,
+		FunctionName:   tx.FunctionName,
+	}
+	if !tx.Timestamp.IsZero() {
+		timestamp := tx.Timestamp
+		transaction.Timestamp = &timestamp
+	}
+	return transaction
+}
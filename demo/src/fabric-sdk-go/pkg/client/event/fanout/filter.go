@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fanout
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/eventfilter"
+	"github.com/pkg/errors"
+)
+
+// Query parameters of a subscription request
+const (
+	channelParam    = "channel"
+	typesParam      = "types"
+	chaincodeParam  = "chaincode"
+	eventNameParam  = "event"
+	txIDParam       = "txid"
+	mspIDParam      = "mspid"
+	functionParam   = "fn"
+	statusParam     = "status"
+	fromBlockParam  = "fromBlock"
+	tokenParam      = "token"
+	lastEventHeader = "Last-Event-ID"
+)
+
+// subscription describes the events requested by a subscriber
+type subscription struct {
+	channelID    string
+	types        map[string]bool
+	txFilter     fab.TxFilter
+	eventFilter  *regexp.Regexp
+	fromBlock    uint64
+	hasFromBlock bool
+}
+
+// parseSubscription creates a subscription from the query parameters of the request:
+//  channel   - the channel ID (required)
+//  types     - a comma separated list of event types (block, txstatus, chaincode). All types by default.
+//  chaincode - only transactions of the given chaincode
+//  event     - only chaincode events whose name matches the given regular expression
+//  txid      - only the transaction with the given ID
+//  mspid     - only transactions created by the given MSP
+//  fn        - only transactions which invoke the given chaincode function
+//  status    - only transactions with the given validation code, e.g. VALID or MVCC_READ_CONFLICT
+//  fromBlock - replay events from the given block
+// Block events are published if at least one of the block's transactions matches the transaction filters.
+func parseSubscription(query url.Values) (*subscription, error) {
+	sub := &subscription{
+		channelID: query.Get(channelParam),
+		types:     map[string]bool{BlockEventType: true, TxStatusEventType: true, ChaincodeEventType: true},
+	}
+	if sub.channelID == "" {
+		return nil, errors.New("channel is required")
+	}
+
+	if types := query.Get(typesParam); types != "" {
+		sub.types = make(map[string]bool)
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if t != BlockEventType && t != TxStatusEventType && t != ChaincodeEventType {
+				return nil, errors.Errorf("invalid event type [%s]", t)
+			}
+			sub.types[t] = true
+		}
+	}
+
+	txFilter, err := parseTxFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	sub.txFilter = txFilter
+
+	if eventName := query.Get(eventNameParam); eventName != "" {
+		sub.eventFilter, err = regexp.Compile(eventName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid event filter [%s]", eventName)
+		}
+	}
+
+	if fromBlock := query.Get(fromBlockParam); fromBlock != "" {
+		sub.fromBlock, err = strconv.ParseUint(fromBlock, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid block number [%s]", fromBlock)
+		}
+		sub.hasFromBlock = true
+	}
+
+	return sub, nil
+}
+
+func parseTxFilter(query url.Values) (fab.TxFilter, error) {
+	var filters []fab.TxFilter
+
+	if ccID := query.Get(chaincodeParam); ccID != "" {
+		filters = append(filters, func(tx *fab.TransactionInfo) bool {
+			return tx.ChaincodeID == ccID
+		})
+	}
+	if txID := query.Get(txIDParam); txID != "" {
+		filters = append(filters, func(tx *fab.TransactionInfo) bool {
+			return tx.TxID == txID
+		})
+	}
+	if mspID := query.Get(mspIDParam); mspID != "" {
+		filters = append(filters, eventfilter.CreatorMSPID(mspID))
+	}
+	if fn := query.Get(functionParam); fn != "" {
+		filters = append(filters, eventfilter.FunctionName(fn))
+	}
+	if status := query.Get(statusParam); status != "" {
+		code, ok := pb.TxValidationCode_value[status]
+		if !ok {
+			return nil, errors.Errorf("invalid validation code [%s]", status)
+		}
+		filters = append(filters, eventfilter.ValidationCode(pb.TxValidationCode(code)))
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return eventfilter.And(filters...), nil
+}
+
+// matches returns true if the event should be published to the subscriber
+func (s *subscription) matches(event *Event) bool {
+	if !s.types[event.Type] {
+		return false
+	}
+	if s.hasFromBlock && event.BlockNumber < s.fromBlock {
+		return false
+	}
+	if event.Type == ChaincodeEventType && s.eventFilter != nil && !s.eventFilter.MatchString(event.ChaincodeEvent.EventName) {
+		return false
+	}
+	if s.txFilter == nil {
+		return true
+	}
+	for _, tx := range event.txs {
+		if s.txFilter(tx) {
+			return true
+		}
+	}
+	return false
+}
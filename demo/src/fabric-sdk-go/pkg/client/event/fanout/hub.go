@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fanout
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// eventSource delivers the block events of a hub
+type eventSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+type closable interface {
+	Close()
+}
+
+// hub holds a single block event registration for a channel and publishes
+// the events derived from each block to its subscribers
+type hub struct {
+	channelID   string
+	source      eventSource
+	reg         fab.Registration
+	mutex       sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+	closeOnce   sync.Once
+	onClose     func(*hub)
+}
+
+func newHub(channelID string, source eventSource, onClose func(*hub)) (*hub, error) {
+	reg, blockch, err := source.RegisterBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to register for block events on channel [%s]", channelID)
+	}
+
+	h := &hub{
+		channelID:   channelID,
+		source:      source,
+		reg:         reg,
+		subscribers: make(map[*subscriber]struct{}),
+		onClose:     onClose,
+	}
+
+	go h.run(blockch)
+
+	return h, nil
+}
+
+// add adds the subscriber to the hub. False is returned if the hub has been closed.
+func (h *hub) add(sub *subscriber) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return false
+	}
+	h.subscribers[sub] = struct{}{}
+	return true
+}
+
+// remove removes the subscriber from the hub and returns the number of remaining subscribers
+func (h *hub) remove(sub *subscriber) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.subscribers, sub)
+	return len(h.subscribers)
+}
+
+// close unregisters from block events. The block channel is closed by the
+// event client, at which point all subscribers are disconnected. An event source
+// which may be closed is dedicated to the hub, so it is closed as well.
+func (h *hub) close() {
+	h.closeOnce.Do(func() {
+		h.source.Unregister(h.reg)
+		if c, ok := h.source.(closable); ok {
+			c.Close()
+		}
+	})
+}
+
+func (h *hub) run(blockch <-chan *fab.BlockEvent) {
+	for blockEvent := range blockch {
+		h.publish(eventsFromBlock(h.channelID, blockEvent))
+	}
+
+	logger.Debugf("Block event channel closed for channel [%s]", h.channelID)
+
+	h.mutex.Lock()
+	h.closed = true
+	subscribers := h.subscribers
+	h.subscribers = nil
+	h.mutex.Unlock()
+
+	for sub := range subscribers {
+		sub.close()
+	}
+
+	if h.onClose != nil {
+		h.onClose(h)
+	}
+}
+
+func (h *hub) publish(events []*Event) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for sub := range h.subscribers {
+		sub.publish(events)
+	}
+}
+
+// subscriber is a single HTTP client. Events are buffered so that a slow subscriber doesn't hold up the
+// other subscribers. A subscriber that falls more than the buffer size behind is disconnected.
+type subscriber struct {
+	*subscription
+	eventch   chan *Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newSubscriber(sub *subscription, bufferSize int) *subscriber {
+	return &subscriber{
+		subscription: sub,
+		eventch:      make(chan *Event, bufferSize),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *subscriber) publish(events []*Event) {
+	for _, e := range events {
+		if !s.matches(e) {
+			continue
+		}
+
+		select {
+		case <-s.done:
+			return
+		case s.eventch <- e:
+		default:
+			logger.Warnf("Disconnecting subscriber on channel [%s] since its event buffer is full", s.channelID)
+			s.close()
+			return
+		}
+	}
+}
+
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
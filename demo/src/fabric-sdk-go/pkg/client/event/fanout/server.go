@@ -0,0 +1,429 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fanout re-publishes the events of Fabric channels to HTTP clients over WebSocket
+// and Server-Sent Events (SSE), so that consumers which are not written in Go don't each
+// have to hold a deliver stream to a peer.
+//
+// The server holds a single block event registration per channel (see package event) which
+// is shared by all subscribers of the channel. Each block is published as a block event
+// followed by a transaction status event for each transaction and a chaincode event for each
+// transaction that set one. Events are published as JSON (see Event).
+//
+// A subscriber connects to the server's handler with the subscription in the query string,
+// e.g. /events?channel=mychannel&types=chaincode&chaincode=mycc&event=transfer.*
+// If the request contains the "Upgrade: websocket" header then events are sent as WebSocket
+// text messages, otherwise they're sent as an SSE stream in which the event ID is the block
+// number. A subscriber may request a replay of events from a given block with the fromBlock
+// parameter (or the SSE Last-Event-ID header) in which case a dedicated deliver stream is
+// opened for the subscriber and closed when the subscriber disconnects. Browsers may only open
+// WebSocket connections from the server's own origin or from an origin that has been allowed
+// with WithAllowedOrigins.
+//  Basic Flow:
+//  1) Create the server with a function that returns the channel context for a channel ID
+//  2) Serve the server's handler, e.g. http.ListenAndServe(":8080", server)
+//  3) Close the server
+package fanout
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultBufferSize        = 100
+	defaultKeepAliveInterval = 15 * time.Second
+)
+
+// ChannelProvider returns the context of the given channel, for example:
+//  func(channelID string) context.ChannelProvider {
+//      return sdk.ChannelContext(channelID, fabsdk.WithUser("User1"))
+//  }
+type ChannelProvider func(channelID string) context.ChannelProvider
+
+// Option configures the server
+type Option func(s *Server)
+
+// WithAuthToken specifies the token that subscribers must present, either in an
+// "Authorization: Bearer <token>" header or in the "token" query parameter (for
+// browser clients, which cannot set headers on WebSocket and SSE requests)
+func WithAuthToken(token string) Option {
+	return func(s *Server) {
+		s.authToken = token
+	}
+}
+
+// WithAllowedOrigins specifies the origins (e.g. https://example.com) of the web pages from which browsers may
+// open WebSocket connections, in addition to the server's own origin
+func WithAllowedOrigins(origins ...string) Option {
+	return func(s *Server) {
+		s.allowedOrigins = append(s.allowedOrigins, origins...)
+	}
+}
+
+// WithBufferSize specifies the number of events that are buffered for each subscriber.
+// A subscriber that falls further behind is disconnected.
+func WithBufferSize(size int) Option {
+	return func(s *Server) {
+		s.bufferSize = size
+	}
+}
+
+// WithKeepAliveInterval specifies the interval at which SSE comments are sent to idle subscribers
+// so that proxies don't close the connection
+func WithKeepAliveInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.keepAliveInterval = interval
+	}
+}
+
+// Server re-publishes channel events to WebSocket and SSE subscribers
+type Server struct {
+	channelProvider   ChannelProvider
+	authToken         string
+	allowedOrigins    []string
+	bufferSize        int
+	keepAliveInterval time.Duration
+	mutex             sync.Mutex
+	hubs              map[string]*hub
+	replays           map[*hub]struct{}
+	closed            bool
+}
+
+// New returns a new event fan-out server
+func New(channelProvider ChannelProvider, opts ...Option) *Server {
+	s := &Server{
+		channelProvider:   channelProvider,
+		bufferSize:        defaultBufferSize,
+		keepAliveInterval: defaultKeepAliveInterval,
+		hubs:              make(map[string]*hub),
+		replays:           make(map[*hub]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Close disconnects all subscribers and unregisters from the event service of each channel
+func (s *Server) Close() {
+	s.mutex.Lock()
+	s.closed = true
+	hubs := s.hubs
+	s.hubs = make(map[string]*hub)
+	replays := s.replays
+	s.replays = make(map[*hub]struct{})
+	s.mutex.Unlock()
+
+	for _, h := range hubs {
+		h.close()
+	}
+	for h := range replays {
+		h.close()
+	}
+}
+
+// ServeHTTP subscribes the client to the events of a channel
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sub, err := parseSubscription(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webSocket := strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+	if webSocket && !s.allowedOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	if !sub.hasFromBlock {
+		if lastEventID := r.Header.Get(lastEventHeader); lastEventID != "" {
+			// The events of the last block may have only partially been received so the block is delivered again
+			if blockNum, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+				sub.fromBlock = blockNum
+				sub.hasFromBlock = true
+			}
+		}
+	}
+
+	subscriber, unsubscribe, err := s.subscribe(sub)
+	if err != nil {
+		logger.Warnf("Failed to subscribe to events on channel [%s]: %s", sub.channelID, err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer unsubscribe()
+
+	if webSocket {
+		s.serveWebSocket(w, r, subscriber)
+		return
+	}
+
+	s.serveSSE(w, r, subscriber)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.authToken == "" {
+		return true
+	}
+
+	token := r.URL.Query().Get(tokenParam)
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) == 1
+}
+
+// allowedOrigin returns true if the request was not sent by a browser (it has no Origin header), or if it
+// was sent from a page of the server's own origin or of an allowed origin
+func (s *Server) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originURL.Host, r.Host) {
+		return true
+	}
+
+	for _, allowed := range s.allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe adds a subscriber to the channel's hub, or to a dedicated hub if a replay was requested.
+// The returned function must be called when the subscriber disconnects.
+func (s *Server) subscribe(sub *subscription) (*subscriber, func(), error) {
+	subscriber := newSubscriber(sub, s.bufferSize)
+
+	if sub.hasFromBlock {
+		h, err := s.replayHub(sub.channelID, sub.fromBlock)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !h.add(subscriber) {
+			h.close()
+			return nil, nil, errors.Errorf("replay of events for channel [%s] has been closed", sub.channelID)
+		}
+
+		return subscriber, func() {
+			subscriber.close()
+			h.close()
+		}, nil
+	}
+
+	h, err := s.hub(sub.channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !h.add(subscriber) {
+		return nil, nil, errors.Errorf("event stream for channel [%s] has been closed", sub.channelID)
+	}
+
+	return subscriber, func() {
+		subscriber.close()
+		h.remove(subscriber)
+	}, nil
+}
+
+// hub returns the shared hub for the given channel
+func (s *Server) hub(channelID string) (*hub, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil, errors.New("server is closed")
+	}
+
+	if h, ok := s.hubs[channelID]; ok {
+		return h, nil
+	}
+
+	eventClient, err := event.New(s.channelProvider(channelID), event.WithBlockEvents())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to create event client for channel [%s]", channelID)
+	}
+
+	h, err := newHub(channelID, eventClient, s.removeHub)
+	if err != nil {
+		return nil, err
+	}
+	s.hubs[channelID] = h
+
+	return h, nil
+}
+
+// replayHub returns a dedicated hub which receives blocks from the given block number. The hub's event
+// service is closed when the hub is closed.
+func (s *Server) replayHub(channelID string, fromBlock uint64) (*hub, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil, errors.New("server is closed")
+	}
+
+	es, err := s.replayEventService(channelID, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := newHub(channelID, es, s.removeHub)
+	if err != nil {
+		if c, ok := es.(closable); ok {
+			c.Close()
+		}
+		return nil, err
+	}
+	s.replays[h] = struct{}{}
+
+	return h, nil
+}
+
+// replayEventService returns a new event service which delivers blocks from the given block number. The channel
+// service does not cache event services that seek from a given block, so the event service isn't shared with other
+// subscribers.
+func (s *Server) replayEventService(channelID string, fromBlock uint64) (fab.EventService, error) {
+	ctx, err := s.channelProvider(channelID)()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to create channel context for channel [%s]", channelID)
+	}
+
+	if ctx.ChannelService() == nil {
+		return nil, errors.New("channel service not initialized")
+	}
+
+	es, err := ctx.ChannelService().EventService(client.WithBlockEvents(), deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(fromBlock))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to create event service for channel [%s]", channelID)
+	}
+
+	return es, nil
+}
+
+// removeHub is invoked when a hub's block event channel has been closed,
+// so that the next subscriber of the channel registers again
+func (s *Server) removeHub(h *hub) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.hubs[h.channelID] == h {
+		delete(s.hubs, h.channelID)
+	}
+	delete(s.replays, h)
+}
+
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request, sub *subscriber) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(s.keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case e := <-sub.eventch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				logger.Errorf("Failed to marshal event: %s", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.BlockNumber, e.Type, data); err != nil {
+				logger.Debugf("Failed to write event to subscriber: %s", err)
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-sub.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request, sub *subscriber) {
+	server := websocket.Server{
+		// The origin has already been checked by ServeHTTP
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// Subscribers don't send messages. A read error means that the connection was closed.
+			closed := make(chan struct{})
+			go func() {
+				_, _ = io.Copy(ioutil.Discard, ws)
+				close(closed)
+			}()
+
+			for {
+				select {
+				case e := <-sub.eventch:
+					if err := websocket.JSON.Send(ws, e); err != nil {
+						logger.Debugf("Failed to send event to subscriber: %s", err)
+						return
+					}
+				case <-sub.done:
+					return
+				case <-closed:
+					return
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(w, r)
+}
@@ -0,0 +1,439 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fanout
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	eventmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/mocks"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+)
+
+const (
+	channelID = "mychannel"
+	ccID      = "mycc"
+	authToken = "secret"
+)
+
+func TestAuthAndValidation(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	resp, err := http.Get(env.url("channel=" + channelID))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "expecting unauthorized without token")
+
+	resp, err = http.Get(env.url("channel=" + channelID + "&token=invalid"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "expecting unauthorized with invalid token")
+
+	for _, query := range []string{"", "channel=" + channelID + "&types=invalid", "channel=" + channelID + "&fromBlock=x", "channel=" + channelID + "&status=invalid"} {
+		req, err := http.NewRequest(http.MethodGet, env.url(query), nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+authToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, "expecting bad request for query [%s]", query)
+	}
+}
+
+func TestSSE(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	stream1 := env.openSSE(t, "channel="+channelID+"&token="+authToken+"&types=txstatus,chaincode")
+	defer stream1.close()
+	stream2 := env.openSSE(t, "channel="+channelID+"&token="+authToken+"&types=block")
+	defer stream2.close()
+
+	env.server.mutex.Lock()
+	require.Len(t, env.server.hubs, 1, "expecting subscribers to share a single registration")
+	env.server.mutex.Unlock()
+
+	env.blocks <- newBlock(5, newTxWithCCEvent("txid1", "transfer", []byte(`{"id":"a1"}`)))
+
+	id, eventType, e := stream1.next(t)
+	require.Equal(t, "5", id)
+	require.Equal(t, TxStatusEventType, eventType)
+	require.Equal(t, channelID, e.ChannelID)
+	require.Equal(t, "txid1", e.Transaction.TxID)
+	require.Equal(t, "VALID", e.Transaction.ValidationCode)
+	require.Equal(t, ccID, e.Transaction.ChaincodeID)
+
+	_, eventType, e = stream1.next(t)
+	require.Equal(t, ChaincodeEventType, eventType)
+	require.Equal(t, "transfer", e.ChaincodeEvent.EventName)
+	require.Equal(t, []byte(`{"id":"a1"}`), e.ChaincodeEvent.Payload)
+
+	_, eventType, e = stream2.next(t)
+	require.Equal(t, BlockEventType, eventType)
+	require.Equal(t, uint64(5), e.Block.Number)
+	require.Len(t, e.Block.Transactions, 1)
+}
+
+func TestWebSocket(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	config, err := websocket.NewConfig(strings.Replace(env.url("channel="+channelID+"&types=chaincode&event=^transfer$"), "http", "ws", 1), env.httpServer.URL)
+	require.NoError(t, err)
+	config.Header.Set("Authorization", "Bearer "+authToken)
+
+	ws, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	env.blocks <- newBlock(5,
+		servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "create", nil),
+		servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, ccID, "transfer", nil),
+	)
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var e Event
+	require.NoError(t, websocket.JSON.Receive(ws, &e))
+	require.Equal(t, ChaincodeEventType, e.Type)
+	require.Equal(t, "txid2", e.Transaction.TxID)
+	require.Equal(t, "transfer", e.ChaincodeEvent.EventName)
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	const allowedOrigin = "https://app.example.com"
+
+	env := newTestEnv(t, WithAllowedOrigins(allowedOrigin))
+	defer env.close()
+
+	wsURL := strings.Replace(env.url("channel="+channelID+"&token="+authToken), "http", "ws", 1)
+
+	config, err := websocket.NewConfig(wsURL, "https://evil.example.com")
+	require.NoError(t, err)
+	_, err = websocket.DialConfig(config)
+	require.Error(t, err, "expecting WebSocket connection from another origin to be rejected")
+
+	for _, origin := range []string{env.httpServer.URL, allowedOrigin} {
+		config, err := websocket.NewConfig(wsURL, origin)
+		require.NoError(t, err)
+		ws, err := websocket.DialConfig(config)
+		require.NoError(t, err, "expecting WebSocket connection from origin [%s] to be accepted", origin)
+		ws.Close()
+	}
+}
+
+func TestReplay(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	stream := env.openSSE(t, "channel="+channelID+"&token="+authToken+"&fromBlock=2&types=txstatus&txid=txid2")
+	defer stream.close()
+
+	env.server.mutex.Lock()
+	require.Empty(t, env.server.hubs, "replay should not use the shared registration")
+	require.Len(t, env.server.replays, 1)
+	env.server.mutex.Unlock()
+
+	// The mock deliver server sends block 1 in response to the seek request. It's before the
+	// requested block so it must not be published.
+	env.replayBlocks <- newBlock(2, servicemocks.NewTransaction("txid1", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION))
+	env.replayBlocks <- newBlock(3, servicemocks.NewTransaction("txid2", pb.TxValidationCode_MVCC_READ_CONFLICT, cb.HeaderType_ENDORSER_TRANSACTION))
+
+	id, eventType, e := stream.next(t)
+	require.Equal(t, "3", id)
+	require.Equal(t, TxStatusEventType, eventType)
+	require.Equal(t, "txid2", e.Transaction.TxID)
+	require.Equal(t, "MVCC_READ_CONFLICT", e.Transaction.ValidationCode)
+
+	stream.close()
+
+	require.Eventually(t, func() bool {
+		env.server.mutex.Lock()
+		defer env.server.mutex.Unlock()
+		return len(env.server.replays) == 0
+	}, 5*time.Second, 10*time.Millisecond, "expecting replay registration to be closed after the subscriber disconnected")
+
+	replayClients := env.chService.replayClients()
+	require.Len(t, replayClients, 1)
+	require.True(t, replayClients[0].Stopped(), "expecting replay event client to be closed after the subscriber disconnected")
+}
+
+func TestConcurrentReplays(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	stream1 := env.openSSE(t, "channel="+channelID+"&token="+authToken+"&fromBlock=2")
+	defer stream1.close()
+	stream2 := env.openSSE(t, "channel="+channelID+"&token="+authToken+"&fromBlock=2")
+	defer stream2.close()
+
+	env.server.mutex.Lock()
+	require.Len(t, env.server.replays, 2, "expecting each replay to have its own registration")
+	env.server.mutex.Unlock()
+
+	replayClients := env.chService.replayClients()
+	require.Len(t, replayClients, 2)
+	require.True(t, replayClients[0] != replayClients[1], "expecting each replay to have its own event client")
+
+	stream1.close()
+
+	require.Eventually(t, func() bool {
+		return replayClients[0].Stopped() || replayClients[1].Stopped()
+	}, 5*time.Second, 10*time.Millisecond, "expecting replay event client to be closed after the subscriber disconnected")
+	require.False(t, replayClients[0].Stopped() && replayClients[1].Stopped(), "expecting other replay to remain open")
+}
+
+func TestClose(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.close()
+
+	stream := env.openSSE(t, "channel="+channelID+"&token="+authToken)
+	defer stream.close()
+
+	env.server.Close()
+
+	_, err := stream.reader.ReadString('\n')
+	require.Error(t, err, "expecting stream to be closed")
+
+	resp, err := http.Get(env.url("channel=" + channelID + "&token=" + authToken))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func newTxWithCCEvent(txID, eventName string, payload []byte) *servicemocks.TxInfo {
+	tx := servicemocks.NewTransactionWithCCEvent(txID, pb.TxValidationCode_VALID, ccID, eventName, payload)
+	tx.FunctionName = "invoke"
+	return tx
+}
+
+func newBlock(blockNum uint64, txs ...*servicemocks.TxInfo) *cb.Block {
+	block := servicemocks.NewBlock(channelID, txs...)
+	block.Header.Number = blockNum
+	return block
+}
+
+// testEnv holds a fan-out server whose event clients are connected to mock deliver servers. The live
+// event stream is served by one mock deliver server and replays by another, since the mock deliver
+// server ignores the seek request.
+type testEnv struct {
+	server       *Server
+	httpServer   *httptest.Server
+	grpcServers  []*grpc.Server
+	blocks       chan *cb.Block
+	replayBlocks chan *cb.Block
+	chService    *testChannelService
+}
+
+func newTestEnv(t *testing.T, opts ...Option) *testEnv {
+	env := &testEnv{
+		blocks:       make(chan *cb.Block, 10),
+		replayBlocks: make(chan *cb.Block, 10),
+	}
+
+	ctx := fcmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	ctx.SetCustomInfraProvider(comm.NewMockInfraProvider())
+
+	chProvider := ctx.MockProviderContext.ChannelProvider().(*fcmocks.MockChannelProvider)
+	cs, err := chProvider.ChannelService(ctx, channelID)
+	require.NoError(t, err)
+
+	env.chService = &testChannelService{
+		ChannelService:  cs,
+		ctx:             ctx,
+		discovery:       fcmocks.NewMockDiscoveryService(nil, fcmocks.NewMockPeer("peer1", env.startDeliverServer(t, env.blocks))),
+		replayDiscovery: fcmocks.NewMockDiscoveryService(nil, fcmocks.NewMockPeer("peer2", env.startDeliverServer(t, env.replayBlocks))),
+	}
+	chProvider.SetCustomChannelService(env.chService)
+
+	clientProvider := func() (context.Client, error) { return ctx, nil }
+	env.server = New(func(channelID string) context.ChannelProvider {
+		return func() (context.Channel, error) {
+			return contextImpl.NewChannel(clientProvider, channelID)
+		}
+	}, append([]Option{WithAuthToken(authToken)}, opts...)...)
+
+	env.httpServer = httptest.NewServer(env.server)
+
+	return env
+}
+
+func (env *testEnv) startDeliverServer(t *testing.T, blocks chan *cb.Block) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterDeliverServer(grpcServer, eventmocks.NewMockDeliverServerWithDeliveries(blocks))
+	go grpcServer.Serve(lis)
+
+	env.grpcServers = append(env.grpcServers, grpcServer)
+
+	return "grpc://" + lis.Addr().String()
+}
+
+func (env *testEnv) url(query string) string {
+	return env.httpServer.URL + "/events?" + query
+}
+
+func (env *testEnv) close() {
+	env.server.Close()
+	env.httpServer.Close()
+	env.chService.close()
+	for _, s := range env.grpcServers {
+		s.Stop()
+	}
+}
+
+func (env *testEnv) openSSE(t *testing.T, query string) *sseStream {
+	resp, err := http.Get(env.url(query))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return &sseStream{resp: resp, reader: bufio.NewReader(resp.Body)}
+}
+
+type sseStream struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+func (s *sseStream) close() {
+	s.resp.Body.Close()
+}
+
+// next returns the ID, type and data of the next event in the stream
+func (s *sseStream) next(t *testing.T) (string, string, *Event) {
+	type result struct {
+		id, eventType string
+		event         *Event
+		err           error
+	}
+
+	resultch := make(chan result, 1)
+	go func() {
+		var r result
+		for {
+			line, err := s.reader.ReadString('\n')
+			if err != nil {
+				r.err = err
+				resultch <- r
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				r.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				r.eventType = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				r.event = &Event{}
+				r.err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), r.event)
+			case line == "" && r.event != nil:
+				resultch <- r
+				return
+			}
+		}
+	}()
+
+	select {
+	case r := <-resultch:
+		require.NoError(t, r.err)
+		return r.id, r.eventType, r.event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return "", "", nil
+	}
+}
+
+// testChannelService creates deliver clients which connect to the mock deliver servers
+type testChannelService struct {
+	fab.ChannelService
+	ctx             context.Client
+	discovery       fab.DiscoveryService
+	replayDiscovery fab.DiscoveryService
+	mutex           sync.Mutex
+	clients         []*deliverclient.Client
+	replays         []*deliverclient.Client
+}
+
+type seekParams struct {
+	seekType seek.Type
+}
+
+func (p *seekParams) SetSeekType(value seek.Type) {
+	p.seekType = value
+}
+
+func (cs *testChannelService) EventService(opts ...options.Opt) (fab.EventService, error) {
+	params := &seekParams{}
+	options.Apply(params, opts)
+
+	discovery := cs.discovery
+	if params.seekType == seek.FromBlock {
+		discovery = cs.replayDiscovery
+	}
+
+	client, err := deliverclient.New(cs.ctx, fcmocks.NewMockChannelCfg(channelID), discovery, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	cs.clients = append(cs.clients, client)
+	if params.seekType == seek.FromBlock {
+		cs.replays = append(cs.replays, client)
+	}
+	cs.mutex.Unlock()
+
+	return client, nil
+}
+
+// replayClients returns the deliver clients that were created for replays
+func (cs *testChannelService) replayClients() []*deliverclient.Client {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	return append([]*deliverclient.Client(nil), cs.replays...)
+}
+
+func (cs *testChannelService) close() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	for _, client := range cs.clients {
+		client.Close()
+	}
+}
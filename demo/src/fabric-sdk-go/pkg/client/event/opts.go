@@ -6,7 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package event
 
-import "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/checkpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/overflow"
+	"github.com/pkg/errors"
+)
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
@@ -20,6 +25,18 @@ func WithBlockEvents() ClientOption {
 	}
 }
 
+// WithBlockAndPrivateData indicates that block events, including the private data
+// of each transaction, are to be received. Block events are also permitted.
+// Note that the caller must have sufficient privileges for this option and only the
+// private data of collections of which the caller is a member is received.
+func WithBlockAndPrivateData() ClientOption {
+	return func(c *Client) error {
+		c.permitBlockEvents = true
+		c.permitPvtData = true
+		return nil
+	}
+}
+
 // WithBlockNum indicates the block number from which events are to be received.
 // Only deliverclient supports this
 func WithBlockNum(from uint64) ClientOption {
@@ -29,6 +46,17 @@ func WithBlockNum(from uint64) ClientOption {
 	}
 }
 
+// WithStopBlockNum indicates the last block for which events are to be received. After the events of the
+// stop block have been delivered, all event channels are closed.
+// Only deliverclient supports this and the seek type must be oldest or from
+func WithStopBlockNum(to uint64) ClientOption {
+	return func(c *Client) error {
+		c.stopBlock = to
+		c.hasStopBlock = true
+		return nil
+	}
+}
+
 // WithSeekType indicates the  type of seek desired - newest, oldest or from given block
 // Only deliverclient supports this
 func WithSeekType(seek seek.Type) ClientOption {
@@ -37,3 +65,62 @@ func WithSeekType(seek seek.Type) ClientOption {
 		return nil
 	}
 }
+
+// WithCheckpointer indicates that the position of the last event processed by each checkpointed registration
+// (see RegisterCheckpointedChaincodeEvent and RegisterCheckpointedBlockEvent) is persisted using the given
+// checkpointer. The delivery mode determines whether the events of a partially processed block are
+// delivered again (AtLeastOnce) or skipped by transaction ID (ExactlyOnce) when delivery resumes.
+func WithCheckpointer(checkpointer checkpoint.Checkpointer, mode checkpoint.DeliveryMode) ClientOption {
+	return func(c *Client) error {
+		c.checkpointer = checkpointer
+		c.deliveryMode = mode
+		return nil
+	}
+}
+
+// RegistrationOption describes a functional parameter for the registrations of a Registrar (see Client.WithRegistrationOptions)
+type RegistrationOption func(*registrationOptions) error
+
+type registrationOptions struct {
+	overflowPolicy overflow.Policy
+	bufferSize     int
+	spillDir       string
+	onDrop         overflow.DropHandler
+}
+
+// WithOverflowPolicy specifies how the events of a registration are buffered. With any policy other than
+// overflow.Block (the default), up to bufferSize events are queued for the registration so that a slow consumer
+// never blocks the event service; when the buffer is full the oldest or newest event is dropped
+// (overflow.DropOldest, overflow.DropNewest) or events are written to a spill file (overflow.SpillToDisk,
+// see WithSpillDir). With overflow.Block, events which the consumer does not receive within the event
+// consumer timeout are dropped by the event service.
+func WithOverflowPolicy(policy overflow.Policy, bufferSize int) RegistrationOption {
+	return func(o *registrationOptions) error {
+		if policy != overflow.Block && bufferSize <= 0 {
+			return errors.Errorf("buffer size must be greater than zero for overflow policy %s", policy)
+		}
+		o.overflowPolicy = policy
+		o.bufferSize = bufferSize
+		return nil
+	}
+}
+
+// WithSpillDir specifies the directory in which spill files are created for the overflow.SpillToDisk policy.
+// The system's temporary directory is used by default.
+func WithSpillDir(dir string) RegistrationOption {
+	return func(o *registrationOptions) error {
+		o.spillDir = dir
+		return nil
+	}
+}
+
+// WithDropHandler specifies a handler that is invoked when an event of a registration is dropped, either by the
+// overflow policy (because the buffer is full or the event could not be spilled to disk) or, with overflow.Block,
+// by the event service (because the consumer did not receive the event in time). In the latter case the handler
+// is invoked from the event service's dispatcher and must not block.
+func WithDropHandler(handler overflow.DropHandler) RegistrationOption {
+	return func(o *registrationOptions) error {
+		o.onDrop = handler
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	commmetrics "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/overflow"
+	"github.com/pkg/errors"
+)
+
+// Event types used to label the event metrics
+const (
+	blockEventType         = "block"
+	blockAndPvtDataType    = "block_and_pvtdata"
+	filteredBlockEventType = "filtered_block"
+	chaincodeEventType     = "chaincode"
+	txStatusEventType      = "tx_status"
+)
+
+// overflowReg is a registration whose events are delivered through an overflow Forwarder
+type overflowReg struct {
+	reg       fab.Registration
+	forwarder *overflow.Forwarder
+}
+
+// newOverflowReg wraps the event channel of the given registration with a Forwarder which
+// applies the registration's overflow policy
+func (c *Client) newOverflowReg(reg fab.Registration, eventch interface{}, eventType string, opts *registrationOptions) (fab.Registration, interface{}, error) {
+	forwarder, out, err := overflow.New(eventch, c.overflowOptions(eventType, opts))
+	if err != nil {
+		c.Unregister(reg)
+		return nil, nil, errors.WithMessage(err, "failed to create overflow forwarder")
+	}
+
+	return &overflowReg{reg: reg, forwarder: forwarder}, out, nil
+}
+
+func (c *Client) overflowOptions(eventType string, opts *registrationOptions) overflow.Options {
+	oopts := overflow.Options{
+		Policy:     opts.overflowPolicy,
+		BufferSize: opts.bufferSize,
+		SpillDir:   opts.spillDir,
+		OnDrop:     opts.onDrop,
+	}
+
+	if c.metrics != nil {
+		labels := c.metricLabels(eventType)
+		oopts.Queued = withLabels(c.metrics.EventsQueued, labels)
+		oopts.Delivered = withLabels(c.metrics.EventsDelivered, labels)
+		oopts.Dropped = withLabels(c.metrics.EventsDropped, labels)
+	}
+
+	return oopts
+}
+
+// setDropHandler reports the events of a registration with the Block policy which are dropped by the
+// event service (because the consumer did not receive them within the event consumer timeout) to the
+// registration's drop handler and the dropped events counter
+func (c *Client) setDropHandler(es fab.EventService, reg fab.Registration, eventType string, opts *registrationOptions) error {
+	var dropped commmetrics.Counter
+	if c.metrics != nil {
+		dropped = withLabels(c.metrics.EventsDropped, c.metricLabels(eventType))
+	}
+	if dropped == nil && opts.onDrop == nil {
+		return nil
+	}
+
+	s, ok := es.(dropReportingService)
+	if !ok {
+		logger.Debugf("Event service %T does not report dropped events", es)
+		return nil
+	}
+
+	onDrop := opts.onDrop
+	err := s.SetDropHandler(reg, func(event interface{}, reason error) {
+		if dropped != nil {
+			dropped.Add(1)
+		}
+		if onDrop != nil {
+			onDrop(event, reason)
+		}
+	})
+	if err != nil {
+		c.Unregister(reg)
+		return errors.WithMessage(err, "failed to set drop handler")
+	}
+	return nil
+}
+
+func (c *Client) metricLabels(eventType string) []string {
+	return []string{"channel", c.channelID, "type", eventType}
+}
+
+type dropReportingService interface {
+	SetDropHandler(reg fab.Registration, handler func(event interface{}, reason error)) error
+}
+
+// withLabels applies the labels to the given counter. Counters are nil in the standard build.
+func withLabels(counter commmetrics.Counter, labels []string) commmetrics.Counter {
+	if counter == nil {
+		return nil
+	}
+	return counter.With(labels...)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// Registrar registers for events with a set of registration options, e.g. the overflow policy of
+// the registrations (see WithOverflowPolicy). Registrations are made on the client's event service
+// and are unregistered with Unregister (of either the Registrar or the Client).
+type Registrar struct {
+	client *Client
+	opts   registrationOptions
+}
+
+// WithRegistrationOptions returns a Registrar whose registrations apply the given options.
+// Registrations made directly on the Client use the overflow.Block policy.
+func (c *Client) WithRegistrationOptions(opts ...RegistrationOption) (*Registrar, error) {
+	r := &Registrar{client: c}
+	for _, opt := range opts {
+		if err := opt(&r.opts); err != nil {
+			return nil, errors.WithMessage(err, "option failed")
+		}
+	}
+	return r, nil
+}
+
+// RegisterBlockEvent registers for block events (see Client.RegisterBlockEvent)
+func (r *Registrar) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return r.client.registerBlockEvent(&r.opts, filter...)
+}
+
+// RegisterBlockAndPrivateDataEvent registers for block and private data events (see Client.RegisterBlockAndPrivateDataEvent)
+func (r *Registrar) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	return r.client.registerBlockAndPrivateDataEvent(&r.opts, filter...)
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events (see Client.RegisterFilteredBlockEvent)
+func (r *Registrar) RegisterFilteredBlockEvent(filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return r.client.registerFilteredBlockEvent(&r.opts, filter...)
+}
+
+// RegisterChaincodeEvent registers for chaincode events (see Client.RegisterChaincodeEvent)
+func (r *Registrar) RegisterChaincodeEvent(ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return r.client.registerChaincodeEvent(&r.opts, ccID, eventFilter, filter...)
+}
+
+// RegisterChaincodeEventRange replays the chaincode events that were committed in the given range of blocks
+// (see Client.RegisterChaincodeEventRange)
+func (r *Registrar) RegisterChaincodeEventRange(ccID, eventFilter string, fromBlock, toBlock uint64) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return r.client.registerChaincodeEventRange(&r.opts, ccID, eventFilter, fromBlock, toBlock)
+}
+
+// RegisterTxStatusEvent registers for transaction status events (see Client.RegisterTxStatusEvent)
+func (r *Registrar) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	return r.client.registerTxStatusEvent(&r.opts, txID)
+}
+
+// Unregister removes the given registration and closes the event channel
+func (r *Registrar) Unregister(reg fab.Registration) {
+	r.client.Unregister(reg)
+}
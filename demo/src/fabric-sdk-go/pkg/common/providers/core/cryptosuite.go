@@ -119,3 +119,49 @@ type KeyGenOpts interface {
 	// false otherwise.
 	Ephemeral() bool
 }
+
+// Encrypter is implemented by crypto suites that support encryption and decryption.
+// It is optional: callers should check for it with a type assertion on CryptoSuite.
+type Encrypter interface {
+
+	// Encrypt encrypts plaintext using key k.
+	// The opts argument should be appropriate for the algorithm used.
+	Encrypt(k Key, plaintext []byte, opts EncrypterOpts) (ciphertext []byte, err error)
+
+	// Decrypt decrypts ciphertext using key k.
+	// The opts argument should be appropriate for the algorithm used.
+	Decrypt(k Key, ciphertext []byte, opts DecrypterOpts) (plaintext []byte, err error)
+}
+
+// EncrypterOpts contains options for encrypting with a CSP.
+type EncrypterOpts interface{}
+
+// DecrypterOpts contains options for decrypting with a CSP.
+type DecrypterOpts interface{}
+
+// NetworkHashProvider is implemented by crypto suites that know which hash function the
+// network uses (e.g. for transaction IDs and TLS certificate hashes).
+// It is optional: use cryptosuite.GetNetworkHashOpts to resolve the network hash of any CryptoSuite.
+type NetworkHashProvider interface {
+
+	// NetworkHashOpts returns the hash opts of the network hash function.
+	NetworkHashOpts() HashOpts
+}
+
+// SignatureDigestPreparer is implemented by crypto suites whose signature scheme doesn't sign the plain
+// digest of a message, e.g. SM2 which signs the message preprocessed with the signer's identity (Z||M).
+// It is optional: use cryptosuite.PrepareSignatureDigest to prepare the digest for any CryptoSuite.
+type SignatureDigestPreparer interface {
+
+	// PrepareSignatureDigest returns the value to pass to Sign and Verify for msg, given its digest.
+	PrepareSignatureDigest(msg, digest []byte) []byte
+}
+
+// KeySignatureDigestPreparer is implemented by crypto suites whose signature scheme depends on the key,
+// e.g. a suite verifying both ECDSA and SM2 signatures.
+// It is optional: use cryptosuite.PrepareKeySignatureDigest to prepare the digest for any CryptoSuite.
+type KeySignatureDigestPreparer interface {
+
+	// PrepareKeySignatureDigest returns the value to pass to Sign and Verify with key k for msg, given its digest.
+	PrepareKeySignatureDigest(k Key, msg, digest []byte) []byte
+}
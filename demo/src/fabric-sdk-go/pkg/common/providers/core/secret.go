@@ -0,0 +1,18 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package core
+
+// SecretProvider supplies the secrets needed to load the client key material of an SDK instance.
+// Implementations return nil (and no error) for secrets they don't know about, in which case the
+// SDK falls back to its configuration.
+type SecretProvider interface {
+	// KeyPassword returns the password of the encrypted private keys of the given user of the given org
+	KeyPassword(orgID, user string) ([]byte, error)
+	// TLSClientKey returns the PEM encoded TLS client key of the given org, in place of the key
+	// configured in client.tlsCerts.client.key
+	TLSClientKey(orgID string) ([]byte, error)
+}
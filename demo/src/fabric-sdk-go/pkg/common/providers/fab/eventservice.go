@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"time"
+
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 )
 
@@ -19,6 +22,18 @@ type BlockEvent struct {
 	SourceURL string
 }
 
+// BlockAndPrivateDataEvent contains the data for a block event which includes private data
+type BlockAndPrivateDataEvent struct {
+	// Block is the block that was committed
+	Block *cb.Block
+	// PrivateDataMap contains the private read/write sets of the transactions in the block,
+	// keyed by the index of the transaction within the block. Only the private data of the
+	// collections of which the client is a member are included.
+	PrivateDataMap map[uint64]*rwset.TxPvtReadWriteSet
+	// SourceURL specifies the URL of the peer that produced the event
+	SourceURL string
+}
+
 // FilteredBlockEvent contains the data for a filtered block event
 type FilteredBlockEvent struct {
 	// FilteredBlock contains a filtered version of the block that was committed
@@ -66,6 +81,36 @@ type Registration interface{}
 // should be ignored
 type BlockFilter func(block *cb.Block) bool
 
+// FilteredBlockFilter is a function that determines whether a FilteredBlock event
+// should be ignored
+type FilteredBlockFilter func(fblock *pb.FilteredBlock) bool
+
+// TxFilter is a function that determines whether the chaincode event emitted
+// by a transaction should be ignored
+type TxFilter func(tx *TransactionInfo) bool
+
+// TransactionInfo contains the attributes of a transaction against which event filters are evaluated.
+// Attributes that can't be determined from the received event have their zero value, e.g. the creator,
+// function name and timestamp are not available for transactions received in a filtered block.
+type TransactionInfo struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// ValidationCode is the validation code of the transaction
+	ValidationCode pb.TxValidationCode
+	// BlockNumber is the number of the block containing the transaction
+	BlockNumber uint64
+	// CreatorMSPID is the MSP ID of the identity that created the transaction
+	CreatorMSPID string
+	// ChaincodeID is the ID of the chaincode that was invoked
+	ChaincodeID string
+	// FunctionName is the name of the chaincode function that was invoked
+	FunctionName string
+	// Timestamp is the time at which the transaction was created
+	Timestamp time.Time
+	// ChaincodeEvent is the chaincode event emitted by the transaction, if any
+	ChaincodeEvent *pb.ChaincodeEvent
+}
+
 // EventService is a service that receives events such as block, filtered block,
 // chaincode, and transaction status events.
 type EventService interface {
@@ -77,19 +122,30 @@ type EventService interface {
 	//   is closed when Unregister is called.
 	RegisterBlockEvent(filter ...BlockFilter) (Registration, <-chan *BlockEvent, error)
 
+	// RegisterBlockAndPrivateDataEvent registers for block events which include the private data
+	// of each transaction. If the caller does not have permission to register for block and private
+	// data events then an error is returned.
+	// Note that Unregister must be called when the registration is no longer needed.
+	// - filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+	// - Returns the registration and a channel that is used to receive events. The channel
+	//   is closed when Unregister is called.
+	RegisterBlockAndPrivateDataEvent(filter ...BlockFilter) (Registration, <-chan *BlockAndPrivateDataEvent, error)
+
 	// RegisterFilteredBlockEvent registers for filtered block events.
 	// Note that Unregister must be called when the registration is no longer needed.
+	// - filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
 	// - Returns the registration and a channel that is used to receive events. The channel
 	//   is closed when Unregister is called.
-	RegisterFilteredBlockEvent() (Registration, <-chan *FilteredBlockEvent, error)
+	RegisterFilteredBlockEvent(filter ...FilteredBlockFilter) (Registration, <-chan *FilteredBlockEvent, error)
 
 	// RegisterChaincodeEvent registers for chaincode events.
 	// Note that Unregister must be called when the registration is no longer needed.
 	// - ccID is the chaincode ID for which events are to be received
 	// - eventFilter is the chaincode event filter (regular expression) for which events are to be received
+	// - filter is an optional filter on the transaction that emitted the event. (Note: Only one filter may be specified.)
 	// - Returns the registration and a channel that is used to receive events. The channel
 	//   is closed when Unregister is called.
-	RegisterChaincodeEvent(ccID, eventFilter string) (Registration, <-chan *CCEvent, error)
+	RegisterChaincodeEvent(ccID, eventFilter string, filter ...TxFilter) (Registration, <-chan *CCEvent, error)
 
 	// RegisterTxStatusEvent registers for transaction status events.
 	// Note that Unregister must be called when the registration is no longer needed.
@@ -113,6 +169,53 @@ type ConnectionEvent struct {
 	Err       error
 }
 
+// ConnectionLifecycleEventType is the type of a connection lifecycle event
+type ConnectionLifecycleEventType string
+
+const (
+	// PeerConnected indicates that the event client has connected to a peer
+	PeerConnected ConnectionLifecycleEventType = "connected"
+	// PeerDisconnected indicates that the event client has disconnected from a peer
+	PeerDisconnected ConnectionLifecycleEventType = "disconnected"
+	// PeerSwitched indicates that the event client has reconnected to a different peer than the one it was last connected to
+	PeerSwitched ConnectionLifecycleEventType = "switched"
+	// PeerLagging indicates that the connected peer is lagging behind the other peers in the channel
+	PeerLagging ConnectionLifecycleEventType = "lagging"
+)
+
+// ConnectionLifecycleEvent is sent when the event client connects to, disconnects from or switches peers,
+// or when the connected peer falls behind the other peers in the channel. Reason contains the peer resolver's
+// explanation of the decision that led to the event.
+type ConnectionLifecycleEvent struct {
+	Type            ConnectionLifecycleEventType
+	PeerURL         string
+	PreviousPeerURL string
+	BlocksBehind    uint64
+	Err             error
+	Reason          string
+	Timestamp       time.Time
+}
+
+// EventClientStatus contains the health of the event client's connection
+type EventClientStatus struct {
+	// Connected is true if the client is connected to a peer
+	Connected bool
+	// PeerURL is the URL of the connected peer (or the last connected peer if the client is disconnected)
+	PeerURL string
+	// PeerMSPID is the MSP ID of the connected peer
+	PeerMSPID string
+	// ConnectedSince is the time at which the client connected to the peer
+	ConnectedSince time.Time
+	// LastBlockReceived is the number of the last block received (math.MaxUint64 if no block has been received)
+	LastBlockReceived uint64
+	// PeerBlockHeight is the block height of the connected peer according to Discovery (0 if unknown)
+	PeerBlockHeight uint64
+	// BlocksBehind is the number of blocks by which the client lags the highest block height of the channel's peers
+	BlocksBehind uint64
+	// Reason is the peer resolver's explanation of the last connect or disconnect decision
+	Reason string
+}
+
 // EventSnapshot contains a snapshot of the event client before it was stopped.
 // The snapshot includes all of the event registrations and the last block received.
 type EventSnapshot interface {
@@ -123,6 +226,9 @@ type EventSnapshot interface {
 	// BlockRegistrations returns the block registrations.
 	BlockRegistrations() []Registration
 
+	// BlockAndPrivateDataRegistrations returns the block and private data registrations.
+	BlockAndPrivateDataRegistrations() []Registration
+
 	// FilteredBlockRegistrations returns the filtered block registrations.
 	FilteredBlockRegistrations() []Registration
 
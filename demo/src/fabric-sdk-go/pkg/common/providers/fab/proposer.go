@@ -46,6 +46,12 @@ type ProposalSender interface {
 	SendTransactionProposal(*TransactionProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
 }
 
+// SignedProposalSender provides the ability to send a transaction proposal that was signed
+// by an external signer (e.g. an offline signer that holds the private key).
+type SignedProposalSender interface {
+	SendSignedTransactionProposal(*pb.SignedProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
+}
+
 // TransactionID provides the identifier of a Fabric transaction proposal.
 type TransactionID string
 
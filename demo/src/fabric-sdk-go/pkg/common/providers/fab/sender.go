@@ -24,6 +24,12 @@ type Sender interface {
 	SendTransaction(tx *Transaction) (*TransactionResponse, error)
 }
 
+// SignedEnvelopeSender provides the ability to send a transaction envelope that was signed
+// by an external signer (e.g. an offline signer that holds the private key).
+type SignedEnvelopeSender interface {
+	SendSignedEnvelope(envelope *SignedEnvelope) (*TransactionResponse, error)
+}
+
 // The Transaction object created from an endorsed proposal.
 type Transaction struct {
 	Proposal    *TransactionProposal
@@ -42,6 +42,7 @@ type ClientConfig struct {
 	CryptoConfig    CCType
 	TLSKey          []byte
 	TLSCert         []byte
+	TLSType         string
 	CredentialStore CredentialStoreType
 }
 
@@ -74,6 +75,9 @@ type CAConfig struct {
 	TLSCAServerCerts [][]byte
 	TLSCAClientCert  []byte
 	TLSCAClientKey   []byte
+	// GM/T TLS encryption cert and key of the client, optional
+	TLSCAClientEncCert []byte
+	TLSCAClientEncKey  []byte
 }
 
 // Providers represents a provider of MSP service.
@@ -12,10 +12,29 @@ import (
 	"crypto/x509"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/gmtls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+	"google.golang.org/grpc/credentials"
 )
 
+// TLSTypeGM is the value of client.tlsCerts.type selecting GM/T TLS (TLCP, SM2/SM4/SM3) for the
+// connections to peers, orderers and CAs. Standard TLS is used otherwise.
+const TLSTypeGM = "gm"
+
+// gmTLSConfig is implemented by endpoint configs supporting GM/T TLS
+type gmTLSConfig interface {
+	TLSType() string
+	GMTLSClientCert() *gmtls.Certificate
+	GMTLSClientEncCert() *gmtls.Certificate
+}
+
+// certLister is implemented by cert pools that can list their certificates
+type certLister interface {
+	Certs() []*x509.Certificate
+}
+
 // TLSConfig returns the appropriate config for TLS including the root CAs,
 // certs for mutual TLS, and server host override. Works with certs loaded either from a path or embedded pem.
 func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointConfig) (*tls.Config, error) {
@@ -31,6 +50,70 @@ func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointCon
 	return &tls.Config{RootCAs: certPool, Certificates: config.TLSClientCerts(), ServerName: serverName}, nil
 }
 
+// IsGMTLS returns true if the endpoint config selects GM/T TLS (client.tlsCerts.type: gm)
+func IsGMTLS(config fab.EndpointConfig) bool {
+	c, ok := config.(gmTLSConfig)
+	return ok && c.TLSType() == TLSTypeGM
+}
+
+// GMTLSConfig returns the GM/T TLS config including the root CAs, the signing and encryption certs
+// for mutual TLS, and server host override. It is the GM/T counterpart of TLSConfig.
+func GMTLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointConfig) (*gmtls.Config, error) {
+	c, ok := config.(gmTLSConfig)
+	if !ok {
+		return nil, errors.New("endpoint config doesn't support GM/T TLS")
+	}
+
+	if cert != nil {
+		config.TLSCACertPool().Add(cert)
+	}
+	if _, err := config.TLSCACertPool().Get(); err != nil {
+		return nil, err
+	}
+	lister, ok := config.TLSCACertPool().(certLister)
+	if !ok {
+		return nil, errors.New("TLS CA cert pool doesn't support GM/T TLS")
+	}
+
+	rootCAs := sm2.NewCertPool()
+	for _, caCert := range lister.Certs() {
+		smCert, err := sm2.ParseCertificate(caCert.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse TLS CA certificate [%s]", caCert.Subject)
+		}
+		rootCAs.AddCert(smCert)
+	}
+
+	return &gmtls.Config{
+		RootCAs:         rootCAs,
+		SignCertificate: c.GMTLSClientCert(),
+		EncCertificate:  c.GMTLSClientEncCert(),
+		ServerName:      serverName,
+	}, nil
+}
+
+// TransportCredentials returns the gRPC credentials of a secured connection, using GM/T TLS if it is
+// configured and TLS otherwise. verifyPeerCertificate is only used with TLS: GM/T TLS checks the
+// validity of the peer certificates while verifying their chain.
+func TransportCredentials(cert *x509.Certificate, serverName string, config fab.EndpointConfig,
+	verifyPeerCertificate func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error) (credentials.TransportCredentials, error) {
+
+	if IsGMTLS(config) {
+		gmConfig, err := GMTLSConfig(cert, serverName, config)
+		if err != nil {
+			return nil, err
+		}
+		return gmtls.NewTransportCredentials(gmConfig), nil
+	}
+
+	tlsConfig, err := TLSConfig(cert, serverName, config)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.VerifyPeerCertificate = verifyPeerCertificate
+	return credentials.NewTLS(tlsConfig), nil
+}
+
 // TLSCertHash is a utility method to calculate the SHA256 hash of the configured certificate (for usage in channel headers)
 func TLSCertHash(config fab.EndpointConfig) ([]byte, error) {
 	certs := config.TLSClientCerts()
@@ -48,7 +131,8 @@ func TLSCertHash(config fab.EndpointConfig) ([]byte, error) {
 
 //computeHash computes hash for given bytes using underlying cryptosuite default
 func computeHash(msg []byte) ([]byte, error) {
-	h, err := cryptosuite.GetDefault().Hash(msg, cryptosuite.GetSHA256Opts())
+	cs := cryptosuite.GetDefault()
+	h, err := cs.Hash(msg, cryptosuite.GetNetworkHashOpts(cs))
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to compute tls cert hash")
 	}
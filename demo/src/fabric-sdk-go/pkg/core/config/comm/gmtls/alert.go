@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import "strconv"
+
+type alert uint8
+
+const (
+	alertLevelWarning = 1
+	alertLevelError   = 2
+)
+
+const (
+	alertCloseNotify            alert = 0
+	alertUnexpectedMessage      alert = 10
+	alertBadRecordMAC           alert = 20
+	alertRecordOverflow         alert = 22
+	alertHandshakeFailure       alert = 40
+	alertBadCertificate         alert = 42
+	alertUnsupportedCertificate alert = 43
+	alertCertificateExpired     alert = 45
+	alertUnknownCA              alert = 48
+	alertDecodeError            alert = 50
+	alertDecryptError           alert = 51
+	alertProtocolVersion        alert = 70
+	alertInternalError          alert = 80
+)
+
+var alertText = map[alert]string{
+	alertCloseNotify:            "close notify",
+	alertUnexpectedMessage:      "unexpected message",
+	alertBadRecordMAC:           "bad record MAC",
+	alertRecordOverflow:         "record overflow",
+	alertHandshakeFailure:       "handshake failure",
+	alertBadCertificate:         "bad certificate",
+	alertUnsupportedCertificate: "unsupported certificate",
+	alertCertificateExpired:     "expired certificate",
+	alertUnknownCA:              "unknown certificate authority",
+	alertDecodeError:            "error decoding message",
+	alertDecryptError:           "error decrypting message",
+	alertProtocolVersion:        "protocol version not supported",
+	alertInternalError:          "internal error",
+}
+
+func (e alert) String() string {
+	s, ok := alertText[e]
+	if ok {
+		return "gmtls: " + s
+	}
+	return "gmtls: alert(" + strconv.Itoa(int(e)) + ")"
+}
+
+func (e alert) Error() string {
+	return e.String()
+}
+
+// remoteAlert is an alert received from the peer
+type remoteAlert struct {
+	alert
+}
+
+func (e remoteAlert) Error() string {
+	return "remote error: " + e.alert.String()
+}
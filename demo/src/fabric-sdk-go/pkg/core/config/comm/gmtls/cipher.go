@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"hash"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm3"
+	"github.com/tjfoc/gmsm/sm4"
+)
+
+// halfConn is the protection state of one direction of a connection:
+// SM4-CBC with an explicit IV per record, and HMAC-SM3 computed over the plaintext
+type halfConn struct {
+	block cipher.Block // nil until the first ChangeCipherSpec
+	mac   hash.Hash
+	seq   [8]byte
+
+	nextBlock cipher.Block
+	nextMac   hash.Hash
+}
+
+// prepareCipherSpec sets the keys to use after the next ChangeCipherSpec
+func (hc *halfConn) prepareCipherSpec(key, macKey []byte) error {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to create SM4 cipher")
+	}
+	hc.nextBlock = block
+	hc.nextMac = hmac.New(sm3.New, macKey)
+	return nil
+}
+
+// changeCipherSpec switches to the prepared keys
+func (hc *halfConn) changeCipherSpec() error {
+	if hc.nextBlock == nil {
+		return alertUnexpectedMessage
+	}
+	hc.block, hc.mac = hc.nextBlock, hc.nextMac
+	hc.nextBlock, hc.nextMac = nil, nil
+	hc.seq = [8]byte{}
+	return nil
+}
+
+func (hc *halfConn) incSeq() {
+	for i := 7; i >= 0; i-- {
+		hc.seq[i]++
+		if hc.seq[i] != 0 {
+			return
+		}
+	}
+	// Not allowed to let the sequence number wrap: the connection must be closed before
+	panic("gmtls: sequence number wraparound")
+}
+
+func (hc *halfConn) computeMAC(typ recordType, fragment []byte) []byte {
+	hc.mac.Reset()
+	hc.mac.Write(hc.seq[:])
+	hc.mac.Write([]byte{byte(typ), byte(VersionTLCP >> 8), byte(VersionTLCP & 0xff), byte(len(fragment) >> 8), byte(len(fragment))})
+	hc.mac.Write(fragment)
+	return hc.mac.Sum(nil)
+}
+
+// encrypt returns the protected payload of a record of the given type
+func (hc *halfConn) encrypt(typ recordType, fragment []byte, rand io.Reader) ([]byte, error) {
+	if hc.block == nil {
+		hc.incSeq()
+		return fragment, nil
+	}
+
+	blockSize := hc.block.BlockSize()
+	mac := hc.computeMAC(typ, fragment)
+	hc.incSeq()
+
+	plaintextLen := len(fragment) + len(mac)
+	paddingLen := blockSize - plaintextLen%blockSize
+
+	payload := make([]byte, blockSize+plaintextLen+paddingLen)
+	iv := payload[:blockSize]
+	if _, err := io.ReadFull(rand, iv); err != nil {
+		return nil, errors.Wrap(err, "failed to generate record IV")
+	}
+	body := payload[blockSize:]
+	copy(body, fragment)
+	copy(body[len(fragment):], mac)
+	for i := plaintextLen; i < len(body); i++ {
+		body[i] = byte(paddingLen - 1)
+	}
+
+	cipher.NewCBCEncrypter(hc.block, iv).CryptBlocks(body, body)
+	return payload, nil
+}
+
+// decrypt checks and removes the protection of the payload of a record of the given type
+func (hc *halfConn) decrypt(typ recordType, payload []byte) ([]byte, error) {
+	if hc.block == nil {
+		hc.incSeq()
+		return payload, nil
+	}
+
+	blockSize := hc.block.BlockSize()
+	macSize := hc.mac.Size()
+	if len(payload)%blockSize != 0 || len(payload) < blockSize+roundUp(macSize+1, blockSize) {
+		return nil, alertBadRecordMAC
+	}
+
+	iv := payload[:blockSize]
+	body := payload[blockSize:]
+	cipher.NewCBCDecrypter(hc.block, iv).CryptBlocks(body, body)
+
+	paddingLen := int(body[len(body)-1])
+	if paddingLen+1+macSize > len(body) {
+		return nil, alertBadRecordMAC
+	}
+	good := byte(0)
+	for _, b := range body[len(body)-paddingLen-1:] {
+		good |= b ^ byte(paddingLen)
+	}
+
+	body = body[:len(body)-paddingLen-1]
+	fragment := body[:len(body)-macSize]
+	mac := hc.computeMAC(typ, fragment)
+	hc.incSeq()
+
+	if !hmac.Equal(mac, body[len(fragment):]) || good != 0 {
+		return nil, alertBadRecordMAC
+	}
+	return fragment, nil
+}
+
+func roundUp(a, b int) int {
+	return a + (b-a%b)%b
+}
@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gmtls implements the client and server side of GM/T 0024-2014 (TLCP), the Chinese
+// national TLS profile built on SM2, SM3 and SM4.
+//
+// Only the ECC_SM4_CBC_SM3 cipher suite is supported: the pre-master secret is encrypted with
+// the SM2 key of the server's encryption certificate, which is sent after its signing certificate.
+// Session resumption and renegotiation are not supported.
+package gmtls
+
+import (
+	"crypto"
+	"crypto/rand"
+	"io"
+	"time"
+
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+// VersionTLCP is the protocol version of GM/T 0024-2014
+const VersionTLCP = 0x0101
+
+// Cipher suites of GM/T 0024-2014 implemented by this package
+const (
+	ECC_SM4_CBC_SM3 uint16 = 0xe013 //nolint
+)
+
+const (
+	maxPlaintext      = 16384        // maximum plaintext payload length
+	maxCiphertext     = 16384 + 2048 // maximum ciphertext payload length
+	recordHeaderLen   = 5            // record header length
+	maxHandshake      = 65536        // maximum handshake message length
+	masterSecretLen   = 48
+	preMasterLen      = 48
+	finishedVerifyLen = 12
+)
+
+// TLS record types
+type recordType uint8
+
+const (
+	recordTypeChangeCipherSpec recordType = 20
+	recordTypeAlert            recordType = 21
+	recordTypeHandshake        recordType = 22
+	recordTypeApplicationData  recordType = 23
+)
+
+// TLS handshake message types
+const (
+	typeClientHello        uint8 = 1
+	typeServerHello        uint8 = 2
+	typeCertificate        uint8 = 11
+	typeServerKeyExchange  uint8 = 12
+	typeCertificateRequest uint8 = 13
+	typeServerHelloDone    uint8 = 14
+	typeCertificateVerify  uint8 = 15
+	typeClientKeyExchange  uint8 = 16
+	typeFinished           uint8 = 20
+)
+
+// client certificate type requested by servers
+const certTypeECDSASign = 64
+
+// ClientAuthType declares the policy the server will follow for client authentication
+type ClientAuthType int
+
+const (
+	// NoClientCert indicates that no client certificate should be requested
+	NoClientCert ClientAuthType = iota
+	// RequestClientCert indicates that a client certificate should be requested, and verified if sent
+	RequestClientCert
+	// RequireAndVerifyClientCert indicates that a valid client certificate is required
+	RequireAndVerifyClientCert
+)
+
+// Decrypter is the private key of an encryption certificate. It decrypts SM2 ciphertexts in the
+// C1C3C2 format of sm2.Encrypt; *sm2.PrivateKey implements it.
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// Certificate is a certificate chain and the private key of its leaf
+type Certificate struct {
+	// Certificate is the DER encoded chain, leaf first
+	Certificate [][]byte
+	// PrivateKey is a crypto.Signer producing SM2 signatures for signing certificates, and a Decrypter
+	// for encryption certificates
+	PrivateKey crypto.PrivateKey
+}
+
+// Config configures a TLCP client or server. A Config may be reused, it must not be modified
+// once it has been passed to a function of this package.
+type Config struct {
+	// Rand provides the source of entropy, crypto/rand is used if nil
+	Rand io.Reader
+
+	// Time returns the current time, time.Now is used if nil
+	Time func() time.Time
+
+	// SignCertificate is used for authentication. It is sent to clients by servers (required) and
+	// to servers by clients when they request it.
+	SignCertificate *Certificate
+
+	// EncCertificate is the encryption certificate of servers (required). Clients send it after
+	// their signing certificate when it is set.
+	EncCertificate *Certificate
+
+	// RootCAs are the root certificates used by clients to verify server certificates
+	RootCAs *sm2.CertPool
+
+	// ServerName is used to verify the hostname of server certificates
+	ServerName string
+
+	// InsecureSkipVerify disables the verification of server certificates by clients
+	InsecureSkipVerify bool
+
+	// ClientAuth is the client authentication policy of servers
+	ClientAuth ClientAuthType
+
+	// ClientCAs are the root certificates used by servers to verify client certificates
+	ClientCAs *sm2.CertPool
+
+	// VerifyPeerCertificate, if not nil, is called after normal certificate verification
+	VerifyPeerCertificate func(rawCerts [][]byte, verifiedChains [][]*sm2.Certificate) error
+}
+
+// Clone returns a shallow copy of the config
+func (c *Config) Clone() *Config {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
+
+func (c *Config) rand() io.Reader {
+	if c.Rand == nil {
+		return rand.Reader
+	}
+	return c.Rand
+}
+
+func (c *Config) time() time.Time {
+	if c.Time == nil {
+		return time.Now()
+	}
+	return c.Time()
+}
+
+// ConnectionState records basic details about the connection
+type ConnectionState struct {
+	Version           uint16
+	HandshakeComplete bool
+	CipherSuite       uint16
+	ServerName        string
+	PeerCertificates  []*sm2.Certificate
+	VerifiedChains    [][]*sm2.Certificate
+}
@@ -0,0 +1,412 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+// Conn is a TLCP connection. It implements net.Conn.
+type Conn struct {
+	conn     net.Conn
+	isClient bool
+	config   *Config
+
+	handshakeMutex  sync.Mutex
+	handshakeErr    error
+	handshakeStatus uint32 // 1 once the handshake completed, accessed atomically
+
+	// connection state, set by the handshake
+	cipherSuite      uint16
+	peerCertificates []*sm2.Certificate
+	verifiedChains   [][]*sm2.Certificate
+
+	in     halfConn
+	inLock sync.Mutex
+	hand   bytes.Buffer // pending handshake data
+	input  []byte       // pending application data
+	inErr  error        // sticky read error
+
+	out        halfConn
+	outLock    sync.Mutex
+	outErr     error // sticky write error
+	closeNotif bool  // close_notify was sent
+}
+
+// Client returns a new TLCP client side connection using conn as the underlying transport.
+// The config cannot be nil: either ServerName or InsecureSkipVerify must be set.
+func Client(conn net.Conn, config *Config) *Conn {
+	return &Conn{conn: conn, config: config, isClient: true}
+}
+
+// Server returns a new TLCP server side connection using conn as the underlying transport.
+// The config must have both a signing and an encryption certificate.
+func Server(conn net.Conn, config *Config) *Conn {
+	return &Conn{conn: conn, config: config}
+}
+
+// Dial connects to the given network address and initiates a TLCP handshake.
+// If config.ServerName is empty, the host of addr is used.
+func Dial(network, addr string, config *Config) (*Conn, error) {
+	return DialWithDialer(new(net.Dialer), network, addr, config)
+}
+
+// DialWithDialer connects to the given network address using dialer and initiates a TLCP handshake.
+func DialWithDialer(dialer *net.Dialer, network, addr string, config *Config) (*Conn, error) {
+	rawConn, err := dialer.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ServerName == "" {
+		config = config.Clone()
+		config.ServerName = hostname(addr)
+	}
+
+	if dialer.Timeout > 0 {
+		rawConn.SetDeadline(time.Now().Add(dialer.Timeout)) //nolint
+	}
+
+	conn := Client(rawConn, config)
+	if err := conn.Handshake(); err != nil {
+		rawConn.Close()
+		return nil, err
+	}
+	rawConn.SetDeadline(time.Time{}) //nolint
+	return conn, nil
+}
+
+func hostname(addr string) string {
+	colonPos := strings.LastIndex(addr, ":")
+	if colonPos == -1 {
+		colonPos = len(addr)
+	}
+	host := addr[:colonPos]
+	if len(host) > 1 && host[0] == '[' && host[len(host)-1] == ']' {
+		host = host[1 : len(host)-1]
+	}
+	return host
+}
+
+// Handshake runs the TLCP handshake, if it has not yet been run.
+// Read and Write call it automatically.
+func (c *Conn) Handshake() error {
+	c.handshakeMutex.Lock()
+	defer c.handshakeMutex.Unlock()
+
+	if c.handshakeComplete() || c.handshakeErr != nil {
+		return c.handshakeErr
+	}
+
+	c.inLock.Lock()
+	defer c.inLock.Unlock()
+
+	if c.isClient {
+		c.handshakeErr = c.clientHandshake()
+	} else {
+		c.handshakeErr = c.serverHandshake()
+	}
+	if c.handshakeErr == nil {
+		atomic.StoreUint32(&c.handshakeStatus, 1)
+	}
+	return c.handshakeErr
+}
+
+func (c *Conn) handshakeComplete() bool {
+	return atomic.LoadUint32(&c.handshakeStatus) == 1
+}
+
+// ConnectionState returns basic details about the connection
+func (c *Conn) ConnectionState() ConnectionState {
+	c.handshakeMutex.Lock()
+	defer c.handshakeMutex.Unlock()
+
+	state := ConnectionState{HandshakeComplete: c.handshakeComplete()}
+	if state.HandshakeComplete {
+		state.Version = VersionTLCP
+		state.CipherSuite = c.cipherSuite
+		state.ServerName = c.config.ServerName
+		state.PeerCertificates = c.peerCertificates
+		state.VerifiedChains = c.verifiedChains
+	}
+	return state
+}
+
+// Read reads application data from the connection
+func (c *Conn) Read(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	c.inLock.Lock()
+	defer c.inLock.Unlock()
+
+	for len(c.input) == 0 {
+		if c.inErr != nil {
+			return 0, c.inErr
+		}
+		typ, data, err := c.readRecord()
+		if err != nil {
+			c.inErr = err
+			return 0, err
+		}
+		if typ != recordTypeApplicationData {
+			// renegotiation is not supported
+			c.inErr = c.sendAlert(alertUnexpectedMessage)
+			continue
+		}
+		c.input = data
+	}
+
+	n := copy(b, c.input)
+	c.input = c.input[n:]
+	return n, nil
+}
+
+// Write writes application data to the connection
+func (c *Conn) Write(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+
+	c.outLock.Lock()
+	defer c.outLock.Unlock()
+
+	if c.outErr != nil {
+		return 0, c.outErr
+	}
+
+	n := 0
+	for len(b) > 0 {
+		m := len(b)
+		if m > maxPlaintext {
+			m = maxPlaintext
+		}
+		if err := c.writeRecordLocked(recordTypeApplicationData, b[:m]); err != nil {
+			return n, err
+		}
+		n += m
+		b = b[m:]
+	}
+	return n, nil
+}
+
+// Close sends a close_notify alert, if the handshake completed, and closes the underlying connection
+func (c *Conn) Close() error {
+	var alertErr error
+	if c.handshakeComplete() {
+		// don't block forever on a peer that doesn't read
+		c.conn.SetWriteDeadline(time.Now().Add(5 * time.Second)) //nolint
+		c.outLock.Lock()
+		if !c.closeNotif && c.outErr == nil {
+			alertErr = c.writeRecordLocked(recordTypeAlert, []byte{alertLevelWarning, byte(alertCloseNotify)})
+			c.closeNotif = true
+		}
+		c.outLock.Unlock()
+	}
+
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+	return alertErr
+}
+
+// LocalAddr returns the local network address
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote network address
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the underlying connection
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the underlying connection
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline of the underlying connection
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// readRecord reads and decrypts the next record. Alerts are returned as errors.
+// c.inLock must be held.
+func (c *Conn) readRecord() (recordType, []byte, error) {
+	var header [recordHeaderLen]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	typ := recordType(header[0])
+	vers := uint16(header[1])<<8 | uint16(header[2])
+	n := int(header[3])<<8 | int(header[4])
+	if vers != VersionTLCP {
+		return 0, nil, c.sendAlert(alertProtocolVersion)
+	}
+	if n > maxCiphertext {
+		return 0, nil, c.sendAlert(alertRecordOverflow)
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+
+	data, err := c.in.decrypt(typ, payload)
+	if err != nil {
+		if a, ok := err.(alert); ok {
+			return 0, nil, c.sendAlert(a)
+		}
+		return 0, nil, err
+	}
+	if len(data) > maxPlaintext {
+		return 0, nil, c.sendAlert(alertRecordOverflow)
+	}
+
+	if typ == recordTypeAlert {
+		if len(data) != 2 {
+			return 0, nil, c.sendAlert(alertUnexpectedMessage)
+		}
+		if alert(data[1]) == alertCloseNotify {
+			return 0, nil, io.EOF
+		}
+		return 0, nil, remoteAlert{alert(data[1])}
+	}
+	return typ, data, nil
+}
+
+// readHandshake returns the next handshake message, including its header.
+// c.inLock must be held.
+func (c *Conn) readHandshake() ([]byte, error) {
+	for c.hand.Len() < 4 || c.hand.Len() < 4+handshakeLength(c.hand.Bytes()) {
+		if c.hand.Len() >= 4 && handshakeLength(c.hand.Bytes()) > maxHandshake {
+			return nil, c.sendAlert(alertInternalError)
+		}
+		typ, data, err := c.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if typ != recordTypeHandshake {
+			return nil, c.sendAlert(alertUnexpectedMessage)
+		}
+		c.hand.Write(data)
+	}
+
+	msg := make([]byte, 4+handshakeLength(c.hand.Bytes()))
+	c.hand.Read(msg) //nolint
+	return msg, nil
+}
+
+func handshakeLength(b []byte) int {
+	return int(b[1])<<16 | int(b[2])<<8 | int(b[3])
+}
+
+// readChangeCipherSpec reads a ChangeCipherSpec and switches to the prepared read keys.
+// c.inLock must be held.
+func (c *Conn) readChangeCipherSpec() error {
+	if c.hand.Len() > 0 {
+		return c.sendAlert(alertUnexpectedMessage)
+	}
+	typ, data, err := c.readRecord()
+	if err != nil {
+		return err
+	}
+	if typ != recordTypeChangeCipherSpec || len(data) != 1 || data[0] != 1 {
+		return c.sendAlert(alertUnexpectedMessage)
+	}
+	if err := c.in.changeCipherSpec(); err != nil {
+		return c.sendAlert(alertUnexpectedMessage)
+	}
+	return nil
+}
+
+// writeRecord encrypts and writes a record
+func (c *Conn) writeRecord(typ recordType, data []byte) error {
+	c.outLock.Lock()
+	defer c.outLock.Unlock()
+	return c.writeRecordLocked(typ, data)
+}
+
+func (c *Conn) writeRecordLocked(typ recordType, data []byte) error {
+	if c.outErr != nil {
+		return c.outErr
+	}
+
+	for len(data) > 0 {
+		m := len(data)
+		if m > maxPlaintext {
+			m = maxPlaintext
+		}
+
+		payload, err := c.out.encrypt(typ, data[:m], c.config.rand())
+		if err != nil {
+			c.outErr = err
+			return err
+		}
+
+		record := make([]byte, recordHeaderLen, recordHeaderLen+len(payload))
+		record[0] = byte(typ)
+		record[1] = byte(VersionTLCP >> 8)
+		record[2] = byte(VersionTLCP & 0xff)
+		record[3] = byte(len(payload) >> 8)
+		record[4] = byte(len(payload))
+		record = append(record, payload...)
+
+		if _, err := c.conn.Write(record); err != nil {
+			c.outErr = err
+			return err
+		}
+
+		data = data[m:]
+	}
+	return nil
+}
+
+// writeChangeCipherSpec writes a ChangeCipherSpec and switches to the prepared write keys
+func (c *Conn) writeChangeCipherSpec() error {
+	c.outLock.Lock()
+	defer c.outLock.Unlock()
+
+	if err := c.writeRecordLocked(recordTypeChangeCipherSpec, []byte{1}); err != nil {
+		return err
+	}
+	return c.out.changeCipherSpec()
+}
+
+// sendAlert sends a fatal alert to the peer and returns it as an error
+func (c *Conn) sendAlert(a alert) error {
+	c.outLock.Lock()
+	defer c.outLock.Unlock()
+
+	if c.outErr == nil {
+		c.writeRecordLocked(recordTypeAlert, []byte{alertLevelError, byte(a)}) //nolint
+		c.outErr = errors.Errorf("gmtls: connection failed with alert: %s", a)
+	}
+	return a
+}
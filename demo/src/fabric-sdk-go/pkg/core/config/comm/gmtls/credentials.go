@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// AuthInfo contains the auth information of a TLCP connection
+type AuthInfo struct {
+	State ConnectionState
+	credentials.CommonAuthInfo
+}
+
+// AuthType returns the type of the auth information
+func (AuthInfo) AuthType() string {
+	return "gmtls"
+}
+
+type transportCredentials struct {
+	config *Config
+}
+
+// NewTransportCredentials returns gRPC transport credentials based on TLCP
+func NewTransportCredentials(config *Config) credentials.TransportCredentials {
+	return &transportCredentials{config: config.Clone()}
+}
+
+func (c *transportCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "gmtls",
+		SecurityVersion:  "1.1",
+		ServerName:       c.config.ServerName,
+	}
+}
+
+func (c *transportCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	config := c.config
+	if config.ServerName == "" {
+		config = config.Clone()
+		config.ServerName = hostname(authority)
+	}
+	conn := Client(rawConn, config)
+	if err := handshake(ctx, conn); err != nil {
+		rawConn.Close()
+		return nil, nil, err
+	}
+	return conn, AuthInfo{
+		State:          conn.ConnectionState(),
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+	}, nil
+}
+
+func (c *transportCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn := Server(rawConn, c.config)
+	if err := conn.Handshake(); err != nil {
+		rawConn.Close()
+		return nil, nil, err
+	}
+	return conn, AuthInfo{
+		State:          conn.ConnectionState(),
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+	}, nil
+}
+
+func (c *transportCredentials) Clone() credentials.TransportCredentials {
+	return NewTransportCredentials(c.config)
+}
+
+func (c *transportCredentials) OverrideServerName(serverNameOverride string) error {
+	c.config.ServerName = serverNameOverride
+	return nil
+}
+
+// handshake runs the handshake of conn, aborting it when ctx is done
+func handshake(ctx context.Context, conn *Conn) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- conn.Handshake()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		// unblock the handshake
+		conn.conn.Close()
+		<-errCh
+		return ctx.Err()
+	}
+}
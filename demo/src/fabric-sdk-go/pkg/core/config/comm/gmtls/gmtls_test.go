@@ -0,0 +1,328 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHandshake(t *testing.T) {
+	pki := newTestPKI(t)
+	serverConfig := pki.serverConfig(t, RequireAndVerifyClientCert)
+	clientCert := pki.issue(t, "client", nil, sm2.ExtKeyUsageClientAuth)
+	clientConfig := &Config{RootCAs: pki.roots, ServerName: "localhost", SignCertificate: &clientCert}
+
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 3000) // spans several records
+
+	client, server := connect(t, clientConfig, serverConfig)
+	defer client.Close()
+	defer server.Close()
+
+	done := make(chan []byte)
+	go func() {
+		received := make([]byte, len(payload))
+		_, err := io.ReadFull(server, received)
+		assert.NoError(t, err)
+		_, err = server.Write([]byte("pong"))
+		assert.NoError(t, err)
+		done <- received
+	}()
+
+	_, err := client.Write(payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, <-done)
+
+	pong := make([]byte, 4)
+	_, err = io.ReadFull(client, pong)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(pong))
+
+	clientState := client.ConnectionState()
+	assert.True(t, clientState.HandshakeComplete)
+	assert.Equal(t, ECC_SM4_CBC_SM3, clientState.CipherSuite)
+	require.Len(t, clientState.PeerCertificates, 2)
+	assert.Equal(t, "server", clientState.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "server-enc", clientState.PeerCertificates[1].Subject.CommonName)
+
+	serverState := server.ConnectionState()
+	require.Len(t, serverState.PeerCertificates, 1)
+	assert.Equal(t, "client", serverState.PeerCertificates[0].Subject.CommonName)
+
+	// close_notify is reported as EOF
+	require.NoError(t, client.Close())
+	_, err = server.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestHandshakeClientEncCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+	signCert := pki.issue(t, "client", nil, sm2.ExtKeyUsageClientAuth)
+	encCert := pki.issue(t, "client-enc", nil, sm2.ExtKeyUsageClientAuth)
+	clientConfig := &Config{RootCAs: pki.roots, ServerName: "localhost", SignCertificate: &signCert, EncCertificate: &encCert}
+
+	client, server := connect(t, clientConfig, pki.serverConfig(t, RequireAndVerifyClientCert))
+	defer client.Close()
+	defer server.Close()
+
+	serverState := server.ConnectionState()
+	require.Len(t, serverState.PeerCertificates, 2)
+	assert.Equal(t, "client", serverState.PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "client-enc", serverState.PeerCertificates[1].Subject.CommonName)
+
+	t.Run("Untrusted encryption certificate", func(t *testing.T) {
+		untrusted := newTestPKI(t).issue(t, "client-enc", nil, sm2.ExtKeyUsageClientAuth)
+		clientConfig := &Config{RootCAs: pki.roots, ServerName: "localhost", SignCertificate: &signCert, EncCertificate: &untrusted}
+		_, serverErr := handshakeErrors(t, clientConfig, pki.serverConfig(t, RequireAndVerifyClientCert))
+		assert.Error(t, serverErr)
+	})
+}
+
+func TestX509KeyPairEncryptedKey(t *testing.T) {
+	certPEM, keyPEM := newTestPKI(t).issuePEM(t, "client", nil, sm2.ExtKeyUsageClientAuth)
+
+	key, err := hx509.PEMtoPrivateKey(keyPEM, nil)
+	require.NoError(t, err)
+	der, err := hx509.PrivateKeyToDER(key.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("password"), x509.PEMCipherAES256) //nolint
+	require.NoError(t, err)
+	encryptedPEM := pem.EncodeToMemory(block)
+
+	cert, err := X509KeyPair(certPEM, encryptedPEM, []byte("password"))
+	require.NoError(t, err)
+	assert.Equal(t, key.(*ecdsa.PrivateKey).D, cert.PrivateKey.(*sm2.PrivateKey).D)
+
+	_, err = X509KeyPair(certPEM, encryptedPEM, nil)
+	assert.Error(t, err)
+	_, err = X509KeyPair(certPEM, encryptedPEM, []byte("wrong"))
+	assert.Error(t, err)
+}
+
+func TestHandshakeFailure(t *testing.T) {
+	pki := newTestPKI(t)
+	other := newTestPKI(t)
+
+	t.Run("Unknown authority", func(t *testing.T) {
+		clientErr, _ := handshakeErrors(t, &Config{RootCAs: other.roots, ServerName: "localhost"}, pki.serverConfig(t, NoClientCert))
+		assert.Error(t, clientErr)
+	})
+
+	t.Run("Wrong server name", func(t *testing.T) {
+		clientErr, _ := handshakeErrors(t, &Config{RootCAs: pki.roots, ServerName: "example.com"}, pki.serverConfig(t, NoClientCert))
+		assert.Error(t, clientErr)
+	})
+
+	t.Run("Missing client certificate", func(t *testing.T) {
+		_, serverErr := handshakeErrors(t, &Config{RootCAs: pki.roots, ServerName: "localhost"}, pki.serverConfig(t, RequireAndVerifyClientCert))
+		assert.Error(t, serverErr)
+	})
+
+	t.Run("Untrusted client certificate", func(t *testing.T) {
+		clientCert := other.issue(t, "client", nil, sm2.ExtKeyUsageClientAuth)
+		clientConfig := &Config{RootCAs: pki.roots, ServerName: "localhost", SignCertificate: &clientCert}
+		_, serverErr := handshakeErrors(t, clientConfig, pki.serverConfig(t, RequireAndVerifyClientCert))
+		assert.Error(t, serverErr)
+	})
+
+	t.Run("Missing server name", func(t *testing.T) {
+		err := Client(nil, &Config{RootCAs: pki.roots}).Handshake()
+		assert.Error(t, err)
+	})
+}
+
+func TestTransportCredentials(t *testing.T) {
+	pki := newTestPKI(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.Creds(NewTransportCredentials(pki.serverConfig(t, NoClientCert))))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis) //nolint
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	creds := NewTransportCredentials(&Config{RootCAs: pki.roots, ServerName: "localhost"})
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithTransportCredentials(creds), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestSM2Ciphertext(t *testing.T) {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	raw, err := sm2.Encrypt(&key.PublicKey, []byte("pre-master secret"))
+	require.NoError(t, err)
+
+	der, err := marshalSM2Ciphertext(raw)
+	require.NoError(t, err)
+	converted, err := unmarshalSM2Ciphertext(der)
+	require.NoError(t, err)
+	assert.Equal(t, raw, converted)
+
+	plaintext, err := key.Decrypt(converted)
+	require.NoError(t, err)
+	assert.Equal(t, "pre-master secret", string(plaintext))
+
+	_, err = unmarshalSM2Ciphertext(raw)
+	assert.Error(t, err)
+}
+
+// connect returns the client and server sides of a TLCP connection
+func connect(t *testing.T, clientConfig, serverConfig *Config) (*Conn, *Conn) {
+	clientConn, serverConn := tcpPipe(t)
+
+	serverErr := make(chan error)
+	server := Server(serverConn, serverConfig)
+	go func() {
+		serverErr <- server.Handshake()
+	}()
+
+	client := Client(clientConn, clientConfig)
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-serverErr)
+	return client, server
+}
+
+// handshakeErrors runs a handshake that is expected to fail and returns the errors of both sides
+func handshakeErrors(t *testing.T, clientConfig, serverConfig *Config) (error, error) {
+	clientConn, serverConn := tcpPipe(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	serverErr := make(chan error)
+	go func() {
+		err := Server(serverConn, serverConfig).Handshake()
+		// unblock the client if the server gave up
+		serverConn.Close()
+		serverErr <- err
+	}()
+
+	clientErr := Client(clientConn, clientConfig).Handshake()
+	clientConn.Close()
+	err := <-serverErr
+	assert.True(t, clientErr != nil || err != nil, "handshake is expected to fail")
+	return clientErr, err
+}
+
+// tcpPipe returns both ends of a loopback TCP connection. Unlike net.Pipe, writes are buffered:
+// alerts sent by both sides at once don't deadlock.
+func tcpPipe(t *testing.T) (net.Conn, net.Conn) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := lis.Accept()
+		assert.NoError(t, err)
+		accepted <- conn
+	}()
+
+	clientConn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	serverConn := <-accepted
+	require.NotNil(t, serverConn)
+	return clientConn, serverConn
+}
+
+type testPKI struct {
+	ca    *sm2.Certificate
+	caKey *sm2.PrivateKey
+	roots *sm2.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	template := &sm2.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tlsca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              sm2.KeyUsageCertSign | sm2.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SignatureAlgorithm:    sm2.SM2WithSM3,
+	}
+	certPEM, err := sm2.CreateCertificateToMem(template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := sm2.ReadCertificateFromMem(certPEM)
+	require.NoError(t, err)
+
+	roots := sm2.NewCertPool()
+	roots.AddCert(ca)
+	return &testPKI{ca: ca, caKey: key, roots: roots}
+}
+
+// issue issues an SM2 certificate and loads it with X509KeyPair
+func (p *testPKI) issue(t *testing.T, cn string, dnsNames []string, extKeyUsage sm2.ExtKeyUsage) Certificate {
+	certPEM, keyPEM := p.issuePEM(t, cn, dnsNames, extKeyUsage)
+	cert, err := X509KeyPair(certPEM, keyPEM, nil)
+	require.NoError(t, err)
+	return cert
+}
+
+// issuePEM issues an SM2 certificate and returns it with its private key, PEM encoded
+func (p *testPKI) issuePEM(t *testing.T, cn string, dnsNames []string, extKeyUsage sm2.ExtKeyUsage) ([]byte, []byte) {
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	template := &sm2.Certificate{
+		SerialNumber:       big.NewInt(time.Now().UnixNano()),
+		Subject:            pkix.Name{CommonName: cn},
+		DNSNames:           dnsNames,
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		KeyUsage:           sm2.KeyUsageDigitalSignature | sm2.KeyUsageKeyEncipherment,
+		ExtKeyUsage:        []sm2.ExtKeyUsage{extKeyUsage},
+		SignatureAlgorithm: sm2.SM2WithSM3,
+	}
+	certPEM, err := sm2.CreateCertificateToMem(template, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyPEM, err := sm2.WritePrivateKeytoMem(key, nil)
+	require.NoError(t, err)
+	return certPEM, keyPEM
+}
+
+func (p *testPKI) serverConfig(t *testing.T, clientAuth ClientAuthType) *Config {
+	signCert := p.issue(t, "server", []string{"localhost"}, sm2.ExtKeyUsageServerAuth)
+	encCert := p.issue(t, "server-enc", []string{"localhost"}, sm2.ExtKeyUsageServerAuth)
+	return &Config{
+		SignCertificate: &signCert,
+		EncCertificate:  &encCert,
+		ClientAuth:      clientAuth,
+		ClientCAs:       p.roots,
+	}
+}
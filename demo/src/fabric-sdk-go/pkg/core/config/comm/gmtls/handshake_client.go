@@ -0,0 +1,279 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func (c *Conn) clientHandshake() error {
+	config := c.config
+	if config == nil || (config.ServerName == "" && !config.InsecureSkipVerify) {
+		return errors.New("gmtls: either ServerName or InsecureSkipVerify must be specified in the config")
+	}
+
+	hello := &clientHelloMsg{
+		vers:               VersionTLCP,
+		random:             make([]byte, 32),
+		cipherSuites:       []uint16{ECC_SM4_CBC_SM3},
+		compressionMethods: []uint8{0},
+	}
+	if _, err := io.ReadFull(config.rand(), hello.random); err != nil {
+		return errors.Wrap(err, "gmtls: short read from Rand")
+	}
+
+	hs := &finishedHash{}
+	if err := c.writeHandshake(hs, hello.marshal()); err != nil {
+		return err
+	}
+
+	// ServerHello
+	serverHello := &serverHelloMsg{}
+	if err := c.readHandshakeMsg(hs, typeServerHello, serverHello.unmarshal); err != nil {
+		return err
+	}
+	if serverHello.vers != VersionTLCP {
+		return c.sendAlert(alertProtocolVersion)
+	}
+	if serverHello.cipherSuite != ECC_SM4_CBC_SM3 || serverHello.compressionMethod != 0 {
+		return c.sendAlert(alertHandshakeFailure)
+	}
+
+	// Certificate: signing certificate, encryption certificate and intermediates
+	certMsg := &certificateMsg{}
+	if err := c.readHandshakeMsg(hs, typeCertificate, certMsg.unmarshal); err != nil {
+		return err
+	}
+	signPub, encCert, err := c.verifyServerCertificates(certMsg.certificates)
+	if err != nil {
+		return err
+	}
+
+	// ServerKeyExchange: signature of the randoms and of the encryption certificate
+	skx := &serverKeyExchangeMsg{}
+	if err := c.readHandshakeMsg(hs, typeServerKeyExchange, skx.unmarshal); err != nil {
+		return err
+	}
+	if !signPub.Verify(keyExchangeSigned(hello.random, serverHello.random, encCert.Raw), skx.signature) {
+		return c.sendAlert(alertDecryptError)
+	}
+	encPub, err := sm2PublicKey(encCert.PublicKey)
+	if err != nil {
+		return c.sendAlert(alertUnsupportedCertificate)
+	}
+
+	// optional CertificateRequest, then ServerHelloDone
+	msg, err := c.readHandshake()
+	if err != nil {
+		return err
+	}
+	certRequested := msg[0] == typeCertificateRequest
+	if certRequested {
+		certReq := &certificateRequestMsg{}
+		if !certReq.unmarshal(msg[4:]) {
+			return c.sendAlert(alertDecodeError)
+		}
+		hs.Write(msg)
+
+		if msg, err = c.readHandshake(); err != nil {
+			return err
+		}
+	}
+	if msg[0] != typeServerHelloDone || len(msg) != 4 {
+		return c.sendAlert(alertUnexpectedMessage)
+	}
+	hs.Write(msg)
+
+	// client Certificate, if requested
+	var signer crypto.Signer
+	if certRequested {
+		clientCert := &certificateMsg{}
+		if config.SignCertificate != nil {
+			s, ok := config.SignCertificate.PrivateKey.(crypto.Signer)
+			if !ok {
+				c.sendAlert(alertInternalError) //nolint
+				return errors.Errorf("gmtls: client certificate private key of type %T is not a signer", config.SignCertificate.PrivateKey)
+			}
+			signer = s
+			clientCert.certificates = config.SignCertificate.Certificate
+			if config.EncCertificate != nil && len(config.EncCertificate.Certificate) > 0 {
+				// signing certificate, encryption certificate and intermediates, like servers
+				signChain := config.SignCertificate.Certificate
+				clientCert.certificates = append([][]byte{signChain[0], config.EncCertificate.Certificate[0]}, signChain[1:]...)
+			}
+		}
+		if err := c.writeHandshake(hs, clientCert.marshal()); err != nil {
+			return err
+		}
+	}
+
+	// ClientKeyExchange: pre-master secret encrypted with the server encryption key
+	preMasterSecret := make([]byte, preMasterLen)
+	preMasterSecret[0] = byte(VersionTLCP >> 8)
+	preMasterSecret[1] = byte(VersionTLCP & 0xff)
+	if _, err := io.ReadFull(config.rand(), preMasterSecret[2:]); err != nil {
+		return errors.Wrap(err, "gmtls: short read from Rand")
+	}
+	encrypted, err := sm2.Encrypt(encPub, preMasterSecret)
+	if err != nil {
+		c.sendAlert(alertInternalError) //nolint
+		return errors.Wrap(err, "gmtls: failed to encrypt pre-master secret")
+	}
+	ckx := &clientKeyExchangeMsg{}
+	if ckx.ciphertext, err = marshalSM2Ciphertext(encrypted); err != nil {
+		c.sendAlert(alertInternalError) //nolint
+		return err
+	}
+	if err := c.writeHandshake(hs, ckx.marshal()); err != nil {
+		return err
+	}
+
+	// CertificateVerify
+	if signer != nil {
+		signature, err := signer.Sign(config.rand(), hs.sum(), nil)
+		if err != nil {
+			c.sendAlert(alertInternalError) //nolint
+			return errors.Wrap(err, "gmtls: failed to sign handshake")
+		}
+		certVerify := &certificateVerifyMsg{signature: signature}
+		if err := c.writeHandshake(hs, certVerify.marshal()); err != nil {
+			return err
+		}
+	}
+
+	masterSecret := masterFromPreMasterSecret(preMasterSecret, hello.random, serverHello.random)
+	clientMAC, serverMAC, clientKey, serverKey := keysFromMasterSecret(masterSecret, hello.random, serverHello.random)
+	if err := c.out.prepareCipherSpec(clientKey, clientMAC); err != nil {
+		return err
+	}
+	if err := c.in.prepareCipherSpec(serverKey, serverMAC); err != nil {
+		return err
+	}
+
+	if err := c.writeChangeCipherSpec(); err != nil {
+		return err
+	}
+	finished := &finishedMsg{verifyData: hs.clientSum(masterSecret)}
+	if err := c.writeHandshake(hs, finished.marshal()); err != nil {
+		return err
+	}
+
+	if err := c.readChangeCipherSpec(); err != nil {
+		return err
+	}
+	expected := hs.serverSum(masterSecret)
+	serverFinished := &finishedMsg{}
+	if err := c.readHandshakeMsg(hs, typeFinished, serverFinished.unmarshal); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expected, serverFinished.verifyData) != 1 {
+		return c.sendAlert(alertHandshakeFailure)
+	}
+
+	c.cipherSuite = serverHello.cipherSuite
+	return nil
+}
+
+// verifyServerCertificates verifies the signing and encryption certificates of the server
+// and returns the signing public key and the encryption certificate
+func (c *Conn) verifyServerCertificates(rawCerts [][]byte) (*sm2.PublicKey, *sm2.Certificate, error) {
+	if len(rawCerts) < 2 {
+		return nil, nil, c.sendAlert(alertBadCertificate)
+	}
+
+	certs := make([]*sm2.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := sm2.ParseCertificate(raw)
+		if err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, nil, errors.Wrap(err, "gmtls: failed to parse certificate from server")
+		}
+		certs[i] = cert
+	}
+	signCert, encCert := certs[0], certs[1]
+
+	if !c.config.InsecureSkipVerify {
+		intermediates := sm2.NewCertPool()
+		for _, cert := range certs[2:] {
+			intermediates.AddCert(cert)
+		}
+
+		opts := sm2.VerifyOptions{
+			Roots:         c.config.RootCAs,
+			CurrentTime:   c.config.time(),
+			DNSName:       c.config.ServerName,
+			Intermediates: intermediates,
+		}
+		chains, err := signCert.Verify(opts)
+		if err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, nil, errors.Wrap(err, "gmtls: failed to verify server signing certificate")
+		}
+
+		// the encryption certificate only needs to chain to a trusted root
+		opts.DNSName = ""
+		opts.KeyUsages = []sm2.ExtKeyUsage{sm2.ExtKeyUsageAny}
+		if _, err := encCert.Verify(opts); err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, nil, errors.Wrap(err, "gmtls: failed to verify server encryption certificate")
+		}
+		c.verifiedChains = chains
+	}
+
+	if c.config.VerifyPeerCertificate != nil {
+		if err := c.config.VerifyPeerCertificate(rawCerts, c.verifiedChains); err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, nil, err
+		}
+	}
+
+	signPub, err := sm2PublicKey(signCert.PublicKey)
+	if err != nil {
+		c.sendAlert(alertUnsupportedCertificate) //nolint
+		return nil, nil, errors.WithMessage(err, "gmtls: unsupported server signing certificate")
+	}
+
+	c.peerCertificates = certs
+	return signPub, encCert, nil
+}
+
+// keyExchangeSigned returns the data signed in ServerKeyExchange messages
+func keyExchangeSigned(clientRandom, serverRandom, encCert []byte) []byte {
+	signed := make([]byte, 0, len(clientRandom)+len(serverRandom)+3+len(encCert))
+	signed = append(signed, clientRandom...)
+	signed = append(signed, serverRandom...)
+	signed = appendUint24(signed, len(encCert))
+	return append(signed, encCert...)
+}
+
+// writeHandshake writes a handshake message and adds it to the handshake hash
+func (c *Conn) writeHandshake(hs *finishedHash, msg []byte) error {
+	hs.Write(msg)
+	return c.writeRecord(recordTypeHandshake, msg)
+}
+
+// readHandshakeMsg reads a handshake message of the expected type, parses its body and adds it to
+// the handshake hash
+func (c *Conn) readHandshakeMsg(hs *finishedHash, typ uint8, unmarshal func([]byte) bool) error {
+	msg, err := c.readHandshake()
+	if err != nil {
+		return err
+	}
+	if msg[0] != typ {
+		return c.sendAlert(alertUnexpectedMessage)
+	}
+	if !unmarshal(msg[4:]) {
+		return c.sendAlert(alertDecodeError)
+	}
+	hs.Write(msg)
+	return nil
+}
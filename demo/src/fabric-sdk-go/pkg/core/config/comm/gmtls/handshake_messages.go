@@ -0,0 +1,271 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+// handshake message encoding, GM/T 0024-2014 section 6.4.5
+
+// handshakeMessage frames a handshake message body with its type and length
+func handshakeMessage(typ uint8, body []byte) []byte {
+	msg := make([]byte, 4+len(body))
+	msg[0] = typ
+	putUint24(msg[1:], len(body))
+	copy(msg[4:], body)
+	return msg
+}
+
+func putUint24(b []byte, v int) {
+	b[0] = byte(v >> 16)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v)
+}
+
+func appendUint16(b []byte, v int) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint24(b []byte, v int) []byte {
+	return append(b, byte(v>>16), byte(v>>8), byte(v))
+}
+
+// reader parses the fields of a handshake message body
+type reader struct {
+	data []byte
+	ok   bool
+}
+
+func newReader(data []byte) *reader {
+	return &reader{data: data, ok: true}
+}
+
+func (r *reader) bytes(n int) []byte {
+	if !r.ok || n < 0 || len(r.data) < n {
+		r.ok = false
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint8() int {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])
+}
+
+func (r *reader) uint16() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])<<8 | int(b[1])
+}
+
+func (r *reader) uint24() int {
+	b := r.bytes(3)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+}
+
+// done returns true if the message was parsed entirely without error
+func (r *reader) done() bool {
+	return r.ok && len(r.data) == 0
+}
+
+type clientHelloMsg struct {
+	vers               uint16
+	random             []byte
+	sessionID          []byte
+	cipherSuites       []uint16
+	compressionMethods []uint8
+}
+
+func (m *clientHelloMsg) marshal() []byte {
+	b := appendUint16(nil, int(m.vers))
+	b = append(b, m.random...)
+	b = append(b, byte(len(m.sessionID)))
+	b = append(b, m.sessionID...)
+	b = appendUint16(b, 2*len(m.cipherSuites))
+	for _, suite := range m.cipherSuites {
+		b = appendUint16(b, int(suite))
+	}
+	b = append(b, byte(len(m.compressionMethods)))
+	b = append(b, m.compressionMethods...)
+	return handshakeMessage(typeClientHello, b)
+}
+
+func (m *clientHelloMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.vers = uint16(r.uint16())
+	m.random = r.bytes(32)
+	m.sessionID = r.bytes(r.uint8())
+	suites := newReader(r.bytes(r.uint16()))
+	for suites.ok && len(suites.data) > 0 {
+		m.cipherSuites = append(m.cipherSuites, uint16(suites.uint16()))
+	}
+	m.compressionMethods = r.bytes(r.uint8())
+	// extensions are ignored
+	if r.ok && len(r.data) > 0 {
+		r.bytes(r.uint16())
+	}
+	return r.ok && suites.done() && len(m.sessionID) <= 32
+}
+
+type serverHelloMsg struct {
+	vers              uint16
+	random            []byte
+	sessionID         []byte
+	cipherSuite       uint16
+	compressionMethod uint8
+}
+
+func (m *serverHelloMsg) marshal() []byte {
+	b := appendUint16(nil, int(m.vers))
+	b = append(b, m.random...)
+	b = append(b, byte(len(m.sessionID)))
+	b = append(b, m.sessionID...)
+	b = appendUint16(b, int(m.cipherSuite))
+	b = append(b, m.compressionMethod)
+	return handshakeMessage(typeServerHello, b)
+}
+
+func (m *serverHelloMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.vers = uint16(r.uint16())
+	m.random = r.bytes(32)
+	m.sessionID = r.bytes(r.uint8())
+	m.cipherSuite = uint16(r.uint16())
+	m.compressionMethod = uint8(r.uint8())
+	// extensions are ignored
+	if r.ok && len(r.data) > 0 {
+		r.bytes(r.uint16())
+	}
+	return r.ok && len(m.sessionID) <= 32
+}
+
+type certificateMsg struct {
+	certificates [][]byte
+}
+
+func (m *certificateMsg) marshal() []byte {
+	length := 0
+	for _, cert := range m.certificates {
+		length += 3 + len(cert)
+	}
+	b := appendUint24(nil, length)
+	for _, cert := range m.certificates {
+		b = appendUint24(b, len(cert))
+		b = append(b, cert...)
+	}
+	return handshakeMessage(typeCertificate, b)
+}
+
+func (m *certificateMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	certs := newReader(r.bytes(r.uint24()))
+	for certs.ok && len(certs.data) > 0 {
+		cert := certs.bytes(certs.uint24())
+		m.certificates = append(m.certificates, cert)
+	}
+	return r.done() && certs.done()
+}
+
+// serverKeyExchangeMsg carries the signature of the ECC key exchange
+type serverKeyExchangeMsg struct {
+	signature []byte
+}
+
+func (m *serverKeyExchangeMsg) marshal() []byte {
+	b := appendUint16(nil, len(m.signature))
+	b = append(b, m.signature...)
+	return handshakeMessage(typeServerKeyExchange, b)
+}
+
+func (m *serverKeyExchangeMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.signature = r.bytes(r.uint16())
+	return r.done()
+}
+
+type certificateRequestMsg struct {
+	certificateTypes       []byte
+	certificateAuthorities [][]byte
+}
+
+func (m *certificateRequestMsg) marshal() []byte {
+	b := append([]byte{byte(len(m.certificateTypes))}, m.certificateTypes...)
+	length := 0
+	for _, ca := range m.certificateAuthorities {
+		length += 2 + len(ca)
+	}
+	b = appendUint16(b, length)
+	for _, ca := range m.certificateAuthorities {
+		b = appendUint16(b, len(ca))
+		b = append(b, ca...)
+	}
+	return handshakeMessage(typeCertificateRequest, b)
+}
+
+func (m *certificateRequestMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.certificateTypes = r.bytes(r.uint8())
+	cas := newReader(r.bytes(r.uint16()))
+	for cas.ok && len(cas.data) > 0 {
+		m.certificateAuthorities = append(m.certificateAuthorities, cas.bytes(cas.uint16()))
+	}
+	return r.done() && cas.done()
+}
+
+// clientKeyExchangeMsg carries the encrypted pre-master secret
+type clientKeyExchangeMsg struct {
+	ciphertext []byte
+}
+
+func (m *clientKeyExchangeMsg) marshal() []byte {
+	b := appendUint16(nil, len(m.ciphertext))
+	b = append(b, m.ciphertext...)
+	return handshakeMessage(typeClientKeyExchange, b)
+}
+
+func (m *clientKeyExchangeMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.ciphertext = r.bytes(r.uint16())
+	return r.done()
+}
+
+type certificateVerifyMsg struct {
+	signature []byte
+}
+
+func (m *certificateVerifyMsg) marshal() []byte {
+	b := appendUint16(nil, len(m.signature))
+	b = append(b, m.signature...)
+	return handshakeMessage(typeCertificateVerify, b)
+}
+
+func (m *certificateVerifyMsg) unmarshal(body []byte) bool {
+	r := newReader(body)
+	m.signature = r.bytes(r.uint16())
+	return r.done()
+}
+
+type finishedMsg struct {
+	verifyData []byte
+}
+
+func (m *finishedMsg) marshal() []byte {
+	return handshakeMessage(typeFinished, m.verifyData)
+}
+
+func (m *finishedMsg) unmarshal(body []byte) bool {
+	m.verifyData = body
+	return len(body) == finishedVerifyLen
+}
@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func (c *Conn) serverHandshake() error {
+	config := c.config
+	if config == nil || config.SignCertificate == nil || config.EncCertificate == nil ||
+		len(config.SignCertificate.Certificate) == 0 || len(config.EncCertificate.Certificate) == 0 {
+		return errors.New("gmtls: signing and encryption certificates are required by servers")
+	}
+	signer, ok := config.SignCertificate.PrivateKey.(crypto.Signer)
+	if !ok {
+		return errors.Errorf("gmtls: signing certificate private key of type %T is not a signer", config.SignCertificate.PrivateKey)
+	}
+	decrypter, ok := config.EncCertificate.PrivateKey.(Decrypter)
+	if !ok {
+		return errors.Errorf("gmtls: encryption certificate private key of type %T is not a decrypter", config.EncCertificate.PrivateKey)
+	}
+
+	// ClientHello
+	hs := &finishedHash{}
+	hello := &clientHelloMsg{}
+	if err := c.readHandshakeMsg(hs, typeClientHello, hello.unmarshal); err != nil {
+		return err
+	}
+	if hello.vers < VersionTLCP {
+		return c.sendAlert(alertProtocolVersion)
+	}
+	if !containsSuite(hello.cipherSuites, ECC_SM4_CBC_SM3) || !containsCompression(hello.compressionMethods, 0) {
+		return c.sendAlert(alertHandshakeFailure)
+	}
+
+	// ServerHello
+	serverHello := &serverHelloMsg{
+		vers:        VersionTLCP,
+		random:      make([]byte, 32),
+		cipherSuite: ECC_SM4_CBC_SM3,
+	}
+	if _, err := io.ReadFull(config.rand(), serverHello.random); err != nil {
+		return errors.Wrap(err, "gmtls: short read from Rand")
+	}
+	if err := c.writeHandshake(hs, serverHello.marshal()); err != nil {
+		return err
+	}
+
+	// Certificate: signing certificate, encryption certificate and intermediates
+	signChain, encChain := config.SignCertificate.Certificate, config.EncCertificate.Certificate
+	certMsg := &certificateMsg{certificates: append([][]byte{signChain[0], encChain[0]}, signChain[1:]...)}
+	if err := c.writeHandshake(hs, certMsg.marshal()); err != nil {
+		return err
+	}
+
+	// ServerKeyExchange
+	signature, err := signer.Sign(config.rand(), keyExchangeSigned(hello.random, serverHello.random, encChain[0]), nil)
+	if err != nil {
+		c.sendAlert(alertInternalError) //nolint
+		return errors.Wrap(err, "gmtls: failed to sign key exchange")
+	}
+	skx := &serverKeyExchangeMsg{signature: signature}
+	if err := c.writeHandshake(hs, skx.marshal()); err != nil {
+		return err
+	}
+
+	// CertificateRequest
+	if config.ClientAuth != NoClientCert {
+		certReq := &certificateRequestMsg{certificateTypes: []byte{certTypeECDSASign}}
+		if config.ClientCAs != nil {
+			certReq.certificateAuthorities = config.ClientCAs.Subjects()
+		}
+		if err := c.writeHandshake(hs, certReq.marshal()); err != nil {
+			return err
+		}
+	}
+
+	// ServerHelloDone
+	if err := c.writeHandshake(hs, handshakeMessage(typeServerHelloDone, nil)); err != nil {
+		return err
+	}
+
+	// client Certificate
+	var clientPub *sm2.PublicKey
+	if config.ClientAuth != NoClientCert {
+		clientCert := &certificateMsg{}
+		if err := c.readHandshakeMsg(hs, typeCertificate, clientCert.unmarshal); err != nil {
+			return err
+		}
+		if clientPub, err = c.verifyClientCertificates(clientCert.certificates); err != nil {
+			return err
+		}
+	}
+
+	// ClientKeyExchange
+	ckx := &clientKeyExchangeMsg{}
+	if err := c.readHandshakeMsg(hs, typeClientKeyExchange, ckx.unmarshal); err != nil {
+		return err
+	}
+	ciphertext, err := unmarshalSM2Ciphertext(ckx.ciphertext)
+	if err != nil {
+		return c.sendAlert(alertDecodeError)
+	}
+	preMasterSecret, err := decrypter.Decrypt(ciphertext)
+	if err != nil || len(preMasterSecret) != preMasterLen {
+		return c.sendAlert(alertDecryptError)
+	}
+
+	// CertificateVerify
+	if clientPub != nil {
+		digest := hs.sum()
+		certVerify := &certificateVerifyMsg{}
+		if err := c.readHandshakeMsg(hs, typeCertificateVerify, certVerify.unmarshal); err != nil {
+			return err
+		}
+		if !clientPub.Verify(digest, certVerify.signature) {
+			return c.sendAlert(alertDecryptError)
+		}
+	}
+
+	masterSecret := masterFromPreMasterSecret(preMasterSecret, hello.random, serverHello.random)
+	clientMAC, serverMAC, clientKey, serverKey := keysFromMasterSecret(masterSecret, hello.random, serverHello.random)
+	if err := c.in.prepareCipherSpec(clientKey, clientMAC); err != nil {
+		return err
+	}
+	if err := c.out.prepareCipherSpec(serverKey, serverMAC); err != nil {
+		return err
+	}
+
+	if err := c.readChangeCipherSpec(); err != nil {
+		return err
+	}
+	expected := hs.clientSum(masterSecret)
+	clientFinished := &finishedMsg{}
+	if err := c.readHandshakeMsg(hs, typeFinished, clientFinished.unmarshal); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expected, clientFinished.verifyData) != 1 {
+		return c.sendAlert(alertHandshakeFailure)
+	}
+
+	if err := c.writeChangeCipherSpec(); err != nil {
+		return err
+	}
+	finished := &finishedMsg{verifyData: hs.serverSum(masterSecret)}
+	if err := c.writeHandshake(hs, finished.marshal()); err != nil {
+		return err
+	}
+
+	c.cipherSuite = ECC_SM4_CBC_SM3
+	return nil
+}
+
+// verifyClientCertificates verifies the certificates sent by a client and returns its signing public key,
+// or nil if the client didn't send any certificate and it isn't required
+func (c *Conn) verifyClientCertificates(rawCerts [][]byte) (*sm2.PublicKey, error) {
+	if len(rawCerts) == 0 {
+		if c.config.ClientAuth == RequireAndVerifyClientCert {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, errors.New("gmtls: client didn't provide a certificate")
+		}
+		return nil, nil
+	}
+
+	certs := make([]*sm2.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := sm2.ParseCertificate(raw)
+		if err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, errors.Wrap(err, "gmtls: failed to parse client certificate")
+		}
+		certs[i] = cert
+	}
+
+	// the encryption certificate, if any, follows the signing certificate like for servers
+	var encCert *sm2.Certificate
+	chainCerts := certs[1:]
+	if len(certs) > 1 && !certs[1].IsCA {
+		encCert, chainCerts = certs[1], certs[2:]
+	}
+
+	intermediates := sm2.NewCertPool()
+	for _, cert := range chainCerts {
+		intermediates.AddCert(cert)
+	}
+	opts := sm2.VerifyOptions{
+		Roots:         c.config.ClientCAs,
+		CurrentTime:   c.config.time(),
+		Intermediates: intermediates,
+		KeyUsages:     []sm2.ExtKeyUsage{sm2.ExtKeyUsageClientAuth},
+	}
+	chains, err := certs[0].Verify(opts)
+	if err != nil {
+		c.sendAlert(alertBadCertificate) //nolint
+		return nil, errors.Wrap(err, "gmtls: failed to verify client certificate")
+	}
+	if encCert != nil {
+		opts.KeyUsages = []sm2.ExtKeyUsage{sm2.ExtKeyUsageAny}
+		if _, err := encCert.Verify(opts); err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, errors.Wrap(err, "gmtls: failed to verify client encryption certificate")
+		}
+	}
+
+	if c.config.VerifyPeerCertificate != nil {
+		if err := c.config.VerifyPeerCertificate(rawCerts, chains); err != nil {
+			c.sendAlert(alertBadCertificate) //nolint
+			return nil, err
+		}
+	}
+
+	pub, err := sm2PublicKey(certs[0].PublicKey)
+	if err != nil {
+		c.sendAlert(alertUnsupportedCertificate) //nolint
+		return nil, errors.WithMessage(err, "gmtls: unsupported client certificate")
+	}
+
+	c.peerCertificates = certs
+	c.verifiedChains = chains
+	return pub, nil
+}
+
+func containsSuite(suites []uint16, suite uint16) bool {
+	for _, s := range suites {
+		if s == suite {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCompression(methods []uint8, method uint8) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
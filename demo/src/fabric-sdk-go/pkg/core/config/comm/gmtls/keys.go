@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+// sm2Ciphertext is the ASN.1 structure of SM2 ciphertexts defined by GM/T 0009-2012
+type sm2Ciphertext struct {
+	X          *big.Int
+	Y          *big.Int
+	Hash       []byte
+	CipherText []byte
+}
+
+// marshalSM2Ciphertext converts a ciphertext from the 0x04||C1||C3||C2 format of sm2.Encrypt to GM/T 0009-2012
+func marshalSM2Ciphertext(raw []byte) ([]byte, error) {
+	if len(raw) < 97 || raw[0] != 0x04 {
+		return nil, errors.New("invalid SM2 ciphertext")
+	}
+	return asn1.Marshal(sm2Ciphertext{
+		X:          new(big.Int).SetBytes(raw[1:33]),
+		Y:          new(big.Int).SetBytes(raw[33:65]),
+		Hash:       raw[65:97],
+		CipherText: raw[97:],
+	})
+}
+
+// unmarshalSM2Ciphertext converts a GM/T 0009-2012 ciphertext to the format of sm2.Decrypt
+func unmarshalSM2Ciphertext(der []byte) ([]byte, error) {
+	var c sm2Ciphertext
+	rest, err := asn1.Unmarshal(der, &c)
+	if err != nil || len(rest) != 0 {
+		return nil, errors.New("invalid SM2 ciphertext")
+	}
+	if c.X.Sign() < 0 || c.X.BitLen() > 256 || c.Y.Sign() < 0 || c.Y.BitLen() > 256 || len(c.Hash) != 32 {
+		return nil, errors.New("invalid SM2 ciphertext")
+	}
+
+	raw := make([]byte, 97, 97+len(c.CipherText))
+	raw[0] = 0x04
+	x, y := c.X.Bytes(), c.Y.Bytes()
+	copy(raw[33-len(x):33], x)
+	copy(raw[65-len(y):65], y)
+	copy(raw[65:], c.Hash)
+	return append(raw, c.CipherText...), nil
+}
+
+// sm2PublicKey returns the SM2 public key of a certificate
+func sm2PublicKey(pub interface{}) (*sm2.PublicKey, error) {
+	switch k := pub.(type) {
+	case *sm2.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		if !isSM2Curve(k) {
+			return nil, errors.New("certificate public key is not an SM2 key")
+		}
+		return &sm2.PublicKey{Curve: sm2.P256Sm2(), X: k.X, Y: k.Y}, nil
+	default:
+		return nil, errors.Errorf("unsupported certificate public key type %T", pub)
+	}
+}
+
+func isSM2Curve(k *ecdsa.PublicKey) bool {
+	params, sm2Params := k.Curve.Params(), sm2.P256Sm2().Params()
+	return params.P.Cmp(sm2Params.P) == 0 && params.N.Cmp(sm2Params.N) == 0 && params.B.Cmp(sm2Params.B) == 0
+}
+
+// X509KeyPair parses an SM2 certificate chain and its private key from PEM encoded data.
+// The private key may be in PKCS#8 or SEC 1 form. The key is used for signing or decryption
+// depending on the certificate it is configured as. pwd decrypts an encrypted private key,
+// it may be nil otherwise.
+func X509KeyPair(certPEMBlock, keyPEMBlock, pwd []byte) (Certificate, error) {
+	var cert Certificate
+	for {
+		var block *pem.Block
+		block, certPEMBlock = pem.Decode(certPEMBlock)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			cert.Certificate = append(cert.Certificate, block.Bytes)
+		}
+	}
+	if len(cert.Certificate) == 0 {
+		return Certificate{}, errors.New("no certificate found in certificate PEM data")
+	}
+
+	leaf, err := sm2.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return Certificate{}, errors.Wrap(err, "failed to parse certificate")
+	}
+	pub, err := sm2PublicKey(leaf.PublicKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	key, err := hx509.PEMtoPrivateKey(keyPEMBlock, pwd)
+	if err != nil {
+		return Certificate{}, errors.WithMessage(err, "failed to parse private key")
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok || !isSM2Curve(&ecKey.PublicKey) {
+		return Certificate{}, errors.New("private key is not an SM2 key")
+	}
+	if ecKey.X.Cmp(pub.X) != 0 || ecKey.Y.Cmp(pub.Y) != 0 {
+		return Certificate{}, errors.New("private key does not match certificate public key")
+	}
+
+	cert.PrivateKey = &sm2.PrivateKey{PublicKey: *pub, D: ecKey.D}
+	return cert, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmtls
+
+import (
+	"bytes"
+	"crypto/hmac"
+
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+// ECC_SM4_CBC_SM3 key block lengths
+const (
+	macLen = 32
+	keyLen = 16
+	ivLen  = 16
+)
+
+// pHash implements the P_hash function of RFC 5246 with HMAC-SM3
+func pHash(result, secret, seed []byte) {
+	h := hmac.New(sm3.New, secret)
+	h.Write(seed)
+	a := h.Sum(nil)
+
+	j := 0
+	for j < len(result) {
+		h.Reset()
+		h.Write(a)
+		h.Write(seed)
+		b := h.Sum(nil)
+		copy(result[j:], b)
+		j += len(b)
+
+		h.Reset()
+		h.Write(a)
+		a = h.Sum(nil)
+	}
+}
+
+// prf is the TLS 1.2 pseudo-random function with SM3, as specified by GM/T 0024-2014
+func prf(result, secret []byte, label string, seed []byte) {
+	labelAndSeed := make([]byte, len(label)+len(seed))
+	copy(labelAndSeed, label)
+	copy(labelAndSeed[len(label):], seed)
+	pHash(result, secret, labelAndSeed)
+}
+
+func masterFromPreMasterSecret(preMasterSecret, clientRandom, serverRandom []byte) []byte {
+	seed := make([]byte, 0, len(clientRandom)+len(serverRandom))
+	seed = append(seed, clientRandom...)
+	seed = append(seed, serverRandom...)
+
+	masterSecret := make([]byte, masterSecretLen)
+	prf(masterSecret, preMasterSecret, "master secret", seed)
+	return masterSecret
+}
+
+// keysFromMasterSecret generates the connection keys from the master secret
+func keysFromMasterSecret(masterSecret, clientRandom, serverRandom []byte) (clientMAC, serverMAC, clientKey, serverKey []byte) {
+	seed := make([]byte, 0, len(serverRandom)+len(clientRandom))
+	seed = append(seed, serverRandom...)
+	seed = append(seed, clientRandom...)
+
+	keyMaterial := make([]byte, 2*macLen+2*keyLen+2*ivLen)
+	prf(keyMaterial, masterSecret, "key expansion", seed)
+	clientMAC = keyMaterial[:macLen]
+	keyMaterial = keyMaterial[macLen:]
+	serverMAC = keyMaterial[:macLen]
+	keyMaterial = keyMaterial[macLen:]
+	clientKey = keyMaterial[:keyLen]
+	keyMaterial = keyMaterial[keyLen:]
+	serverKey = keyMaterial[:keyLen]
+	// the IVs are not used, records carry an explicit IV
+	return
+}
+
+// finishedHash keeps the handshake messages exchanged so far
+type finishedHash struct {
+	buf bytes.Buffer
+}
+
+func (h *finishedHash) Write(msg []byte) {
+	h.buf.Write(msg)
+}
+
+// sum returns the SM3 digest of the handshake messages
+func (h *finishedHash) sum() []byte {
+	return sm3.Sm3Sum(h.buf.Bytes())
+}
+
+func (h *finishedHash) clientSum(masterSecret []byte) []byte {
+	out := make([]byte, finishedVerifyLen)
+	prf(out, masterSecret, "client finished", h.sum())
+	return out
+}
+
+func (h *finishedHash) serverSum(masterSecret []byte) []byte {
+	out := make([]byte, finishedVerifyLen)
+	prf(out, masterSecret, "server finished", h.sum())
+	return out
+}
@@ -100,6 +100,16 @@ func (c *certPool) Add(certs ...*x509.Certificate) {
 	}
 }
 
+//Certs returns the certs added to the cert pool, not including the system trust store
+func (c *certPool) Certs() []*x509.Certificate {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	certs := make([]*x509.Certificate, len(c.certs))
+	copy(certs, c.certs)
+	return certs
+}
+
 func (c *certPool) swapCertPool() error {
 
 	newCertPool, err := loadSystemCertPool(c.systemCertPool)
@@ -14,6 +14,7 @@ import (
 
 	"regexp"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	"github.com/pkg/errors"
 )
 
@@ -62,6 +63,9 @@ type MutualTLSConfig struct {
 
 	//Client TLS information
 	Client TLSKeyPair
+
+	//Client GM/T TLS encryption key pair, sent along with Client (optional)
+	EncClient TLSKeyPair
 }
 
 // TLSKeyPair contains the private key and certificate for TLS encryption
@@ -110,7 +114,8 @@ func (cfg *TLSConfig) TLSCert() (*x509.Certificate, bool, error) {
 	block, _ := pem.Decode(cfg.bytes)
 
 	if block != nil {
-		pub, err := x509.ParseCertificate(block.Bytes)
+		// SM2 certificates are supported for GM/T TLS
+		pub, err := hx509.ParseCertificate(block.Bytes)
 		if err != nil {
 			return nil, false, errors.Wrap(err, "certificate parsing failed")
 		}
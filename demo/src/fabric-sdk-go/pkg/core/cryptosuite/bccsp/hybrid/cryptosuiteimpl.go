@@ -0,0 +1,253 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hybrid
+
+import (
+	"crypto/x509"
+	"hash"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// hybridConfig is implemented by crypto suite configs that can enable the hybrid crypto suite
+// (see cryptosuite.Config.SecurityHybrid)
+type hybridConfig interface {
+	SecurityHybrid() bool
+}
+
+// GetSuiteByConfig returns local, the crypto suite loaded for the provider of config, combined with
+// the SW and SM suites if the config enables the hybrid crypto suite, and local itself otherwise
+func GetSuiteByConfig(config core.CryptoSuiteConfig, local core.CryptoSuite) (core.CryptoSuite, error) {
+	if hc, ok := config.(hybridConfig); !ok || !hc.SecurityHybrid() {
+		return local, nil
+	}
+
+	switch config.SecurityProvider() {
+	case "sm", "smpkcs11":
+		other, err := sw.GetSuiteWithDefaultEphemeral()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to initialize SW crypto suite")
+		}
+		logger.Debug("Initialized hybrid cryptosuite signing with SM2")
+		return New(local, other, local), nil
+	default:
+		other, err := sm.GetSuiteWithDefaultEphemeral()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to initialize SM crypto suite")
+		}
+		logger.Debug("Initialized hybrid cryptosuite signing with ECDSA")
+		return New(local, local, other), nil
+	}
+}
+
+// New returns a crypto suite for networks mixing SW and SM organizations. Keys are generated and looked up
+// by local, the suite of the local identity, which is either swSuite or smSuite. Keys are imported by
+// swSuite or smSuite depending on the algorithm of the key or certificate (ECDSA or SM2), and then
+// sign and verify with the suite that imported them. Digests are computed by the suite of the hash algorithm.
+//
+// Unlike the SM suite in legacy hash mode, SHA hash opts always yield SHA digests: the generic
+// SHA opts (cryptosuite.GetSHAOpts) follow the local suite.
+func New(local, swSuite, smSuite core.CryptoSuite) *CryptoSuite {
+	return &CryptoSuite{
+		local: local,
+		sw:    swSuite,
+		sm:    smSuite,
+	}
+}
+
+// CryptoSuite dispatches to the SW or SM crypto suite
+type CryptoSuite struct {
+	local core.CryptoSuite
+	sw    core.CryptoSuite
+	sm    core.CryptoSuite
+}
+
+// KeyGen generates a key with the SM suite for the SM algorithms and with the local suite otherwise
+func (c *CryptoSuite) KeyGen(opts core.KeyGenOpts) (k core.Key, err error) {
+	cs := c.local
+	if opts != nil {
+		cs = c.suiteForAlgorithm(opts.Algorithm())
+	}
+	k, err = cs.KeyGen(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &key{Key: k, suite: cs}, nil
+}
+
+// KeyImport imports a key with the suite of the algorithm of raw, which is a certificate, a public
+// or private key or its DER encoding, or of the algorithm of opts for symmetric keys
+func (c *CryptoSuite) KeyImport(raw interface{}, opts core.KeyImportOpts) (k core.Key, err error) {
+	cs := c.suiteForKey(raw, opts)
+	k, err = cs.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &key{Key: k, suite: cs}, nil
+}
+
+// GetKey returns the key of the local suite, or of the other suite if the local suite doesn't know ski
+func (c *CryptoSuite) GetKey(ski []byte) (k core.Key, err error) {
+	k, err = c.local.GetKey(ski)
+	if err == nil {
+		return &key{Key: k, suite: c.local}, nil
+	}
+
+	other := c.other()
+	if k, otherErr := other.GetKey(ski); otherErr == nil {
+		return &key{Key: k, suite: other}, nil
+	}
+	return nil, err
+}
+
+// Hash hashes msg with the suite of the hash algorithm of opts
+func (c *CryptoSuite) Hash(msg []byte, opts core.HashOpts) (hash []byte, err error) {
+	return c.suiteForHash(opts).Hash(msg, opts)
+}
+
+// GetHash returns the hash function of the suite of the hash algorithm of opts
+func (c *CryptoSuite) GetHash(opts core.HashOpts) (h hash.Hash, err error) {
+	return c.suiteForHash(opts).GetHash(opts)
+}
+
+// Sign signs digest with the suite of k
+func (c *CryptoSuite) Sign(k core.Key, digest []byte, opts core.SignerOpts) (signature []byte, err error) {
+	cs, k := c.suiteOf(k)
+	return cs.Sign(k, digest, opts)
+}
+
+// Verify verifies signature with the suite of k
+func (c *CryptoSuite) Verify(k core.Key, signature, digest []byte, opts core.SignerOpts) (valid bool, err error) {
+	cs, k := c.suiteOf(k)
+	return cs.Verify(k, signature, digest, opts)
+}
+
+// Encrypt encrypts plaintext with the suite of k
+func (c *CryptoSuite) Encrypt(k core.Key, plaintext []byte, opts core.EncrypterOpts) (ciphertext []byte, err error) {
+	cs, k := c.suiteOf(k)
+	e, ok := cs.(core.Encrypter)
+	if !ok {
+		return nil, errors.New("crypto suite of the key does not support encryption")
+	}
+	return e.Encrypt(k, plaintext, opts)
+}
+
+// Decrypt decrypts ciphertext with the suite of k
+func (c *CryptoSuite) Decrypt(k core.Key, ciphertext []byte, opts core.DecrypterOpts) (plaintext []byte, err error) {
+	cs, k := c.suiteOf(k)
+	e, ok := cs.(core.Encrypter)
+	if !ok {
+		return nil, errors.New("crypto suite of the key does not support decryption")
+	}
+	return e.Decrypt(k, ciphertext, opts)
+}
+
+// NetworkHashOpts returns the network hash opts of the local suite
+func (c *CryptoSuite) NetworkHashOpts() core.HashOpts {
+	return cryptosuite.GetNetworkHashOpts(c.local)
+}
+
+// PrepareSignatureDigest prepares the digest for the keys of the local suite
+func (c *CryptoSuite) PrepareSignatureDigest(msg, digest []byte) []byte {
+	return cryptosuite.PrepareSignatureDigest(c.local, msg, digest)
+}
+
+// PrepareKeySignatureDigest prepares the digest for the suite of k: msg for SM2 keys,
+// digest for ECDSA keys
+func (c *CryptoSuite) PrepareKeySignatureDigest(k core.Key, msg, digest []byte) []byte {
+	cs, _ := c.suiteOf(k)
+	return cryptosuite.PrepareSignatureDigest(cs, msg, digest)
+}
+
+func (c *CryptoSuite) other() core.CryptoSuite {
+	if c.local == c.sm {
+		return c.sw
+	}
+	return c.sm
+}
+
+// suiteOf returns the suite of k, which is unwrapped, or the local suite for keys of other suites
+func (c *CryptoSuite) suiteOf(k core.Key) (core.CryptoSuite, core.Key) {
+	if hk, ok := k.(*key); ok {
+		return hk.suite, hk.Key
+	}
+	return c.local, k
+}
+
+// suiteForAlgorithm returns the SM suite for the SM algorithms (SM2, SM3, SM4) and the local suite otherwise
+func (c *CryptoSuite) suiteForAlgorithm(algorithm string) core.CryptoSuite {
+	if strings.HasPrefix(algorithm, "SM") {
+		return c.sm
+	}
+	return c.local
+}
+
+func (c *CryptoSuite) suiteForKey(raw interface{}, opts core.KeyImportOpts) core.CryptoSuite {
+	switch r := raw.(type) {
+	case *x509.Certificate:
+		if hx509.IsSMCertificate(r) {
+			return c.sm
+		}
+		return c.sw
+	case []byte:
+		if hx509.IsSMKey(r) {
+			return c.sm
+		}
+		if opts == nil {
+			return c.local
+		}
+		// only the SW suite imports ECDSA and AES keys
+		if strings.HasPrefix(opts.Algorithm(), bccsp.ECDSA) || opts.Algorithm() == bccsp.AES {
+			return c.sw
+		}
+		// symmetric keys
+		return c.suiteForAlgorithm(opts.Algorithm())
+	}
+
+	if hx509.IsSMKey(raw) {
+		return c.sm
+	}
+	return c.sw
+}
+
+func (c *CryptoSuite) suiteForHash(opts core.HashOpts) core.CryptoSuite {
+	if opts == nil {
+		return c.local
+	}
+	switch opts.Algorithm() {
+	case bccsp.SHA:
+		return c.local
+	case "SM3":
+		return c.sm
+	}
+	// SHA2 and SHA3
+	return c.sw
+}
+
+// key is a key of the SW or SM suite
+type key struct {
+	core.Key
+	suite core.CryptoSuite
+}
+
+func (k *key) PublicKey() (core.Key, error) {
+	pk, err := k.Key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &key{Key: pk, suite: k.suite}, nil
+}
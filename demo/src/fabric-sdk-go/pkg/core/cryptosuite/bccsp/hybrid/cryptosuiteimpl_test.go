@@ -0,0 +1,279 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hybrid
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+type hybridCryptoSuiteConfig struct {
+	core.CryptoSuiteConfig
+	hybrid bool
+}
+
+func (c *hybridCryptoSuiteConfig) SecurityHybrid() bool {
+	return c.hybrid
+}
+
+func TestGetSuiteByConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	local, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	cs, err := GetSuiteByConfig(mockcore.NewMockCryptoSuiteConfig(mockCtrl), local)
+	require.NoError(t, err)
+	assert.Equal(t, local, cs, "configs without the hybrid setting keep the provider suite")
+
+	cs, err = GetSuiteByConfig(&hybridCryptoSuiteConfig{CryptoSuiteConfig: mockcore.NewMockCryptoSuiteConfig(mockCtrl)}, local)
+	require.NoError(t, err)
+	assert.Equal(t, local, cs)
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw")
+	cs, err = GetSuiteByConfig(&hybridCryptoSuiteConfig{CryptoSuiteConfig: mockConfig, hybrid: true}, local)
+	require.NoError(t, err)
+	hcs, ok := cs.(*CryptoSuite)
+	require.True(t, ok)
+	assert.Equal(t, local, hcs.local)
+	assert.Equal(t, local, hcs.sw)
+
+	mockConfig = mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sm")
+	cs, err = GetSuiteByConfig(&hybridCryptoSuiteConfig{CryptoSuiteConfig: mockConfig, hybrid: true}, local)
+	require.NoError(t, err)
+	hcs, ok = cs.(*CryptoSuite)
+	require.True(t, ok)
+	assert.Equal(t, local, hcs.local)
+	assert.Equal(t, local, hcs.sm)
+}
+
+// newSuites returns hybrid suites signing with ECDSA and SM2
+func newSuites(t *testing.T) (swLocal, smLocal *CryptoSuite) {
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	smSuite, err := sm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	return New(swSuite, swSuite, smSuite), New(smSuite, swSuite, smSuite)
+}
+
+func TestSignVerify(t *testing.T) {
+	swLocal, smLocal := newSuites(t)
+	msg := []byte("message")
+
+	for name, cs := range map[string]*CryptoSuite{"SW": swLocal, "SM": smLocal} {
+		t.Run(name, func(t *testing.T) {
+			key, err := cs.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+			require.NoError(t, err)
+			pub, err := key.PublicKey()
+			require.NoError(t, err)
+			raw, err := pub.Bytes()
+			require.NoError(t, err)
+			assert.Equal(t, cs == smLocal, hx509.IsSMKey(raw), "keys are generated by the local suite")
+
+			digest, err := cs.Hash(msg, cryptosuite.GetSHAOpts())
+			require.NoError(t, err)
+			prepared := cryptosuite.PrepareKeySignatureDigest(cs, key, msg, digest)
+			signature, err := cs.Sign(key, prepared, nil)
+			require.NoError(t, err)
+
+			valid, err := cs.Verify(pub, signature, prepared, nil)
+			require.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+}
+
+func TestVerifyMixedIdentities(t *testing.T) {
+	swLocal, smLocal := newSuites(t)
+	msg := []byte("message")
+
+	smKey, err := sm2.GenerateKey()
+	require.NoError(t, err)
+	smSignature, err := smKey.Sign(rand.Reader, msg, nil)
+	require.NoError(t, err)
+
+	swKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sum := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, swKey, sum[:])
+	require.NoError(t, err)
+	swSignature, err := utils.MarshalECDSASignature(r, s)
+	require.NoError(t, err)
+	swSignature, err = utils.SignatureToLowS(&swKey.PublicKey, swSignature)
+	require.NoError(t, err)
+
+	identities := map[string]struct {
+		key       crypto.Signer
+		signature []byte
+	}{
+		"SW identity": {swKey, swSignature},
+		"SM identity": {smKey, smSignature},
+	}
+
+	for suiteName, cs := range map[string]*CryptoSuite{"SW suite": swLocal, "SM suite": smLocal} {
+		for name, id := range identities {
+			t.Run(suiteName+"/"+name, func(t *testing.T) {
+				cert := newCertificate(t, id.key)
+				key, err := cs.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+				require.NoError(t, err)
+
+				// identities hash with SHA-256 (SignatureHashFamily SHA2)
+				digest, err := cs.Hash(msg, cryptosuite.GetSHA256Opts())
+				require.NoError(t, err)
+				assert.Equal(t, sum[:], digest)
+				prepared := cryptosuite.PrepareKeySignatureDigest(cs, key, msg, digest)
+
+				valid, err := cs.Verify(key, id.signature, prepared, nil)
+				require.NoError(t, err)
+				assert.True(t, valid)
+
+				tampered := cryptosuite.PrepareKeySignatureDigest(cs, key, []byte("tampered"), make([]byte, len(digest)))
+				valid, _ = cs.Verify(key, id.signature, tampered, nil)
+				assert.False(t, valid)
+			})
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	swLocal, smLocal := newSuites(t)
+	msg := []byte("message")
+	sha256Sum := sha256.Sum256(msg)
+	sm3Sum := sm3.Sm3Sum(msg)
+
+	for name, cs := range map[string]*CryptoSuite{"SW": swLocal, "SM": smLocal} {
+		t.Run(name, func(t *testing.T) {
+			digest, err := cs.Hash(msg, cryptosuite.GetSHA256Opts())
+			require.NoError(t, err)
+			assert.Equal(t, sha256Sum[:], digest, "SHA-256 is computed for both kinds of identities")
+
+			digest, err = cs.Hash(msg, cryptosuite.GetNetworkHashOpts(smLocal.sm))
+			require.NoError(t, err)
+			assert.Equal(t, sm3Sum, digest)
+
+			h, err := cs.GetHash(cryptosuite.GetSHA256Opts())
+			require.NoError(t, err)
+			h.Write(msg)
+			assert.Equal(t, sha256Sum[:], h.Sum(nil))
+		})
+	}
+
+	// the generic SHA opts and the network hash follow the local suite (legacy SM hash mode)
+	digest, err := swLocal.Hash(msg, cryptosuite.GetSHAOpts())
+	require.NoError(t, err)
+	assert.Equal(t, sha256Sum[:], digest)
+	digest, err = smLocal.Hash(msg, cryptosuite.GetSHAOpts())
+	require.NoError(t, err)
+	assert.Equal(t, sm3Sum, digest)
+
+	digest, err = swLocal.Hash(msg, cryptosuite.GetNetworkHashOpts(swLocal))
+	require.NoError(t, err)
+	assert.Equal(t, sha256Sum[:], digest)
+	digest, err = smLocal.Hash(msg, cryptosuite.GetNetworkHashOpts(smLocal))
+	require.NoError(t, err)
+	assert.Equal(t, sm3Sum, digest)
+}
+
+func TestSymmetricKeys(t *testing.T) {
+	swLocal, smLocal := newSuites(t)
+
+	// SM4 keys are handled by the SM suite whatever the local suite
+	key, err := swLocal.KeyGen(cryptosuite.GetSM4KeyGenOpts(true))
+	require.NoError(t, err)
+
+	plaintext := []byte("off-chain payload")
+	ciphertext, err := swLocal.Encrypt(key, plaintext, cryptosuite.GetSM4CBCPKCS7ModeOpts())
+	require.NoError(t, err)
+	decrypted, err := swLocal.Decrypt(key, ciphertext, cryptosuite.GetSM4CBCPKCS7ModeOpts())
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	imported, err := swLocal.KeyImport([]byte("0123456789abcdef"), cryptosuite.GetSM4ImportKeyOpts(true))
+	require.NoError(t, err)
+	_, err = swLocal.Encrypt(imported, plaintext, cryptosuite.GetSM4CBCPKCS7ModeOpts())
+	assert.NoError(t, err)
+
+	// AES keys are handled by the SW suite whatever the local suite
+	imported, err = smLocal.KeyImport([]byte("0123456789abcdef0123456789abcdef"), cryptosuite.GetAES256ImportKeyOpts(true))
+	require.NoError(t, err)
+	ciphertext, err = smLocal.Encrypt(imported, plaintext, cryptosuite.GetAESGCMModeOpts([]byte("additional data")))
+	require.NoError(t, err)
+	decrypted, err = smLocal.Decrypt(imported, ciphertext, cryptosuite.GetAESGCMModeOpts([]byte("additional data")))
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+	_, err = smLocal.Decrypt(imported, ciphertext, cryptosuite.GetAESGCMModeOpts(nil))
+	assert.Error(t, err)
+}
+
+func newCertificate(t *testing.T, key crypto.Signer) *x509.Certificate {
+	template := &sm2.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "identity"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if _, ok := key.(*sm2.PrivateKey); ok {
+		template.SignatureAlgorithm = sm2.SM2WithSM3
+	}
+	der, err := sm2.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := hx509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestEnvelope(t *testing.T) {
+	swLocal, smLocal := newSuites(t)
+	data := []byte("off-chain payload")
+
+	// envelopes are sealed for SW and SM recipients whatever the local suite
+	for name, cs := range map[string]*CryptoSuite{"SW": swLocal, "SM": smLocal} {
+		for recipientName, opts := range map[string]core.KeyGenOpts{"ECDSA": cryptosuite.GetECDSAP256KeyGenOpts(true), "SM2": cryptosuite.GetSM2KeyGenOpts(true)} {
+			t.Run(name+"/"+recipientName, func(t *testing.T) {
+				key, err := cs.KeyGen(opts)
+				require.NoError(t, err)
+				pub, err := key.PublicKey()
+				require.NoError(t, err)
+				raw, err := pub.Bytes()
+				require.NoError(t, err)
+				assert.Equal(t, recipientName == "SM2" || cs == smLocal, hx509.IsSMKey(raw))
+
+				env, err := cryptosuite.SealEnvelope(cs, pub, data)
+				require.NoError(t, err)
+				opened, err := cryptosuite.OpenEnvelope(cs, key, env)
+				require.NoError(t, err)
+				assert.Equal(t, data, opened)
+			})
+		}
+	}
+}
@@ -8,14 +8,24 @@ package multisuite
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/hybrid"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/smpkcs11"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/pkg/errors"
 )
 
 //GetSuiteByConfig returns cryptosuite adaptor for bccsp loaded according to given config
 func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	cs, err := getProviderSuiteByConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.GetSuiteByConfig(config, cs)
+}
+
+func getProviderSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	switch config.SecurityProvider() {
 	case "sw":
 		return sw.GetSuiteByConfig(config)
@@ -23,6 +33,8 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return pkcs11.GetSuiteByConfig(config)
 	case "sm":
 		return sm.GetSuiteByConfig(config)
+	case "smpkcs11":
+		return smpkcs11.GetSuiteByConfig(config)
 	}
 
 	return nil, errors.Errorf("Unsupported security provider requested: %s", config.SecurityProvider())
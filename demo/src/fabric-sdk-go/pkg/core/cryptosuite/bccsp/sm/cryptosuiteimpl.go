@@ -19,6 +19,14 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	}
 
 	opts := getOptsByConfig(config)
+	if c, ok := config.(keyStorePasswordConfig); ok {
+		password, err := c.KeyStorePassword()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get keystore password")
+		}
+		opts.FileKeystore.Password = password
+	}
+
 	bccsp, err := getBCCSPFromOpts(opts)
 	if err != nil {
 		return nil, err
@@ -47,6 +55,18 @@ func getBCCSPFromOpts(config *hfactory.SmOpts) (bccsp.BCCSP, error) {
 	return csp, nil
 }
 
+// hashModeConfig is implemented by crypto suite configs that provide the SM hash mode
+// (see cryptosuite.Config.SecurityHashMode)
+type hashModeConfig interface {
+	SecurityHashMode() string
+}
+
+// keyStorePasswordConfig is implemented by crypto suite configs that provide a keystore password
+// (see cryptosuite.Config.KeyStorePassword)
+type keyStorePasswordConfig interface {
+	KeyStorePassword() ([]byte, error)
+}
+
 //GetOptsByConfig Returns Factory opts for given SDK config
 func getOptsByConfig(c core.CryptoSuiteConfig) *hfactory.SmOpts {
 	opts := &hfactory.SmOpts{
@@ -56,6 +76,9 @@ func getOptsByConfig(c core.CryptoSuiteConfig) *hfactory.SmOpts {
 			KeyStorePath: c.KeyStorePath(),
 		},
 	}
+	if hc, ok := c.(hashModeConfig); ok {
+		opts.HashMode = hc.SecurityHashMode()
+	}
 	logger.Debug("Initialized SM cryptosuite")
 
 	return opts
@@ -0,0 +1,253 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sm
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+func TestSM4Encryption(t *testing.T) {
+	cs, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	encrypter, ok := cs.(core.Encrypter)
+	require.True(t, ok, "SM crypto suite is expected to support encryption")
+
+	key, err := cs.KeyGen(cryptosuite.GetSM4KeyGenOpts(true))
+	require.NoError(t, err)
+	assert.True(t, key.Symmetric())
+	assert.True(t, key.Private())
+	assert.NotEmpty(t, key.SKI())
+
+	plaintext := []byte("off-chain payload")
+
+	t.Run("CBC", func(t *testing.T) {
+		opts := cryptosuite.GetSM4CBCPKCS7ModeOpts()
+		ciphertext, err := encrypter.Encrypt(key, plaintext, opts)
+		require.NoError(t, err)
+		assert.NotEqual(t, plaintext, ciphertext)
+
+		decrypted, err := encrypter.Decrypt(key, ciphertext, opts)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+
+		_, err = encrypter.Decrypt(key, ciphertext[:10], opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("GCM", func(t *testing.T) {
+		ciphertext, err := encrypter.Encrypt(key, plaintext, cryptosuite.GetSM4GCMModeOpts([]byte("aad")))
+		require.NoError(t, err)
+
+		decrypted, err := encrypter.Decrypt(key, ciphertext, cryptosuite.GetSM4GCMModeOpts([]byte("aad")))
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+
+		_, err = encrypter.Decrypt(key, ciphertext, cryptosuite.GetSM4GCMModeOpts([]byte("other")))
+		assert.Error(t, err, "authentication is expected to fail with different additional data")
+	})
+
+	t.Run("Unsupported mode", func(t *testing.T) {
+		_, err := encrypter.Encrypt(key, plaintext, cryptosuite.GetSHAOpts())
+		assert.Error(t, err)
+	})
+}
+
+func TestSM4KeyImport(t *testing.T) {
+	cs, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	encrypter := cs.(core.Encrypter)
+
+	raw := []byte("0123456789abcdef")
+	key1, err := cs.KeyImport(raw, cryptosuite.GetSM4ImportKeyOpts(true))
+	require.NoError(t, err)
+	key2, err := cs.KeyImport(raw, cryptosuite.GetSM4ImportKeyOpts(true))
+	require.NoError(t, err)
+	assert.Equal(t, key1.SKI(), key2.SKI())
+
+	ciphertext, err := encrypter.Encrypt(key1, []byte("transient data"), cryptosuite.GetSM4CBCPKCS7ModeOpts())
+	require.NoError(t, err)
+	decrypted, err := encrypter.Decrypt(key2, ciphertext, cryptosuite.GetSM4CBCPKCS7ModeOpts())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("transient data"), decrypted)
+
+	_, err = cs.KeyImport([]byte("short"), cryptosuite.GetSM4ImportKeyOpts(true))
+	assert.Error(t, err)
+}
+
+func TestSM4KeyStore(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "smkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(keyStorePath)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sm")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return(keyStorePath)
+
+	cs, err := GetSuiteByConfig(mockConfig)
+	require.NoError(t, err)
+
+	key, err := cs.KeyGen(cryptosuite.GetSM4KeyGenOpts(false))
+	require.NoError(t, err)
+
+	stored, err := cs.GetKey(key.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, key.SKI(), stored.SKI())
+
+	encrypter := cs.(core.Encrypter)
+	ciphertext, err := encrypter.Encrypt(key, []byte("payload"), cryptosuite.GetSM4GCMModeOpts(nil))
+	require.NoError(t, err)
+	decrypted, err := encrypter.Decrypt(stored, ciphertext, cryptosuite.GetSM4GCMModeOpts(nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), decrypted)
+}
+
+func TestLegacyHashMode(t *testing.T) {
+	cs, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	msg := []byte("message")
+	expected := sm3.Sm3Sum(msg)
+
+	// SHA opts silently yield SM3 for compatibility with existing SM deployments
+	digest, err := cs.Hash(msg, cryptosuite.GetSHA256Opts())
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+
+	digest, err = cs.Hash(msg, cryptosuite.GetSHAOpts())
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+
+	digest, err = cs.Hash(msg, cryptosuite.GetNetworkHashOpts(cs))
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+}
+
+func TestPrepareSignatureDigest(t *testing.T) {
+	cs, err := GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	// the SM suite preprocesses the message itself (Z||M) while signing
+	msg := []byte("message")
+	digest, err := cs.Hash(msg, cryptosuite.GetSHAOpts())
+	require.NoError(t, err)
+	prepared := cryptosuite.PrepareSignatureDigest(cs, msg, digest)
+	assert.Equal(t, msg, prepared)
+
+	key, err := cs.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+	require.NoError(t, err)
+	signature, err := cs.Sign(key, prepared, nil)
+	require.NoError(t, err)
+	pub, err := key.PublicKey()
+	require.NoError(t, err)
+	valid, err := cs.Verify(pub, signature, prepared, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestStrictHashMode(t *testing.T) {
+	msg := []byte("message")
+	sha256Sum := sha256.Sum256(msg)
+	sm3Sum := sm3.Sm3Sum(msg)
+
+	keyStorePath, err := ioutil.TempDir("", "smkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(keyStorePath)
+
+	t.Run("SM3 family", func(t *testing.T) {
+		cs := newStrictSuite(t, keyStorePath, "SM3")
+
+		digest, err := cs.Hash(msg, cryptosuite.GetSHA256Opts())
+		require.NoError(t, err)
+		assert.Equal(t, sha256Sum[:], digest)
+
+		digest, err = cs.Hash(msg, cryptosuite.GetSHAOpts())
+		require.NoError(t, err)
+		assert.Equal(t, sm3Sum, digest)
+
+		digest, err = cs.Hash(msg, cryptosuite.GetNetworkHashOpts(cs))
+		require.NoError(t, err)
+		assert.Equal(t, sm3Sum, digest)
+
+		h, err := cs.GetHash(cryptosuite.GetSHA256Opts())
+		require.NoError(t, err)
+		h.Write(msg)
+		assert.Equal(t, sha256Sum[:], h.Sum(nil))
+	})
+
+	t.Run("SHA2 family", func(t *testing.T) {
+		cs := newStrictSuite(t, keyStorePath, "SHA2")
+
+		digest, err := cs.Hash(msg, cryptosuite.GetSHAOpts())
+		require.NoError(t, err)
+		assert.Equal(t, sha256Sum[:], digest)
+
+		digest, err = cs.Hash(msg, cryptosuite.GetNetworkHashOpts(cs))
+		require.NoError(t, err)
+		assert.Equal(t, sha256Sum[:], digest)
+	})
+
+	t.Run("Unsupported family", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		_, err := GetSuiteByConfig(newHashModeConfig(mockCtrl, keyStorePath, "MD5", "strict"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported mode", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		_, err := GetSuiteByConfig(newHashModeConfig(mockCtrl, keyStorePath, "SM3", "lenient"))
+		assert.Error(t, err)
+	})
+}
+
+func newStrictSuite(t *testing.T, keyStorePath, hashFamily string) core.CryptoSuite {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cs, err := GetSuiteByConfig(newHashModeConfig(mockCtrl, keyStorePath, hashFamily, "strict"))
+	require.NoError(t, err)
+	return cs
+}
+
+type hashModeCryptoSuiteConfig struct {
+	core.CryptoSuiteConfig
+	hashMode string
+}
+
+func (c *hashModeCryptoSuiteConfig) SecurityHashMode() string {
+	return c.hashMode
+}
+
+func newHashModeConfig(mockCtrl *gomock.Controller, keyStorePath, hashFamily, hashMode string) core.CryptoSuiteConfig {
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sm")
+	mockConfig.EXPECT().SecurityAlgorithm().Return(hashFamily)
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return(keyStorePath)
+
+	return &hashModeCryptoSuiteConfig{CryptoSuiteConfig: mockConfig, hashMode: hashMode}
+}
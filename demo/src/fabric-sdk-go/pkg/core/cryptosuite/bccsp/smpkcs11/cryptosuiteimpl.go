@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package smpkcs11
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	factory "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hfactory/smpkcs11"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/smpkcs11"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// GetSuiteByConfig returns cryptosuite adaptor for bccsp loaded according to given config
+func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "smpkcs11" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+
+	opts := getOptsByConfig(config)
+	bccsp, err := getBCCSPFromOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	return wrapper.NewCryptoSuite(bccsp), nil
+}
+
+func getBCCSPFromOpts(config *smpkcs11.SMPKCS11Opts) (bccsp.BCCSP, error) {
+	f := &factory.SMPKCS11Factory{}
+
+	csp, err := f.Get(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not initialize BCCSP %s", f.Name())
+	}
+	return csp, nil
+}
+
+// hashModeConfig is implemented by crypto suite configs that provide the SM hash mode
+// (see cryptosuite.Config.SecurityHashMode)
+type hashModeConfig interface {
+	SecurityHashMode() string
+}
+
+// sm2MechanismConfig is implemented by crypto suite configs that provide the vendor defined
+// SM2 values of the token (see cryptosuite.Config.SecuritySM2SignMechanism)
+type sm2MechanismConfig interface {
+	SecuritySM2KeyType() uint
+	SecuritySM2KeyPairGenMechanism() uint
+	SecuritySM2SignMechanism() uint
+}
+
+// getOptsByConfig Returns Factory opts for given SDK config
+func getOptsByConfig(c core.CryptoSuiteConfig) *smpkcs11.SMPKCS11Opts {
+	opts := &smpkcs11.SMPKCS11Opts{
+		SecLevel:   c.SecurityLevel(),
+		HashFamily: c.SecurityAlgorithm(),
+		Library:    c.SecurityProviderLibPath(),
+		Pin:        c.SecurityProviderPin(),
+		Label:      c.SecurityProviderLabel(),
+		SoftVerify: c.SoftVerify(),
+	}
+	if hc, ok := c.(hashModeConfig); ok {
+		opts.HashMode = hc.SecurityHashMode()
+	}
+	if mc, ok := c.(sm2MechanismConfig); ok {
+		opts.KeyType = mc.SecuritySM2KeyType()
+		opts.KeyPairGenMechanism = mc.SecuritySM2KeyPairGenMechanism()
+		opts.SignMechanism = mc.SecuritySM2SignMechanism()
+	}
+	logger.Debug("Initialized SM PKCS11 cryptosuite")
+
+	return opts
+}
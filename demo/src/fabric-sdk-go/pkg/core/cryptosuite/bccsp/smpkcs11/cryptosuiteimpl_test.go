@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package smpkcs11
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+
+	_, err := GetSuiteByConfig(mockConfig)
+	assert.Error(t, err, "only the smpkcs11 provider is supported")
+}
+
+func TestCryptoSuiteByConfigFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("smpkcs11")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().SecurityProviderLibPath().Return("")
+	mockConfig.EXPECT().SecurityProviderLabel().Return("")
+	mockConfig.EXPECT().SecurityProviderPin().Return("")
+	mockConfig.EXPECT().SoftVerify().Return(true)
+
+	cs, err := GetSuiteByConfig(mockConfig)
+	assert.Error(t, err, "the PKCS11 library is missing")
+	assert.Nil(t, cs)
+}
+
+func TestGetOptsByConfig(t *testing.T) {
+	backend := &mocks.MockConfigBackend{KeyValueMap: map[string]interface{}{
+		"client.BCCSP.security.default.provider":        "SMPKCS11",
+		"client.BCCSP.security.hashAlgorithm":           "SM3",
+		"client.BCCSP.security.hashMode":                "strict",
+		"client.BCCSP.security.softVerify":              false,
+		"client.BCCSP.security.pin":                     "1234",
+		"client.BCCSP.security.label":                   "ForFabric",
+		"client.BCCSP.security.sm2.keyType":             "0x80000001",
+		"client.BCCSP.security.sm2.keyPairGenMechanism": "0x80000101",
+		"client.BCCSP.security.sm2.signMechanism":       2147484162,
+	}}
+	opts := getOptsByConfig(cryptosuite.ConfigFromBackend(backend))
+
+	assert.Equal(t, "SM3", opts.HashFamily)
+	assert.Equal(t, 256, opts.SecLevel)
+	assert.Equal(t, "strict", opts.HashMode)
+	assert.False(t, opts.SoftVerify)
+	assert.Equal(t, "1234", opts.Pin)
+	assert.Equal(t, "ForFabric", opts.Label)
+	assert.Equal(t, uint(0x80000001), opts.KeyType)
+	assert.Equal(t, uint(0x80000101), opts.KeyPairGenMechanism)
+	assert.Equal(t, uint(0x80000202), opts.SignMechanism)
+}
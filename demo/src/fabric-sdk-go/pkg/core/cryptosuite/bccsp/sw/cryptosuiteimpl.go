@@ -25,6 +25,19 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
 	}
 
+	password, err := keyStorePasswordFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) != 0 {
+		// The SW factory doesn't take a password, so build the encrypted keystore here
+		ks, err := sw.NewFileBasedKeyStore(password, config.KeyStorePath(), false)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to initialize encrypted software key store")
+		}
+		return GetSuite(config.SecurityLevel(), config.SecurityAlgorithm(), ks)
+	}
+
 	opts := getOptsByConfig(config)
 	bccsp, err := getBCCSPFromOpts(opts)
 	if err != nil {
@@ -33,6 +46,24 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 	return wrapper.NewCryptoSuite(bccsp), nil
 }
 
+// keyStorePasswordConfig is implemented by crypto suite configs that provide a keystore password
+// (see cryptosuite.Config.KeyStorePassword)
+type keyStorePasswordConfig interface {
+	KeyStorePassword() ([]byte, error)
+}
+
+func keyStorePasswordFromConfig(config core.CryptoSuiteConfig) ([]byte, error) {
+	c, ok := config.(keyStorePasswordConfig)
+	if !ok {
+		return nil, nil
+	}
+	password, err := c.KeyStorePassword()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get keystore password")
+	}
+	return password, nil
+}
+
 //GetSuiteWithDefaultEphemeral returns cryptosuite adaptor for bccsp with default ephemeral options (intended to aid testing)
 func GetSuiteWithDefaultEphemeral() (core.CryptoSuite, error) {
 	opts := getEphemeralOpts()
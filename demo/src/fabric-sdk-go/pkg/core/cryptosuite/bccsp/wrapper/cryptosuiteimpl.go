@@ -10,6 +10,7 @@ import (
 	"hash"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 )
 
@@ -68,6 +69,36 @@ func (c *CryptoSuite) Verify(k core.Key, signature, digest []byte, opts core.Sig
 	return c.BCCSP.Verify(k.(*key).key, signature, digest, opts)
 }
 
+// Encrypt is a wrapper of BCCSP.Encrypt
+func (c *CryptoSuite) Encrypt(k core.Key, plaintext []byte, opts core.EncrypterOpts) (ciphertext []byte, err error) {
+	return c.BCCSP.Encrypt(k.(*key).key, plaintext, opts)
+}
+
+// Decrypt is a wrapper of BCCSP.Decrypt
+func (c *CryptoSuite) Decrypt(k core.Key, ciphertext []byte, opts core.DecrypterOpts) (plaintext []byte, err error) {
+	return c.BCCSP.Decrypt(k.(*key).key, ciphertext, opts)
+}
+
+// NetworkHashOpts returns the network hash opts of the underlying BCCSP if it provides them,
+// SHA-256 otherwise
+func (c *CryptoSuite) NetworkHashOpts() core.HashOpts {
+	if p, ok := c.BCCSP.(networkHashProvider); ok {
+		return p.NetworkHashOpts()
+	}
+	return &bccsp.SHA256Opts{}
+}
+
+// networkHashProvider is implemented by BCCSPs that use a network hash other than SHA-256
+type networkHashProvider interface {
+	NetworkHashOpts() bccsp.HashOpts
+}
+
+// PrepareSignatureDigest returns msg for the SM BCCSP, which preprocesses it with the signer's
+// identity (Z||M) while signing, and digest for the other BCCSPs
+func (c *CryptoSuite) PrepareSignatureDigest(msg, digest []byte) []byte {
+	return hx509.WrapHashResult(c.BCCSP, msg, digest)
+}
+
 type key struct {
 	key bccsp.Key
 }
@@ -20,6 +20,8 @@ import (
 const (
 	defEnabled       = true
 	defHashAlgorithm = "SHA2"
+	defHashMode      = "legacy"
+	defHybrid        = false
 	defLevel         = 256
 	defProvider      = "SW"
 	defSoftVerify    = true
@@ -62,6 +64,26 @@ func (c *Config) SecurityLevel() int {
 	return cast.ToInt(val)
 }
 
+// SecurityHashMode returns the hash mode of the SM crypto suite: "legacy" (default)
+// computes SM3 for all hash opts while "strict" computes real SHA digests for SHA opts
+func (c *Config) SecurityHashMode() string {
+	val, ok := c.backend.Lookup("client.BCCSP.security.hashMode")
+	if !ok {
+		return defHashMode
+	}
+	return strings.ToLower(cast.ToString(val))
+}
+
+// SecurityHybrid returns true if the crypto suite of the provider is combined with the SW and SM suites to
+// verify, import and hash for both ECDSA and SM2 identities, as in networks mixing SW and SM organizations
+func (c *Config) SecurityHybrid() bool {
+	val, ok := c.backend.Lookup("client.BCCSP.security.hybrid")
+	if !ok {
+		return defHybrid
+	}
+	return cast.ToBool(val)
+}
+
 //SecurityProvider provider SW or PKCS11
 func (c *Config) SecurityProvider() string {
 	val, ok := c.backend.Lookup("client.BCCSP.security.default.provider")
@@ -110,6 +132,43 @@ func (c *Config) SecurityProviderLabel() string {
 	return c.backend.GetString("client.BCCSP.security.label")
 }
 
+// SecuritySM2KeyType returns the CKA_KEY_TYPE of SM2 keys on the token, used by the smpkcs11 provider.
+// Zero (default) selects CKK_EC with the SM2 curve.
+func (c *Config) SecuritySM2KeyType() uint {
+	return c.lookupUint("client.BCCSP.security.sm2.keyType")
+}
+
+// SecuritySM2KeyPairGenMechanism returns the mechanism generating SM2 key pairs on the token, used by
+// the smpkcs11 provider. Zero (default) selects CKM_EC_KEY_PAIR_GEN.
+func (c *Config) SecuritySM2KeyPairGenMechanism() uint {
+	return c.lookupUint("client.BCCSP.security.sm2.keyPairGenMechanism")
+}
+
+// SecuritySM2SignMechanism returns the vendor defined SM3-with-SM2 mechanism of the token,
+// required by the smpkcs11 provider
+func (c *Config) SecuritySM2SignMechanism() uint {
+	return c.lookupUint("client.BCCSP.security.sm2.signMechanism")
+}
+
+// lookupUint returns the unsigned value of key, which may be written in hexadecimal ("0x...")
+func (c *Config) lookupUint(key string) uint {
+	val, ok := c.backend.Lookup(key)
+	if !ok {
+		return 0
+	}
+	return cast.ToUint(val)
+}
+
+// KeyStorePassword returns the password that encrypts the keys in the BCCSP keystore.
+// Keys are stored in the clear if no password is configured.
+func (c *Config) KeyStorePassword() ([]byte, error) {
+	password := c.backend.GetString("client.BCCSP.security.keystore.password")
+	if password == "" {
+		return nil, nil
+	}
+	return []byte(password), nil
+}
+
 // KeyStorePath returns the keystore path used by BCCSP
 func (c *Config) KeyStorePath() string {
 	keystorePath := pathvar.Subst(c.backend.GetString("client.credentialStore.cryptoStore.path"))
@@ -38,6 +38,14 @@ func TestEmptyTestFile(t *testing.T) {
 	// Note that we transform to lower case in SecurityProvider()
 	assert.Equal(t, "sw", cryptoConfig.SecurityProvider())
 	assert.Equal(t, true, cryptoConfig.SoftVerify())
+	assert.Equal(t, "legacy", cryptoConfig.SecurityHashMode())
+	assert.False(t, cryptoConfig.SecurityHybrid())
+	assert.Zero(t, cryptoConfig.SecuritySM2KeyType())
+	assert.Zero(t, cryptoConfig.SecuritySM2KeyPairGenMechanism())
+	assert.Zero(t, cryptoConfig.SecuritySM2SignMechanism())
+	password, err := cryptoConfig.KeyStorePassword()
+	assert.NoError(t, err)
+	assert.Nil(t, password)
 }
 
 func TestCAConfigKeyStorePath(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hfactory"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
@@ -92,7 +93,86 @@ func GetSHAOpts() core.HashOpts {
 	return &bccsp.SHAOpts{}
 }
 
+//GetNetworkHashOpts returns options for computing the hash that the network uses, for
+//example for transaction IDs. Unlike GetSHA256Opts, the result follows the crypto suite
+//(e.g. SM3 for the SM suite in legacy hash mode). Suites that don't say otherwise yield SHA-256.
+func GetNetworkHashOpts(cs core.CryptoSuite) core.HashOpts {
+	if p, ok := cs.(core.NetworkHashProvider); ok {
+		return p.NetworkHashOpts()
+	}
+	return &bccsp.SHA256Opts{}
+}
+
+//PrepareSignatureDigest returns the value to sign or verify for msg, given its digest computed with
+//the signature hash. Suites that don't implement core.SignatureDigestPreparer sign the digest itself.
+func PrepareSignatureDigest(cs core.CryptoSuite, msg, digest []byte) []byte {
+	if p, ok := cs.(core.SignatureDigestPreparer); ok {
+		return p.PrepareSignatureDigest(msg, digest)
+	}
+	return digest
+}
+
+//PrepareKeySignatureDigest returns the value to sign or verify with key k for msg, given its digest computed
+//with the signature hash. Suites that don't implement core.KeySignatureDigestPreparer prepare the digest
+//regardless of the key (see PrepareSignatureDigest).
+func PrepareKeySignatureDigest(cs core.CryptoSuite, k core.Key, msg, digest []byte) []byte {
+	if p, ok := cs.(core.KeySignatureDigestPreparer); ok {
+		return p.PrepareKeySignatureDigest(k, msg, digest)
+	}
+	return PrepareSignatureDigest(cs, msg, digest)
+}
+
 //GetECDSAP256KeyGenOpts returns options for ECDSA key generation with curve P-256.
 func GetECDSAP256KeyGenOpts(ephemeral bool) core.KeyGenOpts {
 	return &bccsp.ECDSAP256KeyGenOpts{Temporary: ephemeral}
 }
+
+//GetSM2KeyGenOpts returns options for SM2 key generation.
+func GetSM2KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return &hfactory.SM2KeyGenOpts{Temporary: ephemeral}
+}
+
+//GetSM4KeyGenOpts returns options for SM4 key generation.
+func GetSM4KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return &hfactory.SM4KeyGenOpts{Temporary: ephemeral}
+}
+
+//GetSM4ImportKeyOpts returns options for importing a raw 16 byte SM4 key.
+func GetSM4ImportKeyOpts(ephemeral bool) core.KeyImportOpts {
+	return &hfactory.SM4ImportKeyOpts{Temporary: ephemeral}
+}
+
+//GetSM4CBCPKCS7ModeOpts returns options for SM4 encryption and decryption in CBC mode with PKCS7 padding.
+//A random IV is sampled on encryption and prepended to the ciphertext.
+func GetSM4CBCPKCS7ModeOpts() core.EncrypterOpts {
+	return &hfactory.SM4CBCPKCS7ModeOpts{}
+}
+
+//GetSM4GCMModeOpts returns options for SM4 encryption and decryption in GCM mode.
+//A random nonce is sampled on encryption and prepended to the ciphertext. The same
+//additional data must be given on encryption and decryption.
+func GetSM4GCMModeOpts(additionalData []byte) core.EncrypterOpts {
+	return &hfactory.SM4GCMModeOpts{AdditionalData: additionalData}
+}
+
+//GetSM2EncryptionOpts returns options for SM2 encryption to a public key and decryption with the private key.
+func GetSM2EncryptionOpts() core.EncrypterOpts {
+	return &hfactory.SM2EncryptionOpts{}
+}
+
+//GetECIESOpts returns options for ECIES encryption to an ECDSA public key and decryption with the private key.
+func GetECIESOpts() core.EncrypterOpts {
+	return &bccsp.ECIESOpts{}
+}
+
+//GetAES256ImportKeyOpts returns options for importing a raw 32 byte AES key.
+func GetAES256ImportKeyOpts(ephemeral bool) core.KeyImportOpts {
+	return &bccsp.AES256ImportKeyOpts{Temporary: ephemeral}
+}
+
+//GetAESGCMModeOpts returns options for AES encryption and decryption in GCM mode.
+//A random nonce is sampled on encryption and prepended to the ciphertext. The same
+//additional data must be given on encryption and decryption.
+func GetAESGCMModeOpts(additionalData []byte) core.EncrypterOpts {
+	return &bccsp.AESGCMModeOpts{AdditionalData: additionalData}
+}
@@ -11,6 +11,7 @@ import (
 
 	"sync/atomic"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/stretchr/testify/assert"
 )
@@ -84,6 +85,24 @@ func TestHashOpts(t *testing.T) {
 	assert.NotZero(t, hashOpts, "Not supposed to be empty sha256HashOpts")
 	assert.True(t, hashOpts.Algorithm() == sha256HashOptsAlgorithm, "Unexpected SHA hash opts, expected [%v], got [%v]", sha256HashOptsAlgorithm, hashOpts.Algorithm())
 
+	//Get network hash opts of the SW suite, which uses SHA-256
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	assert.NoError(t, err)
+	hashOpts = GetNetworkHashOpts(swSuite)
+	assert.True(t, hashOpts.Algorithm() == sha256HashOptsAlgorithm, "Unexpected network hash opts, expected [%v], got [%v]", sha256HashOptsAlgorithm, hashOpts.Algorithm())
+}
+
+func TestPrepareSignatureDigest(t *testing.T) {
+	msg := []byte("message")
+	digest := []byte("digest")
+
+	//The SW suite signs the digest
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	assert.NoError(t, err)
+	assert.Equal(t, digest, PrepareSignatureDigest(swSuite, msg, digest))
+
+	//Third-party suites that don't implement SignatureDigestPreparer sign the digest
+	assert.Equal(t, digest, PrepareSignatureDigest(struct{ core.CryptoSuite }{}, msg, digest))
 }
 
 func TestKeyGenOpts(t *testing.T) {
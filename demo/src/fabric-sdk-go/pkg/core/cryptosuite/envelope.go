@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const (
+	sm4DataKeyLength = 16
+	aesDataKeyLength = 32
+)
+
+// Envelope is data sealed for the owner of a public key: the data is encrypted with a random data key,
+// which is itself encrypted to the public key
+type Envelope struct {
+	// EncryptedKey is the data key encrypted with SM2 (SM2 recipients) or ECIES (ECDSA recipients)
+	EncryptedKey []byte
+	// Ciphertext is the data encrypted with the data key in SM4-GCM (SM2 recipients)
+	// or AES-256-GCM (ECDSA recipients) mode, prefixed with the nonce. EncryptedKey is
+	// authenticated as additional data, binding the ciphertext to its wrapped key.
+	Ciphertext []byte
+}
+
+// SealEnvelope encrypts data for the owner of recipient, an SM2 or ECDSA public key of cs.
+// The crypto suite must implement core.Encrypter.
+func SealEnvelope(cs core.CryptoSuite, recipient core.Key, data []byte) (*Envelope, error) {
+	e, ok := cs.(core.Encrypter)
+	if !ok {
+		return nil, errors.New("crypto suite does not support encryption")
+	}
+	sm, err := isSMKey(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLength, keyOpts := aesDataKeyLength, GetECIESOpts()
+	if sm {
+		keyLength, keyOpts = sm4DataKeyLength, GetSM2EncryptionOpts()
+	}
+	dataKey := make([]byte, keyLength)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "failed generating data key")
+	}
+
+	encryptedKey, err := e.Encrypt(recipient, dataKey, keyOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed encrypting data key")
+	}
+
+	importOpts, dataOpts := dataKeyOpts(sm, encryptedKey)
+	k, err := cs.KeyImport(dataKey, importOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed importing data key")
+	}
+	ciphertext, err := e.Encrypt(k, data, dataOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed encrypting data")
+	}
+	return &Envelope{EncryptedKey: encryptedKey, Ciphertext: ciphertext}, nil
+}
+
+// OpenEnvelope decrypts the data of env with k, the SM2 or ECDSA private key of cs that env was sealed for.
+// The crypto suite must implement core.Encrypter.
+func OpenEnvelope(cs core.CryptoSuite, k core.Key, env *Envelope) ([]byte, error) {
+	e, ok := cs.(core.Encrypter)
+	if !ok {
+		return nil, errors.New("crypto suite does not support decryption")
+	}
+	if env == nil {
+		return nil, errors.New("envelope is nil")
+	}
+	sm, err := isSMKey(k)
+	if err != nil {
+		return nil, err
+	}
+
+	keyOpts := GetECIESOpts()
+	if sm {
+		keyOpts = GetSM2EncryptionOpts()
+	}
+	dataKey, err := e.Decrypt(k, env.EncryptedKey, keyOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed decrypting data key")
+	}
+
+	importOpts, dataOpts := dataKeyOpts(sm, env.EncryptedKey)
+	dk, err := cs.KeyImport(dataKey, importOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed importing data key")
+	}
+	data, err := e.Decrypt(dk, env.Ciphertext, dataOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed decrypting data")
+	}
+	return data, nil
+}
+
+// dataKeyOpts returns the options for importing the data key and for encrypting the data in GCM mode
+// with encryptedKey as additional data: SM4 for SM2 recipients, AES-256 for ECDSA recipients
+func dataKeyOpts(sm bool, encryptedKey []byte) (core.KeyImportOpts, core.EncrypterOpts) {
+	if sm {
+		return GetSM4ImportKeyOpts(true), GetSM4GCMModeOpts(encryptedKey)
+	}
+	return GetAES256ImportKeyOpts(true), GetAESGCMModeOpts(encryptedKey)
+}
+
+func isSMKey(k core.Key) (bool, error) {
+	if k == nil {
+		return false, errors.New("key is nil")
+	}
+	pk, err := k.PublicKey()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed getting public key")
+	}
+	raw, err := pk.Bytes()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed marshalling public key")
+	}
+	return hx509.IsSMKey(raw), nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	smSuite, err := sm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	data := []byte("off-chain payload")
+
+	for name, cs := range map[string]core.CryptoSuite{"SW": swSuite, "SM": smSuite} {
+		t.Run(name, func(t *testing.T) {
+			key, err := cs.KeyGen(GetECDSAP256KeyGenOpts(true))
+			require.NoError(t, err)
+			pub, err := key.PublicKey()
+			require.NoError(t, err)
+
+			env, err := SealEnvelope(cs, pub, data)
+			require.NoError(t, err)
+			assert.NotContains(t, string(env.Ciphertext), string(data))
+
+			opened, err := OpenEnvelope(cs, key, env)
+			require.NoError(t, err)
+			assert.Equal(t, data, opened)
+
+			// the public key can't open the envelope
+			_, err = OpenEnvelope(cs, pub, env)
+			assert.Error(t, err)
+
+			other, err := cs.KeyGen(GetECDSAP256KeyGenOpts(true))
+			require.NoError(t, err)
+			_, err = OpenEnvelope(cs, other, env)
+			assert.Error(t, err, "the envelope was sealed for another key")
+
+			tampered := *env
+			tampered.Ciphertext = append([]byte(nil), env.Ciphertext...)
+			tampered.Ciphertext[len(tampered.Ciphertext)-1] ^= 1
+			_, err = OpenEnvelope(cs, key, &tampered)
+			assert.Error(t, err)
+
+			tampered.Ciphertext = env.Ciphertext
+			tampered.EncryptedKey = env.EncryptedKey[:10]
+			_, err = OpenEnvelope(cs, key, &tampered)
+			assert.Error(t, err)
+
+			// the ciphertext is bound to its wrapped key: a data key wrapped again for the same
+			// recipient doesn't open it
+			another, err := SealEnvelope(cs, pub, data)
+			require.NoError(t, err)
+			tampered.EncryptedKey = another.EncryptedKey
+			_, err = OpenEnvelope(cs, key, &tampered)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncryptToPublicKey(t *testing.T) {
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	smSuite, err := sm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	suites := map[string]struct {
+		cs   core.CryptoSuite
+		opts core.EncrypterOpts
+	}{
+		"SW": {swSuite, GetECIESOpts()},
+		"SM": {smSuite, GetSM2EncryptionOpts()},
+	}
+
+	plaintext := []byte("data key")
+	for name, s := range suites {
+		t.Run(name, func(t *testing.T) {
+			e, ok := s.cs.(core.Encrypter)
+			require.True(t, ok)
+
+			key, err := s.cs.KeyGen(GetECDSAP256KeyGenOpts(true))
+			require.NoError(t, err)
+			pub, err := key.PublicKey()
+			require.NoError(t, err)
+
+			ciphertext, err := e.Encrypt(pub, plaintext, s.opts)
+			require.NoError(t, err)
+			decrypted, err := e.Decrypt(key, ciphertext, s.opts)
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+
+			// the private key encrypts to its public key as well
+			ciphertext, err = e.Encrypt(key, plaintext, s.opts)
+			require.NoError(t, err)
+			decrypted, err = e.Decrypt(key, ciphertext, s.opts)
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+
+			_, err = e.Decrypt(key, ciphertext[:len(ciphertext)-len(plaintext)], s.opts)
+			assert.Error(t, err)
+			_, err = e.Decrypt(key, []byte("short"), s.opts)
+			assert.Error(t, err)
+		})
+	}
+}
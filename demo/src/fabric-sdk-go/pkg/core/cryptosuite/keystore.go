@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// keystore file suffixes of private, public and symmetric keys (SW and SM keystores)
+var keyFileSuffixes = []string{"_sk", "_pk", "_key"}
+
+// the SM4 keystore uses a dedicated PEM type for encrypted keys
+var encryptedPEMTypes = map[string]string{
+	"SM4 KEY": "SM4 ENCRYPTED KEY",
+}
+
+// ReEncryptKeyStore re-encrypts the keys of an existing SW or SM file keystore, e.g. to protect a keystore
+// that was written in the clear before client.BCCSP.security.keystore.password was configured, or to
+// change its password. Keys are decrypted with oldPassword (nil if they are stored in the clear) and
+// encrypted with newPassword (nil to store them in the clear).
+// All keys are converted before any file is written, so that the keystore is left untouched if a key
+// cannot be decrypted.
+func ReEncryptKeyStore(keyStorePath string, oldPassword, newPassword []byte) error {
+	files, err := ioutil.ReadDir(keyStorePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read keystore [%s]", keyStorePath)
+	}
+
+	converted := make(map[string][]byte)
+	for _, f := range files {
+		if f.IsDir() || !isKeyFile(f.Name()) {
+			continue
+		}
+
+		path := filepath.Join(keyStorePath, f.Name())
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read key file [%s]", path)
+		}
+
+		key, err := reEncryptPEM(raw, oldPassword, newPassword)
+		if err != nil {
+			return errors.WithMessage(err, "failed to re-encrypt key file "+path)
+		}
+		converted[path] = key
+	}
+
+	for path, key := range converted {
+		if err := writeFileAtomic(path, key); err != nil {
+			return errors.Wrapf(err, "failed to write key file [%s]", path)
+		}
+	}
+
+	logger.Debugf("Re-encrypted %d keys in keystore [%s]", len(converted), keyStorePath)
+	return nil
+}
+
+func isKeyFile(name string) bool {
+	for _, suffix := range keyFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func reEncryptPEM(raw []byte, oldPassword, newPassword []byte) ([]byte, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("failed decoding PEM")
+	}
+
+	der := block.Bytes
+	plainType := block.Type
+	if x509.IsEncryptedPEMBlock(block) {
+		if len(oldPassword) == 0 {
+			return nil, errors.New("key is encrypted, the current password is required")
+		}
+
+		var err error
+		der, err = x509.DecryptPEMBlock(block, oldPassword)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed PEM decryption")
+		}
+
+		for plain, encrypted := range encryptedPEMTypes {
+			if block.Type == encrypted {
+				plainType = plain
+			}
+		}
+	}
+
+	if len(newPassword) == 0 {
+		return pem.EncodeToMemory(&pem.Block{Type: plainType, Bytes: der}), nil
+	}
+
+	encryptedType := plainType
+	if t, ok := encryptedPEMTypes[plainType]; ok {
+		encryptedType = t
+	}
+
+	encrypted, err := x509.EncryptPEMBlock(rand.Reader, encryptedType, der, newPassword, x509.PEMCipherAES256)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed PEM encryption")
+	}
+	return pem.EncodeToMemory(encrypted), nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptosuite
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	bccspSw "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReEncryptSWKeyStore(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "swkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(keyStorePath)
+
+	password := []byte("secret")
+
+	cs := newSWSuite(t, keyStorePath, nil)
+	key, err := cs.KeyGen(GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	keyFile := filepath.Join(keyStorePath, hex.EncodeToString(key.SKI())+"_sk")
+	assert.False(t, isEncrypted(t, keyFile))
+
+	require.NoError(t, ReEncryptKeyStore(keyStorePath, nil, password))
+	assert.True(t, isEncrypted(t, keyFile))
+
+	// Keys are decrypted transparently when the keystore has the password
+	loaded, err := newSWSuite(t, keyStorePath, password).GetKey(key.SKI())
+	require.NoError(t, err)
+	assert.True(t, loaded.Private())
+
+	_, err = newSWSuite(t, keyStorePath, nil).GetKey(key.SKI())
+	assert.Error(t, err, "encrypted key is expected to require a password")
+
+	// The keystore is left untouched if the current password is wrong
+	raw, err := ioutil.ReadFile(keyFile)
+	require.NoError(t, err)
+	assert.Error(t, ReEncryptKeyStore(keyStorePath, []byte("wrong"), nil))
+	unchanged, err := ioutil.ReadFile(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, raw, unchanged)
+}
+
+func TestSMKeyStorePassword(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "smkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(keyStorePath)
+
+	password := []byte("secret")
+
+	cs := newSMSuite(t, keyStorePath, password)
+	sm2Key, err := cs.KeyGen(GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	sm4Key, err := cs.KeyGen(GetSM4KeyGenOpts(false))
+	require.NoError(t, err)
+
+	sm2File := filepath.Join(keyStorePath, hex.EncodeToString(sm2Key.SKI())+"_sk")
+	sm4File := filepath.Join(keyStorePath, hex.EncodeToString(sm4Key.SKI())+"_key")
+	assert.True(t, isEncrypted(t, sm2File))
+	assert.True(t, isEncrypted(t, sm4File))
+
+	cs = newSMSuite(t, keyStorePath, password)
+	_, err = cs.GetKey(sm2Key.SKI())
+	require.NoError(t, err)
+	_, err = cs.GetKey(sm4Key.SKI())
+	require.NoError(t, err)
+
+	// Migrate the keystore back to clear text
+	require.NoError(t, ReEncryptKeyStore(keyStorePath, password, nil))
+	assert.False(t, isEncrypted(t, sm2File))
+	assert.False(t, isEncrypted(t, sm4File))
+
+	cs = newSMSuite(t, keyStorePath, nil)
+	_, err = cs.GetKey(sm2Key.SKI())
+	require.NoError(t, err)
+	_, err = cs.GetKey(sm4Key.SKI())
+	require.NoError(t, err)
+}
+
+func newSWSuite(t *testing.T, keyStorePath string, password []byte) core.CryptoSuite {
+	ks, err := bccspSw.NewFileBasedKeyStore(password, keyStorePath, false)
+	require.NoError(t, err)
+	cs, err := sw.GetSuite(256, "SHA2", ks)
+	require.NoError(t, err)
+	return cs
+}
+
+func newSMSuite(t *testing.T, keyStorePath string, password []byte) core.CryptoSuite {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sm").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3").AnyTimes()
+	mockConfig.EXPECT().SecurityLevel().Return(256).AnyTimes()
+	mockConfig.EXPECT().KeyStorePath().Return(keyStorePath).AnyTimes()
+
+	config, err := BuildCryptoSuiteConfigFromOptions(mockConfig, KeyStorePasswordProvider(func() ([]byte, error) {
+		return password, nil
+	}))
+	require.NoError(t, err)
+
+	cs, err := sm.GetSuiteByConfig(config)
+	require.NoError(t, err)
+	return cs
+}
+
+func isEncrypted(t *testing.T, keyFile string) bool {
+	raw, err := ioutil.ReadFile(keyFile)
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	return x509.IsEncryptedPEMBlock(block)
+}
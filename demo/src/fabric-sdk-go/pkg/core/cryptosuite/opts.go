@@ -23,6 +23,26 @@ type CryptoConfigOptions struct {
 	securityProviderPin
 	securityProviderLabel
 	keyStorePath
+	keyStorePassword keyStorePassword
+}
+
+// KeyStorePasswordProvider is a callback that returns the password that encrypts the keys in the
+// BCCSP keystore. It can be passed to fabsdk.WithCryptoSuiteConfig in place of the
+// client.BCCSP.security.keystore.password configuration (e.g. to read the password from a vault).
+type KeyStorePasswordProvider func() ([]byte, error)
+
+// KeyStorePassword returns the password provided by the callback
+func (p KeyStorePasswordProvider) KeyStorePassword() ([]byte, error) {
+	return p()
+}
+
+// KeyStorePassword returns the keystore password of the overriding option, if any.
+// Unlike the other CryptoSuiteConfig functions, overriding it is optional.
+func (c *CryptoConfigOptions) KeyStorePassword() ([]byte, error) {
+	if c.keyStorePassword == nil {
+		return nil, nil
+	}
+	return c.keyStorePassword.KeyStorePassword()
 }
 
 type applier func()
@@ -74,6 +94,11 @@ type keyStorePath interface {
 	KeyStorePath() string
 }
 
+// keyStorePassword interface allows to uniquely override CryptoConfig's KeyStorePassword() function
+type keyStorePassword interface {
+	KeyStorePassword() ([]byte, error)
+}
+
 // BuildCryptoSuiteConfigFromOptions will return an CryptoConfig instance pre-built with Optional interfaces
 // provided in fabsdk's WithConfigCrypto(opts...) call
 func BuildCryptoSuiteConfigFromOptions(opts ...interface{}) (core.CryptoSuiteConfig, error) {
@@ -104,6 +129,9 @@ func UpdateMissingOptsWithDefaultConfig(c *CryptoConfigOptions, d core.CryptoSui
 	s.set(c.securityProviderPin, nil, func() { c.securityProviderPin = d })
 	s.set(c.securityProviderLabel, nil, func() { c.securityProviderLabel = d })
 	s.set(c.keyStorePath, nil, func() { c.keyStorePath = d })
+	if p, ok := d.(keyStorePassword); ok {
+		s.set(c.keyStorePassword, nil, func() { c.keyStorePassword = p })
+	}
 
 	return c
 }
@@ -127,6 +155,7 @@ func setCryptoConfigWithOptionInterface(c *CryptoConfigOptions, o interface{}) e
 	s.set(c.securityProviderPin, func() bool { _, ok := o.(securityProviderPin); return ok }, func() { c.securityProviderPin = o.(securityProviderPin) })
 	s.set(c.securityProviderLabel, func() bool { _, ok := o.(securityProviderLabel); return ok }, func() { c.securityProviderLabel = o.(securityProviderLabel) })
 	s.set(c.keyStorePath, func() bool { _, ok := o.(keyStorePath); return ok }, func() { c.keyStorePath = o.(keyStorePath) })
+	s.set(c.keyStorePassword, func() bool { _, ok := o.(keyStorePassword); return ok }, func() { c.keyStorePassword = o.(keyStorePassword) })
 
 	if !s.isSet {
 		return errors.Errorf("option %#v is not a sub interface of CryptoSuiteConfig, at least one of its functions must be implemented.", o)
@@ -235,3 +235,25 @@ type mockKeyStorePath struct{}
 func (m *mockKeyStorePath) KeyStorePath() string {
 	return "test/keystore/path"
 }
+
+func TestCreateCustomCryptoConfigWithKeyStorePasswordProvider(t *testing.T) {
+	provider := KeyStorePasswordProvider(func() ([]byte, error) { return []byte("secret"), nil })
+
+	cryptoConfigOption, err := BuildCryptoSuiteConfigFromOptions(m1, provider)
+	require.NoError(t, err)
+	cco, ok := cryptoConfigOption.(*CryptoConfigOptions)
+	require.True(t, ok, "BuildCryptoSuiteConfigFromOptions did not return an Options instance %T", cryptoConfigOption)
+
+	password, err := cco.KeyStorePassword()
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), password)
+
+	// the password is optional: no password if neither the options nor the default config provide it
+	cryptoConfigOption, err = BuildCryptoSuiteConfigFromOptions(m1, m2, m3, m4, m5, m6, m7, m8, m9)
+	require.NoError(t, err)
+	cco = cryptoConfigOption.(*CryptoConfigOptions)
+	require.True(t, IsCryptoConfigFullyOverridden(cco))
+	password, err = cco.KeyStorePassword()
+	require.NoError(t, err)
+	require.Nil(t, password)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+// KeyPasswordFunc returns the key password of the given user of the given org
+type KeyPasswordFunc func(orgID, user string) ([]byte, error)
+
+// TLSClientKeyFunc returns the PEM encoded TLS client key of the given org
+type TLSClientKeyFunc func(orgID string) ([]byte, error)
+
+// CallbackProvider delegates to application callbacks, e.g. to fetch secrets from a vault.
+// Either callback may be nil.
+type CallbackProvider struct {
+	keyPassword  KeyPasswordFunc
+	tlsClientKey TLSClientKeyFunc
+}
+
+// NewCallbackProvider returns a secret provider calling the given functions
+func NewCallbackProvider(keyPassword KeyPasswordFunc, tlsClientKey TLSClientKeyFunc) *CallbackProvider {
+	return &CallbackProvider{keyPassword: keyPassword, tlsClientKey: tlsClientKey}
+}
+
+// KeyPassword returns the key password of the given user
+func (p *CallbackProvider) KeyPassword(orgID, user string) ([]byte, error) {
+	if p.keyPassword == nil {
+		return nil, nil
+	}
+	return p.keyPassword(orgID, user)
+}
+
+// TLSClientKey returns the TLS client key of the given org
+func (p *CallbackProvider) TLSClientKey(orgID string) ([]byte, error) {
+	if p.tlsClientKey == nil {
+		return nil, nil
+	}
+	return p.tlsClientKey(orgID)
+}
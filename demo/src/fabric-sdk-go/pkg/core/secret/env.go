@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+import "os"
+
+// DefaultEnvPrefix is the default prefix of the environment variables read by the env provider
+const DefaultEnvPrefix = "FABRIC_SDK"
+
+// EnvProvider reads secrets from environment variables. Names are upper-cased and any character other
+// than letters and digits is replaced by an underscore. With the default prefix:
+//
+//	FABRIC_SDK_KEY_PASSWORD_<ORG>_<USER>   key password of a user
+//	FABRIC_SDK_KEY_PASSWORD_<ORG>          key password of all the users of an org
+//	FABRIC_SDK_KEY_PASSWORD                key password of all the users
+//	FABRIC_SDK_TLS_CLIENT_KEY_<ORG>        PEM encoded TLS client key of an org
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider returns a secret provider reading environment variables with the given prefix
+// (DefaultEnvPrefix if empty)
+func NewEnvProvider(prefix string) *EnvProvider {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	return &EnvProvider{prefix: prefix}
+}
+
+// KeyPassword returns the key password of the given user, falling back to the org and global passwords
+func (p *EnvProvider) KeyPassword(orgID, user string) ([]byte, error) {
+	names := []string{
+		envName(p.prefix, "KEY_PASSWORD", orgID, user),
+		envName(p.prefix, "KEY_PASSWORD", orgID),
+		envName(p.prefix, "KEY_PASSWORD"),
+	}
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			return []byte(v), nil
+		}
+	}
+	return nil, nil
+}
+
+// TLSClientKey returns the TLS client key of the given org
+func (p *EnvProvider) TLSClientKey(orgID string) ([]byte, error) {
+	if orgID == "" {
+		return nil, nil
+	}
+	if v, ok := os.LookupEnv(envName(p.prefix, "TLS_CLIENT_KEY", orgID)); ok && v != "" {
+		return []byte(v), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/pkg/errors"
+)
+
+const (
+	passwordFile     = "password"
+	tlsClientKeyFile = "tls_client.key"
+)
+
+// FileProvider reads secrets from files under a root directory, e.g. a mounted secrets volume:
+//
+//	<root>/<org>/<user>/password   key password of a user
+//	<root>/<org>/password          key password of all the users of an org
+//	<root>/password                key password of all the users
+//	<root>/<org>/tls_client.key    PEM encoded TLS client key of an org
+//
+// Trailing new lines are stripped from passwords.
+type FileProvider struct {
+	root string
+}
+
+// NewFileProvider returns a secret provider reading files under the given directory
+func NewFileProvider(root string) *FileProvider {
+	return &FileProvider{root: pathvar.Subst(root)}
+}
+
+// KeyPassword returns the key password of the given user, falling back to the org and global passwords
+func (p *FileProvider) KeyPassword(orgID, user string) ([]byte, error) {
+	var paths []string
+	if orgID != "" {
+		if user != "" {
+			paths = append(paths, filepath.Join(p.root, orgID, user, passwordFile))
+		}
+		paths = append(paths, filepath.Join(p.root, orgID, passwordFile))
+	}
+	paths = append(paths, filepath.Join(p.root, passwordFile))
+
+	for _, path := range paths {
+		password, err := readSecretFile(path)
+		if err != nil {
+			return nil, err
+		}
+		password = bytes.TrimRight(password, "\r\n")
+		if len(password) > 0 {
+			return password, nil
+		}
+	}
+	return nil, nil
+}
+
+// TLSClientKey returns the TLS client key of the given org
+func (p *FileProvider) TLSClientKey(orgID string) ([]byte, error) {
+	if orgID == "" {
+		return nil, nil
+	}
+	return readSecretFile(filepath.Join(p.root, orgID, tlsClientKeyFile))
+}
+
+func readSecretFile(path string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read secret file [%s]", path)
+	}
+	return raw, nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package secret provides implementations of core.SecretProvider, which supplies key passwords and
+// TLS client keys per SDK instance (see fabsdk.WithSecretProvider).
+package secret
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// KeyPassword returns the key password of the given user of the given org. The password is looked up
+// in the secret provider first (which may be nil), then in the deprecated process-wide hutil.UserPassword.
+func KeyPassword(p core.SecretProvider, orgID, user string) ([]byte, error) {
+	if p != nil {
+		password, err := p.KeyPassword(orgID, user)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get key password from secret provider")
+		}
+		if len(password) > 0 {
+			return password, nil
+		}
+	}
+
+	if hutil.UserPassword != "" {
+		logger.Debug("using key password set with apiKey.SetUserPassword, which is deprecated")
+		return []byte(hutil.UserPassword), nil
+	}
+	return nil, nil
+}
+
+var nonAlphanumeric = regexp.MustCompile("[^A-Z0-9]+")
+
+// envName converts the given name parts into an environment variable name,
+// e.g. ("FABRIC_SDK", "Org1MSP", "user@org1") -> FABRIC_SDK_ORG1MSP_USER_ORG1
+func envName(parts ...string) string {
+	var names []string
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		names = append(names, strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToUpper(part), "_"), "_"))
+	}
+	return strings.Join(names, "_")
+}
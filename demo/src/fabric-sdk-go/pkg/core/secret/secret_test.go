@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvProvider(t *testing.T) {
+	p := NewEnvProvider("TEST_SECRET")
+
+	password, err := p.KeyPassword("org1", "User1@org1.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, password)
+
+	setEnv(t, "TEST_SECRET_KEY_PASSWORD", "global")
+	setEnv(t, "TEST_SECRET_KEY_PASSWORD_ORG1", "org1")
+	setEnv(t, "TEST_SECRET_KEY_PASSWORD_ORG1_USER1_ORG1_EXAMPLE_COM", "user1")
+	setEnv(t, "TEST_SECRET_TLS_CLIENT_KEY_ORG1", "tls key")
+
+	assertPassword(t, p, "org1", "User1@org1.example.com", "user1")
+	assertPassword(t, p, "org1", "Admin@org1.example.com", "org1")
+	assertPassword(t, p, "org2", "User1@org2.example.com", "global")
+
+	key, err := p.TLSClientKey("org1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tls key"), key)
+
+	key, err = p.TLSClientKey("org2")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	assert.Equal(t, DefaultEnvPrefix, NewEnvProvider("").prefix)
+}
+
+func TestFileProvider(t *testing.T) {
+	root, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	p := NewFileProvider(root)
+
+	password, err := p.KeyPassword("org1", "User1")
+	require.NoError(t, err)
+	assert.Nil(t, password)
+
+	writeFile(t, filepath.Join(root, passwordFile), "global\n")
+	writeFile(t, filepath.Join(root, "org1", passwordFile), "org1\n")
+	writeFile(t, filepath.Join(root, "org1", "User1", passwordFile), "user1")
+	writeFile(t, filepath.Join(root, "org1", tlsClientKeyFile), "tls key\n")
+
+	assertPassword(t, p, "org1", "User1", "user1")
+	assertPassword(t, p, "org1", "Admin", "org1")
+	assertPassword(t, p, "org2", "User1", "global")
+
+	key, err := p.TLSClientKey("org1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tls key\n"), key)
+
+	key, err = p.TLSClientKey("org2")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+}
+
+func TestCallbackProvider(t *testing.T) {
+	p := NewCallbackProvider(func(orgID, user string) ([]byte, error) {
+		if orgID == "org1" {
+			return []byte(user), nil
+		}
+		return nil, errors.New("vault unavailable")
+	}, nil)
+
+	assertPassword(t, p, "org1", "User1", "User1")
+
+	_, err := p.KeyPassword("org2", "User1")
+	assert.Error(t, err)
+
+	key, err := p.TLSClientKey("org1")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+}
+
+func TestKeyPassword(t *testing.T) {
+	defer func(password string) { hutil.UserPassword = password }(hutil.UserPassword)
+	hutil.UserPassword = ""
+
+	p := NewCallbackProvider(func(orgID, user string) ([]byte, error) {
+		if orgID == "org1" {
+			return []byte("org1"), nil
+		}
+		return nil, nil
+	}, nil)
+
+	password, err := KeyPassword(p, "org2", "User1")
+	require.NoError(t, err)
+	assert.Nil(t, password)
+
+	// The deprecated global password is the fallback for secrets the provider doesn't know about
+	hutil.UserPassword = "global"
+
+	password, err = KeyPassword(p, "org1", "User1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("org1"), password)
+
+	password, err = KeyPassword(p, "org2", "User1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("global"), password)
+
+	password, err = KeyPassword(nil, "org1", "User1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("global"), password)
+
+	_, err = KeyPassword(NewCallbackProvider(func(orgID, user string) ([]byte, error) {
+		return nil, errors.New("vault unavailable")
+	}, nil), "org1", "User1")
+	assert.Error(t, err)
+}
+
+func assertPassword(t *testing.T, p core.SecretProvider, orgID, user, expected string) {
+	password, err := p.KeyPassword(orgID, user)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(expected), password)
+}
+
+func setEnv(t *testing.T, name, value string) {
+	require.NoError(t, os.Setenv(name, value))
+	t.Cleanup(func() { os.Unsetenv(name) })
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
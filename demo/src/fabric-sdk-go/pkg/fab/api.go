@@ -24,6 +24,10 @@ type ClientConfig struct {
 type ClientTLSConfig struct {
 	//Client TLS information
 	Client endpoint.TLSKeyPair
+	//Client GM/T TLS encryption key pair, sent along with Client (optional)
+	EncClient endpoint.TLSKeyPair
+	//Type of TLS, empty for standard TLS or "gm" for GM/T TLS (SM2/SM4/SM3)
+	Type string
 }
 
 // OrdererConfig defines an orderer configuration
@@ -163,7 +167,11 @@ type MatchConfig struct {
 	IgnoreEndpoint bool
 }
 
+// TlsClientKey holds the TLS client keys set with SetTlsClientKey, by org
+//
+// Deprecated: use fabsdk.WithSecretProvider instead.
 type TlsClientKey struct {
-	Mutex           sync.RWMutex
+	Mutex sync.RWMutex
+	// Deprecated: use fabsdk.WithSecretProvider instead.
 	TlsClientKeyMap map[string]string
 }
\ No newline at end of file
@@ -69,6 +69,16 @@ func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error
 	return id.Verify(msg, sig)
 }
 
+// SatisfiesPrincipal returns nil if the given identity satisfies the MSP principal
+func (i *identityImpl) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	id, err := i.mspManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return err
+	}
+
+	return id.SatisfiesPrincipal(principal)
+}
+
 func (i *identityImpl) ContainsMSP(msp string) bool {
 	for _, v := range i.msps {
 		if v == strings.ToLower(msp) {
@@ -234,7 +244,7 @@ func addCertsToConfig(config fab.EndpointConfig, pemCertsList [][]byte) {
 				continue
 			}
 
-			cert, err := x509.ParseCertificate(block.Bytes)
+			cert, err := hx509.ParseCertificate(block.Bytes)
 			if err != nil {
 				continue
 			}
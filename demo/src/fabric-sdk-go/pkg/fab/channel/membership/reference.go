@@ -9,6 +9,7 @@ package membership
 import (
 	"time"
 
+	mb "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
@@ -67,6 +68,24 @@ func (ref *Ref) ContainsMSP(msp string) bool {
 	return membership.ContainsMSP(msp)
 }
 
+// SatisfiesPrincipal checks whether the given identity satisfies the MSP principal using the underlying reference
+func (ref *Ref) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	membership, err := ref.get()
+	if err != nil {
+		return err
+	}
+
+	evaluator, ok := membership.(principalEvaluator)
+	if !ok {
+		return errors.Errorf("membership %T does not support principal evaluation", membership)
+	}
+	return evaluator.SatisfiesPrincipal(serializedID, principal)
+}
+
+type principalEvaluator interface {
+	SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error
+}
+
 func (ref *Ref) get() (fab.ChannelMembership, error) {
 	m, err := ref.Get()
 	if err != nil {
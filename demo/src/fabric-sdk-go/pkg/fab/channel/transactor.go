@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -186,3 +187,21 @@ func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Tra
 func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
 	return txn.Send(t.reqCtx, tx, t.orderers)
 }
+
+// SendSignedTransactionProposal sends a proposal that was signed by an external signer to the target peers.
+func (t *Transactor) SendSignedTransactionProposal(signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for SendSignedTransactionProposal")
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(t.reqCtx))
+	defer cancel()
+
+	return txn.SendSignedProposal(reqCtx, signedProposal, targets)
+}
+
+// SendSignedEnvelope sends a transaction envelope that was signed by an external signer to the chain’s orderer service.
+func (t *Transactor) SendSignedEnvelope(envelope *fab.SignedEnvelope) (*fab.TransactionResponse, error) {
+	return txn.BroadcastEnvelope(t.reqCtx, envelope, t.orderers)
+}
@@ -18,19 +18,20 @@ import (
 	"sync"
 	"time"
 
-	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hutil"
-
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/gmtls"
 	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -153,6 +154,12 @@ func ConfigFromBackend(coreBackend ...core.ConfigBackend) (fab.EndpointConfig, e
 
 //NewConfigFromBackend returns endpoint config implementation for given backend with user and orgid
 func NewConfigFromBackend(user string, orgid string, coreBackend ...core.ConfigBackend) (fab.EndpointConfig, error) {
+	return NewConfigFromBackendWithSecretProvider(user, orgid, nil, coreBackend...)
+}
+
+//NewConfigFromBackendWithSecretProvider returns endpoint config implementation for given backend with user and orgid,
+//the secret provider (optional) supplies the TLS client key of the org and the password of encrypted keys
+func NewConfigFromBackendWithSecretProvider(user string, orgid string, secretProvider core.SecretProvider, coreBackend ...core.ConfigBackend) (fab.EndpointConfig, error) {
 	var peer []matcherEntry
 	var orderer []matcherEntry
 	var channel []matcherEntry
@@ -163,6 +170,7 @@ func NewConfigFromBackend(user string, orgid string, coreBackend ...core.ConfigB
 		channelMatchers: channel,
 		user:            user,
 		orgid:           orgid,
+		secretProvider:  secretProvider,
 	}
 	if err := config.loadEndpointConfiguration(); err != nil {
 		return nil, errors.WithMessage(err, "network configuration load failed")
@@ -184,6 +192,9 @@ type EndpointConfig struct {
 	channelPeersByChannel    map[string][]fab.ChannelPeer
 	channelOrderersByChannel map[string][]fab.OrdererConfig
 	tlsClientCerts           []tls.Certificate
+	tlsType                  string
+	gmTLSClientCert          *gmtls.Certificate
+	gmTLSClientEncCert       *gmtls.Certificate
 	peerMatchers             []matcherEntry
 	ordererMatchers          []matcherEntry
 	channelMatchers          []matcherEntry
@@ -193,6 +204,7 @@ type EndpointConfig struct {
 	defaultChannel           *fab.ChannelEndpointConfig
 	user                     string
 	orgid                    string
+	secretProvider           core.SecretProvider
 }
 
 //endpointConfigEntity contains endpoint config elements needed by endpointconfig
@@ -329,19 +341,88 @@ func (c *EndpointConfig) TLSClientCerts() []tls.Certificate {
 	return c.tlsClientCerts
 }
 
+// TLSType returns the configured type of TLS, empty for standard TLS or comm.TLSTypeGM for GM/T TLS
+func (c *EndpointConfig) TLSType() string {
+	return c.tlsType
+}
+
+// GMTLSClientCert returns the client's cert for mutual GM/T TLS, nil if none is configured
+func (c *EndpointConfig) GMTLSClientCert() *gmtls.Certificate {
+	return c.gmTLSClientCert
+}
+
+// GMTLSClientEncCert returns the client's encryption cert for mutual GM/T TLS, nil if none is configured
+func (c *EndpointConfig) GMTLSClientEncCert() *gmtls.Certificate {
+	return c.gmTLSClientEncCert
+}
+
+// SecretProvider returns the secret provider of the SDK instance, nil if none was provided
+func (c *EndpointConfig) SecretProvider() core.SecretProvider {
+	return c.secretProvider
+}
+
+// tlsClientKey returns the TLS client key supplied by the secret provider or else by the deprecated
+// SetTlsClientKey, nil if the configured key should be used
+func (c *EndpointConfig) tlsClientKey() ([]byte, error) {
+	if c.secretProvider != nil {
+		key, err := c.secretProvider.TLSClientKey(c.orgid)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get TLS client key from secret provider")
+		}
+		if len(key) > 0 {
+			return key, nil
+		}
+	}
+
+	tlsClientKey.Mutex.RLock()
+	defer tlsClientKey.Mutex.RUnlock()
+	if readKey, ok := tlsClientKey.TlsClientKeyMap[c.orgid]; ok {
+		return []byte(readKey), nil
+	}
+	return nil, nil
+}
+
 func (c *EndpointConfig) loadPrivateKeyFromConfig(clientConfig *ClientConfig, clientCerts tls.Certificate, cb []byte) ([]tls.Certificate, error) {
 
+	clientKey, err := c.clientKeyFromConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// load the key/cert pair from []byte
+	clientCerts, err = tls.X509KeyPair(cb, clientKey)
+	if err != nil {
+		return nil, errors.Errorf("Error loading cert/key pair as TLS client credentials: %s", err)
+	}
+
+	logger.Debug("pk read from config successfully")
+
+	return []tls.Certificate{clientCerts}, nil
+}
+
+// clientKeyFromConfig returns the PEM of the TLS client key, decrypted with the user password if needed
+func (c *EndpointConfig) clientKeyFromConfig(clientConfig *ClientConfig) ([]byte, error) {
+
 	clientKey := clientConfig.TLSCerts.Client.Key.Bytes()
 
 	//if clientKey is encrypted, it may cause decode error,
-	//so we intend to replace the it to which read from tlsClientKey instance
-	tlsClientKey.Mutex.RLock()
-	if readKey, ok := tlsClientKey.TlsClientKeyMap[c.orgid]; ok {
-		clientKey = []byte(readKey)
+	//so we intend to replace the it to which supplied by the secret provider
+	readKey, err := c.tlsClientKey()
+	if err != nil {
+		return nil, err
 	}
-	tlsClientKey.Mutex.RUnlock()
+	if readKey != nil {
+		clientKey = readKey
+	}
+	return c.decryptClientKey(clientKey)
+}
 
-	userPassword := []byte(hutil.UserPassword)
+// decryptClientKey decrypts the PEM of a TLS client key with the user password if it is encrypted
+func (c *EndpointConfig) decryptClientKey(clientKey []byte) ([]byte, error) {
+	userPassword, err := secret.KeyPassword(c.secretProvider, c.orgid, c.user)
+	if err != nil {
+		return nil, err
+	}
 	blockKey, _ := pem.Decode(clientKey)
 	if blockKey == nil {
 		return nil, fmt.Errorf("Failed decoding PEM. Block must be different from nil.")
@@ -360,15 +441,7 @@ func (c *EndpointConfig) loadPrivateKeyFromConfig(clientConfig *ClientConfig, cl
 		logger.Warn("private key was not encrypted, please consider encrypt your private key")
 	}
 
-	// load the key/cert pair from []byte
-	clientCerts, err := tls.X509KeyPair(cb, clientKey)
-	if err != nil {
-		return nil, errors.Errorf("Error loading cert/key pair as TLS client credentials: %s", err)
-	}
-
-	logger.Debug("pk read from config successfully")
-
-	return []tls.Certificate{clientCerts}, nil
+	return clientKey, nil
 }
 
 // CryptoConfigPath ...
@@ -1222,6 +1295,11 @@ func (c *EndpointConfig) loadClientTLSConfig(configEntity *endpointConfigEntity)
 	//resolve paths and org name
 	configEntity.Client.Organization = strings.ToLower(configEntity.Client.Organization)
 
+	c.tlsType = strings.ToLower(configEntity.Client.TLSCerts.Type)
+	if c.tlsType != "" && c.tlsType != comm.TLSTypeGM {
+		return errors.Errorf("unsupported client TLS type [%s]", configEntity.Client.TLSCerts.Type)
+	}
+
 	//modify to support client can use different org tlsCerts
 	keyPath, certPath, err := c.clientCertPath(configEntity)
 	if err != nil {
@@ -1229,6 +1307,8 @@ func (c *EndpointConfig) loadClientTLSConfig(configEntity *endpointConfigEntity)
 	}
 	configEntity.Client.TLSCerts.Client.Key.Path = keyPath
 	configEntity.Client.TLSCerts.Client.Cert.Path = certPath
+	configEntity.Client.TLSCerts.EncClient.Key.Path = pathvar.Subst(configEntity.Client.TLSCerts.EncClient.Key.Path)
+	configEntity.Client.TLSCerts.EncClient.Cert.Path = pathvar.Subst(configEntity.Client.TLSCerts.EncClient.Cert.Path)
 
 	//pre load client key and cert bytes
 	err = configEntity.Client.TLSCerts.Client.Key.LoadBytes()
@@ -1241,6 +1321,16 @@ func (c *EndpointConfig) loadClientTLSConfig(configEntity *endpointConfigEntity)
 		return errors.WithMessage(err, "failed to load client cert")
 	}
 
+	err = configEntity.Client.TLSCerts.EncClient.Key.LoadBytes()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load client encryption key")
+	}
+
+	err = configEntity.Client.TLSCerts.EncClient.Cert.LoadBytes()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load client encryption cert")
+	}
+
 	return nil
 }
 
@@ -1488,6 +1578,10 @@ func (c *EndpointConfig) loadTLSClientCerts(configEntity *endpointConfigEntity)
 		return nil
 	}
 
+	if c.tlsType == comm.TLSTypeGM {
+		return c.loadGMTLSClientCert(&configEntity.Client, cb)
+	}
+
 	// Load private key from cert using default crypto suite
 	cs := cryptosuite.GetSwDefault()
 	pk, err := cryptoutil.GetPrivateKeyFromCert(cb, cs)
@@ -1513,6 +1607,38 @@ func (c *EndpointConfig) loadTLSClientCerts(configEntity *endpointConfigEntity)
 	return nil
 }
 
+// loadGMTLSClientCert loads the client's SM2 signing cert and key for mutual GM/T TLS, and its
+// encryption cert and key if they are configured
+func (c *EndpointConfig) loadGMTLSClientCert(clientConfig *ClientConfig, cb []byte) error {
+	clientKey, err := c.clientKeyFromConfig(clientConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load GM TLS client certs")
+	}
+
+	// clientKeyFromConfig has decrypted the key already
+	clientCert, err := gmtls.X509KeyPair(cb, clientKey, nil)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load GM TLS client certs")
+	}
+	c.gmTLSClientCert = &clientCert
+
+	if encCB := clientConfig.TLSCerts.EncClient.Cert.Bytes(); len(encCB) > 0 {
+		encKey, err := c.decryptClientKey(clientConfig.TLSCerts.EncClient.Key.Bytes())
+		if err != nil {
+			return errors.WithMessage(err, "failed to load GM TLS client encryption certs")
+		}
+		encCert, err := gmtls.X509KeyPair(encCB, encKey, nil)
+		if err != nil {
+			return errors.WithMessage(err, "failed to load GM TLS client encryption certs")
+		}
+		c.gmTLSClientEncCert = &encCert
+	}
+
+	// the certificate alone is enough to compute the TLS cert hash
+	c.tlsClientCerts = []tls.Certificate{{Certificate: clientCert.Certificate}}
+	return nil
+}
+
 func (c *EndpointConfig) isPeerToBeIgnored(peerName string) bool {
 	for _, matcher := range c.peerMatchers {
 		if matcher.regex.MatchString(peerName) {
@@ -1942,6 +2068,8 @@ func detectDeprecatedNetworkConfig(endpointConfig *EndpointConfig) {
 
 //SetTlsClientKey is intended to update the tlskey corresponding with orgId stored in tlsClientKey,
 //which is used in function loadPrivateKeyFromConfig
+//
+//Deprecated: the key is shared by all the SDK instances of the process, use fabsdk.WithSecretProvider instead.
 func SetTlsClientKey(orgID, tlsKey string) {
 	tlsClientKey.Mutex.Lock()
 	defer tlsClientKey.Mutex.Unlock()
@@ -1951,6 +2079,8 @@ func SetTlsClientKey(orgID, tlsKey string) {
 }
 
 //ResetTlsClientKey is intended to clear the tlskey corresponding with orgId stored in tlsClientKey
+//
+//Deprecated: use fabsdk.WithSecretProvider instead of SetTlsClientKey.
 func ResetTlsClientKeyWithOrgID(orgID string) {
 	tlsClientKey.Mutex.Lock()
 	defer tlsClientKey.Mutex.Unlock()
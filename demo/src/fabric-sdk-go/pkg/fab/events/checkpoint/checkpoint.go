@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// Checkpointer persists the position of the last event processed by a registration
+// so that delivery may resume from that position after a restart.
+// Load returns nil if no checkpoint has been saved for the registration.
+type Checkpointer interface {
+	Load(channelID, registrationID string) (*Checkpoint, error)
+	Save(channelID, registrationID string, checkpoint *Checkpoint) error
+}
+
+// Checkpoint is the position of the last event processed by a registration.
+// EventIndex is the index of the last processed event among the registration's events in block
+// BlockNumber (not the index of its transaction within the block), or -1 if the whole block has been processed. TransactionIDs holds the transactions within BlockNumber
+// that have been processed and is only recorded in ExactlyOnce mode.
+type Checkpoint struct {
+	BlockNumber    uint64   `json:"blockNumber"`
+	EventIndex     int      `json:"eventIndex"`
+	TransactionIDs []string `json:"transactionIds,omitempty"`
+}
+
+// ResumeBlock returns the block from which delivery should resume
+func (c *Checkpoint) ResumeBlock() uint64 {
+	if c.EventIndex < 0 {
+		return c.BlockNumber + 1
+	}
+	return c.BlockNumber
+}
+
+// DeliveryMode specifies how events of a partially processed block are handled after a restart
+type DeliveryMode int
+
+const (
+	// AtLeastOnce replays all events of a partially processed block, so events
+	// may be delivered more than once
+	AtLeastOnce DeliveryMode = iota
+	// ExactlyOnce skips the events of a partially processed block whose transaction
+	// has already been processed
+	ExactlyOnce
+)
+
+// Tracker records the events processed by a single registration and determines
+// which of the replayed events have already been processed.
+type Tracker struct {
+	mutex          sync.Mutex
+	checkpointer   Checkpointer
+	channelID      string
+	registrationID string
+	mode           DeliveryMode
+	checkpoint     *Checkpoint
+}
+
+// NewTracker returns a Tracker for the given registration, loaded with the registration's last checkpoint
+func NewTracker(checkpointer Checkpointer, channelID, registrationID string, mode DeliveryMode) (*Tracker, error) {
+	if checkpointer == nil {
+		return nil, errors.New("checkpointer is required")
+	}
+	if registrationID == "" {
+		return nil, errors.New("registration ID is required")
+	}
+
+	checkpoint, err := checkpointer.Load(channelID, registrationID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load checkpoint for registration [%s]", registrationID)
+	}
+
+	return &Tracker{
+		checkpointer:   checkpointer,
+		channelID:      channelID,
+		registrationID: registrationID,
+		mode:           mode,
+		checkpoint:     checkpoint,
+	}, nil
+}
+
+// ResumeBlock returns the block from which delivery should resume. False is returned
+// if the registration has no checkpoint.
+func (t *Tracker) ResumeBlock() (uint64, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.checkpoint == nil {
+		return 0, false
+	}
+	return t.checkpoint.ResumeBlock(), true
+}
+
+// TxProcessed returns true if the event for the given transaction has already been processed
+func (t *Tracker) TxProcessed(blockNum uint64, txID string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.checkpoint == nil {
+		return false
+	}
+	if blockNum != t.checkpoint.BlockNumber {
+		return blockNum < t.checkpoint.BlockNumber
+	}
+	if t.checkpoint.EventIndex < 0 {
+		return true
+	}
+	if t.mode != ExactlyOnce {
+		return false
+	}
+	for _, id := range t.checkpoint.TransactionIDs {
+		if id == txID {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockProcessed returns true if the given block has already been processed
+func (t *Tracker) BlockProcessed(blockNum uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.checkpoint == nil {
+		return false
+	}
+	return blockNum < t.checkpoint.ResumeBlock()
+}
+
+// RecordTx saves a checkpoint after the event for the given transaction has been processed
+func (t *Tracker) RecordTx(blockNum uint64, txID string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	checkpoint := &Checkpoint{BlockNumber: blockNum}
+	if t.checkpoint != nil && t.checkpoint.BlockNumber == blockNum && t.checkpoint.EventIndex >= 0 {
+		checkpoint.EventIndex = t.checkpoint.EventIndex + 1
+		checkpoint.TransactionIDs = append(checkpoint.TransactionIDs, t.checkpoint.TransactionIDs...)
+	}
+	if t.mode == ExactlyOnce {
+		checkpoint.TransactionIDs = append(checkpoint.TransactionIDs, txID)
+	}
+
+	return t.save(checkpoint)
+}
+
+// RecordBlock saves a checkpoint after the given block has been processed
+func (t *Tracker) RecordBlock(blockNum uint64) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.save(&Checkpoint{BlockNumber: blockNum, EventIndex: -1})
+}
+
+func (t *Tracker) save(checkpoint *Checkpoint) error {
+	logger.Debugf("Saving checkpoint for registration [%s] on channel [%s]: block %d, event index %d", t.registrationID, t.channelID, checkpoint.BlockNumber, checkpoint.EventIndex)
+
+	if err := t.checkpointer.Save(t.channelID, t.registrationID, checkpoint); err != nil {
+		return errors.WithMessagef(err, "failed to save checkpoint for registration [%s]", t.registrationID)
+	}
+	t.checkpoint = checkpoint
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "mychannel"
+	regID     = "myreg"
+)
+
+func TestFileCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	checkpointer, err := NewFileCheckpointer(dir)
+	require.NoError(t, err)
+
+	testCheckpointer(t, checkpointer)
+
+	_, err = os.Stat(filepath.Join(dir, channelID, regID+".json"))
+	require.NoError(t, err)
+
+	// IDs can't refer to files outside of the checkpoint directory
+	for _, id := range []string{".", "..", "../../escape", `..\escape`} {
+		require.Error(t, checkpointer.Save(id, regID, &Checkpoint{BlockNumber: 1}))
+		require.Error(t, checkpointer.Save(channelID, id, &Checkpoint{BlockNumber: 1}))
+		_, err = checkpointer.Load(channelID, id)
+		require.Error(t, err)
+	}
+	_, err = os.Stat(filepath.Join(dir, "..", "escape.json"))
+	require.True(t, os.IsNotExist(err))
+
+	_, err = NewFileCheckpointer("")
+	require.Error(t, err)
+}
+
+func TestKVStoreCheckpointer(t *testing.T) {
+	checkpointer, err := NewKVStoreCheckpointer(newMemoryStore())
+	require.NoError(t, err)
+
+	testCheckpointer(t, checkpointer)
+
+	// IDs containing '/' don't collide
+	require.NoError(t, checkpointer.Save("a/b", "c", &Checkpoint{BlockNumber: 1}))
+	require.NoError(t, checkpointer.Save("a", "b/c", &Checkpoint{BlockNumber: 2}))
+	cp, err := checkpointer.Load("a/b", "c")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), cp.BlockNumber)
+
+	_, err = NewKVStoreCheckpointer(nil)
+	require.Error(t, err)
+}
+
+func TestTrackerAtLeastOnce(t *testing.T) {
+	checkpointer, err := NewKVStoreCheckpointer(newMemoryStore())
+	require.NoError(t, err)
+
+	tracker, err := NewTracker(checkpointer, channelID, regID, AtLeastOnce)
+	require.NoError(t, err)
+
+	_, ok := tracker.ResumeBlock()
+	require.False(t, ok)
+	require.False(t, tracker.TxProcessed(5, "tx1"))
+
+	require.NoError(t, tracker.RecordTx(5, "tx1"))
+	require.NoError(t, tracker.RecordTx(5, "tx2"))
+
+	// Reload after a restart
+	tracker, err = NewTracker(checkpointer, channelID, regID, AtLeastOnce)
+	require.NoError(t, err)
+
+	fromBlock, ok := tracker.ResumeBlock()
+	require.True(t, ok)
+	require.Equal(t, uint64(5), fromBlock)
+
+	require.True(t, tracker.TxProcessed(4, "tx0"))
+	require.False(t, tracker.TxProcessed(5, "tx1"), "events of a partially processed block should be delivered again")
+	require.False(t, tracker.TxProcessed(6, "tx3"))
+
+	cp, err := checkpointer.Load(channelID, regID)
+	require.NoError(t, err)
+	require.Equal(t, 1, cp.EventIndex)
+	require.Empty(t, cp.TransactionIDs)
+}
+
+func TestTrackerExactlyOnce(t *testing.T) {
+	checkpointer, err := NewKVStoreCheckpointer(newMemoryStore())
+	require.NoError(t, err)
+
+	tracker, err := NewTracker(checkpointer, channelID, regID, ExactlyOnce)
+	require.NoError(t, err)
+
+	require.NoError(t, tracker.RecordTx(4, "tx0"))
+	require.NoError(t, tracker.RecordTx(5, "tx1"))
+
+	tracker, err = NewTracker(checkpointer, channelID, regID, ExactlyOnce)
+	require.NoError(t, err)
+
+	fromBlock, ok := tracker.ResumeBlock()
+	require.True(t, ok)
+	require.Equal(t, uint64(5), fromBlock)
+
+	require.True(t, tracker.TxProcessed(5, "tx1"))
+	require.False(t, tracker.TxProcessed(5, "tx2"))
+
+	cp, err := checkpointer.Load(channelID, regID)
+	require.NoError(t, err)
+	require.Equal(t, 0, cp.EventIndex)
+	require.Equal(t, []string{"tx1"}, cp.TransactionIDs)
+}
+
+func TestTrackerBlocks(t *testing.T) {
+	checkpointer, err := NewKVStoreCheckpointer(newMemoryStore())
+	require.NoError(t, err)
+
+	tracker, err := NewTracker(checkpointer, channelID, regID, AtLeastOnce)
+	require.NoError(t, err)
+	require.False(t, tracker.BlockProcessed(0))
+
+	require.NoError(t, tracker.RecordBlock(7))
+
+	tracker, err = NewTracker(checkpointer, channelID, regID, AtLeastOnce)
+	require.NoError(t, err)
+
+	fromBlock, ok := tracker.ResumeBlock()
+	require.True(t, ok)
+	require.Equal(t, uint64(8), fromBlock)
+	require.True(t, tracker.BlockProcessed(7))
+	require.False(t, tracker.BlockProcessed(8))
+	require.True(t, tracker.TxProcessed(7, "tx1"))
+}
+
+func TestNewTrackerErrors(t *testing.T) {
+	_, err := NewTracker(nil, channelID, regID, AtLeastOnce)
+	require.Error(t, err)
+
+	checkpointer, err := NewKVStoreCheckpointer(newMemoryStore())
+	require.NoError(t, err)
+	_, err = NewTracker(checkpointer, channelID, "", AtLeastOnce)
+	require.Error(t, err)
+}
+
+func testCheckpointer(t *testing.T, checkpointer Checkpointer) {
+	cp, err := checkpointer.Load(channelID, regID)
+	require.NoError(t, err)
+	require.Nil(t, cp)
+
+	expected := &Checkpoint{BlockNumber: 10, EventIndex: 1, TransactionIDs: []string{"tx1", "tx2"}}
+	require.NoError(t, checkpointer.Save(channelID, regID, expected))
+
+	cp, err = checkpointer.Load(channelID, regID)
+	require.NoError(t, err)
+	require.Equal(t, expected, cp)
+
+	cp, err = checkpointer.Load("otherchannel", regID)
+	require.NoError(t, err)
+	require.Nil(t, cp)
+
+	require.Error(t, checkpointer.Save(channelID, regID, nil))
+}
+
+type memoryStore struct {
+	values map[interface{}]interface{}
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[interface{}]interface{})}
+}
+
+func (s *memoryStore) Store(key interface{}, value interface{}) error {
+	s.values[key] = value
+	return nil
+}
+
+func (s *memoryStore) Load(key interface{}) (interface{}, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return value, nil
+}
+
+func (s *memoryStore) Delete(key interface{}) error {
+	delete(s.values, key)
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	newDirMode  = 0700
+	newFileMode = 0600
+)
+
+// FileCheckpointer stores the checkpoint of each registration in a separate JSON file
+// under <path>/<channelID>/<registrationID>.json
+type FileCheckpointer struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileCheckpointer returns a Checkpointer that stores checkpoints under the given directory
+func NewFileCheckpointer(path string) (*FileCheckpointer, error) {
+	if path == "" {
+		return nil, errors.New("checkpoint path is empty")
+	}
+
+	cleanPath := filepath.Clean(path)
+	if err := os.MkdirAll(cleanPath, newDirMode); err != nil {
+		return nil, errors.Wrapf(err, "failed to create checkpoint directory [%s]", cleanPath)
+	}
+
+	return &FileCheckpointer{path: cleanPath}, nil
+}
+
+// Load reads the checkpoint of the given registration. Nil is returned if the file does not exist.
+func (c *FileCheckpointer) Load(channelID, registrationID string) (*Checkpoint, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file, err := c.fileName(channelID, registrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{}
+	ok, err := LoadFile(file, checkpoint)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint of the given registration
+func (c *FileCheckpointer) Save(channelID, registrationID string, checkpoint *Checkpoint) error {
+	if checkpoint == nil {
+		return errors.New("checkpoint is nil")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file, err := c.fileName(channelID, registrationID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), newDirMode); err != nil {
+		return errors.Wrapf(err, "failed to create checkpoint directory for channel [%s]", channelID)
+	}
+
+	return SaveFile(file, checkpoint)
+}
+
+// fileName returns the checkpoint file of the given registration. IDs that could refer to a file
+// outside of the checkpoint directory are rejected.
+func (c *FileCheckpointer) fileName(channelID, registrationID string) (string, error) {
+	if err := validatePathElement(channelID); err != nil {
+		return "", errors.WithMessage(err, "invalid channel ID")
+	}
+	if err := validatePathElement(registrationID); err != nil {
+		return "", errors.WithMessage(err, "invalid registration ID")
+	}
+	return filepath.Join(c.path, url.PathEscape(channelID), url.PathEscape(registrationID)+".json"), nil
+}
+
+func validatePathElement(id string) error {
+	if id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return errors.Errorf("[%s] may not be a relative path element or contain a path separator", id)
+	}
+	return nil
+}
+
+// LoadFile reads the JSON checkpoint in the given file into checkpoint. False is returned if the file does not exist.
+func LoadFile(file string, checkpoint interface{}) (bool, error) {
+	content, err := ioutil.ReadFile(file) // nolint: gas
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to read checkpoint file [%s]", file)
+	}
+
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return false, errors.Wrapf(err, "invalid checkpoint file [%s]", file)
+	}
+
+	return true, nil
+}
+
+// SaveFile writes checkpoint as JSON to the given file. The checkpoint is written to a temporary
+// file which then replaces the existing one, so a crash never leaves a partial checkpoint.
+func SaveFile(file string, checkpoint interface{}) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, content, newFileMode); err != nil {
+		return errors.Wrapf(err, "failed to write checkpoint file [%s]", tmpFile)
+	}
+
+	return os.Rename(tmpFile, file)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// KVStoreCheckpointer stores the checkpoint of each registration as a JSON value
+// in a key value store (e.g. keyvaluestore.FileKeyValueStore).
+// The key is "<channelID>/<registrationID>" (each path-escaped, so that IDs containing '/' can't
+// collide) and the value is a byte array.
+type KVStoreCheckpointer struct {
+	store core.KVStore
+}
+
+// NewKVStoreCheckpointer returns a Checkpointer that is backed by the given key value store
+func NewKVStoreCheckpointer(store core.KVStore) (*KVStoreCheckpointer, error) {
+	if store == nil {
+		return nil, errors.New("key value store is nil")
+	}
+	return &KVStoreCheckpointer{store: store}, nil
+}
+
+// Load reads the checkpoint of the given registration. Nil is returned if no checkpoint is stored.
+func (c *KVStoreCheckpointer) Load(channelID, registrationID string) (*Checkpoint, error) {
+	value, err := c.store.Load(storeKey(channelID, registrationID))
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, nil
+		}
+		return nil, errors.WithMessage(err, "failed to load checkpoint")
+	}
+
+	content, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected checkpoint value type: %T", value)
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint")
+	}
+
+	return checkpoint, nil
+}
+
+// Save stores the checkpoint of the given registration
+func (c *KVStoreCheckpointer) Save(channelID, registrationID string, checkpoint *Checkpoint) error {
+	if checkpoint == nil {
+		return errors.New("checkpoint is nil")
+	}
+
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+
+	return c.store.Store(storeKey(channelID, registrationID), content)
+}
+
+func storeKey(channelID, registrationID string) string {
+	return fmt.Sprintf("%s/%s", url.PathEscape(channelID), url.PathEscape(registrationID))
+}
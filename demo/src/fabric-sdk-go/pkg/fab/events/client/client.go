@@ -317,6 +317,15 @@ func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration
 	return c.Service.RegisterBlockEvent(filter...)
 }
 
+// RegisterBlockAndPrivateDataEvent registers for block events which include private data. If the client
+// is not authorized to receive block and private data events then an error is returned.
+func (c *Client) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	if !c.permitPvtDataEvents {
+		return nil, nil, errors.New("block and private data events are not permitted")
+	}
+	return c.Service.RegisterBlockAndPrivateDataEvent(filter...)
+}
+
 // registerConnectionEvent registers a connection event. The returned
 // ConnectionEvent channel will be called whenever the client clients or disconnects
 // from the event server
@@ -340,6 +349,55 @@ func (c *Client) registerConnectionEvent() (fab.Registration, chan *dispatcher.C
 	}
 }
 
+// RegisterConnectionLifecycleEvent registers for connection lifecycle events, i.e. when the client
+// connects to, disconnects from or switches peers, or when the connected peer lags behind the
+// other peers in the channel. Each event includes the peer resolver's reasoning.
+func (c *Client) RegisterConnectionLifecycleEvent() (fab.Registration, <-chan *fab.ConnectionLifecycleEvent, error) {
+	if c.Stopped() {
+		return nil, nil, errors.New("event client is closed")
+	}
+
+	eventch := make(chan *fab.ConnectionLifecycleEvent, c.eventConsumerBufferSize)
+	errch := make(chan error)
+	regch := make(chan fab.Registration)
+	err1 := c.Submit(dispatcher.NewRegisterLifecycleEvent(eventch, regch, errch))
+	if err1 != nil {
+		return nil, nil, err1
+	}
+	select {
+	case reg := <-regch:
+		return reg, eventch, nil
+	case err := <-errch:
+		return nil, nil, err
+	}
+}
+
+// Status returns the health of the client's connection
+func (c *Client) Status() (*fab.EventClientStatus, error) {
+	if c.Stopped() {
+		return nil, errors.New("event client is closed")
+	}
+
+	reporter, ok := c.Dispatcher().(statusReporter)
+	if !ok {
+		return nil, errors.Errorf("dispatcher %T does not report status", c.Dispatcher())
+	}
+
+	status, err := reporter.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	// The connection state takes into account that the client may be reconnecting
+	status.Connected = status.Connected && c.ConnectionState() == Connected
+
+	return status, nil
+}
+
+type statusReporter interface {
+	Status() (*fab.EventClientStatus, error)
+}
+
 // Stopped returns true if the client has been stopped (disconnected)
 // and is no longer usable.
 func (c *Client) Stopped() bool {
@@ -86,6 +86,53 @@ func TestConnect(t *testing.T) {
 	time.Sleep(2 * time.Second)
 }
 
+func TestStatusAndLifecycleEvents(t *testing.T) {
+	connectionProvider := clientmocks.NewProviderFactory().Provider(
+		clientmocks.NewMockConnection(
+			clientmocks.WithLedger(servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL)),
+		),
+	)
+
+	eventClient, _, err := newClientWithMockConnAndOpts(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg("mychannel"),
+		clientmocks.NewDiscoveryService(peer1),
+		connectionProvider, filteredClientProvider, []options.Opt{},
+	)
+	require.NoError(t, err)
+
+	reg, lifecyclech, err := eventClient.RegisterConnectionLifecycleEvent()
+	require.NoError(t, err)
+
+	require.NoError(t, eventClient.Connect())
+
+	select {
+	case e := <-lifecyclech:
+		assert.Equal(t, fab.PeerConnected, e.Type)
+		assert.Equal(t, peer1.URL(), e.PeerURL)
+		assert.NotEmpty(t, e.Reason)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for connected event")
+	}
+
+	status, err := eventClient.Status()
+	require.NoError(t, err)
+	assert.True(t, status.Connected)
+	assert.Equal(t, peer1.URL(), status.PeerURL)
+	assert.NotEmpty(t, status.Reason)
+
+	eventClient.Unregister(reg)
+	_, ok := <-lifecyclech
+	assert.False(t, ok, "expecting lifecycle event channel to be closed")
+
+	eventClient.Close()
+
+	_, err = eventClient.Status()
+	assert.Error(t, err)
+}
+
 func TestFailConnect(t *testing.T) {
 	eventClient, _, err := newClientWithMockConnAndOpts(
 		fabmocks.NewMockContext(
@@ -228,6 +275,9 @@ func TestUnauthorizedBlockEvents(t *testing.T) {
 	if _, _, err := eventClient.RegisterBlockEvent(); err == nil {
 		t.Fatal("expecting error registering for block events on a filtered client")
 	}
+	if _, _, err := eventClient.RegisterBlockAndPrivateDataEvent(); err == nil {
+		t.Fatal("expecting error registering for block and private data events on a filtered client")
+	}
 }
 
 func TestBlockEvents(t *testing.T) {
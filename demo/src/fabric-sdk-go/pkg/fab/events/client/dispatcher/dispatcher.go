@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -39,6 +42,13 @@ type Dispatcher struct {
 	peerMonitorDone        chan struct{}
 	peer                   fab.Peer
 	lock                   sync.RWMutex
+	lifecycleRegistrations map[*LifecycleReg]struct{}
+	lastPeer               fab.Peer
+	previousPeerURL        string
+	connectedSince         time.Time
+	reason                 string
+	disconnectReason       string
+	lagReported            int32
 }
 
 // New creates a new dispatcher
@@ -53,6 +63,8 @@ func New(context context.Client, chConfig fab.ChannelCfg, discoveryService fab.D
 		chConfig:           chConfig,
 		discoveryService:   discoveryService,
 		connectionProvider: connectionProvider,
+
+		lifecycleRegistrations: make(map[*LifecycleReg]struct{}),
 	}
 	dispatcher.peerResolver = params.peerResolverProvider(dispatcher, context, chConfig.ID(), opts...)
 
@@ -85,10 +97,8 @@ func (ed *Dispatcher) HandleStopEvent(e esdispatcher.Event) {
 	// Remove all registrations and close the associated event channels
 	// so that the client is notified that the registration has been removed
 	ed.clearConnectionRegistration()
-	if ed.peerMonitorDone != nil {
-		close(ed.peerMonitorDone)
-		ed.peerMonitorDone = nil
-	}
+	ed.clearLifecycleRegistrations()
+	ed.stopPeerMonitor()
 
 	ed.Dispatcher.HandleStopEvent(e)
 }
@@ -120,12 +130,14 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 		return
 	}
 
-	peer, err := ed.peerResolver.Resolve(peers)
+	peer, reason, err := peerresolver.ResolveWithReason(ed.peerResolver, peers)
 	if err != nil {
 		evt.ErrCh <- err
 		return
 	}
 
+	logger.Debugf("Connecting to peer [%s] on channel [%s]: %s", peer.URL(), ed.chConfig.ID(), reason)
+
 	conn, err := ed.connectionProvider(ed.context, ed.chConfig, peer)
 	if err != nil {
 		logger.Warnf("error creating connection: %s", err)
@@ -135,6 +147,11 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 
 	ed.connection = conn
 	ed.setConnectedPeer(peer)
+	if ed.lastPeer != nil {
+		ed.previousPeerURL = ed.lastPeer.URL()
+	}
+	ed.lastPeer = peer
+	ed.reason = reason
 
 	go ed.connection.Receive(eventch)
 
@@ -152,6 +169,8 @@ func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
 
 	logger.Debug("Closing connection due to disconnect event...")
 
+	ed.disconnectReason = evt.Reason
+
 	ed.connection.Close()
 	ed.connection = nil
 	ed.setConnectedPeer(nil)
@@ -172,6 +191,73 @@ func (ed *Dispatcher) HandleRegisterConnectionEvent(e esdispatcher.Event) {
 	evt.RegCh <- evt.Reg
 }
 
+// HandleRegisterLifecycleEvent registers a connection lifecycle listener
+func (ed *Dispatcher) HandleRegisterLifecycleEvent(e esdispatcher.Event) {
+	evt := e.(*RegisterLifecycleEvent)
+
+	ed.lifecycleRegistrations[evt.Reg] = struct{}{}
+	ed.lifecycleRegistrationsChanged()
+	evt.RegCh <- evt.Reg
+}
+
+// HandleUnregisterEvent unregisters connection lifecycle listeners and delegates
+// all other registrations to the event service dispatcher
+func (ed *Dispatcher) HandleUnregisterEvent(e esdispatcher.Event) {
+	evt := e.(*esdispatcher.UnregisterEvent)
+
+	reg, ok := evt.Reg.(*LifecycleReg)
+	if !ok {
+		ed.Dispatcher.HandleUnregisterEvent(e)
+		return
+	}
+
+	if _, exists := ed.lifecycleRegistrations[reg]; !exists {
+		logger.Warnf("Error in unregister: connection lifecycle registration not found")
+		return
+	}
+
+	delete(ed.lifecycleRegistrations, reg)
+	close(reg.Eventch)
+	ed.lifecycleRegistrationsChanged()
+}
+
+// HandleStatusEvent returns the status of the connection. The block heights of the
+// peers are not known to the dispatcher and are filled in by Status.
+func (ed *Dispatcher) HandleStatusEvent(e esdispatcher.Event) {
+	evt := e.(*StatusEvent)
+
+	status := &fab.EventClientStatus{
+		Connected:         ed.connection != nil,
+		LastBlockReceived: ed.LastBlockNum(),
+		Reason:            ed.reason,
+	}
+	if ed.lastPeer != nil {
+		status.PeerURL = ed.lastPeer.URL()
+		status.PeerMSPID = ed.lastPeer.MSPID()
+	}
+	if status.Connected {
+		status.ConnectedSince = ed.connectedSince
+	}
+
+	evt.StatusCh <- status
+}
+
+func (ed *Dispatcher) handleLagEvent(e esdispatcher.Event) {
+	evt := e.(*lagEvent)
+
+	if ed.connection == nil || ed.lastPeer == nil {
+		logger.Debugf("Ignoring lag event since the client is not connected")
+		return
+	}
+
+	ed.publish(&fab.ConnectionLifecycleEvent{
+		Type:         fab.PeerLagging,
+		PeerURL:      ed.lastPeer.URL(),
+		BlocksBehind: evt.blocksBehind,
+		Reason:       evt.reason,
+	})
+}
+
 // HandleConnectedEvent sends a 'connected' event to any registered listener
 func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 	evt := e.(*ConnectedEvent)
@@ -186,10 +272,10 @@ func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 		}
 	}
 
-	if ed.peerMonitorPeriod > 0 {
-		ed.peerMonitorDone = make(chan struct{})
-		go ed.monitorPeer(ed.peerMonitorDone)
-	}
+	ed.connectedSince = time.Now()
+	ed.publishConnected()
+
+	ed.startPeerMonitor()
 }
 
 // HandleDisconnectedEvent sends a 'disconnected' event to any registered listener
@@ -214,15 +300,15 @@ func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 		logger.Warnf("Disconnected from event server: %s", evt.Err)
 	}
 
-	if ed.peerMonitorDone != nil {
-		close(ed.peerMonitorDone)
-		ed.peerMonitorDone = nil
-	}
+	ed.publishDisconnected(evt.Err)
+
+	ed.stopPeerMonitor()
 }
 
 func (ed *Dispatcher) registerHandlers() {
 	// Override existing handlers
 	ed.RegisterHandler(&esdispatcher.StopEvent{}, ed.HandleStopEvent)
+	ed.RegisterHandler(&esdispatcher.UnregisterEvent{}, ed.HandleUnregisterEvent)
 
 	// Register new handlers
 	ed.RegisterHandler(&ConnectEvent{}, ed.HandleConnectEvent)
@@ -230,6 +316,9 @@ func (ed *Dispatcher) registerHandlers() {
 	ed.RegisterHandler(&ConnectedEvent{}, ed.HandleConnectedEvent)
 	ed.RegisterHandler(&DisconnectedEvent{}, ed.HandleDisconnectedEvent)
 	ed.RegisterHandler(&RegisterConnectionEvent{}, ed.HandleRegisterConnectionEvent)
+	ed.RegisterHandler(&RegisterLifecycleEvent{}, ed.HandleRegisterLifecycleEvent)
+	ed.RegisterHandler(&StatusEvent{}, ed.HandleStatusEvent)
+	ed.RegisterHandler(&lagEvent{}, ed.handleLagEvent)
 }
 
 func (ed *Dispatcher) clearConnectionRegistration() {
@@ -240,20 +329,156 @@ func (ed *Dispatcher) clearConnectionRegistration() {
 	}
 }
 
+func (ed *Dispatcher) clearLifecycleRegistrations() {
+	for reg := range ed.lifecycleRegistrations {
+		close(reg.Eventch)
+	}
+	ed.lifecycleRegistrations = make(map[*LifecycleReg]struct{})
+	ed.lifecycleRegistrationsChanged()
+}
+
+// lifecycleRegistrationsChanged starts reporting the lag of the client when the first connection lifecycle
+// listener registers and stops when the last one unregisters, so that clients without listeners don't poll
+// discovery for the block heights of peers
+func (ed *Dispatcher) lifecycleRegistrationsChanged() {
+	if ed.lagThreshold >= 0 && len(ed.lifecycleRegistrations) > 0 {
+		atomic.StoreInt32(&ed.lagReported, 1)
+		if ed.connection != nil {
+			ed.startPeerMonitor()
+		}
+		return
+	}
+
+	atomic.StoreInt32(&ed.lagReported, 0)
+	if ed.peerMonitorPeriod <= 0 {
+		ed.stopPeerMonitor()
+	}
+}
+
+// startPeerMonitor starts monitoring the connected peer if the peer monitor is enabled or if the lag
+// of the client is reported, unless the peer is already monitored
+func (ed *Dispatcher) startPeerMonitor() {
+	if ed.peerMonitorDone != nil {
+		return
+	}
+	if ed.peerMonitorPeriod <= 0 && atomic.LoadInt32(&ed.lagReported) == 0 {
+		return
+	}
+
+	ed.peerMonitorDone = make(chan struct{})
+	go ed.monitorPeer(ed.peerMonitorDone)
+}
+
+func (ed *Dispatcher) stopPeerMonitor() {
+	if ed.peerMonitorDone != nil {
+		close(ed.peerMonitorDone)
+		ed.peerMonitorDone = nil
+	}
+}
+
+func (ed *Dispatcher) publishConnected() {
+	event := &fab.ConnectionLifecycleEvent{
+		Type:            fab.PeerConnected,
+		PreviousPeerURL: ed.previousPeerURL,
+		Reason:          ed.reason,
+	}
+	if ed.lastPeer != nil {
+		event.PeerURL = ed.lastPeer.URL()
+	}
+	if event.PreviousPeerURL != "" && event.PreviousPeerURL != event.PeerURL {
+		event.Type = fab.PeerSwitched
+	}
+
+	ed.publish(event)
+}
+
+func (ed *Dispatcher) publishDisconnected(err error) {
+	event := &fab.ConnectionLifecycleEvent{
+		Type:   fab.PeerDisconnected,
+		Err:    err,
+		Reason: ed.disconnectReason,
+	}
+	if ed.lastPeer != nil {
+		event.PeerURL = ed.lastPeer.URL()
+	}
+	if event.Reason == "" && err != nil {
+		event.Reason = err.Error()
+	}
+	ed.disconnectReason = ""
+
+	ed.publish(event)
+}
+
+func (ed *Dispatcher) publish(event *fab.ConnectionLifecycleEvent) {
+	event.Timestamp = time.Now()
+
+	for reg := range ed.lifecycleRegistrations {
+		select {
+		case reg.Eventch <- event:
+		default:
+			logger.Warn("Unable to send to connection lifecycle event channel.")
+		}
+	}
+}
+
+// Status returns the status of the connection, including the number of blocks by
+// which the client lags the highest block height of the channel's peers
+func (ed *Dispatcher) Status() (*fab.EventClientStatus, error) {
+	eventch, err := ed.EventCh()
+	if err != nil {
+		return nil, err
+	}
+
+	statusch := make(chan *fab.EventClientStatus, 1)
+	eventch <- NewStatusEvent(statusch)
+	status := <-statusch
+
+	if !status.Connected {
+		return status, nil
+	}
+
+	peers, err := ed.discoveryService.GetPeers()
+	if err != nil {
+		logger.Debugf("Unable to determine the block heights of peers: %s", err)
+		return status, nil
+	}
+
+	for _, p := range peers {
+		if p.URL() != status.PeerURL {
+			continue
+		}
+		if peerState, ok := p.(fab.PeerState); ok {
+			status.PeerBlockHeight = peerState.BlockHeight()
+		}
+	}
+	status.BlocksBehind = blocksBehind(status.LastBlockReceived, getMaxBlockHeight(peers))
+
+	return status, nil
+}
+
+// monitorPeer periodically checks whether the connected peer should be disconnected (if the peer monitor is enabled)
+// and whether the client lags behind the other peers in the channel (if the lag is reported)
 func (ed *Dispatcher) monitorPeer(done chan struct{}) {
 	logger.Debugf("Starting peer monitor on channel [%s]", ed.chConfig.ID())
 
-	ticker := time.NewTicker(ed.peerMonitorPeriod)
+	period := ed.peerMonitorPeriod
+	if period <= 0 {
+		period = ed.lagCheckPeriod
+	}
+
+	ticker := time.NewTicker(period)
 	defer ticker.Stop()
 
+	var reportedLag uint64
 	for {
 		select {
 		case <-ticker.C:
-			if ed.disconnected() {
+			if ed.peerMonitorPeriod > 0 && ed.disconnected() {
 				// Disconnected
 				logger.Debugf("Client on channel [%s] has disconnected - stopping disconnect monitor", ed.chConfig.ID())
 				return
 			}
+			reportedLag = ed.checkLag(reportedLag)
 		case <-done:
 			logger.Debugf("Stopping block height monitor on channel [%s]", ed.chConfig.ID())
 			return
@@ -278,14 +503,15 @@ func (ed *Dispatcher) disconnected() bool {
 		return false
 	}
 
-	if !ed.peerResolver.ShouldDisconnect(peers, connectedPeer) {
-		logger.Debugf("Event client will not disconnect from peer [%s] on channel [%s]...", connectedPeer.URL(), ed.chConfig.ID())
+	disconnect, reason := peerresolver.ShouldDisconnectWithReason(ed.peerResolver, peers, connectedPeer)
+	if !disconnect {
+		logger.Debugf("Event client will not disconnect from peer [%s] on channel [%s]: %s", connectedPeer.URL(), ed.chConfig.ID(), reason)
 		return false
 	}
 
-	logger.Warnf("The peer resolver determined that the event client should be disconnected from connected peer [%s] on channel [%s]. Disconnecting ...", connectedPeer.URL(), ed.chConfig.ID())
+	logger.Warnf("The peer resolver determined that the event client should be disconnected from connected peer [%s] on channel [%s] since %s. Disconnecting ...", connectedPeer.URL(), ed.chConfig.ID(), reason)
 
-	if err := ed.disconnect(); err != nil {
+	if err := ed.disconnect(reason); err != nil {
 		logger.Warnf("Error disconnecting event client from peer [%s] on channel [%s]: %s", connectedPeer.URL(), ed.chConfig.ID(), err)
 		return false
 	}
@@ -294,14 +520,60 @@ func (ed *Dispatcher) disconnected() bool {
 	return true
 }
 
-func (ed *Dispatcher) disconnect() error {
+// checkLag sends a lag event to the dispatcher if the client lags the highest block height of the
+// channel's peers by more than the lag threshold and the lag differs from the last reported lag.
+// The lag that was reported is returned (or zero if the client is not lagging).
+// The lag is only checked while there are connection lifecycle listeners.
+func (ed *Dispatcher) checkLag(reportedLag uint64) uint64 {
+	if atomic.LoadInt32(&ed.lagReported) == 0 {
+		return 0
+	}
+
+	lastBlockReceived := ed.LastBlockNum()
+	if lastBlockReceived == math.MaxUint64 {
+		// No blocks received yet
+		return 0
+	}
+
+	peers, err := ed.discoveryService.GetPeers()
+	if err != nil {
+		logger.Debugf("Unable to determine the block heights of peers: %s", err)
+		return reportedLag
+	}
+
+	maxHeight := getMaxBlockHeight(peers)
+	lag := blocksBehind(lastBlockReceived, maxHeight)
+	if lag <= uint64(ed.lagThreshold) {
+		return 0
+	}
+	if lag == reportedLag {
+		return reportedLag
+	}
+
+	eventch, err := ed.EventCh()
+	if err != nil {
+		logger.Debugf("Unable to report lag: %s", err)
+		return reportedLag
+	}
+
+	eventch <- &lagEvent{
+		blocksBehind: lag,
+		reason:       fmt.Sprintf("the last block received is %d whereas the max block height of peers is %d, which exceeds the lag threshold %d", lastBlockReceived, maxHeight, ed.lagThreshold),
+	}
+
+	return lag
+}
+
+func (ed *Dispatcher) disconnect(reason string) error {
 	eventch, err := ed.EventCh()
 	if err != nil {
 		return errors.WithMessage(err, "unable to get event dispatcher channel")
 	}
 
 	errch := make(chan error)
-	eventch <- NewDisconnectEvent(errch)
+	evt := NewDisconnectEvent(errch)
+	evt.Reason = reason
+	eventch <- evt
 	err = <-errch
 	if err != nil {
 		return err
@@ -324,3 +596,25 @@ func (ed *Dispatcher) ConnectedPeer() fab.Peer {
 	defer ed.lock.RUnlock()
 	return ed.peer
 }
+
+func getMaxBlockHeight(peers []fab.Peer) uint64 {
+	var maxHeight uint64
+	for _, peer := range peers {
+		peerState, ok := peer.(fab.PeerState)
+		if ok && peerState.BlockHeight() > maxHeight {
+			maxHeight = peerState.BlockHeight()
+		}
+	}
+	return maxHeight
+}
+
+// blocksBehind returns the number of blocks between the last block received and the given block height
+func blocksBehind(lastBlockReceived, blockHeight uint64) uint64 {
+	if lastBlockReceived == math.MaxUint64 {
+		return blockHeight
+	}
+	if blockHeight <= lastBlockReceived+1 {
+		return 0
+	}
+	return blockHeight - (lastBlockReceived + 1)
+}
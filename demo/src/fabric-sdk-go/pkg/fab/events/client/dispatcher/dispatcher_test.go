@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/lbp"
 	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/peerresolver/balanced"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/peerresolver/minblockheight"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/peerresolver/preferorg"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
@@ -488,6 +490,7 @@ func TestOpts(t *testing.T) {
 		params := defaultParams(context, channelID)
 		require.NotNil(t, params)
 		assert.Equal(t, 0*time.Second, params.peerMonitorPeriod, "Expecting peer monitor to be disabled")
+		assert.Equal(t, defaultPeerMonitorPeriod, params.lagCheckPeriod, "Expecting lag to be checked with the default period")
 		require.NotNil(t, params.peerResolverProvider)
 	})
 
@@ -503,6 +506,7 @@ func TestOpts(t *testing.T) {
 		params := defaultParams(context, channelID)
 		require.NotNil(t, params)
 		assert.Equalf(t, 0*time.Second, params.peerMonitorPeriod, "Expecting peer monitor to be disabled for Balance strategy")
+		assert.Equal(t, defaultPeerMonitorPeriod, params.lagCheckPeriod, "Expecting lag to be checked with the default period")
 		require.NotNil(t, params.peerResolverProvider)
 	})
 }
@@ -524,3 +528,271 @@ func TestDisconnectedEvent(t *testing.T) {
 		require.False(t, evt.Err.IsFatal())
 	})
 }
+
+func TestConnectionLifecycleEvents(t *testing.T) {
+	p1 := clientmocks.NewMockPeer("peer1", "grpcs://peer1.example.com:7051", 4)
+	p2 := clientmocks.NewMockPeer("peer2", "grpcs://peer2.example.com:7051", 1)
+
+	channelID := "testchannel"
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(p1, p2),
+		clientmocks.NewProviderFactory().Provider(
+			clientmocks.NewMockConnection(
+				clientmocks.WithLedger(
+					servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL),
+				),
+			),
+		),
+		WithPeerResolver(minblockheight.NewResolver()),
+		WithPeerMonitorPeriod(100*time.Millisecond),
+		minblockheight.WithBlockHeightLagThreshold(2),
+		minblockheight.WithReconnectBlockHeightThreshold(20),
+	)
+
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	regerrch := make(chan error)
+	regch := make(chan fab.Registration)
+	lifecyclech := make(chan *fab.ConnectionLifecycleEvent, 10)
+	dispatcherEventch <- NewRegisterLifecycleEvent(lifecyclech, regch, regerrch)
+
+	var reg fab.Registration
+	select {
+	case reg = <-regch:
+	case err := <-regerrch:
+		t.Fatalf("Error registering for connection lifecycle events: %s", err)
+	}
+
+	status, err := dispatcher.Status()
+	require.NoError(t, err)
+	assert.False(t, status.Connected)
+
+	errch := make(chan error)
+	dispatcherEventch <- NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+	dispatcherEventch <- NewConnectedEvent()
+
+	e := nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerConnected, e.Type)
+	assert.Equal(t, p1.URL(), e.PeerURL)
+	assert.Contains(t, e.Reason, "1 of 2 peers are at or above the cutoff block height 2")
+
+	status, err = dispatcher.Status()
+	require.NoError(t, err)
+	assert.True(t, status.Connected)
+	assert.Equal(t, p1.URL(), status.PeerURL)
+	assert.Equal(t, uint64(4), status.PeerBlockHeight)
+	assert.Equal(t, uint64(4), status.BlocksBehind)
+	assert.Equal(t, e.Reason, status.Reason)
+	assert.False(t, status.ConnectedSince.IsZero())
+
+	blockProducer := servicemocks.NewBlockProducer()
+	for i := 0; i < 4; i++ {
+		dispatcherEventch <- esdispatcher.NewBlockEvent(blockProducer.NewBlock(channelID), sourceURL)
+	}
+
+	p2.SetBlockHeight(10)
+
+	e = nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerLagging, e.Type)
+	assert.Equal(t, p1.URL(), e.PeerURL)
+	assert.Equal(t, uint64(6), e.BlocksBehind)
+	assert.Contains(t, e.Reason, "max block height of peers is 10")
+
+	dispatcherEventch <- NewDisconnectedEvent(errors.New("injected error"))
+
+	e = nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerDisconnected, e.Type)
+	assert.Equal(t, p1.URL(), e.PeerURL)
+	assert.Equal(t, "injected error", e.Reason)
+
+	p1.SetBlockHeight(1)
+
+	dispatcherEventch <- NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+	dispatcherEventch <- NewConnectedEvent()
+
+	e = nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerSwitched, e.Type)
+	assert.Equal(t, p2.URL(), e.PeerURL)
+	assert.Equal(t, p1.URL(), e.PreviousPeerURL)
+
+	dispatcherEventch <- esdispatcher.NewUnregisterEvent(reg)
+
+	select {
+	case _, ok := <-lifecyclech:
+		assert.False(t, ok, "expecting lifecycle event channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("Expecting lifecycle event channel to be closed")
+	}
+
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	require.NoError(t, <-stopResp)
+}
+
+// TestLagEventsWithoutPeerMonitor tests that lagging events are published when the peer monitor is disabled
+func TestLagEventsWithoutPeerMonitor(t *testing.T) {
+	p1 := clientmocks.NewMockPeer("peer1", "grpcs://peer1.example.com:7051", 4)
+	p2 := clientmocks.NewMockPeer("peer2", "grpcs://peer2.example.com:7051", 4)
+
+	channelID := "testchannel"
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(p1, p2),
+		clientmocks.NewProviderFactory().Provider(
+			clientmocks.NewMockConnection(
+				clientmocks.WithLedger(
+					servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL),
+				),
+			),
+		),
+		WithPeerResolver(balanced.NewResolver()),
+		WithPeerMonitorPeriod(0),
+		minblockheight.WithBlockHeightLagThreshold(2),
+	)
+	dispatcher.lagCheckPeriod = 100 * time.Millisecond
+
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	regerrch := make(chan error)
+	regch := make(chan fab.Registration)
+	lifecyclech := make(chan *fab.ConnectionLifecycleEvent, 10)
+	dispatcherEventch <- NewRegisterLifecycleEvent(lifecyclech, regch, regerrch)
+
+	select {
+	case <-regch:
+	case err := <-regerrch:
+		t.Fatalf("Error registering for connection lifecycle events: %s", err)
+	}
+
+	errch := make(chan error)
+	dispatcherEventch <- NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+	dispatcherEventch <- NewConnectedEvent()
+
+	e := nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerConnected, e.Type)
+
+	blockProducer := servicemocks.NewBlockProducer()
+	dispatcherEventch <- esdispatcher.NewBlockEvent(blockProducer.NewBlock(channelID), sourceURL)
+
+	p1.SetBlockHeight(10)
+	p2.SetBlockHeight(10)
+
+	e = nextLifecycleEvent(t, lifecyclech)
+	assert.Equal(t, fab.PeerLagging, e.Type)
+	assert.Equal(t, uint64(9), e.BlocksBehind)
+
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	require.NoError(t, <-stopResp)
+}
+
+// TestLagCheckedWithLifecycleListeners tests that discovery is polled for the lag of the client only
+// while there are connection lifecycle listeners when the peer monitor is disabled
+func TestLagCheckedWithLifecycleListeners(t *testing.T) {
+	channelID := "testchannel"
+	discovery := &countingDiscoveryService{DiscoveryService: clientmocks.NewDiscoveryService(peer1, peer2)}
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		discovery,
+		clientmocks.NewProviderFactory().Provider(
+			clientmocks.NewMockConnection(
+				clientmocks.WithLedger(
+					servicemocks.NewMockLedger(servicemocks.FilteredBlockEventFactory, sourceURL),
+				),
+			),
+		),
+		WithPeerResolver(balanced.NewResolver()),
+		WithPeerMonitorPeriod(0),
+	)
+	dispatcher.lagCheckPeriod = 20 * time.Millisecond
+
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	errch := make(chan error)
+	dispatcherEventch <- NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+	dispatcherEventch <- NewConnectedEvent()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), discovery.count(), "expecting discovery to be polled only on connect")
+
+	regerrch := make(chan error)
+	regch := make(chan fab.Registration)
+	lifecyclech := make(chan *fab.ConnectionLifecycleEvent, 10)
+	dispatcherEventch <- NewRegisterLifecycleEvent(lifecyclech, regch, regerrch)
+
+	var reg fab.Registration
+	select {
+	case reg = <-regch:
+	case err := <-regerrch:
+		t.Fatalf("Error registering for connection lifecycle events: %s", err)
+	}
+
+	dispatcherEventch <- esdispatcher.NewBlockEvent(servicemocks.NewBlockProducer().NewBlock(channelID), sourceURL)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, discovery.count() > 1, "expecting discovery to be polled for the lag of the client")
+
+	dispatcherEventch <- esdispatcher.NewUnregisterEvent(reg)
+	for range lifecyclech {
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	count := discovery.count()
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, count, discovery.count(), "expecting discovery to no longer be polled")
+
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	require.NoError(t, <-stopResp)
+}
+
+type countingDiscoveryService struct {
+	fab.DiscoveryService
+	calls int32
+}
+
+func (s *countingDiscoveryService) GetPeers() ([]fab.Peer, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return s.DiscoveryService.GetPeers()
+}
+
+func (s *countingDiscoveryService) count() int32 {
+	return atomic.LoadInt32(&s.calls)
+}
+
+func nextLifecycleEvent(t *testing.T, lifecyclech <-chan *fab.ConnectionLifecycleEvent) *fab.ConnectionLifecycleEvent {
+	select {
+	case e, ok := <-lifecyclech:
+		require.True(t, ok, "lifecycle event channel was closed")
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expecting connection lifecycle event but got none")
+	}
+	return nil
+}
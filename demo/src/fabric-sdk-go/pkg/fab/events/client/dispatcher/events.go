@@ -25,6 +25,36 @@ func NewRegisterConnectionEvent(eventch chan<- *ConnectionEvent, regch chan<- fa
 	}
 }
 
+// RegisterLifecycleEvent is a request to register for connection lifecycle events
+type RegisterLifecycleEvent struct {
+	esdispatcher.RegisterEvent
+	Reg *LifecycleReg
+}
+
+// NewRegisterLifecycleEvent creates a new RegisterLifecycleEvent
+func NewRegisterLifecycleEvent(eventch chan<- *fab.ConnectionLifecycleEvent, regch chan<- fab.Registration, errch chan<- error) *RegisterLifecycleEvent {
+	return &RegisterLifecycleEvent{
+		Reg:           &LifecycleReg{Eventch: eventch},
+		RegisterEvent: esdispatcher.NewRegisterEvent(regch, errch),
+	}
+}
+
+// StatusEvent requests the status of the connection
+type StatusEvent struct {
+	StatusCh chan<- *fab.EventClientStatus
+}
+
+// NewStatusEvent creates a new StatusEvent
+func NewStatusEvent(statusch chan<- *fab.EventClientStatus) *StatusEvent {
+	return &StatusEvent{StatusCh: statusch}
+}
+
+// lagEvent is sent by the peer monitor when the client lags behind the other peers in the channel
+type lagEvent struct {
+	blocksBehind uint64
+	reason       string
+}
+
 // ConnectedEvent indicates that the client has connected to the server
 type ConnectedEvent struct {
 }
@@ -81,9 +111,11 @@ func NewConnectEvent(errch chan<- error) *ConnectEvent {
 	return &ConnectEvent{ErrCh: errch}
 }
 
-// DisconnectEvent is a request to disconnect to the server
+// DisconnectEvent is a request to disconnect to the server. Reason optionally
+// explains why the client is disconnecting.
 type DisconnectEvent struct {
-	Errch chan<- error
+	Errch  chan<- error
+	Reason string
 }
 
 // NewDisconnectEvent creates a new DisconnectEvent
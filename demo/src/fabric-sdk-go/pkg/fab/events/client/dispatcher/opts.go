@@ -26,6 +26,8 @@ const (
 type params struct {
 	peerMonitorPeriod    time.Duration
 	peerResolverProvider peerresolver.Provider
+	lagThreshold         int
+	lagCheckPeriod       time.Duration
 }
 
 func defaultParams(context context.Client, channelID string) *params {
@@ -33,6 +35,12 @@ func defaultParams(context context.Client, channelID string) *params {
 
 	peerMonitorPeriod := policy.PeerMonitorPeriod
 
+	// The lag of the client is checked with the peer monitor period even if the peer monitor is disabled
+	lagCheckPeriod := peerMonitorPeriod
+	if lagCheckPeriod <= 0 {
+		lagCheckPeriod = defaultPeerMonitorPeriod
+	}
+
 	// Set the peer monitor period to 0 (disabled) if explicitly configured to be disabled or
 	// if the resolver is Balanced (since there's no need for a peer monitor for Balanced strategy)
 	if policy.PeerMonitor == fab.Disabled || policy.ResolverStrategy == fab.BalancedStrategy {
@@ -45,6 +53,8 @@ func defaultParams(context context.Client, channelID string) *params {
 	return &params{
 		peerMonitorPeriod:    peerMonitorPeriod,
 		peerResolverProvider: getPeerResolver(policy),
+		lagThreshold:         policy.BlockHeightLagThreshold,
+		lagCheckPeriod:       lagCheckPeriod,
 	}
 }
 
@@ -115,3 +125,10 @@ func getPeerResolver(policy fab.EventServicePolicy) peerresolver.Provider {
 		return preferorg.NewResolver()
 	}
 }
+
+// SetBlockHeightLagThreshold sets the number of blocks by which the client may lag the
+// highest block height of the channel's peers before a lagging event is published
+func (p *params) SetBlockHeightLagThreshold(value int) {
+	logger.Debugf("BlockHeightLagThreshold: %d", value)
+	p.lagThreshold = value
+}
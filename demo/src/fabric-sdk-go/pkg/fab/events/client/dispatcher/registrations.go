@@ -6,7 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 
 package dispatcher
 
+import "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+
 // ConnectionReg is a connection registration
 type ConnectionReg struct {
 	Eventch chan<- *ConnectionEvent
 }
+
+// LifecycleReg is a connection lifecycle registration
+type LifecycleReg struct {
+	Eventch chan<- *fab.ConnectionLifecycleEvent
+}
@@ -22,6 +22,7 @@ type params struct {
 	maxConnAttempts         uint
 	maxReconnAttempts       uint
 	permitBlockEvents       bool
+	permitPvtDataEvents     bool
 	reconn                  bool
 }
 
@@ -47,6 +48,18 @@ func WithBlockEvents() options.Opt {
 	}
 }
 
+// WithBlockAndPrivateData indicates that block events, including the private data
+// of each transaction, are to be received. Block events are also permitted.
+// Note that the caller must have sufficient privileges for this option and only
+// the private data of collections of which the caller is a member is received.
+func WithBlockAndPrivateData() options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(permitBlockAndPrivateDataEventsSetter); ok {
+			setter.PermitBlockAndPrivateDataEvents()
+		}
+	}
+}
+
 // WithReconnect indicates whether the client should automatically attempt to reconnect
 // to the server after a connection has been lost
 func WithReconnect(value bool) options.Opt {
@@ -159,6 +172,12 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) PermitBlockAndPrivateDataEvents() {
+	logger.Debugf("PermitBlockAndPrivateDataEvents")
+	p.permitBlockEvents = true
+	p.permitPvtDataEvents = true
+}
+
 type reconnectSetter interface {
 	SetReconnect(value bool)
 }
@@ -190,3 +209,7 @@ type responseTimeoutSetter interface {
 type permitBlockEventsSetter interface {
 	PermitBlockEvents()
 }
+
+type permitBlockAndPrivateDataEventsSetter interface {
+	PermitBlockAndPrivateDataEvents()
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package balanced
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -46,7 +48,21 @@ func (r *PeerResolver) Resolve(peers []fab.Peer) (fab.Peer, error) {
 	return r.loadBalancePolicy.Choose(peers)
 }
 
+// ResolveWithReason returns a peer using the configured load balancer and explains why it was chosen.
+func (r *PeerResolver) ResolveWithReason(peers []fab.Peer) (fab.Peer, string, error) {
+	peer, err := r.Resolve(peers)
+	if err != nil {
+		return nil, "", err
+	}
+	return peer, fmt.Sprintf("%s was chosen by the load balancer from %d peers", peerresolver.Describe(peer), len(peers)), nil
+}
+
 // ShouldDisconnect always returns false (will not disconnect a connected peer)
 func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer) bool {
 	return false
 }
+
+// ShouldDisconnectWithReason always returns false (will not disconnect a connected peer)
+func (r *PeerResolver) ShouldDisconnectWithReason(peers []fab.Peer, connectedPeer fab.Peer) (bool, string) {
+	return false, "the balanced resolver never disconnects from a connected peer"
+}
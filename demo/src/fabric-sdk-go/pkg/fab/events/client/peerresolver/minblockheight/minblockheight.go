@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package minblockheight
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -51,11 +52,31 @@ func New(dispatcher service.Dispatcher, context context.Client, channelID string
 // Resolve returns the best peer according to a block height lag threshold. The maximum block height of
 // all peers is determined and the peers that are within a provided "lag" threshold are load balanced.
 func (r *PeerResolver) Resolve(peers []fab.Peer) (fab.Peer, error) {
-	return r.loadBalancePolicy.Choose(r.Filter(peers))
+	peer, _, err := r.ResolveWithReason(peers)
+	return peer, err
+}
+
+// ResolveWithReason returns the best peer according to a block height lag threshold and explains why it was chosen.
+func (r *PeerResolver) ResolveWithReason(peers []fab.Peer) (fab.Peer, string, error) {
+	filteredPeers, reason := r.FilterWithReason(peers)
+
+	peer, err := r.loadBalancePolicy.Choose(filteredPeers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return peer, fmt.Sprintf("%s was chosen since %s", peerresolver.Describe(peer), reason), nil
 }
 
 // Filter returns the peers that are within a provided "lag" threshold from the highest block height of all peers.
 func (r *PeerResolver) Filter(peers []fab.Peer) []fab.Peer {
+	filteredPeers, _ := r.FilterWithReason(peers)
+	return filteredPeers
+}
+
+// FilterWithReason returns the peers that are within a provided "lag" threshold from the highest block height
+// of all peers along with a description of the criteria that was applied.
+func (r *PeerResolver) FilterWithReason(peers []fab.Peer) ([]fab.Peer, string) {
 	var minBlockHeight uint64
 	if r.minBlockHeight > 0 {
 		lastBlockReceived := r.dispatcher.LastBlockNum()
@@ -74,31 +95,39 @@ func (r *PeerResolver) Filter(peers []fab.Peer) []fab.Peer {
 		}
 	}
 
-	retPeers := r.doFilterByBlockHeight(minBlockHeight, peers)
+	retPeers, reason := r.doFilterByBlockHeight(minBlockHeight, peers)
 	if len(retPeers) == 0 && minBlockHeight > 0 {
 		// The last block that was received may have been the last block in the channel. Try again with lastBlock-1.
 		logger.Debugf("No peers at the minimum height %d. Trying again with min height %d ...", minBlockHeight, minBlockHeight-1)
 		minBlockHeight--
-		retPeers = r.doFilterByBlockHeight(minBlockHeight, peers)
+		retPeers, reason = r.doFilterByBlockHeight(minBlockHeight, peers)
 		if len(retPeers) == 0 {
 			// No peers at the given height. Try again without min height
 			logger.Debugf("No peers at the minimum height %d. Trying again without min height ...", minBlockHeight)
-			retPeers = r.doFilterByBlockHeight(0, peers)
+			retPeers, reason = r.doFilterByBlockHeight(0, peers)
+			reason = fmt.Sprintf("no peers are at the minimum block height %d and %s", minBlockHeight, reason)
 		}
 	}
 
-	return retPeers
+	return retPeers, reason
 }
 
 // ShouldDisconnect checks the current peer's block height relative to the block heights of the
 // other peers and disconnects the peer if the configured threshold is reached.
 // Returns false if the block height is acceptable; true if the client should be disconnected from the peer
 func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer) bool {
+	disconnect, _ := r.ShouldDisconnectWithReason(peers, connectedPeer)
+	return disconnect
+}
+
+// ShouldDisconnectWithReason checks the current peer's block height relative to the block heights of the
+// other peers and explains whether or not the peer should be disconnected.
+func (r *PeerResolver) ShouldDisconnectWithReason(peers []fab.Peer, connectedPeer fab.Peer) (bool, string) {
 	// Check if the peer should be disconnected
 	peerState, ok := connectedPeer.(fab.PeerState)
 	if !ok {
 		logger.Debugf("Peer does not contain state")
-		return false
+		return false, fmt.Sprintf("the block height of peer [%s] is unknown", connectedPeer.URL())
 	}
 
 	lastBlockReceived := r.dispatcher.LastBlockNum()
@@ -110,14 +139,14 @@ func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer
 
 	if maxHeight <= uint64(r.reconnectBlockHeightLagThreshold) {
 		logger.Debugf("Max block height of peers is %d and reconnect lag threshold is %d so event client will not be disconnected from peer", maxHeight, r.reconnectBlockHeightLagThreshold)
-		return false
+		return false, fmt.Sprintf("the max block height of peers %d is within the reconnect lag threshold %d", maxHeight, r.reconnectBlockHeightLagThreshold)
 	}
 
 	// The last block received may be lagging the actual block height of the peer
 	if lastBlockReceived+1 < connectedPeerBlockHeight {
 		// We can still get more blocks from the connected peer. Don't disconnect
 		logger.Debugf("Block height of connected peer [%s] from Discovery is %d which is greater than last block received+1: %d. Won't disconnect from this peer since more blocks can still be retrieved from the peer", connectedPeer.URL(), connectedPeerBlockHeight, lastBlockReceived+1)
-		return false
+		return false, fmt.Sprintf("more blocks can be retrieved from peer [%s] at block height %d since the last block received is %d", connectedPeer.URL(), connectedPeerBlockHeight, lastBlockReceived)
 	}
 
 	cutoffHeight := maxHeight - uint64(r.reconnectBlockHeightLagThreshold)
@@ -125,23 +154,25 @@ func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer
 
 	if peerBlockHeight >= cutoffHeight {
 		logger.Debugf("Block height from connected peer [%s] is %d which is greater than or equal to the cutoff %d so event client will not be disconnected from peer", connectedPeer.URL(), peerBlockHeight, cutoffHeight)
-		return false
+		return false, fmt.Sprintf("block height %d from peer [%s] is at or above the cutoff %d (max height %d, reconnect lag threshold %d)", peerBlockHeight, connectedPeer.URL(), cutoffHeight, maxHeight, r.reconnectBlockHeightLagThreshold)
 	}
 
 	logger.Debugf("Block height from connected peer is %d which is less than the cutoff %d. Peer should be disconnected.", peerBlockHeight, cutoffHeight)
 
-	return true
+	return true, fmt.Sprintf("block height %d from peer [%s] is below the cutoff %d (max height %d, reconnect lag threshold %d)", peerBlockHeight, connectedPeer.URL(), cutoffHeight, maxHeight, r.reconnectBlockHeightLagThreshold)
 }
 
-func (r *PeerResolver) doFilterByBlockHeight(minBlockHeight uint64, peers []fab.Peer) []fab.Peer {
+func (r *PeerResolver) doFilterByBlockHeight(minBlockHeight uint64, peers []fab.Peer) ([]fab.Peer, string) {
 	var cutoffHeight uint64
+	var criteria string
 	if minBlockHeight > 0 {
 		logger.Debugf("Setting cutoff height to be min block height: %d ...", minBlockHeight)
 		cutoffHeight = minBlockHeight
+		criteria = fmt.Sprintf("the minimum block height %d", minBlockHeight)
 	} else {
 		if r.blockHeightLagThreshold < 0 || len(peers) == 1 {
 			logger.Debugf("Returning all peers")
-			return peers
+			return peers, fmt.Sprintf("all %d peers are eligible", len(peers))
 		}
 
 		maxHeight := getMaxBlockHeight(peers)
@@ -149,9 +180,10 @@ func (r *PeerResolver) doFilterByBlockHeight(minBlockHeight uint64, peers []fab.
 
 		if maxHeight <= uint64(r.blockHeightLagThreshold) {
 			logger.Debugf("Max block height of peers is %d and lag threshold is %d so returning all peers", maxHeight, r.blockHeightLagThreshold)
-			return peers
+			return peers, fmt.Sprintf("all %d peers are eligible since the max block height %d is within the lag threshold %d", len(peers), maxHeight, r.blockHeightLagThreshold)
 		}
 		cutoffHeight = maxHeight - uint64(r.blockHeightLagThreshold)
+		criteria = fmt.Sprintf("the cutoff block height %d (max height %d, lag threshold %d)", cutoffHeight, maxHeight, r.blockHeightLagThreshold)
 	}
 
 	logger.Debugf("Choosing peers whose block heights are at least the cutoff height %d ...", cutoffHeight)
@@ -169,7 +201,7 @@ func (r *PeerResolver) doFilterByBlockHeight(minBlockHeight uint64, peers []fab.
 			logger.Debugf("Rejecting peer [%s] at block height %d which is less than the cutoff %d", p.URL(), peerState.BlockHeight(), cutoffHeight)
 		}
 	}
-	return retPeers
+	return retPeers, fmt.Sprintf("%d of %d peers are at or above %s", len(retPeers), len(peers), criteria)
 }
 
 func getMaxBlockHeight(peers []fab.Peer) uint64 {
@@ -129,6 +129,26 @@ func TestShouldDisconnect(t *testing.T) {
 	assert.Falsef(t, disconnect, "expecting peer NOT to be disconnected since the peer's block height is under the reconnectBlockHeightThreshold")
 }
 
+func TestReasons(t *testing.T) {
+	dispatcher := &clientmocks.MockDispatcher{LastBlock: 100}
+	ctx := mocks.NewMockContext(mockmsp.NewMockSigningIdentity("test", org1MSP))
+
+	resolver := New(dispatcher, ctx, testChannel, WithBlockHeightLagThreshold(5), WithReconnectBlockHeightThreshold(5))
+
+	peer, reason, err := resolver.ResolveWithReason(peers)
+	require.NoError(t, err)
+	assert.Contains(t, reason, peer.URL())
+	assert.Contains(t, reason, "2 of 3 peers are at or above the cutoff block height 106 (max height 111, lag threshold 5)")
+
+	disconnect, reason := resolver.ShouldDisconnectWithReason(peers, p1)
+	assert.True(t, disconnect)
+	assert.Equal(t, "block height 101 from peer [peer1.example.com:7051] is below the cutoff 106 (max height 111, reconnect lag threshold 5)", reason)
+
+	disconnect, reason = resolver.ShouldDisconnectWithReason(peers, p3)
+	assert.False(t, disconnect)
+	assert.Contains(t, reason, "more blocks can be retrieved from peer [peer3.example.com:7051]")
+}
+
 func TestOpts(t *testing.T) {
 	channelID := "testchannel"
 
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package peerresolver
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -23,3 +25,48 @@ type Resolver interface {
 
 // Provider creates a peer Resolver
 type Provider func(ed service.Dispatcher, context context.Client, channelID string, opts ...options.Opt) Resolver
+
+// ReasoningResolver is a Resolver that explains its decisions. The reason is reported to
+// applications in the event client's status and connection lifecycle events.
+type ReasoningResolver interface {
+	Resolver
+	// ResolveWithReason chooses a peer from the given set of peers and explains why it was chosen
+	ResolveWithReason(peers []fab.Peer) (fab.Peer, string, error)
+	// ShouldDisconnectWithReason returns true to disconnect from the connected peer along with the reason for the decision
+	ShouldDisconnectWithReason(peers []fab.Peer, connectedPeer fab.Peer) (bool, string)
+}
+
+// ResolveWithReason chooses a peer using the given resolver. If the resolver is not a
+// ReasoningResolver then a generic reason is returned.
+func ResolveWithReason(resolver Resolver, peers []fab.Peer) (fab.Peer, string, error) {
+	if r, ok := resolver.(ReasoningResolver); ok {
+		return r.ResolveWithReason(peers)
+	}
+
+	peer, err := resolver.Resolve(peers)
+	if err != nil {
+		return nil, "", err
+	}
+	return peer, fmt.Sprintf("%s chosen by resolver %T", Describe(peer), resolver), nil
+}
+
+// ShouldDisconnectWithReason determines whether the connected peer should be disconnected using the
+// given resolver. If the resolver is not a ReasoningResolver then a generic reason is returned.
+func ShouldDisconnectWithReason(resolver Resolver, peers []fab.Peer, connectedPeer fab.Peer) (bool, string) {
+	if r, ok := resolver.(ReasoningResolver); ok {
+		return r.ShouldDisconnectWithReason(peers, connectedPeer)
+	}
+
+	if resolver.ShouldDisconnect(peers, connectedPeer) {
+		return true, fmt.Sprintf("resolver %T determined that %s should be disconnected", resolver, Describe(connectedPeer))
+	}
+	return false, fmt.Sprintf("resolver %T determined that %s should remain connected", resolver, Describe(connectedPeer))
+}
+
+// Describe returns a description of the peer, including its block height if known, for use in reasons
+func Describe(peer fab.Peer) string {
+	if peerState, ok := peer.(fab.PeerState); ok {
+		return fmt.Sprintf("peer [%s] at block height %d", peer.URL(), peerState.BlockHeight())
+	}
+	return fmt.Sprintf("peer [%s]", peer.URL())
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package preferorg
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -52,7 +54,14 @@ func New(dispatcher service.Dispatcher, context context.Client, channelID string
 
 // Resolve uses the MinBlockHeight resolver to choose peers but will prefer peers in the given org.
 func (r *PeerResolver) Resolve(peers []fab.Peer) (fab.Peer, error) {
-	filteredPeers := r.blockHeightResolver.Filter(peers)
+	peer, _, err := r.ResolveWithReason(peers)
+	return peer, err
+}
+
+// ResolveWithReason uses the MinBlockHeight resolver to choose peers but will prefer peers in the given org.
+// The reason explains why the peer was chosen.
+func (r *PeerResolver) ResolveWithReason(peers []fab.Peer) (fab.Peer, string, error) {
+	filteredPeers, filterReason := r.blockHeightResolver.FilterWithReason(peers)
 
 	var orgPeers []fab.Peer
 	for _, p := range filteredPeers {
@@ -64,16 +73,31 @@ func (r *PeerResolver) Resolve(peers []fab.Peer) (fab.Peer, error) {
 	if len(orgPeers) > 0 {
 		// Our org is in the list. Use the default balancer to balance between them.
 		logger.Debugf("Choosing a peer from [%s]", r.mspID)
-		return r.loadBalancePolicy.Choose(orgPeers)
+		peer, err := r.loadBalancePolicy.Choose(orgPeers)
+		if err != nil {
+			return nil, "", err
+		}
+		return peer, fmt.Sprintf("%s was chosen from %d suitable peers in the preferred org [%s] since %s", peerresolver.Describe(peer), len(orgPeers), r.mspID, filterReason), nil
 	}
 
 	logger.Debugf("Choosing a peer from another org since there are no peers from [%s] in the list of peers", r.mspID)
-	return r.loadBalancePolicy.Choose(filteredPeers)
+	peer, err := r.loadBalancePolicy.Choose(filteredPeers)
+	if err != nil {
+		return nil, "", err
+	}
+	return peer, fmt.Sprintf("%s in another org was chosen since there are no suitable peers in the preferred org [%s] and %s", peerresolver.Describe(peer), r.mspID, filterReason), nil
 }
 
 // ShouldDisconnect determines whether connected peer not in our org should be disconnected
 // and reconnected to a peer in our org.
 func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer) bool {
+	disconnect, _ := r.ShouldDisconnectWithReason(peers, connectedPeer)
+	return disconnect
+}
+
+// ShouldDisconnectWithReason determines whether connected peer not in our org should be disconnected
+// and reconnected to a peer in our org and explains why.
+func (r *PeerResolver) ShouldDisconnectWithReason(peers []fab.Peer, connectedPeer fab.Peer) (bool, string) {
 	if connectedPeer.MSPID() != r.mspID {
 		// We're connected to a peer not in our org. Check if we can connect back to one of our peers.
 		logger.Debugf("Currently connected to [%s]. Checking if there are any peers from [%s] that are suitable to connect to", connectedPeer.URL(), r.mspID)
@@ -81,11 +105,11 @@ func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer
 		for _, p := range r.blockHeightResolver.Filter(peers) {
 			if p.MSPID() == r.mspID {
 				logger.Debugf("Peer [%s] in our preferred org [%s] suitable to connect to so the event client will be disconnected from the peer in the other org [%s]", p.URL(), r.mspID, connectedPeer.URL())
-				return true
+				return true, fmt.Sprintf("peer [%s] in the preferred org [%s] is suitable to connect to", p.URL(), r.mspID)
 			}
 		}
 	}
 
 	logger.Debugf("Using the min block height resolver to determine whether peer [%s] should be disconnected", connectedPeer.URL())
-	return r.blockHeightResolver.ShouldDisconnect(peers, connectedPeer)
+	return r.blockHeightResolver.ShouldDisconnectWithReason(peers, connectedPeer)
 }
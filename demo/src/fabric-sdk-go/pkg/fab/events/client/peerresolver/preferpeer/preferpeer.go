@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package preferpeer
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -57,33 +58,56 @@ func New(dispatcher service.Dispatcher, context context.Client, channelID string
 
 // Resolve uses the MinBlockHeight resolver to choose peers but will prefer the ones in the list of preferred peers.
 func (r *PeerResolver) Resolve(peers []fab.Peer) (fab.Peer, error) {
-	preferredPeers := r.getPreferredPeers(r.minBlockHeightResolver.Filter(peers))
+	peer, _, err := r.ResolveWithReason(peers)
+	return peer, err
+}
+
+// ResolveWithReason uses the MinBlockHeight resolver to choose peers but will prefer the ones in the list of
+// preferred peers. The reason explains why the peer was chosen.
+func (r *PeerResolver) ResolveWithReason(peers []fab.Peer) (fab.Peer, string, error) {
+	filteredPeers, filterReason := r.minBlockHeightResolver.FilterWithReason(peers)
+	preferredPeers := r.getPreferredPeers(filteredPeers)
 	if len(preferredPeers) > 0 {
 		// At least one of our preferred peers is suitable. Use the default balancer to balance between them.
 		logger.Debugf("Choosing a peer from the list of preferred peers")
-		return r.loadBalancePolicy.Choose(preferredPeers)
+		peer, err := r.loadBalancePolicy.Choose(preferredPeers)
+		if err != nil {
+			return nil, "", err
+		}
+		return peer, fmt.Sprintf("%s was chosen from %d suitable preferred peers since %s", peerresolver.Describe(peer), len(preferredPeers), filterReason), nil
 	}
 
 	logger.Debugf("There are no suitable peers from the list of preferred peers [%s] so choosing another peer using the 'prefer org' resolver", r.preferredPeers)
-	return r.preferOrgResolver.Resolve(peers)
+	peer, reason, err := r.preferOrgResolver.ResolveWithReason(peers)
+	if err != nil {
+		return nil, "", err
+	}
+	return peer, fmt.Sprintf("none of the preferred peers %s are suitable: %s", r.preferredPeers, reason), nil
 }
 
 // ShouldDisconnect determines whether the connected peer should be disconnected and reconnected to the preferred peer.
 func (r *PeerResolver) ShouldDisconnect(peers []fab.Peer, connectedPeer fab.Peer) bool {
+	disconnect, _ := r.ShouldDisconnectWithReason(peers, connectedPeer)
+	return disconnect
+}
+
+// ShouldDisconnectWithReason determines whether the connected peer should be disconnected and reconnected
+// to the preferred peer and explains why.
+func (r *PeerResolver) ShouldDisconnectWithReason(peers []fab.Peer, connectedPeer fab.Peer) (bool, string) {
 	if !r.isPreferred(connectedPeer) {
 		// We're not connected to a preferred peer. Check if we can connect back to one.
 		logger.Debugf("Currently connected to [%s]. Checking if any of the preferred peers [%s] is suitable to connect back to", connectedPeer.URL(), r.preferredPeers)
 
 		if len(r.getPreferredPeers(r.minBlockHeightResolver.Filter(peers))) > 0 {
 			logger.Debugf("At least one of our preferred peers is suitable to connect back to so the event client will be disconnected from peer [%s]", connectedPeer.URL())
-			return true
+			return true, fmt.Sprintf("peer [%s] is not a preferred peer and at least one of the preferred peers %s is suitable to connect back to", connectedPeer.URL(), r.preferredPeers)
 		}
 
 		logger.Debugf("None of our preferred peers is suitable to connect back to so the event client will NOT be disconnected from peer [%s]", connectedPeer.URL())
 	}
 
 	logger.Debugf("Using the 'prefer org' resolver to determine whether peer [%s] should be disconnected", connectedPeer.URL())
-	return r.preferOrgResolver.ShouldDisconnect(peers, connectedPeer)
+	return r.preferOrgResolver.ShouldDisconnectWithReason(peers, connectedPeer)
 }
 
 func (r *PeerResolver) getPreferredPeers(peers []fab.Peer) []fab.Peer {
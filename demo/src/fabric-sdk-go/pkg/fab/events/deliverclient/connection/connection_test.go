@@ -35,6 +35,7 @@ const (
 
 	streamTypeDeliver         streamType = "DELIVER"
 	streamTypeDeliverFiltered streamType = "DELIVER_FILTERED"
+	streamTypeDeliverPvtData  streamType = "DELIVER_PVT_DATA"
 )
 
 func TestInvalidConnectionOpts(t *testing.T) {
@@ -114,6 +115,9 @@ func TestSend(t *testing.T) {
 	t.Run("SendFilteredBlockEvent", func(t *testing.T) {
 		testSend(t, streamTypeDeliverFiltered)
 	})
+	t.Run("SendBlockAndPrivateDataEvent", func(t *testing.T) {
+		testSend(t, streamTypeDeliverPvtData)
+	})
 }
 
 func TestDisconnected(t *testing.T) {
@@ -153,6 +157,9 @@ func getStreamProvider(streamType streamType) StreamProvider {
 	if streamType == streamTypeDeliverFiltered {
 		return DeliverFiltered
 	}
+	if streamType == streamTypeDeliverPvtData {
+		return DeliverWithPrivateData
+	}
 	return Deliver
 }
 
@@ -194,6 +201,9 @@ func checkEvents(eventch chan interface{}, t *testing.T, streamType streamType)
 		if streamType == streamTypeDeliverFiltered && deliverResponse.GetFilteredBlock() == nil {
 			t.Fatal("expected deliver response filtered block but got none")
 		}
+		if streamType == streamTypeDeliverPvtData && deliverResponse.GetBlockAndPrivateData() == nil {
+			t.Fatal("expected deliver response block and private data but got none")
+		}
 	case <-time.After(5 * time.Second):
 		t.Fatal("timed out waiting for event")
 	}
@@ -52,6 +52,19 @@ var deliverFilteredProvider = func(context fabcontext.Client, chConfig fab.Chann
 	return deliverconn.New(context, chConfig, deliverconn.DeliverFiltered, peer.URL(), eventEndpoint.Opts()...)
 }
 
+// deliverWithPrivateDataProvider is the connection provider used for connecting to the DeliverWithPrivateData service
+var deliverWithPrivateDataProvider = func(context fabcontext.Client, chConfig fab.ChannelCfg, peer fab.Peer) (api.Connection, error) {
+	if peer == nil {
+		return nil, errors.New("Peer is nil")
+	}
+
+	eventEndpoint, ok := peer.(api.EventEndpoint)
+	if !ok {
+		panic("peer is not an EventEndpoint")
+	}
+	return deliverconn.New(context, chConfig, deliverconn.DeliverWithPrivateData, peer.URL(), eventEndpoint.Opts()...)
+}
+
 // Client connects to a peer and receives channel events, such as bock, filtered block, chaincode, and transaction status events.
 type Client struct {
 	*client.Client
@@ -63,6 +76,10 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fa
 	params := defaultParams()
 	options.Apply(params, opts)
 
+	if err := params.validateStopBlock(); err != nil {
+		return nil, err
+	}
+
 	// Use a custom Discovery Service which wraps the given discovery service
 	// and produces event endpoints containing additional GRPC options.
 	discoveryWrapper, err := endpoint.NewEndpointDiscoveryWrapper(context, chConfig.ID(), discoveryService)
@@ -129,6 +146,9 @@ func (c *Client) setSeekFromLastBlockReceived() error {
 
 	// Make sure that, when we reconnect, we receive all of the events that we've missed
 	lastBlockNum := c.Dispatcher().LastBlockNum()
+	if c.hasStopBlock && lastBlockNum < math.MaxUint64 && lastBlockNum >= c.stopBlock {
+		return errors.Errorf("all blocks up to stop block %d have been received", c.stopBlock)
+	}
 	if lastBlockNum < math.MaxUint64 {
 		c.seekType = seek.FromBlock
 		c.fromBlock = c.Dispatcher().LastBlockNum() + 1
@@ -150,9 +170,17 @@ func (c *Client) seekInfo() (*ab.SeekInfo, error) {
 		logger.Debugf("Returning seek info: Newest")
 		return seek.InfoNewest(), nil
 	case seek.Oldest:
+		if c.hasStopBlock {
+			logger.Debugf("Returning seek info: Oldest to StopBlock(%d)", c.stopBlock)
+			return seek.InfoFrom(0, c.stopBlock), nil
+		}
 		logger.Debugf("Returning seek info: Oldest")
 		return seek.InfoOldest(), nil
 	case seek.FromBlock:
+		if c.hasStopBlock {
+			logger.Debugf("Returning seek info: FromBlock(%d) to StopBlock(%d)", c.fromBlock, c.stopBlock)
+			return seek.InfoFrom(c.fromBlock, c.stopBlock), nil
+		}
 		logger.Debugf("Returning seek info: FromBlock(%d)", c.fromBlock)
 		return seek.InfoFrom(c.fromBlock), nil
 	default:
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package deliverclient
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -56,6 +57,52 @@ func TestOptionsInNewClient(t *testing.T) {
 	client.Close()
 }
 
+func TestBlockAndPrivateDataOpts(t *testing.T) {
+	expected := reflect.ValueOf(deliverWithPrivateDataProvider).Pointer()
+
+	params := defaultParams()
+	options.Apply(params, []options.Opt{client.WithBlockAndPrivateData(), client.WithBlockEvents()})
+	require.Equal(t, expected, reflect.ValueOf(params.connProvider).Pointer())
+
+	params = defaultParams()
+	options.Apply(params, []options.Opt{client.WithBlockEvents(), client.WithBlockAndPrivateData()})
+	require.Equal(t, expected, reflect.ValueOf(params.connProvider).Pointer())
+}
+
+func TestStopBlockOpts(t *testing.T) {
+	channelID := "mychannel"
+
+	_, err := New(
+		newMockContext(),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		WithStopBlockNum(10),
+	)
+	require.Error(t, err, "expecting error with stop block and seek type newest")
+
+	_, err = New(
+		newMockContext(),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		WithSeekType(seek.FromBlock), WithBlockNum(10), WithStopBlockNum(9),
+	)
+	require.Error(t, err, "expecting error with stop block less than from block")
+
+	eventClient, err := New(
+		newMockContext(),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		WithSeekType(seek.FromBlock), WithBlockNum(10), WithStopBlockNum(20),
+	)
+	require.NoError(t, err)
+	defer eventClient.Close()
+
+	seekInfo, err := eventClient.seekInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), seekInfo.Start.GetSpecified().Number)
+	require.Equal(t, uint64(20), seekInfo.Stop.GetSpecified().Number)
+}
+
 func TestClientConnect(t *testing.T) {
 	channelID := "mychannel"
 	eventClient, err := New(
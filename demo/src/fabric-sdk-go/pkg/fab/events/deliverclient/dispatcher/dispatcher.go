@@ -79,6 +79,8 @@ func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 		ed.handleDeliverResponseStatus(response)
 	case *pb.DeliverResponse_Block:
 		ed.HandleBlock(response.Block, delevent.SourceURL)
+	case *pb.DeliverResponse_BlockAndPrivateData:
+		ed.HandleBlockAndPrivateData(response.BlockAndPrivateData.Block, response.BlockAndPrivateData.PrivateDataMap, delevent.SourceURL)
 	case *pb.DeliverResponse_FilteredBlock:
 		ed.HandleFilteredBlock(response.FilteredBlock, delevent.SourceURL)
 	default:
@@ -242,6 +242,102 @@ func checkBlockEvents(eventch chan *fab.BlockEvent, t *testing.T) {
 	}
 }
 
+func TestBlockAndPrivateDataEvents(t *testing.T) {
+	channelID := "testchannel"
+	ledger := servicemocks.NewMockLedger(delivermocks.BlockAndPrivateDataEventFactory, sourceURL)
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		clientmocks.NewProviderFactory().Provider(
+			delivermocks.NewConnection(
+				clientmocks.WithLedger(ledger),
+			),
+		),
+	)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	// Connect
+	errch := make(chan error)
+	dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+	if err := <-errch; err != nil {
+		t.Fatalf("Error connecting: %s", err)
+	}
+
+	// Register for block and private data events
+	pvtEventch := make(chan *fab.BlockAndPrivateDataEvent, 10)
+	regch := make(chan fab.Registration)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockAndPrivateDataEvent(blockfilter.AcceptAny, pvtEventch, regch, errch)
+
+	var pvtReg fab.Registration
+	select {
+	case pvtReg = <-regch:
+	case err := <-errch:
+		t.Fatalf("Error registering for block and private data events: %s", err)
+	}
+
+	// Block registrations also receive the block
+	eventch := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+
+	var reg fab.Registration
+	select {
+	case reg = <-regch:
+	case err := <-errch:
+		t.Fatalf("Error registering for block events: %s", err)
+	}
+
+	ledger.NewBlock(channelID)
+
+	select {
+	case event, ok := <-pvtEventch:
+		if !ok {
+			t.Fatal("unexpected closed channel")
+		}
+		assert.Equal(t, sourceURL, event.SourceURL)
+		assert.NotNil(t, event.Block)
+		pvtData, ok := event.PrivateDataMap[0]
+		if !ok {
+			t.Fatal("expecting private data for transaction 0")
+		}
+		assert.Equal(t, "collection1", pvtData.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for block and private data event")
+	}
+
+	checkBlockEvents(eventch, t)
+
+	assert.Equal(t, uint64(0), dispatcher.LastBlockNum())
+
+	// Unregister
+	dispatcherEventch <- esdispatcher.NewUnregisterEvent(pvtReg)
+	dispatcherEventch <- esdispatcher.NewUnregisterEvent(reg)
+
+	select {
+	case _, ok := <-pvtEventch:
+		assert.False(t, ok, "expecting event channel to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event channel to be closed")
+	}
+
+	// Stop
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
 func TestFilteredBlockEvents(t *testing.T) {
 	channelID := "testchannel"
 	ledger := servicemocks.NewMockLedger(delivermocks.FilteredBlockEventFactory, sourceURL)
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
@@ -26,6 +27,20 @@ func NewBlockEvent(block *cb.Block, sourceURL string) *connection.Event {
 	)
 }
 
+// NewBlockAndPrivateDataEvent returns a new mock block and private data event initialized with the given block and private data
+func NewBlockAndPrivateDataEvent(block *cb.Block, pvtData map[uint64]*rwset.TxPvtReadWriteSet, sourceURL string) *connection.Event {
+	return connection.NewEvent(
+		&pb.DeliverResponse{
+			Type: &pb.DeliverResponse_BlockAndPrivateData{
+				BlockAndPrivateData: &pb.BlockAndPrivateData{
+					Block:          block,
+					PrivateDataMap: pvtData,
+				},
+			},
+		}, sourceURL,
+	)
+}
+
 // NewFilteredBlockEvent returns a new mock filtered block event initialized with the given filtered block
 func NewFilteredBlockEvent(fblock *pb.FilteredBlock, sourceURL string) *connection.Event {
 	return connection.NewEvent(
@@ -46,6 +61,29 @@ var BlockEventFactory = func(block servicemocks.Block, sourceURL string) service
 	return NewBlockEvent(b.Block(), sourceURL)
 }
 
+// BlockAndPrivateDataEventFactory creates block and private data events. Each block is
+// delivered with private data for the transaction at index 0.
+var BlockAndPrivateDataEventFactory = func(block servicemocks.Block, sourceURL string) servicemocks.BlockEvent {
+	b, ok := block.(*servicemocks.BlockWrapper)
+	if !ok {
+		panic(fmt.Sprintf("Invalid block type: %T", block))
+	}
+	pvtData := map[uint64]*rwset.TxPvtReadWriteSet{
+		0: {
+			DataModel: rwset.TxReadWriteSet_KV,
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+				{
+					Namespace: "mycc",
+					CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+						{CollectionName: "collection1"},
+					},
+				},
+			},
+		},
+	}
+	return NewBlockAndPrivateDataEvent(b.Block(), pvtData, sourceURL)
+}
+
 // FilteredBlockEventFactory creates filtered block events
 var FilteredBlockEventFactory = func(block servicemocks.Block, sourceURL string) servicemocks.BlockEvent {
 	b, ok := block.(*servicemocks.FilteredBlockWrapper)
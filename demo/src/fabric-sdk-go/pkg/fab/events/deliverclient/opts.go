@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
 )
 
 type params struct {
@@ -20,6 +21,9 @@ type params struct {
 	seekType     seek.Type
 	fromBlock    uint64
 	respTimeout  time.Duration
+	pvtData      bool
+	stopBlock    uint64
+	hasStopBlock bool
 }
 
 func defaultParams() *params {
@@ -48,6 +52,18 @@ func WithBlockNum(value uint64) options.Opt {
 	}
 }
 
+// WithStopBlockNum specifies the last block for which events are to be received. After the
+// events for the stop block have been delivered, all registrations are closed and the
+// event channels are closed so that registrants know that the range has been delivered.
+// Note that this option is only valid if SeekType is set to SeekFrom or SeekOldest.
+func WithStopBlockNum(value uint64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(stopBlockSetter); ok {
+			setter.SetStopBlockNum(value)
+		}
+	}
+}
+
 type seekTypeSetter interface {
 	SetSeekType(value seek.Type)
 }
@@ -56,11 +72,25 @@ type fromBlockSetter interface {
 	SetFromBlock(value uint64)
 }
 
+type stopBlockSetter interface {
+	SetStopBlockNum(value uint64)
+}
+
 func (p *params) PermitBlockEvents() {
 	logger.Debug("PermitBlockEvents")
+	if p.pvtData {
+		// The DeliverWithPrivateData service also delivers full blocks
+		return
+	}
 	p.connProvider = deliverProvider
 }
 
+func (p *params) PermitBlockAndPrivateDataEvents() {
+	logger.Debug("PermitBlockAndPrivateDataEvents")
+	p.pvtData = true
+	p.connProvider = deliverWithPrivateDataProvider
+}
+
 // SetConnectionProvider is only used in unit tests
 func (p *params) SetConnectionProvider(connProvider api.ConnectionProvider) {
 	logger.Debugf("ConnectionProvider: %#v", connProvider)
@@ -72,6 +102,12 @@ func (p *params) SetFromBlock(value uint64) {
 	p.fromBlock = value
 }
 
+func (p *params) SetStopBlockNum(value uint64) {
+	logger.Debugf("StopBlock: %d", value)
+	p.stopBlock = value
+	p.hasStopBlock = true
+}
+
 func (p *params) SetSeekType(value seek.Type) {
 	logger.Debugf("SeekType: %s", value)
 	if value != "" {
@@ -95,3 +131,20 @@ func (p *params) SetSnapshot(value fab.EventSnapshot) error {
 	p.SetFromBlock(value.LastBlockReceived())
 	return nil
 }
+
+func (p *params) validateStopBlock() error {
+	if !p.hasStopBlock {
+		return nil
+	}
+	switch p.seekType {
+	case seek.Oldest:
+		return nil
+	case seek.FromBlock:
+		if p.stopBlock < p.fromBlock {
+			return errors.Errorf("stop block %d must not be less than from block %d", p.stopBlock, p.fromBlock)
+		}
+		return nil
+	default:
+		return errors.New("a stop block may only be specified with seek type oldest or from")
+	}
+}
@@ -43,16 +43,23 @@ func InfoNewest() *ab.SeekInfo {
 }
 
 // InfoFrom returns a SeekInfo struct that indicates to the deliver server
-// that we want all blocks starting from the given block number
-func InfoFrom(fromBlock uint64) *ab.SeekInfo {
-	return newSeekInfo(seekFromPos(fromBlock), maxPos)
+// that we want all blocks starting from the given block number. If a stop block
+// is specified then the deliver server stops delivering blocks after the stop block
+// (inclusive), otherwise blocks are delivered indefinitely.
+// (Note: Only one stop block may be specified.)
+func InfoFrom(fromBlock uint64, stopBlock ...uint64) *ab.SeekInfo {
+	stop := maxPos
+	if len(stopBlock) > 0 {
+		stop = seekFromPos(stopBlock[0])
+	}
+	return newSeekInfo(seekFromPos(fromBlock), stop)
 }
 
-func seekFromPos(fromBlock uint64) *ab.SeekPosition {
+func seekFromPos(blockNum uint64) *ab.SeekPosition {
 	return &ab.SeekPosition{
 		Type: &ab.SeekPosition_Specified{
 			Specified: &ab.SeekSpecified{
-				Number: fromBlock,
+				Number: blockNum,
 			},
 		},
 	}
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/test"
@@ -32,6 +33,9 @@ type MockDeliverServer struct {
 
 	// for mocking communcation with mockBroadCastServer, this channel will received filtered blocks sent by that mockBradcastServer
 	filteredDeliveries <-chan *pb.FilteredBlock
+
+	// for mocking communication with mockBroadcastServer, this channel will receive blocks with private data sent by that mockBroadcastServer
+	pvtDataDeliveries <-chan *pb.BlockAndPrivateData
 }
 
 // NewMockDeliverServer returns a new MockDeliverServer
@@ -57,6 +61,14 @@ func NewMockDeliverServerWithFilteredDeliveries(d <-chan *pb.FilteredBlock) *Moc
 	}
 }
 
+// NewMockDeliverServerWithPrivateDataDeliveries returns a new MockDeliverServer using pvtDataDeliveries channel with BlockAndPrivateData
+func NewMockDeliverServerWithPrivateDataDeliveries(d <-chan *pb.BlockAndPrivateData) *MockDeliverServer {
+	return &MockDeliverServer{
+		status:            cb.Status_UNKNOWN,
+		pvtDataDeliveries: d,
+	}
+}
+
 // SetStatus sets the status to return when calling Deliver or DeliverFiltered
 func (s *MockDeliverServer) SetStatus(status cb.Status) {
 	s.Lock()
@@ -128,9 +140,47 @@ func (s *MockDeliverServer) Deliver(srv pb.Deliver_DeliverServer) error {
 	return nil
 }
 
-// DeliverWithPrivateData is not implemented
-func (s *MockDeliverServer) DeliverWithPrivateData(pb.Deliver_DeliverWithPrivateDataServer) error {
-	return errors.New("not implemented")
+// DeliverWithPrivateData delivers a stream of blocks together with their private data
+func (s *MockDeliverServer) DeliverWithPrivateData(srv pb.Deliver_DeliverWithPrivateDataServer) error {
+	status := s.Status()
+	if status != cb.Status_UNKNOWN {
+		err := srv.Send(&pb.DeliverResponse{
+			Type: &pb.DeliverResponse_Status{
+				Status: status,
+			},
+		})
+		return errors.Errorf("returning error status: %s %s", status, err)
+	}
+	disconnect := make(chan bool)
+
+	go s.handlePvtDataEvents(srv, disconnect)
+
+	for {
+		envelope, err := srv.Recv()
+		if err == io.EOF || envelope == nil {
+			disconnect <- true
+			break
+		}
+
+		err = s.disconnectErr()
+		if err != nil {
+			disconnect <- true
+			return err
+		}
+
+		err1 := srv.Send(&pb.DeliverResponse{
+			Type: &pb.DeliverResponse_BlockAndPrivateData{
+				BlockAndPrivateData: &pb.BlockAndPrivateData{
+					Block:          mocks.NewSimpleMockBlock(),
+					PrivateDataMap: make(map[uint64]*rwset.TxPvtReadWriteSet),
+				},
+			},
+		})
+		if err1 != nil {
+			return err1
+		}
+	}
+	return nil
 }
 
 // DeliverFiltered delivers a stream of filtered blocks
@@ -200,6 +250,29 @@ func (s *MockDeliverServer) handleEvents(srv pb.Deliver_DeliverServer, disconnec
 	}
 }
 
+func (s *MockDeliverServer) handlePvtDataEvents(srv pb.Deliver_DeliverWithPrivateDataServer, disconnect chan bool) {
+	for {
+		select {
+		case blockAndPvtData, ok := <-s.pvtDataDeliveries:
+			if ok {
+				err1 := srv.Send(&pb.DeliverResponse{
+					Type: &pb.DeliverResponse_BlockAndPrivateData{
+						BlockAndPrivateData: blockAndPvtData,
+					},
+				})
+				if err1 != nil {
+					test.Logf("got error during handle block and private data event: %s", err1)
+				}
+			} else {
+				test.Logf("channel is closed")
+				return
+			}
+		case <-disconnect:
+			return
+		}
+	}
+}
+
 func (s *MockDeliverServer) handleFilteredEvents(srv pb.Deliver_DeliverServer, disconnect chan bool) {
 	for {
 		select {
@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package overflow
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// codec serializes events to and from the spill file
+type codec interface {
+	encode(event interface{}) ([]byte, error)
+	decode(data []byte) (interface{}, error)
+}
+
+// codecFor returns the codec for the given event type
+func codecFor(eventType reflect.Type) (codec, error) {
+	switch eventType {
+	case reflect.TypeOf(&fab.BlockEvent{}):
+		return &blockCodec{}, nil
+	case reflect.TypeOf(&fab.BlockAndPrivateDataEvent{}):
+		return &blockAndPvtDataCodec{}, nil
+	case reflect.TypeOf(&fab.FilteredBlockEvent{}):
+		return &filteredBlockCodec{}, nil
+	case reflect.TypeOf(&fab.CCEvent{}):
+		return &jsonCodec{newEvent: func() interface{} { return &fab.CCEvent{} }}, nil
+	case reflect.TypeOf(&fab.TxStatusEvent{}):
+		return &jsonCodec{newEvent: func() interface{} { return &fab.TxStatusEvent{} }}, nil
+	default:
+		return nil, errors.Errorf("events of type %s cannot be spilled to disk", eventType)
+	}
+}
+
+// protoEvent is the serialized form of events which contain a protobuf message
+type protoEvent struct {
+	Message     []byte            `json:"message"`
+	PrivateData map[uint64][]byte `json:"privateData,omitempty"`
+	SourceURL   string            `json:"sourceURL"`
+}
+
+type blockCodec struct{}
+
+func (c *blockCodec) encode(event interface{}) ([]byte, error) {
+	e := event.(*fab.BlockEvent)
+	return encodeProtoEvent(e.Block, nil, e.SourceURL)
+}
+
+func (c *blockCodec) decode(data []byte) (interface{}, error) {
+	block := &cb.Block{}
+	pe, err := decodeProtoEvent(data, block)
+	if err != nil {
+		return nil, err
+	}
+	return &fab.BlockEvent{Block: block, SourceURL: pe.SourceURL}, nil
+}
+
+type blockAndPvtDataCodec struct{}
+
+func (c *blockAndPvtDataCodec) encode(event interface{}) ([]byte, error) {
+	e := event.(*fab.BlockAndPrivateDataEvent)
+
+	pvtData := make(map[uint64][]byte, len(e.PrivateDataMap))
+	for txIndex, rws := range e.PrivateDataMap {
+		data, err := proto.Marshal(rws)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal private data")
+		}
+		pvtData[txIndex] = data
+	}
+
+	return encodeProtoEvent(e.Block, pvtData, e.SourceURL)
+}
+
+func (c *blockAndPvtDataCodec) decode(data []byte) (interface{}, error) {
+	block := &cb.Block{}
+	pe, err := decodeProtoEvent(data, block)
+	if err != nil {
+		return nil, err
+	}
+
+	pvtDataMap := make(map[uint64]*rwset.TxPvtReadWriteSet, len(pe.PrivateData))
+	for txIndex, data := range pe.PrivateData {
+		rws := &rwset.TxPvtReadWriteSet{}
+		if err := proto.Unmarshal(data, rws); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal private data")
+		}
+		pvtDataMap[txIndex] = rws
+	}
+
+	return &fab.BlockAndPrivateDataEvent{Block: block, PrivateDataMap: pvtDataMap, SourceURL: pe.SourceURL}, nil
+}
+
+type filteredBlockCodec struct{}
+
+func (c *filteredBlockCodec) encode(event interface{}) ([]byte, error) {
+	e := event.(*fab.FilteredBlockEvent)
+	return encodeProtoEvent(e.FilteredBlock, nil, e.SourceURL)
+}
+
+func (c *filteredBlockCodec) decode(data []byte) (interface{}, error) {
+	fblock := &pb.FilteredBlock{}
+	pe, err := decodeProtoEvent(data, fblock)
+	if err != nil {
+		return nil, err
+	}
+	return &fab.FilteredBlockEvent{FilteredBlock: fblock, SourceURL: pe.SourceURL}, nil
+}
+
+// jsonCodec serializes events which contain only plain fields
+type jsonCodec struct {
+	newEvent func() interface{}
+}
+
+func (c *jsonCodec) encode(event interface{}) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+	return data, nil
+}
+
+func (c *jsonCodec) decode(data []byte) (interface{}, error) {
+	event := c.newEvent()
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+	return event, nil
+}
+
+func encodeProtoEvent(msg proto.Message, pvtData map[uint64][]byte, sourceURL string) ([]byte, error) {
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+
+	data, err := json.Marshal(&protoEvent{Message: msgBytes, PrivateData: pvtData, SourceURL: sourceURL})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+	return data, nil
+}
+
+func decodeProtoEvent(data []byte, msg proto.Message) (*protoEvent, error) {
+	pe := &protoEvent{}
+	if err := json.Unmarshal(data, pe); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+	if err := proto.Unmarshal(pe.Message, msg); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+	return pe, nil
+}
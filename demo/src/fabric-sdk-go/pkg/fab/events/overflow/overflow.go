@@ -0,0 +1,229 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package overflow decouples the delivery of events to a registration from the event dispatcher.
+// Events received from the registration's channel are queued by a Forwarder according to an overflow
+// policy and delivered to the consumer in order, so that a slow consumer never blocks the dispatcher.
+package overflow
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// Policy specifies what happens to an event when a registration's buffer is full
+type Policy int
+
+const (
+	// Block waits for the consumer to receive the event, up to the event service's
+	// consumer timeout. This is the behaviour of a registration without a Forwarder.
+	Block Policy = iota
+	// DropOldest discards the oldest queued event to make room for the new event
+	DropOldest
+	// DropNewest discards the new event
+	DropNewest
+	// SpillToDisk writes events to a file once the buffer is full and delivers them
+	// (in order) when the consumer catches up
+	SpillToDisk
+)
+
+// String returns the name of the policy
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case SpillToDisk:
+		return "spill-to-disk"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrBufferFull is passed to the DropHandler when an event is dropped because the buffer is full
+var ErrBufferFull = errors.New("event buffer is full")
+
+// DropHandler is invoked (from the Forwarder's goroutine) when an event is dropped
+type DropHandler func(event interface{}, reason error)
+
+// Options configures a Forwarder
+type Options struct {
+	// Policy is the overflow policy. Block is not valid for a Forwarder.
+	Policy Policy
+	// BufferSize is the number of events held in memory
+	BufferSize int
+	// SpillDir is the directory in which the spill file is created (SpillToDisk only).
+	// The system's temporary directory is used if empty.
+	SpillDir string
+	// OnDrop is optionally invoked when an event is dropped
+	OnDrop DropHandler
+	// Queued, Delivered and Dropped are optional counters (with their labels applied)
+	Queued    metrics.Counter
+	Delivered metrics.Counter
+	Dropped   metrics.Counter
+}
+
+// Forwarder receives events from a registration's channel, queues them according to
+// the overflow policy and delivers them to an unbuffered output channel.
+type Forwarder struct {
+	in        reflect.Value
+	out       reflect.Value
+	queue     queue
+	opts      Options
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New starts a Forwarder for the given event channel (e.g. <-chan *fab.BlockEvent). The returned output
+// channel has the same element type (e.g. chan *fab.BlockEvent) and is closed after the input channel
+// has been closed and all queued events have been delivered, or when the Forwarder is closed.
+func New(eventch interface{}, opts Options) (*Forwarder, interface{}, error) {
+	in := reflect.ValueOf(eventch)
+	if in.Kind() != reflect.Chan || in.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, nil, errors.Errorf("expecting a receive channel but got %T", eventch)
+	}
+	if opts.BufferSize <= 0 {
+		return nil, nil, errors.New("buffer size must be greater than zero")
+	}
+
+	elemType := in.Type().Elem()
+
+	var q queue
+	switch opts.Policy {
+	case DropOldest, DropNewest:
+		q = newMemQueue(opts.Policy, opts.BufferSize)
+	case SpillToDisk:
+		c, err := codecFor(elemType)
+		if err != nil {
+			return nil, nil, err
+		}
+		q, err = newSpillQueue(opts.BufferSize, opts.SpillDir, c)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, errors.Errorf("unsupported overflow policy: %s", opts.Policy)
+	}
+
+	f := &Forwarder{
+		in:    in,
+		out:   reflect.MakeChan(reflect.ChanOf(reflect.BothDir, elemType), 0),
+		queue: q,
+		opts:  opts,
+		done:  make(chan struct{}),
+	}
+
+	go f.run()
+
+	return f, f.out.Interface(), nil
+}
+
+// Close stops the Forwarder, discards all queued events and closes the output channel
+func (f *Forwarder) Close() {
+	f.closeOnce.Do(func() {
+		close(f.done)
+	})
+}
+
+func (f *Forwarder) run() {
+	defer f.out.Close()
+	defer f.queue.close()
+
+	done := reflect.ValueOf(f.done)
+	for {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: f.in},
+			{Dir: reflect.SelectRecv, Chan: done},
+		}
+		if next, ok := f.peek(); ok {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: f.out, Send: reflect.ValueOf(next)})
+		}
+
+		chosen, value, ok := reflect.Select(cases)
+		switch chosen {
+		case 0:
+			if !ok {
+				f.flush()
+				return
+			}
+			f.push(value.Interface())
+		case 1:
+			return
+		case 2:
+			f.queue.pop()
+			add(f.opts.Delivered)
+		}
+	}
+}
+
+// flush delivers the queued events after the input channel has been closed
+func (f *Forwarder) flush() {
+	for {
+		next, ok := f.peek()
+		if !ok {
+			return
+		}
+		select {
+		case <-f.done:
+			return
+		default:
+		}
+
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: f.out, Send: reflect.ValueOf(next)},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.done)},
+		})
+		if chosen == 1 {
+			return
+		}
+		f.queue.pop()
+		add(f.opts.Delivered)
+	}
+}
+
+func (f *Forwarder) push(event interface{}) {
+	add(f.opts.Queued)
+
+	dropped, err := f.queue.push(event)
+	if dropped != nil {
+		f.drop(dropped, err)
+	}
+}
+
+func (f *Forwarder) peek() (interface{}, bool) {
+	for {
+		event, ok, err := f.queue.peek()
+		if err == nil {
+			return event, ok
+		}
+		// An event couldn't be read back from the spill file
+		f.drop(nil, err)
+	}
+}
+
+func (f *Forwarder) drop(event interface{}, reason error) {
+	logger.Debugf("Dropping event: %s", reason)
+
+	add(f.opts.Dropped)
+	if f.opts.OnDrop != nil {
+		f.opts.OnDrop(event, reason)
+	}
+}
+
+func add(counter metrics.Counter) {
+	if counter != nil {
+		counter.Add(1)
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package overflow
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "mychannel"
+	sourceURL = "peer1.example.com"
+)
+
+func TestInvalidOptions(t *testing.T) {
+	_, _, err := New(make(chan *fab.CCEvent), Options{Policy: DropOldest})
+	require.Error(t, err, "expecting error for zero buffer size")
+
+	_, _, err = New(make(chan *fab.CCEvent), Options{Policy: Block, BufferSize: 1})
+	require.Error(t, err, "expecting error for block policy")
+
+	_, _, err = New("not a channel", Options{Policy: DropOldest, BufferSize: 1})
+	require.Error(t, err, "expecting error for invalid channel")
+
+	_, _, err = New(make(chan string), Options{Policy: SpillToDisk, BufferSize: 1})
+	require.Error(t, err, "expecting error for unsupported spill type")
+}
+
+func TestDropOldest(t *testing.T) {
+	in := make(chan *fab.CCEvent)
+	drops := &dropRecorder{}
+	f, out, err := New((<-chan *fab.CCEvent)(in), Options{Policy: DropOldest, BufferSize: 2, OnDrop: drops.onDrop})
+	require.NoError(t, err)
+	defer f.Close()
+
+	for i := 1; i <= 4; i++ {
+		in <- &fab.CCEvent{BlockNumber: uint64(i)}
+	}
+	close(in)
+
+	require.Equal(t, []uint64{3, 4}, receiveCCEvents(t, out.(chan *fab.CCEvent)))
+	require.Equal(t, []uint64{1, 2}, drops.blockNumbers())
+	require.Equal(t, ErrBufferFull, drops.reasons[0])
+}
+
+func TestDropNewest(t *testing.T) {
+	in := make(chan *fab.CCEvent)
+	drops := &dropRecorder{}
+	f, out, err := New(in, Options{Policy: DropNewest, BufferSize: 2, OnDrop: drops.onDrop})
+	require.NoError(t, err)
+	defer f.Close()
+
+	for i := 1; i <= 4; i++ {
+		in <- &fab.CCEvent{BlockNumber: uint64(i)}
+	}
+	close(in)
+
+	require.Equal(t, []uint64{1, 2}, receiveCCEvents(t, out.(chan *fab.CCEvent)))
+	require.Equal(t, []uint64{3, 4}, drops.blockNumbers())
+}
+
+func TestSpillToDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overflow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	in := make(chan *fab.BlockEvent)
+	drops := &dropRecorder{}
+	f, out, err := New(in, Options{Policy: SpillToDisk, BufferSize: 2, SpillDir: dir, OnDrop: drops.onDrop})
+	require.NoError(t, err)
+	defer f.Close()
+
+	const numEvents = 10
+	for i := 0; i < numEvents; i++ {
+		block := servicemocks.NewBlock(channelID, servicemocks.NewTransaction("txid", pb.TxValidationCode_VALID, 0))
+		block.Header.Number = uint64(i)
+		in <- &fab.BlockEvent{Block: block, SourceURL: sourceURL}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "expecting a spill file")
+
+	close(in)
+
+	outch := out.(chan *fab.BlockEvent)
+	var received []uint64
+	for event := range outch {
+		require.Equal(t, sourceURL, event.SourceURL)
+		received = append(received, event.Block.Header.Number)
+	}
+	require.Equal(t, []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, received)
+	require.Empty(t, drops.events)
+
+	files, err = ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files, "spill file should have been removed")
+}
+
+func TestSpillCorruptRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overflow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := codecFor(reflect.TypeOf(&fab.CCEvent{}))
+	require.NoError(t, err)
+	q, err := newSpillQueue(2, dir, c)
+	require.NoError(t, err)
+	defer q.close()
+
+	// Events 1 and 2 are held in memory and events 3 to 6 are spilled
+	var offset int64
+	var corruptOffset int64
+	var corruptLen int
+	for i := 1; i <= 6; i++ {
+		event := &fab.CCEvent{BlockNumber: uint64(i)}
+		dropped, err := q.push(event)
+		require.NoError(t, err)
+		require.Nil(t, dropped)
+		if i <= 2 {
+			continue
+		}
+		data, err := c.encode(event)
+		require.NoError(t, err)
+		if i == 4 {
+			corruptOffset, corruptLen = offset+4, len(data)
+		}
+		offset += int64(4 + len(data))
+	}
+
+	garbage := make([]byte, corruptLen)
+	for i := range garbage {
+		garbage[i] = '{'
+	}
+	_, err = q.file.WriteAt(garbage, corruptOffset)
+	require.NoError(t, err)
+
+	drops := &dropRecorder{}
+	f := &Forwarder{queue: q, opts: Options{OnDrop: drops.onDrop}}
+
+	var received []uint64
+	for {
+		event, ok := f.peek()
+		if !ok {
+			break
+		}
+		received = append(received, event.(*fab.CCEvent).BlockNumber)
+		q.pop()
+	}
+
+	require.Equal(t, []uint64{1, 2, 3, 5, 6}, received, "only the corrupt event should have been dropped")
+	require.Len(t, drops.reasons, 1)
+	require.Nil(t, drops.events[0])
+	require.Nil(t, q.file, "spill file should have been removed")
+}
+
+func TestCodecs(t *testing.T) {
+	block := servicemocks.NewBlock(channelID, servicemocks.NewTransaction("txid", pb.TxValidationCode_VALID, 0))
+	fblock := servicemocks.NewFilteredBlock(channelID, servicemocks.NewFilteredTxWithCCEvent("txid", "mycc", "event1"))
+
+	events := []interface{}{
+		&fab.BlockEvent{Block: block, SourceURL: sourceURL},
+		&fab.BlockAndPrivateDataEvent{
+			Block:          block,
+			PrivateDataMap: map[uint64]*rwset.TxPvtReadWriteSet{0: {DataModel: rwset.TxReadWriteSet_KV}},
+			SourceURL:      sourceURL,
+		},
+		&fab.FilteredBlockEvent{FilteredBlock: fblock, SourceURL: sourceURL},
+		&fab.CCEvent{TxID: "txid", ChaincodeID: "mycc", EventName: "event1", Payload: []byte("payload"), BlockNumber: 1, SourceURL: sourceURL},
+		&fab.TxStatusEvent{TxID: "txid", TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 1, SourceURL: sourceURL},
+	}
+
+	for _, event := range events {
+		c, err := codecFor(reflect.TypeOf(event))
+		require.NoError(t, err)
+
+		data, err := c.encode(event)
+		require.NoError(t, err)
+
+		decoded, err := c.decode(data)
+		require.NoError(t, err)
+		require.IsType(t, event, decoded)
+
+		// Compare the encoded forms since decoded protobuf messages have different internal state
+		redata, err := c.encode(decoded)
+		require.NoError(t, err)
+		require.Equal(t, data, redata)
+	}
+}
+
+func TestClose(t *testing.T) {
+	in := make(chan *fab.CCEvent)
+	f, out, err := New(in, Options{Policy: DropOldest, BufferSize: 10})
+	require.NoError(t, err)
+
+	in <- &fab.CCEvent{BlockNumber: 1}
+	in <- &fab.CCEvent{BlockNumber: 2}
+
+	f.Close()
+	f.Close()
+
+	select {
+	case _, ok := <-out.(chan *fab.CCEvent):
+		if ok {
+			// The event may have been sent before the Forwarder was closed. The channel must be closed next.
+			_, ok = <-out.(chan *fab.CCEvent)
+		}
+		require.False(t, ok, "expecting output channel to be closed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for output channel to be closed")
+	}
+}
+
+func TestSlowConsumerDoesNotBlock(t *testing.T) {
+	in := make(chan *fab.CCEvent)
+	_, out, err := New(in, Options{Policy: DropOldest, BufferSize: 1})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			in <- &fab.CCEvent{BlockNumber: uint64(i)}
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("producer was blocked by the consumer")
+	}
+
+	close(in)
+	require.Equal(t, []uint64{99}, receiveCCEvents(t, out.(chan *fab.CCEvent)))
+}
+
+type dropRecorder struct {
+	mutex   sync.Mutex
+	events  []interface{}
+	reasons []error
+}
+
+func (r *dropRecorder) onDrop(event interface{}, reason error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+	r.reasons = append(r.reasons, reason)
+}
+
+func (r *dropRecorder) blockNumbers() []uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var blockNums []uint64
+	for _, event := range r.events {
+		blockNums = append(blockNums, event.(*fab.CCEvent).BlockNumber)
+	}
+	return blockNums
+}
+
+func receiveCCEvents(t *testing.T, eventch chan *fab.CCEvent) []uint64 {
+	var blockNums []uint64
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				return blockNums
+			}
+			blockNums = append(blockNums, event.BlockNumber)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for events")
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package overflow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// queue holds the events that have not yet been delivered. Queues are only accessed from the Forwarder's goroutine.
+type queue interface {
+	// push adds an event to the queue. If an event is dropped then it is returned along with the reason.
+	push(event interface{}) (interface{}, error)
+	// peek returns the event at the head of the queue. An error is returned if an event was
+	// dropped while reading it back from the spill file; the queue is still usable.
+	peek() (interface{}, bool, error)
+	// pop removes the event at the head of the queue
+	pop()
+	// close releases the queue's resources
+	close()
+}
+
+// memQueue is a bounded in-memory FIFO queue which drops either the oldest or the newest event when full
+type memQueue struct {
+	policy   Policy
+	capacity int
+	events   []interface{}
+}
+
+func newMemQueue(policy Policy, capacity int) *memQueue {
+	return &memQueue{policy: policy, capacity: capacity}
+}
+
+func (q *memQueue) push(event interface{}) (interface{}, error) {
+	if len(q.events) < q.capacity {
+		q.events = append(q.events, event)
+		return nil, nil
+	}
+
+	if q.policy == DropNewest {
+		return event, ErrBufferFull
+	}
+
+	oldest := q.events[0]
+	q.events = append(q.events[1:], event)
+	return oldest, ErrBufferFull
+}
+
+func (q *memQueue) peek() (interface{}, bool, error) {
+	if len(q.events) == 0 {
+		return nil, false, nil
+	}
+	return q.events[0], true, nil
+}
+
+func (q *memQueue) pop() {
+	if len(q.events) > 0 {
+		q.events[0] = nil
+		q.events = q.events[1:]
+	}
+}
+
+func (q *memQueue) close() {
+	q.events = nil
+}
+
+// spillQueue holds up to capacity events in memory. Once the memory buffer is full, events are
+// appended to a spill file and read back in order as the memory buffer drains.
+type spillQueue struct {
+	capacity int
+	events   []interface{}
+	codec    codec
+	dir      string
+	file     *os.File
+	reader   *bufio.Reader
+	spilled  int
+}
+
+func newSpillQueue(capacity int, dir string, c codec) (*spillQueue, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, errors.Wrapf(err, "failed to create spill directory [%s]", dir)
+		}
+	}
+	return &spillQueue{capacity: capacity, dir: dir, codec: c}, nil
+}
+
+func (q *spillQueue) push(event interface{}) (interface{}, error) {
+	if q.spilled == 0 && len(q.events) < q.capacity {
+		q.events = append(q.events, event)
+		return nil, nil
+	}
+
+	// Once events have been spilled, new events must also be spilled to preserve their order
+	if err := q.spill(event); err != nil {
+		return event, errors.WithMessage(err, "failed to spill event to disk")
+	}
+	return nil, nil
+}
+
+func (q *spillQueue) peek() (interface{}, bool, error) {
+	if len(q.events) == 0 && q.spilled > 0 {
+		if err := q.unspill(); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(q.events) == 0 {
+		return nil, false, nil
+	}
+	return q.events[0], true, nil
+}
+
+func (q *spillQueue) pop() {
+	if len(q.events) > 0 {
+		q.events[0] = nil
+		q.events = q.events[1:]
+	}
+}
+
+func (q *spillQueue) close() {
+	q.events = nil
+	q.resetFile()
+}
+
+func (q *spillQueue) spill(event interface{}) error {
+	data, err := q.codec.encode(event)
+	if err != nil {
+		return err
+	}
+
+	if q.file == nil {
+		file, err := ioutil.TempFile(q.dir, "events-")
+		if err != nil {
+			return errors.Wrap(err, "failed to create spill file")
+		}
+		q.file = file
+		q.reader = bufio.NewReader(io.NewSectionReader(file, 0, 1<<62))
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := q.file.Write(append(header[:], data...)); err != nil {
+		return errors.Wrap(err, "failed to write to spill file")
+	}
+
+	q.spilled++
+	return nil
+}
+
+// unspill reads spilled events back into the memory buffer. If a record can't be decoded then
+// unspill stops after discarding it, keeping the events read so far. If the spill file can't be
+// read then the remaining spilled events are discarded.
+func (q *spillQueue) unspill() error {
+	for q.spilled > 0 && len(q.events) < q.capacity {
+		data, err := q.readRecord()
+		if err != nil {
+			lost := q.spilled
+			q.resetFile()
+			return errors.WithMessagef(err, "%d spilled events were lost", lost)
+		}
+		q.spilled--
+
+		event, err := q.codec.decode(data)
+		if err != nil {
+			if q.spilled == 0 {
+				q.resetFile()
+			}
+			return errors.WithMessage(err, "failed to decode spilled event")
+		}
+		q.events = append(q.events, event)
+	}
+
+	if q.spilled == 0 {
+		q.resetFile()
+	}
+	return nil
+}
+
+func (q *spillQueue) readRecord() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(q.reader, header[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to read from spill file")
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := io.ReadFull(q.reader, data); err != nil {
+		return nil, errors.Wrap(err, "failed to read from spill file")
+	}
+	return data, nil
+}
+
+func (q *spillQueue) resetFile() {
+	q.spilled = 0
+	q.reader = nil
+	if q.file == nil {
+		return
+	}
+
+	name := q.file.Name()
+	if err := q.file.Close(); err != nil {
+		logger.Warnf("Failed to close spill file [%s]: %s", name, err)
+	}
+	if err := os.Remove(name); err != nil {
+		logger.Warnf("Failed to remove spill file [%s]: %s", name, err)
+	}
+	q.file = nil
+}
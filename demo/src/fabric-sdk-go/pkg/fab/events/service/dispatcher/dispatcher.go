@@ -15,12 +15,14 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/pkg/txflags"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/eventfilter"
 	"github.com/pkg/errors"
 )
 
@@ -47,9 +49,11 @@ type Dispatcher struct {
 	lastBlockNum uint64 // Must be first, do not move
 	params
 	updateLastBlockInfoOnly    bool
+	stopBlockReached           bool
 	state                      int32
 	eventch                    chan interface{}
 	blockRegistrations         []*BlockReg
+	pvtDataRegistrations       []*BlockAndPrivateDataReg
 	filteredBlockRegistrations []*FilteredBlockReg
 	handlers                   map[reflect.Type]Handler
 	txRegistrations            map[string]*TxStatusReg
@@ -79,8 +83,10 @@ func (ed *Dispatcher) RegisterHandlers() {
 	ed.RegisterHandler(&RegisterChaincodeEvent{}, ed.handleRegisterCCEvent)
 	ed.RegisterHandler(&RegisterTxStatusEvent{}, ed.handleRegisterTxStatusEvent)
 	ed.RegisterHandler(&RegisterBlockEvent{}, ed.handleRegisterBlockEvent)
+	ed.RegisterHandler(&RegisterBlockAndPrivateDataEvent{}, ed.handleRegisterBlockAndPrivateDataEvent)
 	ed.RegisterHandler(&RegisterFilteredBlockEvent{}, ed.handleRegisterFilteredBlockEvent)
-	ed.RegisterHandler(&UnregisterEvent{}, ed.handleUnregisterEvent)
+	ed.RegisterHandler(&UnregisterEvent{}, ed.HandleUnregisterEvent)
+	ed.RegisterHandler(&SetDropHandlerEvent{}, ed.handleSetDropHandlerEvent)
 	ed.RegisterHandler(&StopEvent{}, ed.HandleStopEvent)
 	ed.RegisterHandler(&TransferEvent{}, ed.HandleTransferEvent)
 	ed.RegisterHandler(&StopAndTransferEvent{}, ed.HandleStopAndTransferEvent)
@@ -88,6 +94,7 @@ func (ed *Dispatcher) RegisterHandlers() {
 
 	// The following events are used for testing only
 	ed.RegisterHandler(&fab.BlockEvent{}, ed.handleBlockEvent)
+	ed.RegisterHandler(&fab.BlockAndPrivateDataEvent{}, ed.handleBlockAndPrivateDataEvent)
 	ed.RegisterHandler(&fab.FilteredBlockEvent{}, ed.handleFilteredBlockEvent)
 }
 
@@ -170,6 +177,10 @@ func (ed *Dispatcher) initRegistrations() error {
 		logger.Debugf("Adding block registration")
 		ed.registerBlockEvent(reg)
 	}
+	for _, reg := range ed.initialPvtDataRegistrations {
+		logger.Debugf("Adding block and private data registration")
+		ed.registerBlockAndPrivateDataEvent(reg)
+	}
 	for _, reg := range ed.initialFilteredBlockRegistrations {
 		logger.Debugf("Adding filtered block registration")
 		ed.registerFilteredBlockEvent(reg)
@@ -194,6 +205,7 @@ func (ed *Dispatcher) initRegistrations() error {
 
 func (ed *Dispatcher) clearRegistrations(closeChannel bool) {
 	ed.clearBlockRegistrations(closeChannel)
+	ed.clearPvtDataRegistrations(closeChannel)
 	ed.clearFilteredBlockRegistrations(closeChannel)
 	ed.clearTxRegistrations(closeChannel)
 	ed.clearChaincodeRegistrations(closeChannel)
@@ -210,6 +222,17 @@ func (ed *Dispatcher) clearBlockRegistrations(closeChannel bool) {
 	ed.blockRegistrations = nil
 }
 
+// clearPvtDataRegistrations removes all block and private data registrations and closes the corresponding event channels.
+// The listener will receive a 'closed' event to indicate that the channel has been closed.
+func (ed *Dispatcher) clearPvtDataRegistrations(closeChannel bool) {
+	if closeChannel {
+		for _, reg := range ed.pvtDataRegistrations {
+			close(reg.Eventch)
+		}
+	}
+	ed.pvtDataRegistrations = nil
+}
+
 // clearFilteredBlockRegistrations removes all filtered block registrations and closes the corresponding event channels.
 // The listener will receive a 'closed' event to indicate that the channel has been closed.
 func (ed *Dispatcher) clearFilteredBlockRegistrations(closeChannel bool) {
@@ -296,6 +319,11 @@ func (ed *Dispatcher) HandleStopAndTransferEvent(e Event) {
 func (ed *Dispatcher) handleRegisterBlockEvent(e Event) {
 	event := e.(*RegisterBlockEvent)
 
+	if err := ed.checkStopBlockReached(); err != nil {
+		event.ErrCh <- err
+		return
+	}
+
 	ed.registerBlockEvent(event.Reg)
 	event.RegCh <- event.Reg
 }
@@ -304,8 +332,30 @@ func (ed *Dispatcher) registerBlockEvent(reg *BlockReg) {
 	ed.blockRegistrations = append(ed.blockRegistrations, reg)
 }
 
+func (ed *Dispatcher) handleRegisterBlockAndPrivateDataEvent(e Event) {
+	event := e.(*RegisterBlockAndPrivateDataEvent)
+
+	if err := ed.checkStopBlockReached(); err != nil {
+		event.ErrCh <- err
+		return
+	}
+
+	ed.registerBlockAndPrivateDataEvent(event.Reg)
+	event.RegCh <- event.Reg
+}
+
+func (ed *Dispatcher) registerBlockAndPrivateDataEvent(reg *BlockAndPrivateDataReg) {
+	ed.pvtDataRegistrations = append(ed.pvtDataRegistrations, reg)
+}
+
 func (ed *Dispatcher) handleRegisterFilteredBlockEvent(e Event) {
 	event := e.(*RegisterFilteredBlockEvent)
+
+	if err := ed.checkStopBlockReached(); err != nil {
+		event.ErrCh <- err
+		return
+	}
+
 	ed.registerFilteredBlockEvent(event.Reg)
 	event.RegCh <- event.Reg
 }
@@ -317,6 +367,11 @@ func (ed *Dispatcher) registerFilteredBlockEvent(reg *FilteredBlockReg) {
 func (ed *Dispatcher) handleRegisterCCEvent(e Event) {
 	event := e.(*RegisterChaincodeEvent)
 
+	if err := ed.checkStopBlockReached(); err != nil {
+		event.ErrCh <- err
+		return
+	}
+
 	regExp, err := regexp.Compile(event.Reg.EventFilter)
 	if err != nil {
 		event.ErrCh <- errors.Wrapf(err, "error compiling regular expression for event filter [%s]", event.Reg.EventFilter)
@@ -342,6 +397,11 @@ func (ed *Dispatcher) registerCCEvent(reg *ChaincodeReg) error {
 func (ed *Dispatcher) handleRegisterTxStatusEvent(e Event) {
 	event := e.(*RegisterTxStatusEvent)
 
+	if err := ed.checkStopBlockReached(); err != nil {
+		event.ErrCh <- err
+		return
+	}
+
 	if err := ed.registerTxStatusEvent(event.Reg); err != nil {
 		event.ErrCh <- err
 	} else {
@@ -357,13 +417,21 @@ func (ed *Dispatcher) registerTxStatusEvent(reg *TxStatusReg) error {
 	return nil
 }
 
-func (ed *Dispatcher) handleUnregisterEvent(e Event) {
+// HandleUnregisterEvent unregisters the given registration
+func (ed *Dispatcher) HandleUnregisterEvent(e Event) {
 	event := e.(*UnregisterEvent)
 
+	if ed.stopBlockReached {
+		logger.Debugf("Ignoring unregister since all registrations were closed after the stop block")
+		return
+	}
+
 	var err error
 	switch registration := event.Reg.(type) {
 	case *BlockReg:
 		err = ed.unregisterBlockEvents(registration)
+	case *BlockAndPrivateDataReg:
+		err = ed.unregisterPvtDataEvents(registration)
 	case *FilteredBlockReg:
 		err = ed.unregisterFilteredBlockEvents(registration)
 	case *ChaincodeReg:
@@ -378,11 +446,37 @@ func (ed *Dispatcher) handleUnregisterEvent(e Event) {
 	}
 }
 
+func (ed *Dispatcher) handleSetDropHandlerEvent(e Event) {
+	event := e.(*SetDropHandlerEvent)
+
+	switch registration := event.Reg.(type) {
+	case *BlockReg:
+		registration.OnDrop = event.Handler
+	case *BlockAndPrivateDataReg:
+		registration.OnDrop = event.Handler
+	case *FilteredBlockReg:
+		registration.OnDrop = event.Handler
+	case *ChaincodeReg:
+		registration.OnDrop = event.Handler
+	case *TxStatusReg:
+		registration.OnDrop = event.Handler
+	default:
+		event.ErrCh <- errors.Errorf("Unsupported registration type: %+v", reflect.TypeOf(registration))
+		return
+	}
+	event.ErrCh <- nil
+}
+
 func (ed *Dispatcher) handleBlockEvent(e Event) {
 	evt := e.(*fab.BlockEvent)
 	ed.HandleBlock(evt.Block, evt.SourceURL)
 }
 
+func (ed *Dispatcher) handleBlockAndPrivateDataEvent(e Event) {
+	evt := e.(*fab.BlockAndPrivateDataEvent)
+	ed.HandleBlockAndPrivateData(evt.Block, evt.PrivateDataMap, evt.SourceURL)
+}
+
 func (ed *Dispatcher) handleFilteredBlockEvent(e Event) {
 	evt := e.(*fab.FilteredBlockEvent)
 	ed.HandleFilteredBlock(evt.FilteredBlock, evt.SourceURL)
@@ -393,13 +487,14 @@ func (ed *Dispatcher) handleRegistrationInfoEvent(e Event) {
 
 	regInfo := &RegistrationInfo{
 		NumBlockRegistrations:         len(ed.blockRegistrations),
+		NumPvtDataRegistrations:       len(ed.pvtDataRegistrations),
 		NumFilteredBlockRegistrations: len(ed.filteredBlockRegistrations),
 		NumCCRegistrations:            len(ed.ccRegistrations),
 		NumTxStatusRegistrations:      len(ed.txRegistrations),
 	}
 
 	regInfo.TotalRegistrations =
-		regInfo.NumBlockRegistrations + regInfo.NumFilteredBlockRegistrations + regInfo.NumCCRegistrations + regInfo.NumTxStatusRegistrations
+		regInfo.NumBlockRegistrations + regInfo.NumPvtDataRegistrations + regInfo.NumFilteredBlockRegistrations + regInfo.NumCCRegistrations + regInfo.NumTxStatusRegistrations
 
 	evt.RegInfoCh <- regInfo
 }
@@ -420,6 +515,7 @@ func (ed *Dispatcher) newSnapshot() fab.EventSnapshot {
 	return &snapshot{
 		lastBlockReceived:          ed.LastBlockNum(),
 		blockRegistrations:         ed.blockRegistrations,
+		pvtDataRegistrations:       ed.pvtDataRegistrations,
 		filteredBlockRegistrations: ed.filteredBlockRegistrations,
 		ccRegistrations:            ccRegistrations,
 		txStatusRegistrations:      txRegistrations,
@@ -442,7 +538,30 @@ func (ed *Dispatcher) HandleBlock(block *cb.Block, sourceURL string) {
 
 	logger.Debug("Publishing block event...")
 	ed.publishBlockEvents(block, sourceURL)
-	ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
+	ed.publishFilteredBlockEvents(toFilteredBlock(block), ed.fullTransactions(block), sourceURL)
+	ed.handleStopBlock(block.Header.Number)
+}
+
+// HandleBlockAndPrivateData handles a block event which includes the private data of the block's transactions.
+// The block is also published to block, filtered block, chaincode and transaction status registrations.
+func (ed *Dispatcher) HandleBlockAndPrivateData(block *cb.Block, pvtData map[uint64]*rwset.TxPvtReadWriteSet, sourceURL string) {
+	logger.Debugf("Handling block and private data event - Block #%d", block.Header.Number)
+
+	if err := ed.updateLastBlockNum(block.Header.Number); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	if ed.updateLastBlockInfoOnly {
+		ed.updateLastBlockInfoOnly = false
+		return
+	}
+
+	logger.Debug("Publishing block and private data event...")
+	ed.publishPvtDataEvents(block, pvtData, sourceURL)
+	ed.publishBlockEvents(block, sourceURL)
+	ed.publishFilteredBlockEvents(toFilteredBlock(block), ed.fullTransactions(block), sourceURL)
+	ed.handleStopBlock(block.Header.Number)
 }
 
 // HandleFilteredBlock handles a filtered block event
@@ -460,7 +579,28 @@ func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL st
 	}
 
 	logger.Debug("Publishing filtered block event...")
-	ed.publishFilteredBlockEvents(fblock, sourceURL)
+	ed.publishFilteredBlockEvents(fblock, nil, sourceURL)
+	ed.handleStopBlock(fblock.Number)
+}
+
+// handleStopBlock closes all registrations after the stop block (if any) has been dispatched.
+// Closing the event channels signals to registrants that all events in the requested range
+// have been delivered.
+func (ed *Dispatcher) handleStopBlock(blockNum uint64) {
+	if !ed.hasStopBlock || blockNum < ed.stopBlock {
+		return
+	}
+
+	logger.Debugf("Stop block %d reached - closing all registrations", ed.stopBlock)
+	ed.stopBlockReached = true
+	ed.clearRegistrations(true)
+}
+
+func (ed *Dispatcher) checkStopBlockReached() error {
+	if ed.stopBlockReached {
+		return errors.Errorf("all events up to stop block %d have been delivered", ed.stopBlock)
+	}
+	return nil
 }
 
 func (ed *Dispatcher) unregisterBlockEvents(registration *BlockReg) error {
@@ -476,6 +616,19 @@ func (ed *Dispatcher) unregisterBlockEvents(registration *BlockReg) error {
 	return errors.New("the provided registration is invalid")
 }
 
+func (ed *Dispatcher) unregisterPvtDataEvents(registration *BlockAndPrivateDataReg) error {
+	for i, reg := range ed.pvtDataRegistrations {
+		if reg == registration {
+			// Move the 0'th item to i and then delete the 0'th item
+			ed.pvtDataRegistrations[i] = ed.pvtDataRegistrations[0]
+			ed.pvtDataRegistrations = ed.pvtDataRegistrations[1:]
+			close(reg.Eventch)
+			return nil
+		}
+	}
+	return errors.New("the provided registration is invalid")
+}
+
 func (ed *Dispatcher) unregisterFilteredBlockEvents(registration *FilteredBlockReg) error {
 	for i, reg := range ed.filteredBlockRegistrations {
 		if reg == registration {
@@ -521,25 +674,59 @@ func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
 			continue
 		}
 
+		event := NewBlockEvent(block, sourceURL)
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warn("Unable to send to block event channel.")
+				dropped(reg.OnDrop, event)
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewBlockEvent(block, sourceURL)
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
+			case reg.Eventch <- event:
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending block event.")
+				dropped(reg.OnDrop, event)
+			}
+		}
+	}
+}
+
+func (ed *Dispatcher) publishPvtDataEvents(block *cb.Block, pvtData map[uint64]*rwset.TxPvtReadWriteSet, sourceURL string) {
+	for _, reg := range ed.pvtDataRegistrations {
+		if !reg.Filter(block) {
+			logger.Debugf("Not sending block and private data event for block #%d since it was filtered out.", block.Header.Number)
+			continue
+		}
+
+		event := NewBlockAndPrivateDataEvent(block, pvtData, sourceURL)
+		if ed.eventConsumerTimeout < 0 {
+			select {
+			case reg.Eventch <- event:
+			default:
+				logger.Warn("Unable to send to block and private data event channel.")
+				dropped(reg.OnDrop, event)
+			}
+		} else if ed.eventConsumerTimeout == 0 {
+			reg.Eventch <- event
+		} else {
+			select {
+			case reg.Eventch <- event:
+			case <-time.After(ed.eventConsumerTimeout):
+				logger.Warn("Timed out sending block and private data event.")
+				dropped(reg.OnDrop, event)
 			}
 		}
 	}
 }
 
-func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourceURL string) {
+// publishFilteredBlockEvents publishes the given filtered block to filtered block, transaction status and chaincode
+// registrations. fullTxs contains the attributes of the transactions in the full block (if the filtered block was
+// derived from one), keyed by transaction ID, which are used to evaluate the filters of chaincode registrations.
+func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, fullTxs map[string]*fab.TransactionInfo, sourceURL string) {
 	if fblock == nil {
 		logger.Warn("Filtered block is nil. Event will not be published")
 		return
@@ -561,9 +748,13 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourc
 			if len(txActions.ChaincodeActions) == 0 {
 				logger.Debugf("No chaincode action found for TxID[%s], block[%d], source URL[%s]", tx.Txid, fblock.Number, sourceURL)
 			}
+			txInfo, ok := fullTxs[tx.Txid]
+			if !ok {
+				txInfo = eventfilter.FilteredTransactionInfo(tx, fblock.Number)
+			}
 			for _, action := range txActions.ChaincodeActions {
 				if action.ChaincodeEvent != nil {
-					ed.publishCCEvents(action.ChaincodeEvent, fblock.Number, sourceURL)
+					ed.publishCCEvents(action.ChaincodeEvent, txInfo, fblock.Number, sourceURL)
 				}
 			}
 		} else {
@@ -574,19 +765,27 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourc
 
 func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, sourceURL string) {
 	for _, reg := range ed.filteredBlockRegistrations {
+		if reg.Filter != nil && !reg.Filter(fblock) {
+			logger.Debugf("Not sending filtered block event for block #%d since it was filtered out.", fblock.Number)
+			continue
+		}
+
+		event := NewFilteredBlockEvent(fblock, sourceURL)
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warn("Unable to send to filtered block event channel.")
+				dropped(reg.OnDrop, event)
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+			case reg.Eventch <- event:
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending filtered block event.")
+				dropped(reg.OnDrop, event)
 			}
 		}
 	}
@@ -597,49 +796,68 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum
 	if reg, ok := ed.txRegistrations[tx.Txid]; ok {
 		logger.Debugf("Sending Tx Status event for TxID [%s] to registrant...", tx.Txid)
 
+		event := NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warn("Unable to send to Tx Status event channel.")
+				dropped(reg.OnDrop, event)
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
+			case reg.Eventch <- event:
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending Tx Status event.")
+				dropped(reg.OnDrop, event)
 			}
 		}
 	}
 }
 
-func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint64, sourceURL string) {
+func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, tx *fab.TransactionInfo, blockNum uint64, sourceURL string) {
 	for _, reg := range ed.ccRegistrations {
 		logger.Debugf("Matching CCEvent[%s,%s] against Reg[%s,%s] ...", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 		if reg.ChaincodeID == ccEvent.ChaincodeId && reg.EventRegExp.MatchString(ccEvent.EventName) {
+			if reg.TxFilter != nil && !reg.TxFilter(withChaincodeEvent(tx, ccEvent)) {
+				logger.Debugf("... CCEvent[%s,%s] of transaction [%s] was filtered out by Reg[%s,%s]", ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, reg.ChaincodeID, reg.EventFilter)
+				continue
+			}
 			logger.Debugf("... matched CCEvent[%s,%s] against Reg[%s,%s]", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 
+			event := NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
 			if ed.eventConsumerTimeout < 0 {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+				case reg.Eventch <- event:
 				default:
 					logger.Warn("Unable to send to CC event channel.")
+					dropped(reg.OnDrop, event)
 				}
 			} else if ed.eventConsumerTimeout == 0 {
-				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+				reg.Eventch <- event
 			} else {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+				case reg.Eventch <- event:
 				case <-time.After(ed.eventConsumerTimeout):
 					logger.Warn("Timed out sending CC event.")
+					dropped(reg.OnDrop, event)
 				}
 			}
 		}
 	}
 }
 
+// ErrEventNotDelivered is passed to a registration's DropHandler when the consumer did not receive an event in time
+var ErrEventNotDelivered = errors.New("event was not received by the consumer in time")
+
+func dropped(onDrop DropHandler, event interface{}) {
+	if onDrop != nil {
+		onDrop(event, ErrEventNotDelivered)
+	}
+}
+
 // RegisterHandler registers an event handler
 func (ed *Dispatcher) RegisterHandler(t interface{}, h Handler) {
 	htype := reflect.TypeOf(t)
@@ -656,6 +874,34 @@ func (ed *Dispatcher) UpdateLastBlockInfoOnly() {
 	ed.updateLastBlockInfoOnly = true
 }
 
+// fullTransactions returns the attributes of the transactions in the given block, keyed by transaction ID.
+// The block is only decoded if a chaincode registration has a transaction filter.
+func (ed *Dispatcher) fullTransactions(block *cb.Block) map[string]*fab.TransactionInfo {
+	hasTxFilter := false
+	for _, reg := range ed.ccRegistrations {
+		if reg.TxFilter != nil {
+			hasTxFilter = true
+			break
+		}
+	}
+	if !hasTxFilter {
+		return nil
+	}
+
+	txs := make(map[string]*fab.TransactionInfo)
+	for _, tx := range eventfilter.TransactionsFromBlock(block) {
+		txs[tx.TxID] = tx
+	}
+	return txs
+}
+
+// withChaincodeEvent returns a copy of the transaction attributes with the given chaincode event
+func withChaincodeEvent(tx *fab.TransactionInfo, ccEvent *pb.ChaincodeEvent) *fab.TransactionInfo {
+	txCopy := *tx
+	txCopy.ChaincodeEvent = ccEvent
+	return &txCopy
+}
+
 func getCCKey(ccID, eventFilter string) string {
 	return ccID + "/" + eventFilter
 }
@@ -13,9 +13,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter/headertypefilter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/eventfilter"
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
@@ -940,6 +942,128 @@ func testTransferSnapshot(t *testing.T, transferFunc transferFunc) {
 	ensureTxStatusEvent(t, txeventch, txID)
 }
 
+func TestEventFilters(t *testing.T) {
+	channelID := "testchannel"
+	ccID := "mycc"
+	dispatcher := New()
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	ccch := make(chan *fab.CCEvent, 10)
+	ccEvent := NewRegisterChaincodeEvent(ccID, ".*", ccch, regch, errch)
+	ccEvent.Reg.TxFilter = eventfilter.And(eventfilter.CreatorMSPID("Org1MSP"), eventfilter.FunctionName("transfer"))
+	dispatcherEventch <- ccEvent
+	checkReg(t, regch, errch)
+
+	fbeventch := make(chan *fab.FilteredBlockEvent, 10)
+	fbEvent := NewRegisterFilteredBlockEvent(fbeventch, regch, errch)
+	fbEvent.Reg.Filter = eventfilter.FilteredBlock(eventfilter.ValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT))
+	dispatcherEventch <- fbEvent
+	checkReg(t, regch, errch)
+
+	tx1 := servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "event1", nil)
+	tx1.CreatorMSPID = "Org2MSP"
+	tx1.FunctionName = "transfer"
+	tx2 := servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, ccID, "event2", nil)
+	tx2.CreatorMSPID = "Org1MSP"
+	tx2.FunctionName = "transfer"
+
+	producer := servicemocks.NewBlockProducer()
+	dispatcherEventch <- NewBlockEvent(producer.NewBlock(channelID, tx1, tx2), sourceURL)
+	ensureCCEvent(t, ccch, ccID, "event2")
+
+	dispatcherEventch <- NewFilteredBlockEvent(producer.NewFilteredBlock(channelID,
+		servicemocks.NewFilteredTx("txid3", pb.TxValidationCode_VALID),
+	), sourceURL)
+	dispatcherEventch <- NewFilteredBlockEvent(producer.NewFilteredBlock(channelID,
+		servicemocks.NewFilteredTx("txid4", pb.TxValidationCode_MVCC_READ_CONFLICT),
+	), sourceURL)
+
+	select {
+	case event, ok := <-fbeventch:
+		require.True(t, ok, "unexpected closed channel")
+		require.Equal(t, "txid4", event.FilteredBlock.FilteredTransactions[0].Txid, "filtered block with valid transaction should have been filtered out")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for filtered block event")
+	}
+
+	select {
+	case event := <-ccch:
+		t.Fatalf("unexpected chaincode event for transaction %s", event.TxID)
+	default:
+	}
+}
+
+func TestStopBlock(t *testing.T) {
+	channelID := "testchannel"
+	dispatcher := New(withStopBlockNum(1))
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	beventch := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, beventch, regch, errch)
+	checkReg(t, regch, errch)
+
+	ccch := make(chan *fab.CCEvent, 10)
+	dispatcherEventch <- NewRegisterChaincodeEvent("cc1", ".*", ccch, regch, errch)
+	checkReg(t, regch, errch)
+
+	producer := servicemocks.NewBlockProducer()
+	dispatcherEventch <- NewBlockEvent(producer.NewBlock(channelID), sourceURL)
+	ensureBlockEvent(t, beventch)
+
+	dispatcherEventch <- NewBlockEvent(producer.NewBlock(channelID), sourceURL)
+	ensureBlockEvent(t, beventch)
+
+	// All registrations should be closed after the stop block
+	ensureClosed(t, beventch)
+	select {
+	case _, ok := <-ccch:
+		require.False(t, ok, "expecting chaincode event channel to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for chaincode event channel to close")
+	}
+
+	// New registrations should be rejected
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, make(chan *fab.BlockEvent), regch, errch)
+	select {
+	case <-regch:
+		t.Fatal("expecting error registering after stop block")
+	case err := <-errch:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stop block 1")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for registration response")
+	}
+}
+
+func withStopBlockNum(value uint64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(interface{ SetStopBlockNum(uint64) }); ok {
+			setter.SetStopBlockNum(value)
+		}
+	}
+}
+
+func ensureClosed(t *testing.T, eventch <-chan *fab.BlockEvent) {
+	select {
+	case _, ok := <-eventch:
+		require.False(t, ok, "expecting block event channel to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for block event channel to close")
+	}
+}
+
 func checkReg(t *testing.T, regch <-chan fab.Registration, errch <-chan error) {
 	select {
 	case <-regch:
@@ -8,6 +8,7 @@ package dispatcher
 
 import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
@@ -47,6 +48,12 @@ type RegisterBlockEvent struct {
 	Reg *BlockReg
 }
 
+// RegisterBlockAndPrivateDataEvent registers for block and private data events
+type RegisterBlockAndPrivateDataEvent struct {
+	RegisterEvent
+	Reg *BlockAndPrivateDataReg
+}
+
 // RegisterFilteredBlockEvent registers for filtered block events
 type RegisterFilteredBlockEvent struct {
 	RegisterEvent
@@ -70,10 +77,18 @@ type UnregisterEvent struct {
 	Reg fab.Registration
 }
 
+// SetDropHandlerEvent sets the handler that is invoked when an event for a registration is dropped
+type SetDropHandlerEvent struct {
+	Reg     fab.Registration
+	Handler DropHandler
+	ErrCh   chan<- error
+}
+
 // RegistrationInfo contains counts of the current event registrations
 type RegistrationInfo struct {
 	TotalRegistrations            int
 	NumBlockRegistrations         int
+	NumPvtDataRegistrations       int
 	NumFilteredBlockRegistrations int
 	NumCCRegistrations            int
 	NumTxStatusRegistrations      int
@@ -92,6 +107,14 @@ func NewRegisterBlockEvent(filter fab.BlockFilter, eventch chan<- *fab.BlockEven
 	}
 }
 
+// NewRegisterBlockAndPrivateDataEvent creates a new RegisterBlockAndPrivateDataEvent
+func NewRegisterBlockAndPrivateDataEvent(filter fab.BlockFilter, eventch chan<- *fab.BlockAndPrivateDataEvent, respch chan<- fab.Registration, errCh chan<- error) *RegisterBlockAndPrivateDataEvent {
+	return &RegisterBlockAndPrivateDataEvent{
+		Reg:           &BlockAndPrivateDataReg{Filter: filter, Eventch: eventch},
+		RegisterEvent: NewRegisterEvent(respch, errCh),
+	}
+}
+
 // NewRegisterFilteredBlockEvent creates a new RegisterFilterBlockEvent
 func NewRegisterFilteredBlockEvent(eventch chan<- *fab.FilteredBlockEvent, respch chan<- fab.Registration, errCh chan<- error) *RegisterFilteredBlockEvent {
 	return &RegisterFilteredBlockEvent{
@@ -107,6 +130,15 @@ func NewUnregisterEvent(reg fab.Registration) *UnregisterEvent {
 	}
 }
 
+// NewSetDropHandlerEvent creates a new SetDropHandlerEvent
+func NewSetDropHandlerEvent(reg fab.Registration, handler DropHandler, errCh chan<- error) *SetDropHandlerEvent {
+	return &SetDropHandlerEvent{
+		Reg:     reg,
+		Handler: handler,
+		ErrCh:   errCh,
+	}
+}
+
 // NewRegisterChaincodeEvent creates a new RegisterChaincodeEvent
 func NewRegisterChaincodeEvent(ccID, eventFilter string, eventch chan<- *fab.CCEvent, respch chan<- fab.Registration, errCh chan<- error) *RegisterChaincodeEvent {
 	return &RegisterChaincodeEvent{
@@ -143,6 +175,15 @@ func NewBlockEvent(block *cb.Block, sourceURL string) *fab.BlockEvent {
 	}
 }
 
+// NewBlockAndPrivateDataEvent creates a new BlockAndPrivateDataEvent
+func NewBlockAndPrivateDataEvent(block *cb.Block, pvtData map[uint64]*rwset.TxPvtReadWriteSet, sourceURL string) *fab.BlockAndPrivateDataEvent {
+	return &fab.BlockAndPrivateDataEvent{
+		Block:          block,
+		PrivateDataMap: pvtData,
+		SourceURL:      sourceURL,
+	}
+}
+
 // NewFilteredBlockEvent creates a new FilteredBlockEvent
 func NewFilteredBlockEvent(fblock *pb.FilteredBlock, sourceURL string) *fab.FilteredBlockEvent {
 	return &fab.FilteredBlockEvent{
@@ -19,9 +19,12 @@ type params struct {
 	eventConsumerTimeout              time.Duration
 	initialLastBlockNum               uint64
 	initialBlockRegistrations         []*BlockReg
+	initialPvtDataRegistrations       []*BlockAndPrivateDataReg
 	initialFilteredBlockRegistrations []*FilteredBlockReg
 	initialCCRegistrations            []*ChaincodeReg
 	initialTxStatusRegistrations      []*TxStatusReg
+	stopBlock                         uint64
+	hasStopBlock                      bool
 }
 
 func defaultParams() *params {
@@ -82,6 +85,15 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	p.eventConsumerTimeout = value
 }
 
+// SetStopBlockNum sets the last block for which events are dispatched. After the stop block
+// has been dispatched, all registrations are closed and new registrations are rejected.
+// The value is typically set with deliverclient.WithStopBlockNum.
+func (p *params) SetStopBlockNum(value uint64) {
+	logger.Debugf("StopBlock: %d", value)
+	p.stopBlock = value
+	p.hasStopBlock = true
+}
+
 type snapshotSetter interface {
 	SetSnapshot(value fab.EventSnapshot) error
 }
@@ -93,6 +105,10 @@ func (p *params) SetSnapshot(value fab.EventSnapshot) error {
 	if err != nil {
 		return err
 	}
+	pvtRegistrations, err := asPvtDataRegistrations(value.BlockAndPrivateDataRegistrations())
+	if err != nil {
+		return err
+	}
 	fbRegistrations, err := asFBlockRegistrations(value.FilteredBlockRegistrations())
 	if err != nil {
 		return err
@@ -108,6 +124,7 @@ func (p *params) SetSnapshot(value fab.EventSnapshot) error {
 
 	p.initialLastBlockNum = value.LastBlockReceived()
 	p.initialBlockRegistrations = bRegistrations
+	p.initialPvtDataRegistrations = pvtRegistrations
 	p.initialFilteredBlockRegistrations = fbRegistrations
 	p.initialCCRegistrations = ccRegistrations
 	p.initialTxStatusRegistrations = txRegistrations
@@ -127,6 +144,18 @@ func asBlockRegistrations(registrations []fab.Registration) ([]*BlockReg, error)
 	return bRegistrations, nil
 }
 
+func asPvtDataRegistrations(registrations []fab.Registration) ([]*BlockAndPrivateDataReg, error) {
+	var pvtRegistrations []*BlockAndPrivateDataReg
+	for _, reg := range registrations {
+		pvtreg, ok := reg.(*BlockAndPrivateDataReg)
+		if !ok {
+			return nil, errors.New("invalid block and private data registration")
+		}
+		pvtRegistrations = append(pvtRegistrations, pvtreg)
+	}
+	return pvtRegistrations, nil
+}
+
 func asFBlockRegistrations(registrations []fab.Registration) ([]*FilteredBlockReg, error) {
 	var fbRegistrations []*FilteredBlockReg
 	for _, reg := range registrations {
@@ -13,15 +13,29 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
 
+// DropHandler is invoked from the dispatcher's goroutine when an event is dropped because the
+// consumer did not receive it in time. It must not block.
+type DropHandler func(event interface{}, reason error)
+
 // BlockReg contains the data for a block registration
 type BlockReg struct {
 	Filter  fab.BlockFilter
 	Eventch chan<- *fab.BlockEvent
+	OnDrop  DropHandler
+}
+
+// BlockAndPrivateDataReg contains the data for a block and private data registration
+type BlockAndPrivateDataReg struct {
+	Filter  fab.BlockFilter
+	Eventch chan<- *fab.BlockAndPrivateDataEvent
+	OnDrop  DropHandler
 }
 
 // FilteredBlockReg contains the data for a filtered block registration
 type FilteredBlockReg struct {
+	Filter  fab.FilteredBlockFilter
 	Eventch chan<- *fab.FilteredBlockEvent
+	OnDrop  DropHandler
 }
 
 // ChaincodeReg contains the data for a chaincode registration
@@ -29,18 +43,22 @@ type ChaincodeReg struct {
 	ChaincodeID string
 	EventFilter string
 	EventRegExp *regexp.Regexp
+	TxFilter    fab.TxFilter
 	Eventch     chan<- *fab.CCEvent
+	OnDrop      DropHandler
 }
 
 // TxStatusReg contains the data for a transaction status registration
 type TxStatusReg struct {
 	TxID    string
 	Eventch chan<- *fab.TxStatusEvent
+	OnDrop  DropHandler
 }
 
 type snapshot struct {
 	lastBlockReceived          uint64
 	blockRegistrations         []*BlockReg
+	pvtDataRegistrations       []*BlockAndPrivateDataReg
 	filteredBlockRegistrations []*FilteredBlockReg
 	ccRegistrations            []*ChaincodeReg
 	txStatusRegistrations      []*TxStatusReg
@@ -54,6 +72,10 @@ func (s *snapshot) BlockRegistrations() []fab.Registration {
 	return fromBlockReg(s.blockRegistrations)
 }
 
+func (s *snapshot) BlockAndPrivateDataRegistrations() []fab.Registration {
+	return fromPvtDataReg(s.pvtDataRegistrations)
+}
+
 func (s *snapshot) FilteredBlockRegistrations() []fab.Registration {
 	return fromFBlockReg(s.filteredBlockRegistrations)
 }
@@ -77,8 +99,8 @@ func (s *snapshot) String() string {
 		txReg = append(txReg, fmt.Sprintf("{TxID: %s}", reg.TxID))
 	}
 
-	return fmt.Sprintf("Last Block: %d, Block Reg's: %d, Filtered Block Reg's: %d, CC Reg's: %s, TxStatus Reg's: %s",
-		s.lastBlockReceived, len(s.blockRegistrations), len(s.filteredBlockRegistrations), ccReg, txReg)
+	return fmt.Sprintf("Last Block: %d, Block Reg's: %d, Block and Private Data Reg's: %d, Filtered Block Reg's: %d, CC Reg's: %s, TxStatus Reg's: %s",
+		s.lastBlockReceived, len(s.blockRegistrations), len(s.pvtDataRegistrations), len(s.filteredBlockRegistrations), ccReg, txReg)
 }
 
 // Close closes all event registrations
@@ -86,6 +108,9 @@ func (s *snapshot) Close() {
 	for _, reg := range s.blockRegistrations {
 		close(reg.Eventch)
 	}
+	for _, reg := range s.pvtDataRegistrations {
+		close(reg.Eventch)
+	}
 	for _, reg := range s.filteredBlockRegistrations {
 		close(reg.Eventch)
 	}
@@ -105,6 +130,14 @@ func fromBlockReg(bRegistrations []*BlockReg) []fab.Registration {
 	return registrations
 }
 
+func fromPvtDataReg(bRegistrations []*BlockAndPrivateDataReg) []fab.Registration {
+	var registrations []fab.Registration
+	for _, reg := range bRegistrations {
+		registrations = append(registrations, reg)
+	}
+	return registrations
+}
+
 func fromFBlockReg(bRegistrations []*FilteredBlockReg) []fab.Registration {
 	var registrations []fab.Registration
 	for _, reg := range bRegistrations {
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package eventfilter provides composable transaction filters that may be applied to block,
+// filtered block and chaincode event registrations.
+//
+// A filter is a fab.TxFilter, which is passed directly to RegisterChaincodeEvent, or adapted to
+// a block filter with Block or to a filtered block filter with FilteredBlock. A block is accepted
+// if any of its transactions is accepted by the filter.
+package eventfilter
+
+import (
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("eventservice/eventfilter")
+
+// And returns a filter that accepts a transaction if all of the given filters accept it
+func And(filters ...fab.TxFilter) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		for _, filter := range filters {
+			if !filter(tx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a filter that accepts a transaction if any of the given filters accepts it
+func Or(filters ...fab.TxFilter) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		for _, filter := range filters {
+			if filter(tx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a filter that accepts a transaction if the given filter rejects it
+func Not(filter fab.TxFilter) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		return !filter(tx)
+	}
+}
+
+// CreatorMSPID returns a filter that accepts transactions created by an identity of one of the given MSPs.
+// The creator is only available for full blocks so transactions in filtered blocks are rejected.
+func CreatorMSPID(mspIDs ...string) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		for _, mspID := range mspIDs {
+			if tx.CreatorMSPID == mspID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ValidationCode returns a filter that accepts transactions with one of the given validation codes
+func ValidationCode(codes ...pb.TxValidationCode) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		for _, code := range codes {
+			if tx.ValidationCode == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FunctionName returns a filter that accepts transactions which invoked one of the given chaincode functions.
+// The function name is only available for full blocks so transactions in filtered blocks are rejected.
+func FunctionName(names ...string) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		for _, name := range names {
+			if tx.FunctionName == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// TimeWindow returns a filter that accepts transactions created within the given time window (inclusive).
+// A zero from or to time leaves that end of the window open. The timestamp is only available for
+// full blocks so transactions in filtered blocks are rejected.
+func TimeWindow(from, to time.Time) fab.TxFilter {
+	return func(tx *fab.TransactionInfo) bool {
+		if tx.Timestamp.IsZero() {
+			return false
+		}
+		if !from.IsZero() && tx.Timestamp.Before(from) {
+			return false
+		}
+		if !to.IsZero() && tx.Timestamp.After(to) {
+			return false
+		}
+		return true
+	}
+}
+
+// Block adapts the given filter to a block filter that accepts a block if any of its transactions is accepted
+func Block(filter fab.TxFilter) fab.BlockFilter {
+	return func(block *cb.Block) bool {
+		for _, tx := range TransactionsFromBlock(block) {
+			if filter(tx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilteredBlock adapts the given filter to a filtered block filter that accepts a filtered block if any
+// of its transactions is accepted
+func FilteredBlock(filter fab.TxFilter) fab.FilteredBlockFilter {
+	return func(fblock *pb.FilteredBlock) bool {
+		for _, tx := range TransactionsFromFilteredBlock(fblock) {
+			if filter(tx) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventfilter
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "mychannel"
+	ccID      = "mycc"
+)
+
+var txTime = time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestTransactionsFromBlock(t *testing.T) {
+	tx1 := servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "event1", []byte(`{"owner":"alice"}`))
+	tx1.CreatorMSPID = "Org1MSP"
+	tx1.FunctionName = "transfer"
+	tx1.Timestamp = txTime
+	tx2 := servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_MVCC_READ_CONFLICT, ccID, "event2", nil)
+
+	txs := TransactionsFromBlock(servicemocks.NewBlock(channelID, tx1, tx2))
+	require.Len(t, txs, 2)
+
+	require.Equal(t, "txid1", txs[0].TxID)
+	require.Equal(t, pb.TxValidationCode_VALID, txs[0].ValidationCode)
+	require.Equal(t, "Org1MSP", txs[0].CreatorMSPID)
+	require.Equal(t, ccID, txs[0].ChaincodeID)
+	require.Equal(t, "transfer", txs[0].FunctionName)
+	require.True(t, txTime.Equal(txs[0].Timestamp))
+	require.NotNil(t, txs[0].ChaincodeEvent)
+	require.Equal(t, "event1", txs[0].ChaincodeEvent.EventName)
+
+	require.Equal(t, "txid2", txs[1].TxID)
+	require.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, txs[1].ValidationCode)
+	require.Empty(t, txs[1].CreatorMSPID)
+	require.Empty(t, txs[1].FunctionName)
+	require.True(t, txs[1].Timestamp.IsZero())
+}
+
+func TestTxFilters(t *testing.T) {
+	tx := &fab.TransactionInfo{
+		TxID:           "txid1",
+		ValidationCode: pb.TxValidationCode_VALID,
+		CreatorMSPID:   "Org1MSP",
+		FunctionName:   "transfer",
+		Timestamp:      txTime,
+	}
+
+	require.True(t, CreatorMSPID("Org2MSP", "Org1MSP")(tx))
+	require.False(t, CreatorMSPID("Org2MSP")(tx))
+
+	require.True(t, ValidationCode(pb.TxValidationCode_VALID)(tx))
+	require.False(t, ValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT)(tx))
+
+	require.True(t, FunctionName("transfer")(tx))
+	require.False(t, FunctionName("create")(tx))
+
+	require.True(t, TimeWindow(txTime.Add(-time.Minute), txTime.Add(time.Minute))(tx))
+	require.True(t, TimeWindow(txTime, time.Time{})(tx))
+	require.True(t, TimeWindow(time.Time{}, txTime)(tx))
+	require.False(t, TimeWindow(txTime.Add(time.Second), time.Time{})(tx))
+	require.False(t, TimeWindow(time.Time{}, txTime.Add(-time.Second))(tx))
+	require.False(t, TimeWindow(time.Time{}, time.Time{})(&fab.TransactionInfo{}), "transactions without a timestamp should be rejected")
+
+	require.True(t, And(CreatorMSPID("Org1MSP"), FunctionName("transfer"))(tx))
+	require.False(t, And(CreatorMSPID("Org1MSP"), FunctionName("create"))(tx))
+	require.True(t, Or(CreatorMSPID("Org2MSP"), FunctionName("transfer"))(tx))
+	require.False(t, Or(CreatorMSPID("Org2MSP"), FunctionName("create"))(tx))
+	require.True(t, Not(CreatorMSPID("Org2MSP"))(tx))
+}
+
+func TestPayloadJSONPath(t *testing.T) {
+	tx := &fab.TransactionInfo{
+		ChaincodeEvent: &pb.ChaincodeEvent{
+			Payload: []byte(`{"asset":{"id":"a1","value":100,"owners":[{"name":"alice"},{"name":"bob"}]}}`),
+		},
+	}
+
+	filter, err := PayloadJSONPathEquals("$.asset.owners[1].name", "bob")
+	require.NoError(t, err)
+	require.True(t, filter(tx))
+
+	filter, err = PayloadJSONPathEquals("asset.value", 100)
+	require.NoError(t, err)
+	require.True(t, filter(tx))
+
+	filter, err = PayloadJSONPath("$.asset.value", func(value interface{}) bool {
+		v, ok := value.(float64)
+		return ok && v > 50
+	})
+	require.NoError(t, err)
+	require.True(t, filter(tx))
+
+	filter, err = PayloadJSONPathEquals("$.asset.owners[2].name", "carol")
+	require.NoError(t, err)
+	require.False(t, filter(tx), "index out of range should not match")
+
+	filter, err = PayloadJSONPathEquals("$.asset.missing", "x")
+	require.NoError(t, err)
+	require.False(t, filter(tx), "missing key should not match")
+
+	require.False(t, filter(&fab.TransactionInfo{}), "transactions without a payload should be rejected")
+	require.False(t, filter(&fab.TransactionInfo{ChaincodeEvent: &pb.ChaincodeEvent{Payload: []byte("not json")}}))
+
+	_, err = PayloadJSONPath("$.asset..id", nil)
+	require.Error(t, err)
+	_, err = PayloadJSONPath("$.owners[x]", nil)
+	require.Error(t, err)
+	_, err = PayloadJSONPath("$.owners[0", nil)
+	require.Error(t, err)
+}
+
+func TestBlockAdapters(t *testing.T) {
+	tx1 := servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, ccID, "event1", nil)
+	tx1.CreatorMSPID = "Org1MSP"
+	block := servicemocks.NewBlock(channelID, tx1)
+
+	require.True(t, Block(CreatorMSPID("Org1MSP"))(block))
+	require.False(t, Block(CreatorMSPID("Org2MSP"))(block))
+
+	fblock := servicemocks.NewFilteredBlock(channelID,
+		servicemocks.NewFilteredTx("txid1", pb.TxValidationCode_MVCC_READ_CONFLICT),
+		servicemocks.NewFilteredTxWithCCEvent("txid2", ccID, "event1"),
+	)
+
+	require.True(t, FilteredBlock(ValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT))(fblock))
+	require.False(t, FilteredBlock(ValidationCode(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE))(fblock))
+	require.False(t, FilteredBlock(CreatorMSPID("Org1MSP"))(fblock), "creator is not available in filtered blocks")
+}
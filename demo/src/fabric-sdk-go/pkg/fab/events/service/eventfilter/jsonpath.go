@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventfilter
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// pathElement is either an object key or an array index
+type pathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// PayloadJSONPath returns a filter that accepts transactions whose chaincode event payload is a JSON document
+// in which the value at the given path satisfies the predicate. The path is a dot separated list of object keys
+// and array indexes, optionally prefixed with "$", e.g. "$.asset.owners[0].name". The value passed to the
+// predicate is decoded with encoding/json, so numbers are float64. The payload is only available for full
+// blocks so transactions in filtered blocks are rejected.
+func PayloadJSONPath(path string, predicate func(value interface{}) bool) (fab.TxFilter, error) {
+	elements, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(tx *fab.TransactionInfo) bool {
+		if tx.ChaincodeEvent == nil || len(tx.ChaincodeEvent.Payload) == 0 {
+			return false
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(tx.ChaincodeEvent.Payload, &doc); err != nil {
+			logger.Debugf("Chaincode event payload of transaction [%s] is not JSON: %s", tx.TxID, err)
+			return false
+		}
+
+		value, ok := evaluate(doc, elements)
+		if !ok {
+			return false
+		}
+		return predicate(value)
+	}, nil
+}
+
+// PayloadJSONPathEquals returns a filter that accepts transactions whose chaincode event payload is a JSON
+// document in which the value at the given path equals the expected value (see PayloadJSONPath)
+func PayloadJSONPathEquals(path string, expected interface{}) (fab.TxFilter, error) {
+	// Normalize the expected value so that it has the same types as a decoded JSON value
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal expected value")
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(expectedBytes, &normalized); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal expected value")
+	}
+
+	return PayloadJSONPath(path, func(value interface{}) bool {
+		return reflect.DeepEqual(normalized, value)
+	})
+}
+
+func parsePath(path string) ([]pathElement, error) {
+	p := strings.TrimPrefix(path, "$")
+	p = strings.TrimPrefix(p, ".")
+	if p == "" {
+		return nil, nil
+	}
+
+	var elements []pathElement
+	for _, segment := range strings.Split(p, ".") {
+		key := segment
+		if i := strings.Index(segment, "["); i >= 0 {
+			key = segment[:i]
+		}
+		if key != "" {
+			elements = append(elements, pathElement{key: key})
+		}
+
+		indexes := segment[len(key):]
+		if key == "" && indexes == "" {
+			return nil, errors.Errorf("invalid JSON path [%s]: empty segment", path)
+		}
+		for indexes != "" {
+			end := strings.Index(indexes, "]")
+			if indexes[0] != '[' || end < 0 {
+				return nil, errors.Errorf("invalid JSON path [%s]: malformed index in segment [%s]", path, segment)
+			}
+			index, err := strconv.Atoi(indexes[1:end])
+			if err != nil || index < 0 {
+				return nil, errors.Errorf("invalid JSON path [%s]: invalid index in segment [%s]", path, segment)
+			}
+			elements = append(elements, pathElement{index: index, isIndex: true})
+			indexes = indexes[end+1:]
+		}
+	}
+
+	return elements, nil
+}
+
+func evaluate(doc interface{}, elements []pathElement) (interface{}, bool) {
+	value := doc
+	for _, element := range elements {
+		if element.isIndex {
+			array, ok := value.([]interface{})
+			if !ok || element.index >= len(array) {
+				return nil, false
+			}
+			value = array[element.index]
+			continue
+		}
+
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = object[element.key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
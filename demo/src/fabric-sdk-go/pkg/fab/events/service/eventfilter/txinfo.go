@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventfilter
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/pkg/txflags"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// TransactionsFromBlock returns the attributes of each transaction in the given block.
+// Transactions that can't be decoded are skipped.
+func TransactionsFromBlock(block *cb.Block) []*fab.TransactionInfo {
+	if block == nil || block.Data == nil {
+		return nil
+	}
+
+	var txFilter txflags.ValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = txflags.ValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var txs []*fab.TransactionInfo
+	for i, data := range block.Data.Data {
+		tx, err := newTransactionInfo(data)
+		if err != nil {
+			logger.Warnf("error extracting transaction %d from block %d: %s", i, block.Header.Number, err)
+			continue
+		}
+		tx.BlockNumber = block.Header.Number
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// TransactionsFromFilteredBlock returns the attributes of each transaction in the given filtered block.
+// Only the transaction ID, validation code, block number and chaincode event are available.
+func TransactionsFromFilteredBlock(fblock *pb.FilteredBlock) []*fab.TransactionInfo {
+	if fblock == nil {
+		return nil
+	}
+
+	var txs []*fab.TransactionInfo
+	for _, ftx := range fblock.FilteredTransactions {
+		txs = append(txs, FilteredTransactionInfo(ftx, fblock.Number))
+	}
+	return txs
+}
+
+// FilteredTransactionInfo returns the attributes of the given filtered transaction
+func FilteredTransactionInfo(ftx *pb.FilteredTransaction, blockNum uint64) *fab.TransactionInfo {
+	tx := &fab.TransactionInfo{
+		TxID:           ftx.Txid,
+		ValidationCode: ftx.TxValidationCode,
+		BlockNumber:    blockNum,
+	}
+	if actions := ftx.GetTransactionActions(); actions != nil {
+		for _, action := range actions.ChaincodeActions {
+			if action.ChaincodeEvent != nil {
+				tx.ChaincodeEvent = action.ChaincodeEvent
+				tx.ChaincodeID = action.ChaincodeEvent.ChaincodeId
+				break
+			}
+		}
+	}
+	return tx
+}
+
+func newTransactionInfo(data []byte) (*fab.TransactionInfo, error) {
+	env, err := protoutil.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Envelope from block")
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+
+	tx := &fab.TransactionInfo{TxID: channelHeader.TxId}
+	if channelHeader.Timestamp != nil {
+		if timestamp, err := ptypes.Timestamp(channelHeader.Timestamp); err == nil {
+			tx.Timestamp = timestamp
+		}
+	}
+
+	if signatureHeader, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader); err == nil {
+		if creator, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator); err == nil {
+			tx.CreatorMSPID = creator.Mspid
+		}
+	}
+
+	if cb.HeaderType(channelHeader.Type) == cb.HeaderType_ENDORSER_TRANSACTION {
+		if err := addEndorserTransactionInfo(tx, payload.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+func addEndorserTransactionInfo(tx *fab.TransactionInfo, data []byte) error {
+	transaction, err := protoutil.UnmarshalTransaction(data)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	if len(transaction.Actions) == 0 {
+		return nil
+	}
+
+	ccActionPayload, err := protoutil.UnmarshalChaincodeActionPayload(transaction.Actions[0].Payload)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+
+	if proposalPayload, err := protoutil.UnmarshalChaincodeProposalPayload(ccActionPayload.ChaincodeProposalPayload); err == nil {
+		if spec, err := protoutil.UnmarshalChaincodeInvocationSpec(proposalPayload.Input); err == nil && spec.ChaincodeSpec != nil {
+			if spec.ChaincodeSpec.ChaincodeId != nil {
+				tx.ChaincodeID = spec.ChaincodeSpec.ChaincodeId.Name
+			}
+			if spec.ChaincodeSpec.Input != nil && len(spec.ChaincodeSpec.Input.Args) > 0 {
+				tx.FunctionName = string(spec.ChaincodeSpec.Input.Args[0])
+			}
+		}
+	}
+
+	if ccActionPayload.Action == nil {
+		return nil
+	}
+	propRespPayload, err := protoutil.UnmarshalProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling response payload")
+	}
+	ccAction, err := protoutil.UnmarshalChaincodeAction(propRespPayload.Extension)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+	ccEvent, err := protoutil.UnmarshalChaincodeEvents(ccAction.Events)
+	if err != nil {
+		return errors.Wrap(err, "error getting chaincode events")
+	}
+	if ccEvent != nil && ccEvent.ChaincodeId != "" {
+		tx.ChaincodeEvent = ccEvent
+	}
+
+	return nil
+}
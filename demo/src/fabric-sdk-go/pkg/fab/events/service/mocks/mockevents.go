@@ -7,8 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 )
 
@@ -44,6 +48,10 @@ type TxInfo struct {
 	ChaincodeID      string
 	EventName        string
 	Payload          []byte
+	// CreatorMSPID, FunctionName and Timestamp are optional
+	CreatorMSPID string
+	FunctionName string
+	Timestamp    time.Time
 }
 
 // NewTransaction creates a new transaction
@@ -107,7 +115,7 @@ func NewFilteredTxWithCCEvent(txID, ccID, event string) *pb.FilteredTransaction
 
 func newEnvelope(channelID string, txInfo *TxInfo) *cb.Envelope {
 	tx := &pb.Transaction{
-		Actions: []*pb.TransactionAction{newTxAction(txInfo.TxID, txInfo.ChaincodeID, txInfo.EventName, txInfo.Payload)},
+		Actions: []*pb.TransactionAction{newTxAction(txInfo)},
 	}
 	txBytes, err := proto.Marshal(tx)
 	if err != nil {
@@ -119,14 +127,31 @@ func newEnvelope(channelID string, txInfo *TxInfo) *cb.Envelope {
 		TxId:      txInfo.TxID,
 		Type:      int32(txInfo.HeaderType),
 	}
+	if !txInfo.Timestamp.IsZero() {
+		timestamp, err := ptypes.TimestampProto(txInfo.Timestamp)
+		if err != nil {
+			panic(err)
+		}
+		channelHeader.Timestamp = timestamp
+	}
 	channelHeaderBytes, err := proto.Marshal(channelHeader)
 	if err != nil {
 		panic(err)
 	}
 
+	creatorBytes, err := proto.Marshal(&msp.SerializedIdentity{Mspid: txInfo.CreatorMSPID})
+	if err != nil {
+		panic(err)
+	}
+	signatureHeaderBytes, err := proto.Marshal(&cb.SignatureHeader{Creator: creatorBytes})
+	if err != nil {
+		panic(err)
+	}
+
 	payload := &cb.Payload{
 		Header: &cb.Header{
-			ChannelHeader: channelHeaderBytes,
+			ChannelHeader:   channelHeaderBytes,
+			SignatureHeader: signatureHeaderBytes,
 		},
 		Data: txBytes,
 	}
@@ -140,12 +165,13 @@ func newEnvelope(channelID string, txInfo *TxInfo) *cb.Envelope {
 	}
 }
 
-func newTxAction(txID string, ccID string, eventName string, payload []byte) *pb.TransactionAction {
+func newTxAction(txInfo *TxInfo) *pb.TransactionAction {
+	ccID := txInfo.ChaincodeID
 	ccEvent := &pb.ChaincodeEvent{
-		TxId:        txID,
+		TxId:        txInfo.TxID,
 		ChaincodeId: ccID,
-		EventName:   eventName,
-		Payload:     payload,
+		EventName:   txInfo.EventName,
+		Payload:     txInfo.Payload,
 	}
 	eventBytes, err := proto.Marshal(ccEvent)
 	if err != nil {
@@ -173,6 +199,7 @@ func newTxAction(txID string, ccID string, eventName string, payload []byte) *pb
 	}
 
 	cap := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: newChaincodeProposalPayload(ccID, txInfo.FunctionName),
 		Action: &pb.ChaincodeEndorsedAction{
 			ProposalResponsePayload: prpBytes,
 		},
@@ -187,3 +214,25 @@ func newTxAction(txID string, ccID string, eventName string, payload []byte) *pb
 		Header:  nil,
 	}
 }
+
+func newChaincodeProposalPayload(ccID string, functionName string) []byte {
+	if functionName == "" {
+		return nil
+	}
+
+	specBytes, err := proto.Marshal(&pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: ccID},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(functionName)}},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	payloadBytes, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: specBytes})
+	if err != nil {
+		panic(err)
+	}
+	return payloadBytes
+}
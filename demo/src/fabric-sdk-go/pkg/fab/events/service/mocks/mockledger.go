@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 )
 
@@ -39,6 +40,15 @@ var BlockEventFactory = func(block Block, sourceURL string) BlockEvent {
 	return &fab.BlockEvent{Block: b.Block(), SourceURL: sourceURL}
 }
 
+// BlockAndPrivateDataEventFactory creates block and private data events
+var BlockAndPrivateDataEventFactory = func(block Block, sourceURL string) BlockEvent {
+	b, ok := block.(*BlockWrapper)
+	if !ok {
+		panic(fmt.Sprintf("Invalid block type: %T", block))
+	}
+	return &fab.BlockAndPrivateDataEvent{Block: b.Block(), PrivateDataMap: make(map[uint64]*rwset.TxPvtReadWriteSet), SourceURL: sourceURL}
+}
+
 // FilteredBlockEventFactory creates filtered block events
 var FilteredBlockEventFactory = func(block Block, sourceURL string) BlockEvent {
 	b, ok := block.(*FilteredBlockWrapper)
@@ -190,14 +190,51 @@ func (s *Service) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registratio
 	}
 }
 
+// RegisterBlockAndPrivateDataEvent registers for block events which include the private data of each transaction.
+// If the client is not authorized to receive block and private data events then an error is returned.
+func (s *Service) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	eventch := make(chan *fab.BlockAndPrivateDataEvent, s.eventConsumerBufferSize)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	blockFilter := blockfilter.AcceptAny
+	if len(filter) > 1 {
+		return nil, nil, errors.New("only one block filter may be specified")
+	}
+
+	if len(filter) == 1 {
+		blockFilter = filter[0]
+	}
+
+	if err := s.Submit(dispatcher.NewRegisterBlockAndPrivateDataEvent(blockFilter, eventch, regch, errch)); err != nil {
+		return nil, nil, errors.WithMessage(err, "error registering for block and private data events")
+	}
+
+	select {
+	case response := <-regch:
+		return response, eventch, nil
+	case err := <-errch:
+		return nil, nil, err
+	}
+}
+
 // RegisterFilteredBlockEvent registers for filtered block events. If the client is not authorized to receive
 // filtered block events then an error is returned.
-func (s *Service) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+func (s *Service) RegisterFilteredBlockEvent(filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	if len(filter) > 1 {
+		return nil, nil, errors.New("only one filtered block filter may be specified")
+	}
+
 	eventch := make(chan *fab.FilteredBlockEvent, s.eventConsumerBufferSize)
 	regch := make(chan fab.Registration)
 	errch := make(chan error)
 
-	if err := s.Submit(dispatcher.NewRegisterFilteredBlockEvent(eventch, regch, errch)); err != nil {
+	event := dispatcher.NewRegisterFilteredBlockEvent(eventch, regch, errch)
+	if len(filter) == 1 {
+		event.Reg.Filter = filter[0]
+	}
+
+	if err := s.Submit(event); err != nil {
 		return nil, nil, errors.WithMessage(err, "error registering for filtered block events")
 	}
 
@@ -213,19 +250,28 @@ func (s *Service) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.Fi
 // chaincode events then an error is returned.
 // - ccID is the chaincode ID for which events are to be received
 // - eventFilter is the chaincode event name for which events are to be received
-func (s *Service) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+// - filter is an optional filter on the transaction that emitted the event
+func (s *Service) RegisterChaincodeEvent(ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
 	if ccID == "" {
 		return nil, nil, errors.New("chaincode ID is required")
 	}
 	if eventFilter == "" {
 		return nil, nil, errors.New("event filter is required")
 	}
+	if len(filter) > 1 {
+		return nil, nil, errors.New("only one transaction filter may be specified")
+	}
 
 	eventch := make(chan *fab.CCEvent, s.eventConsumerBufferSize)
 	regch := make(chan fab.Registration)
 	errch := make(chan error)
 
-	if err := s.Submit(dispatcher.NewRegisterChaincodeEvent(ccID, eventFilter, eventch, regch, errch)); err != nil {
+	event := dispatcher.NewRegisterChaincodeEvent(ccID, eventFilter, eventch, regch, errch)
+	if len(filter) == 1 {
+		event.Reg.TxFilter = filter[0]
+	}
+
+	if err := s.Submit(event); err != nil {
 		return nil, nil, errors.WithMessage(err, "error registering for chaincode events")
 	}
 
@@ -268,3 +314,15 @@ func (s *Service) Unregister(reg fab.Registration) {
 		logger.Warnf("Error unregistering: %s", err)
 	}
 }
+
+// SetDropHandler sets a handler that is invoked when an event for the given registration is dropped
+// because the consumer did not receive it within the event consumer timeout. The handler is invoked
+// from the dispatcher's goroutine and must not block.
+// - reg is the registration handle that was returned from one of the RegisterXXX functions
+func (s *Service) SetDropHandler(reg fab.Registration, handler func(event interface{}, reason error)) error {
+	errch := make(chan error)
+	if err := s.Submit(dispatcher.NewSetDropHandlerEvent(reg, handler, errch)); err != nil {
+		return errors.WithMessage(err, "error setting drop handler")
+	}
+	return <-errch
+}
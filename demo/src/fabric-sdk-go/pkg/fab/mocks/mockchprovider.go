@@ -29,6 +29,7 @@ type MockChannelService struct {
 	discovery    fab.DiscoveryService
 	selection    fab.SelectionService
 	membership   fab.ChannelMembership
+	eventService fab.EventService
 }
 
 // NewMockChannelProvider returns a mock ChannelProvider
@@ -69,9 +70,17 @@ func (cs *MockChannelService) SetOrderers(orderers []string) {
 
 // EventService returns a mock event service
 func (cs *MockChannelService) EventService(opts ...options.Opt) (fab.EventService, error) {
+	if cs.eventService != nil {
+		return cs.eventService, nil
+	}
 	return NewMockEventService(), nil
 }
 
+// SetEventService sets the event service returned by EventService for unit-test purposes
+func (cs *MockChannelService) SetEventService(eventService fab.EventService) {
+	cs.eventService = eventService
+}
+
 // SetTransactor changes the return value of Transactor
 func (cs *MockChannelService) SetTransactor(t fab.Transactor) {
 	cs.transactor = t
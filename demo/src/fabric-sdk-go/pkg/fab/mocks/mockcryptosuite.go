@@ -35,7 +35,8 @@ func (m *MockCryptoSuite) GetKey(ski []byte) (k core.Key, err error) {
 
 // Hash mock hash
 func (m *MockCryptoSuite) Hash(msg []byte, opts core.HashOpts) (hash []byte, err error) {
-	return nil, nil
+	digest := sha256.Sum256(msg)
+	return digest[:], nil
 }
 
 // GetHash mock get hash
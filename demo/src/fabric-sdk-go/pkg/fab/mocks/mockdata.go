@@ -38,7 +38,7 @@ func NewSimpleMockBlock() *common.Block {
 			Number:       1,
 		},
 		Metadata: &common.BlockMetadata{
-			Metadata: [][]byte{[]byte("test")},
+			Metadata: [][]byte{[]byte("test"), {}, txflags.NewWithValues(1, pp.TxValidationCode_VALID), {}, {}},
 		},
 	}
 }
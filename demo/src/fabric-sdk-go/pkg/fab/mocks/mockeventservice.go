@@ -37,8 +37,17 @@ func (m *MockEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Re
 	return reg, eventCh, nil
 }
 
+// RegisterBlockAndPrivateDataEvent registers for block and private data events.
+func (m *MockEventService) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	eventCh := make(chan *fab.BlockAndPrivateDataEvent)
+	reg := &dispatcher.BlockAndPrivateDataReg{
+		Eventch: eventCh,
+	}
+	return reg, eventCh, nil
+}
+
 // RegisterFilteredBlockEvent registers for filtered block events.
-func (m *MockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+func (m *MockEventService) RegisterFilteredBlockEvent(filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
 	eventCh := make(chan *fab.FilteredBlockEvent)
 	reg := &dispatcher.FilteredBlockReg{
 		Eventch: eventCh,
@@ -47,7 +56,7 @@ func (m *MockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-cha
 }
 
 // RegisterChaincodeEvent registers for chaincode events.
-func (m *MockEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+func (m *MockEventService) RegisterChaincodeEvent(ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
 	eventCh := make(chan *fab.CCEvent)
 	reg := &dispatcher.ChaincodeReg{
 		Eventch:     eventCh,
@@ -6,11 +6,20 @@ SPDX-License-Identifier: Apache-2.0
 
 package mocks
 
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
 // MockMembership mock member id
 type MockMembership struct {
 	ValidateErr error
 	VerifyErr   error
-	excludeMSPs []string
+	// SatisfiesPrincipalErr, if set, is returned from SatisfiesPrincipal. Otherwise an identity
+	// satisfies a role principal if its MSP ID matches the MSP ID of the principal.
+	SatisfiesPrincipalErr error
+	excludeMSPs           []string
 }
 
 // NewMockMembership new mock member id
@@ -42,3 +51,29 @@ func (m *MockMembership) ContainsMSP(msp string) bool {
 	}
 	return true
 }
+
+// SatisfiesPrincipal mocks membership.SatisfiesPrincipal
+func (m *MockMembership) SatisfiesPrincipal(serializedID []byte, principal *msp.MSPPrincipal) error {
+	if m.SatisfiesPrincipalErr != nil {
+		return m.SatisfiesPrincipalErr
+	}
+
+	id := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, id); err != nil {
+		return err
+	}
+
+	if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+		return errors.Errorf("unsupported principal classification: %s", principal.PrincipalClassification)
+	}
+
+	role := &msp.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+
+	if id.Mspid != role.MspIdentifier {
+		return errors.Errorf("identity of MSP [%s] does not satisfy principal of MSP [%s]", id.Mspid, role.MspIdentifier)
+	}
+	return nil
+}
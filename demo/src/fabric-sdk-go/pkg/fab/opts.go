@@ -12,7 +12,9 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/gmtls"
 	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 )
 
@@ -32,6 +34,44 @@ type EndpointConfigOptions struct {
 	tlsCACertPool
 	tlsClientCerts
 	cryptoConfigPath
+	secretProvider secretProvider
+	gmTLSConfig    gmTLSConfig
+}
+
+// SecretProvider returns the secret provider of the overriding option, if any.
+// Unlike the EndpointConfig functions, overriding it is optional.
+func (c *EndpointConfigOptions) SecretProvider() core.SecretProvider {
+	if c.secretProvider == nil {
+		return nil
+	}
+	return c.secretProvider.SecretProvider()
+}
+
+// TLSType returns the TLS type of the overriding option, if any.
+// Unlike the EndpointConfig functions, overriding it is optional.
+func (c *EndpointConfigOptions) TLSType() string {
+	if c.gmTLSConfig == nil {
+		return ""
+	}
+	return c.gmTLSConfig.TLSType()
+}
+
+// GMTLSClientCert returns the GM/T TLS client cert of the overriding option, if any.
+// Unlike the EndpointConfig functions, overriding it is optional.
+func (c *EndpointConfigOptions) GMTLSClientCert() *gmtls.Certificate {
+	if c.gmTLSConfig == nil {
+		return nil
+	}
+	return c.gmTLSConfig.GMTLSClientCert()
+}
+
+// GMTLSClientEncCert returns the GM/T TLS client encryption cert of the overriding option, if any.
+// Unlike the EndpointConfig functions, overriding it is optional.
+func (c *EndpointConfigOptions) GMTLSClientEncCert() *gmtls.Certificate {
+	if c.gmTLSConfig == nil {
+		return nil
+	}
+	return c.gmTLSConfig.GMTLSClientEncCert()
 }
 
 type applier func()
@@ -103,6 +143,19 @@ type cryptoConfigPath interface {
 	CryptoConfigPath() string
 }
 
+// secretProvider interface allows to uniquely override EndpointConfig's SecretProvider() function
+type secretProvider interface {
+	SecretProvider() core.SecretProvider
+}
+
+// gmTLSConfig interface allows to uniquely override EndpointConfig's TLSType(), GMTLSClientCert() and
+// GMTLSClientEncCert() functions
+type gmTLSConfig interface {
+	TLSType() string
+	GMTLSClientCert() *gmtls.Certificate
+	GMTLSClientEncCert() *gmtls.Certificate
+}
+
 // BuildConfigEndpointFromOptions will return an EndpointConfig instance pre-built with Optional interfaces
 // provided in fabsdk's WithEndpointConfig(opts...) call
 func BuildConfigEndpointFromOptions(opts ...interface{}) (fab.EndpointConfig, error) {
@@ -137,6 +190,12 @@ func UpdateMissingOptsWithDefaultConfig(c *EndpointConfigOptions, d fab.Endpoint
 	s.set(c.tlsCACertPool, nil, func() { c.tlsCACertPool = d })
 	s.set(c.tlsClientCerts, nil, func() { c.tlsClientCerts = d })
 	s.set(c.cryptoConfigPath, nil, func() { c.cryptoConfigPath = d })
+	if p, ok := d.(secretProvider); ok {
+		s.set(c.secretProvider, nil, func() { c.secretProvider = p })
+	}
+	if g, ok := d.(gmTLSConfig); ok {
+		s.set(c.gmTLSConfig, nil, func() { c.gmTLSConfig = g })
+	}
 
 	return c
 }
@@ -165,6 +224,8 @@ func setEndpointConfigWithOptionInterface(c *EndpointConfigOptions, o interface{
 	s.set(c.tlsCACertPool, func() bool { _, ok := o.(tlsCACertPool); return ok }, func() { c.tlsCACertPool = o.(tlsCACertPool) })
 	s.set(c.tlsClientCerts, func() bool { _, ok := o.(tlsClientCerts); return ok }, func() { c.tlsClientCerts = o.(tlsClientCerts) })
 	s.set(c.cryptoConfigPath, func() bool { _, ok := o.(cryptoConfigPath); return ok }, func() { c.cryptoConfigPath = o.(cryptoConfigPath) })
+	s.set(c.secretProvider, func() bool { _, ok := o.(secretProvider); return ok }, func() { c.secretProvider = o.(secretProvider) })
+	s.set(c.gmTLSConfig, func() bool { _, ok := o.(gmTLSConfig); return ok }, func() { c.gmTLSConfig = o.(gmTLSConfig) })
 
 	if !s.isSet {
 		return errors.Errorf("option %#v is not a sub interface of EndpointConfig, at least one of its functions must be implemented.", o)
@@ -7,9 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package signingmgr
 
 import (
-	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
-	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/pkg/errors"
@@ -42,10 +40,10 @@ func (mgr *SigningManager) Sign(object []byte, key core.Key) ([]byte, error) {
 	}
 
 	digest, err := mgr.cryptoProvider.Hash(object, mgr.hashOpts)
-	digest = hx509.WrapHashResult(mgr.cryptoProvider.(*wrapper.CryptoSuite).BCCSP, object, digest)
 	if err != nil {
 		return nil, err
 	}
+	digest = cryptosuite.PrepareKeySignatureDigest(mgr.cryptoProvider, key, object, digest)
 	signature, err := mgr.cryptoProvider.Sign(key, digest, mgr.signerOpts)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"crypto/ecdsa"
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hfactory"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+// SignatureScheme is the signature scheme of the key that signs a proposal or transaction
+type SignatureScheme string
+
+const (
+	// ECDSA signatures are computed over the digest of the message (as produced by the crypto suite's hash function).
+	// The signature must be ASN.1 encoded and must have a low S value.
+	ECDSA SignatureScheme = "ECDSA"
+
+	// SM2 signatures are computed over e = SM3(Z || M), where Z is derived from the signer's public key and the
+	// default user ID (1234567812345678). The signature must be ASN.1 encoded.
+	SM2 SignatureScheme = "SM2"
+)
+
+// SigningDigest returns the digest of the given message that must be signed by the private key of the given creator
+// (serialized identity) along with the signature scheme of the creator's key. This allows a proposal or transaction
+// envelope to be signed by an external signer that only signs digests.
+func SigningDigest(cs core.CryptoSuite, creator []byte, msg []byte) ([]byte, SignatureScheme, error) {
+	pubKey, err := publicKeyFromCreator(creator)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if isSM2Key(pubKey) {
+		za, err := sm2.ZA(&sm2.PublicKey{Curve: pubKey.Curve, X: pubKey.X, Y: pubKey.Y}, []byte(hfactory.SM2UserID))
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to compute SM2 Z value")
+		}
+		return sm3.Sm3Sum(append(za, msg...)), SM2, nil
+	}
+
+	digest, err := cs.Hash(msg, cryptosuite.GetSHAOpts())
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to compute digest")
+	}
+	return digest, ECDSA, nil
+}
+
+// NormalizeSignature prepares a signature that was produced by an external signer for submission. ECDSA signatures
+// are converted to low S form, which is required by Fabric. SM2 signatures are returned as is.
+func NormalizeSignature(creator []byte, signature []byte) ([]byte, error) {
+	if len(signature) == 0 {
+		return nil, errors.New("signature is required")
+	}
+
+	pubKey, err := publicKeyFromCreator(creator)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSM2Key(pubKey) {
+		return signature, nil
+	}
+
+	lowS, err := utils.SignatureToLowS(pubKey, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ECDSA signature")
+	}
+	return lowS, nil
+}
+
+func publicKeyFromCreator(creator []byte) (*ecdsa.PublicKey, error) {
+	id := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, id); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal creator")
+	}
+
+	block, _ := pem.Decode(id.IdBytes)
+	if block == nil {
+		return nil, errors.New("creator does not contain a PEM encoded certificate")
+	}
+
+	cert, err := hx509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse creator certificate")
+	}
+
+	pubKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("unsupported public key type: %T", cert.PublicKey)
+	}
+	return pubKey, nil
+}
+
+func isSM2Key(pubKey *ecdsa.PublicKey) bool {
+	return pubKey.Curve.Params() == sm2.P256Sm2().Params()
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hfactory"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func TestSigningDigestECDSA(t *testing.T) {
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := newCertTemplate()
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	creator := newCreator(t, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+
+	msg := []byte("message to sign")
+	digest, scheme, err := SigningDigest(cs, creator, msg)
+	require.NoError(t, err)
+	assert.Equal(t, ECDSA, scheme)
+
+	expected := sha256.Sum256(msg)
+	assert.Equal(t, expected[:], digest)
+
+	// Produce a high-S signature and make sure that it's normalized
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	require.NoError(t, err)
+	isLowS, err := utils.IsLowS(&key.PublicKey, s)
+	require.NoError(t, err)
+	if isLowS {
+		s = new(big.Int).Sub(key.Params().N, s)
+	}
+	signature, err := utils.MarshalECDSASignature(r, s)
+	require.NoError(t, err)
+
+	normalized, err := NormalizeSignature(creator, signature)
+	require.NoError(t, err)
+	_, s, err = utils.UnmarshalECDSASignature(normalized)
+	require.NoError(t, err)
+	isLowS, err = utils.IsLowS(&key.PublicKey, s)
+	require.NoError(t, err)
+	assert.True(t, isLowS)
+	assert.True(t, ecdsa.Verify(&key.PublicKey, digest, r, s))
+
+	_, err = NormalizeSignature(creator, []byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestSigningDigestSM2(t *testing.T) {
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	key, err := sm2.GenerateKey()
+	require.NoError(t, err)
+
+	template := &sm2.Certificate{
+		SerialNumber:       big.NewInt(1),
+		Subject:            pkix.Name{CommonName: "user1"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: sm2.SM2WithSM3,
+	}
+	certPEM, err := sm2.CreateCertificateToMem(template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	creator := newCreator(t, certPEM)
+
+	msg := []byte("message to sign")
+	digest, scheme, err := SigningDigest(cs, creator, msg)
+	require.NoError(t, err)
+	assert.Equal(t, SM2, scheme)
+
+	// A signature over the digest must be verifiable as an SM2 signature over the message
+	r, s, err := sm2.Sign(key, digest)
+	require.NoError(t, err)
+	assert.True(t, sm2.Sm2Verify(&key.PublicKey, msg, []byte(hfactory.SM2UserID), r, s))
+
+	signature, err := sm2.SignDigitToSignData(r, s)
+	require.NoError(t, err)
+	normalized, err := NormalizeSignature(creator, signature)
+	require.NoError(t, err)
+	assert.Equal(t, signature, normalized)
+}
+
+func TestSigningDigestInvalidCreator(t *testing.T) {
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	_, _, err = SigningDigest(cs, []byte("invalid"), []byte("msg"))
+	assert.Error(t, err)
+
+	_, _, err = SigningDigest(cs, newCreator(t, []byte("not a cert")), []byte("msg"))
+	assert.Error(t, err)
+}
+
+func newCertTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+}
+
+func newCreator(t *testing.T, certPEM []byte) []byte {
+	creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: certPEM})
+	require.NoError(t, err)
+	return creator
+}
@@ -94,8 +94,6 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 		}
 	}
 
-	targets = getTargetsWithoutDuplicates(targets)
-
 	ctx, ok := context.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signProposal")
@@ -105,6 +103,28 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 		return nil, errors.WithMessage(err, "sign proposal failed")
 	}
 
+	return SendSignedProposal(reqCtx, signedProposal, targets)
+}
+
+// SendSignedProposal sends a proposal that was signed by an external signer to the ProposalProcessors.
+func SendSignedProposal(reqCtx reqContext.Context, signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+
+	if signedProposal == nil {
+		return nil, errors.New("signed proposal is required")
+	}
+
+	if len(targets) < 1 {
+		return nil, errors.New("targets is required")
+	}
+
+	for _, p := range targets {
+		if p == nil {
+			return nil, errors.New("target is nil")
+		}
+	}
+
+	targets = getTargetsWithoutDuplicates(targets)
+
 	request := fab.ProcessProposalRequest{SignedProposal: signedProposal}
 
 	var responseMtx sync.Mutex
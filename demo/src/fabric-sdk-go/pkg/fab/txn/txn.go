@@ -106,6 +106,22 @@ func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers is nil")
 	}
+
+	payload, err := CreateTransactionPayload(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionResponse, err := BroadcastPayload(reqCtx, payload, orderers)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionResponse, nil
+}
+
+// CreateTransactionPayload creates the (unsigned) payload of the envelope that's sent to the orderer for the given transaction.
+func CreateTransactionPayload(tx *fab.Transaction) (*common.Payload, error) {
 	if tx == nil {
 		return nil, errors.New("transaction is nil")
 	}
@@ -124,15 +140,7 @@ func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer
 		return nil, err
 	}
 
-	// create the payload
-	payload := common.Payload{Header: hdr, Data: txBytes}
-
-	transactionResponse, err := BroadcastPayload(reqCtx, &payload, orderers)
-	if err != nil {
-		return nil, err
-	}
-
-	return transactionResponse, nil
+	return &common.Payload{Header: hdr, Data: txBytes}, nil
 }
 
 // BroadcastPayload will send the given payload to some orderer, picking random endpoints
@@ -155,6 +163,15 @@ func BroadcastPayload(reqCtx reqContext.Context, payload *common.Payload, ordere
 	return broadcastEnvelope(reqCtx, envelope, orderers)
 }
 
+// BroadcastEnvelope will send the given envelope, which was signed by an external signer, to some orderer,
+// picking random endpoints until all are exhausted
+func BroadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	if envelope == nil {
+		return nil, errors.New("envelope is nil")
+	}
+	return broadcastEnvelope(reqCtx, envelope, orderers)
+}
+
 // broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
 // until all are exhausted
 func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
@@ -61,6 +61,7 @@ type options struct {
 	metricsConfig     metricsCfg.MetricsConfig
 	user              string
 	orgid             string
+	secretProvider    core.SecretProvider
 }
 
 // Option configures the SDK.
@@ -237,6 +238,16 @@ func WithUserName(user string) Option {
 	}
 }
 
+// WithSecretProvider sets the provider of the key passwords and TLS client keys of this SDK instance
+// (see package pkg/core/secret). It replaces apiKey.SetUserPassword and fab.SetTlsClientKey, which are
+// shared by all the SDK instances of the process.
+func WithSecretProvider(secretProvider core.SecretProvider) Option {
+	return func(opts *options) error {
+		opts.secretProvider = secretProvider
+		return nil
+	}
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -486,7 +497,7 @@ func (sdk *FabricSDK) loadEndpointConfig(configBackend ...core.ConfigBackend) (f
 	// if optional endpoint was nil or not all of its sub interface functions were overridden,
 	// then get default endpoint config and override the functions that were not overridden by opts
 	if sdk.opts.endpointConfig == nil || (ok && !fabImpl.IsEndpointConfigFullyOverridden(endpointConfigOpt)) {
-		defEndpointConfig, err := fabImpl.NewConfigFromBackend(sdk.opts.user, sdk.opts.orgid, configBackend...)
+		defEndpointConfig, err := fabImpl.NewConfigFromBackendWithSecretProvider(sdk.opts.user, sdk.opts.orgid, sdk.opts.secretProvider, configBackend...)
 		if err != nil {
 			return nil, errors.WithMessage(err, "failed to initialize endpoint config from config backend")
 		}
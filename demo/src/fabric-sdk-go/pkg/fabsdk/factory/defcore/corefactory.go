@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 
+	cryptosuiteimplHybrid "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/hybrid"
 	cryptosuiteimplSm "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sm"
 	cryptosuiteimplSw "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
 	signingMgr "github.com/hyperledger/fabric-sdk-go/pkg/fab/signingmgr"
@@ -45,7 +46,10 @@ func (f *ProviderFactory) CreateCryptoSuiteProvider(config core.CryptoSuiteConfi
 	default:
 		return nil, errors.New("Unsupported BCCSP Provider")
 	}
-	return cryptoSuiteProvider, err
+	if err != nil {
+		return nil, err
+	}
+	return cryptosuiteimplHybrid.GetSuiteByConfig(config, cryptoSuiteProvider)
 }
 
 // CreateSigningManager returns a new default implementation of signing manager
@@ -9,7 +9,7 @@ package metrics
 import "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
 
 var (
-	// for now, only channel clients require metrics tracking. TODO: update to generalize metrics for other client types if needed.
+	// for now, only channel and event clients require metrics tracking. TODO: update to generalize metrics for other client types if needed.
 	queriesReceived = metrics.CounterOpts{
 		Namespace:    "channel",
 		Name:         "queries_received",
@@ -66,6 +66,27 @@ var (
 		LabelNames:   []string{"chaincode", "Fcn"},
 		StatsdFormat: "%{#fqname}.%{type}.%{channel}.%{execution}",
 	}
+	eventsQueued = metrics.CounterOpts{
+		Namespace:    "event",
+		Name:         "events_queued",
+		Help:         "The number of events queued for delivery to event registrations.",
+		LabelNames:   []string{"channel", "type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{type}",
+	}
+	eventsDelivered = metrics.CounterOpts{
+		Namespace:    "event",
+		Name:         "events_delivered",
+		Help:         "The number of queued events delivered to event registrations.",
+		LabelNames:   []string{"channel", "type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{type}",
+	}
+	eventsDropped = metrics.CounterOpts{
+		Namespace:    "event",
+		Name:         "events_dropped",
+		Help:         "The number of events dropped because an event registration's buffer was full.",
+		LabelNames:   []string{"channel", "type"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{type}",
+	}
 )
 
 // ClientMetrics contains the metrics used in the (channel) client
@@ -78,6 +99,9 @@ type ClientMetrics struct {
 	ExecutionsFailed   metrics.Counter
 	ExecutionDuration  metrics.Histogram
 	ExecutionTimeouts  metrics.Counter
+	EventsQueued       metrics.Counter
+	EventsDelivered    metrics.Counter
+	EventsDropped      metrics.Counter
 }
 
 // NewClientMetrics builds a new instance of ClientMetrics
@@ -91,5 +115,8 @@ func NewClientMetrics(p metrics.Provider) *ClientMetrics {
 		ExecutionsFailed:   p.NewCounter(executionsFailed),
 		ExecutionDuration:  p.NewHistogram(executionDuration),
 		ExecutionTimeouts:  p.NewCounter(executionTimeouts),
+		EventsQueued:       p.NewCounter(eventsQueued),
+		EventsDelivered:    p.NewCounter(eventsDelivered),
+		EventsDropped:      p.NewCounter(eventsDropped),
 	}
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 )
 
 // ctxtCacheKey is a lazy cache key for the context cache
@@ -67,6 +68,7 @@ type eventCacheKey struct {
 	key           string
 	channelConfig fab.ChannelCfg
 	opts          []options.Opt
+	dedicated     bool
 }
 
 // newEventCacheKey returns a new eventCacheKey
@@ -84,6 +86,7 @@ func newEventCacheKey(chConfig fab.ChannelCfg, opts ...options.Opt) (*eventCache
 		channelConfig: chConfig,
 		key:           string(hash),
 		opts:          opts,
+		dedicated:     params.isDedicated(),
 	}, nil
 }
 
@@ -99,6 +102,11 @@ func (k *eventCacheKey) String() string {
 
 type params struct {
 	permitBlockEvents bool
+	permitPvtData     bool
+	seekType          seek.Type
+	fromBlock         uint64
+	stopBlock         uint64
+	hasStopBlock      bool
 }
 
 func defaultParams() *params {
@@ -109,8 +117,45 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) PermitBlockAndPrivateDataEvents() {
+	p.permitBlockEvents = true
+	p.permitPvtData = true
+}
+
+func (p *params) SetSeekType(value seek.Type) {
+	p.seekType = value
+}
+
+func (p *params) SetFromBlock(value uint64) {
+	p.fromBlock = value
+}
+
+func (p *params) SetStopBlockNum(value uint64) {
+	p.stopBlock = value
+	p.hasStopBlock = true
+}
+
+// isDedicated returns true if the event client replays blocks from a given position or stops at a given block.
+// Such an event client may not be shared since other users would miss the blocks that have already been delivered.
+func (p *params) isDedicated() bool {
+	return p.hasStopBlock || (p.seekType != "" && p.seekType != seek.Newest)
+}
+
 func (p *params) getOptKey() string {
 	//	Construct opts portion
 	optKey := "blockEvents:" + strconv.FormatBool(p.permitBlockEvents)
+	if p.permitPvtData {
+		optKey += ",pvtData:true"
+	}
+	// Event clients that replay from a given position must not be shared with live event clients
+	if p.seekType != "" {
+		optKey += ",seekType:" + string(p.seekType)
+		if p.seekType == seek.FromBlock {
+			optKey += ",fromBlock:" + strconv.FormatUint(p.fromBlock, 10)
+		}
+	}
+	if p.hasStopBlock {
+		optKey += ",stopBlock:" + strconv.FormatUint(p.stopBlock, 10)
+	}
 	return optKey
 }
@@ -24,6 +24,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/pkg/errors"
@@ -382,6 +385,97 @@ func TestSelectionAccessDenied(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestEventCacheKey(t *testing.T) {
+	chConfig := chconfig.NewChannelCfg("mychannel")
+
+	live, err := newEventCacheKey(chConfig, client.WithBlockEvents())
+	require.NoError(t, err)
+	live2, err := newEventCacheKey(chConfig, client.WithBlockEvents())
+	require.NoError(t, err)
+	assert.Equal(t, live.String(), live2.String())
+
+	from10, err := newEventCacheKey(chConfig, client.WithBlockEvents(), deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10))
+	require.NoError(t, err)
+	assert.NotEqual(t, live.String(), from10.String())
+
+	from20, err := newEventCacheKey(chConfig, client.WithBlockEvents(), deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(20))
+	require.NoError(t, err)
+	assert.NotEqual(t, from10.String(), from20.String())
+}
+
+func TestClosedEventServiceReplaced(t *testing.T) {
+	SetChannelConfig(chconfig.NewChannelCfg("mychannel"))
+
+	channelProvider := getChannelProvider(t, mocks.NewMockProviderContext())
+	defer channelProvider.Close()
+
+	channelService, err := channelProvider.ChannelService(newMockClientContext("user1", "org"), "mychannel")
+	require.NoError(t, err)
+
+	eventService, err := channelService.EventService(client.WithBlockEvents())
+	require.NoError(t, err)
+
+	ref, ok := eventService.(*EventClientRef)
+	require.True(t, ok)
+	ref.Close()
+
+	eventService2, err := channelService.EventService(client.WithBlockEvents())
+	require.NoError(t, err)
+
+	ref2, ok := eventService2.(*EventClientRef)
+	require.True(t, ok)
+	assert.False(t, ref2.Closed())
+	assert.True(t, ref != ref2, "Expecting the closed event client to be replaced")
+}
+
+func TestBoundedEventServiceNotShared(t *testing.T) {
+	SetChannelConfig(chconfig.NewChannelCfg("mychannel"))
+
+	channelProvider := getChannelProvider(t, mocks.NewMockProviderContext())
+	defer channelProvider.Close()
+
+	channelService, err := channelProvider.ChannelService(newMockClientContext("user1", "org"), "mychannel")
+	require.NoError(t, err)
+
+	opts := []options.Opt{deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10), deliverclient.WithStopBlockNum(20)}
+
+	eventService1, err := channelService.EventService(opts...)
+	require.NoError(t, err)
+	eventService2, err := channelService.EventService(opts...)
+	require.NoError(t, err)
+
+	assert.True(t, eventService1 != eventService2, "Expecting event clients with a stop block not to be shared")
+}
+
+func TestReplayEventServiceNotShared(t *testing.T) {
+	SetChannelConfig(chconfig.NewChannelCfg("mychannel"))
+
+	channelProvider := getChannelProvider(t, mocks.NewMockProviderContext())
+	defer channelProvider.Close()
+
+	channelService, err := channelProvider.ChannelService(newMockClientContext("user1", "org"), "mychannel")
+	require.NoError(t, err)
+
+	for _, opts := range [][]options.Opt{
+		{deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10)},
+		{deliverclient.WithSeekType(seek.Oldest)},
+	} {
+		eventService1, err := channelService.EventService(opts...)
+		require.NoError(t, err)
+		eventService2, err := channelService.EventService(opts...)
+		require.NoError(t, err)
+
+		assert.True(t, eventService1 != eventService2, "Expecting event clients that replay blocks not to be shared")
+	}
+
+	eventService1, err := channelService.EventService(deliverclient.WithSeekType(seek.Newest))
+	require.NoError(t, err)
+	eventService2, err := channelService.EventService(deliverclient.WithSeekType(seek.Newest))
+	require.NoError(t, err)
+
+	assert.True(t, eventService1 == eventService2, "Expecting event clients that seek from the newest block to be shared")
+}
+
 func getChannelProvider(t *testing.T, providers context.Providers, opts ...options.Opt) *ChannelProvider {
 	cp, err := New(providers.EndpointConfig(), opts...)
 	require.NoError(t, err)
@@ -22,6 +22,7 @@ import (
 
 type cache interface {
 	Get(lazycache.Key, ...interface{}) (interface{}, error)
+	Delete(lazycache.Key)
 	Close()
 }
 
@@ -39,7 +40,6 @@ var cfgCacheProvider = func(opts ...options.Opt) cache {
 }
 
 func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
-	eventIdleTime := ctx.EndpointConfig().Timeout(fab.EventServiceIdle)
 	chConfigRefresh := ctx.EndpointConfig().Timeout(fab.ChannelConfigRefresh)
 	membershipRefresh := ctx.EndpointConfig().Timeout(fab.ChannelMembershipRefresh)
 
@@ -69,13 +69,7 @@ func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 	c.eventServiceCache = lazycache.New(
 		"Event_Service_Cache",
 		func(key lazycache.Key) (interface{}, error) {
-			ck := key.(*eventCacheKey)
-			return NewEventClientRef(
-				eventIdleTime,
-				func() (fab.EventClient, error) {
-					return c.createEventClient(ck.channelConfig, ck.opts...)
-				},
-			), nil
+			return c.newEventClientRef(key.(*eventCacheKey)), nil
 		},
 	)
 
@@ -99,6 +93,15 @@ func (c *contextCache) Close() {
 	c.discoveryServiceCache.Close()
 }
 
+func (c *contextCache) newEventClientRef(key *eventCacheKey) *EventClientRef {
+	return NewEventClientRef(
+		c.ctx.EndpointConfig().Timeout(fab.EventServiceIdle),
+		func() (fab.EventClient, error) {
+			return c.createEventClient(key.channelConfig, key.opts...)
+		},
+	)
+}
+
 func (c *contextCache) createEventClient(chConfig fab.ChannelCfg, opts ...options.Opt) (fab.EventClient, error) {
 	discovery, err := c.GetDiscoveryService(chConfig.ID())
 	if err != nil {
@@ -170,11 +173,30 @@ func (c *contextCache) GetEventService(channelID string, opts ...options.Opt) (f
 	if err != nil {
 		return nil, err
 	}
+
+	if key.dedicated {
+		// An event client that replays blocks has already moved on by the time another user registers with it,
+		// and an event client with a stop block closes all of its registrations once the stop block has been
+		// delivered, so neither is shared with other users
+		logger.Debugf("Creating dedicated event client for channel [%s]", channelID)
+		return c.newEventClientRef(key), nil
+	}
+
 	eventService, err := c.eventServiceCache.Get(key)
 	if err != nil {
 		return nil, err
 	}
 
+	if ref, ok := eventService.(*EventClientRef); ok && ref.Closed() {
+		// The event client was closed by its user so it is replaced with a new one
+		logger.Debugf("Replacing closed event client for channel [%s]", channelID)
+		c.eventServiceCache.Delete(key)
+		eventService, err = c.eventServiceCache.Get(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return eventService.(fab.EventService), nil
 }
 
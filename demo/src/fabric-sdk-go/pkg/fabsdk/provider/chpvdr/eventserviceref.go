@@ -77,22 +77,31 @@ func (ref *EventClientRef) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Re
 	return service.RegisterBlockEvent(filter...)
 }
 
+// RegisterBlockAndPrivateDataEvent registers for block and private data events.
+func (ref *EventClientRef) RegisterBlockAndPrivateDataEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockAndPrivateDataEvent, error) {
+	service, err := ref.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.RegisterBlockAndPrivateDataEvent(filter...)
+}
+
 // RegisterFilteredBlockEvent registers for filtered block events.
-func (ref *EventClientRef) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+func (ref *EventClientRef) RegisterFilteredBlockEvent(filter ...fab.FilteredBlockFilter) (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
 	}
-	return service.RegisterFilteredBlockEvent()
+	return service.RegisterFilteredBlockEvent(filter...)
 }
 
 // RegisterChaincodeEvent registers for chaincode events.
-func (ref *EventClientRef) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+func (ref *EventClientRef) RegisterChaincodeEvent(ccID, eventFilter string, filter ...fab.TxFilter) (fab.Registration, <-chan *fab.CCEvent, error) {
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
 	}
-	return service.RegisterChaincodeEvent(ccID, eventFilter)
+	return service.RegisterChaincodeEvent(ccID, eventFilter, filter...)
 }
 
 // RegisterTxStatusEvent registers for transaction status events.
@@ -113,6 +122,58 @@ func (ref *EventClientRef) Unregister(reg fab.Registration) {
 	}
 }
 
+// RegisterConnectionLifecycleEvent registers for connection lifecycle events of the event client
+func (ref *EventClientRef) RegisterConnectionLifecycleEvent() (fab.Registration, <-chan *fab.ConnectionLifecycleEvent, error) {
+	service, err := ref.get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, ok := service.(lifecycleEventClient)
+	if !ok {
+		return nil, nil, errors.Errorf("event client %T does not support connection lifecycle events", service)
+	}
+	return client.RegisterConnectionLifecycleEvent()
+}
+
+// Status returns the health of the event client's connection
+func (ref *EventClientRef) Status() (*fab.EventClientStatus, error) {
+	service, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := service.(lifecycleEventClient)
+	if !ok {
+		return nil, errors.Errorf("event client %T does not report status", service)
+	}
+	return client.Status()
+}
+
+// SetDropHandler sets a handler that is invoked when an event for the given registration is dropped
+// because the consumer did not receive it in time
+func (ref *EventClientRef) SetDropHandler(reg fab.Registration, handler func(event interface{}, reason error)) error {
+	service, err := ref.get()
+	if err != nil {
+		return err
+	}
+
+	client, ok := service.(dropReportingClient)
+	if !ok {
+		return errors.Errorf("event client %T does not report dropped events", service)
+	}
+	return client.SetDropHandler(reg, handler)
+}
+
+type dropReportingClient interface {
+	SetDropHandler(reg fab.Registration, handler func(event interface{}, reason error)) error
+}
+
+type lifecycleEventClient interface {
+	RegisterConnectionLifecycleEvent() (fab.Registration, <-chan *fab.ConnectionLifecycleEvent, error)
+	Status() (*fab.EventClientStatus, error)
+}
+
 func (ref *EventClientRef) get() (fab.EventService, error) {
 	if ref.Closed() {
 		return nil, errors.New("event client is closed")
@@ -62,6 +62,11 @@ func (m *chCfgCache) Get(k lazycache.Key, data ...interface{}) (interface{}, err
 func (m *chCfgCache) Close() {
 }
 
+// Delete channel config reference from mock cache
+func (m *chCfgCache) Delete(k lazycache.Key) {
+	m.cfgMap.Delete(k.(chconfig.CacheKey).ChannelID())
+}
+
 // Put channel config reference into mock cache
 func (m *chCfgCache) Put(cfg fab.ChannelCfg) {
 	m.cfgMap.Store(cfg.ID(), newChCfgRef(cfg))
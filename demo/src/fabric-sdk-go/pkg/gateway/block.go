@@ -0,0 +1,242 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/pkg/txflags"
+	"github.com/pkg/errors"
+)
+
+// Block is a decoded block that is delivered to a block listener.
+// The fields that are only available in full blocks are nil for filtered blocks.
+type Block struct {
+	Number       uint64
+	PreviousHash []byte
+	DataHash     []byte
+	Transactions []*BlockTransaction
+	// SourceURL specifies the URL of the peer that produced the event
+	SourceURL string
+}
+
+// BlockTransaction is a decoded transaction within a block
+type BlockTransaction struct {
+	// Index is the position of the transaction within the block
+	Index          int
+	TransactionID  string
+	Type           common.HeaderType
+	ValidationCode peer.TxValidationCode
+	// ChannelHeader is nil for filtered blocks
+	ChannelHeader *common.ChannelHeader
+	// CreatorMSPID is empty for filtered blocks
+	CreatorMSPID string
+	// Actions holds the chaincode actions of an endorser transaction (usually exactly one)
+	Actions []*TransactionAction
+	// PrivateData holds the private data written by the transaction, if any.
+	// It is only populated for PrivateBlockEvents.
+	PrivateData *rwset.TxPvtReadWriteSet
+}
+
+// TransactionAction is a decoded chaincode action of an endorser transaction
+type TransactionAction struct {
+	// InvocationSpec is nil for filtered blocks
+	InvocationSpec *peer.ChaincodeInvocationSpec
+	// ReadWriteSets is nil for filtered blocks
+	ReadWriteSets []*NamespaceReadWriteSet
+	// ChaincodeEvent is nil if the chaincode did not set an event.
+	// The event payload is nil for filtered blocks.
+	ChaincodeEvent *peer.ChaincodeEvent
+}
+
+// NamespaceReadWriteSet is the read/write set of a transaction for one namespace (chaincode)
+type NamespaceReadWriteSet struct {
+	Namespace              string
+	KVReadWriteSet         *kvrwset.KVRWSet
+	CollectionHashedRWSets []*CollectionHashedReadWriteSet
+}
+
+// CollectionHashedReadWriteSet is the hashed read/write set of a transaction for one private data collection
+type CollectionHashedReadWriteSet struct {
+	CollectionName string
+	HashedRWSet    *kvrwset.HashedRWSet
+	PvtRWSetHash   []byte
+}
+
+// newBlock decodes a full block. Transactions that cannot be decoded are logged and
+// delivered with only their index and validation code populated.
+func newBlock(block *common.Block, sourceURL string) *Block {
+	b := &Block{
+		Number:       block.GetHeader().GetNumber(),
+		PreviousHash: block.GetHeader().GetPreviousHash(),
+		DataHash:     block.GetHeader().GetDataHash(),
+		SourceURL:    sourceURL,
+	}
+
+	var txFilter txflags.ValidationFlags
+	if metadata := block.GetMetadata().GetMetadata(); len(metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = txflags.ValidationFlags(metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.GetData().GetData() {
+		tx := &BlockTransaction{Index: i}
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i)
+		}
+		if err := decodeTransaction(tx, data); err != nil {
+			logger.Warnf("Error decoding transaction %d in block %d: %s", i, b.Number, err)
+		}
+		b.Transactions = append(b.Transactions, tx)
+	}
+
+	return b
+}
+
+// newBlockWithPrivateData decodes a block and attaches to each transaction the private data that it wrote
+func newBlockWithPrivateData(block *common.Block, pvtData map[uint64]*rwset.TxPvtReadWriteSet, sourceURL string) *Block {
+	b := newBlock(block, sourceURL)
+	for _, tx := range b.Transactions {
+		tx.PrivateData = pvtData[uint64(tx.Index)]
+	}
+	return b
+}
+
+func decodeTransaction(tx *BlockTransaction, data []byte) error {
+	env, err := protoutil.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return errors.Wrap(err, "error extracting Envelope from block")
+	}
+
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	if payload.Header == nil {
+		return errors.New("missing payload header")
+	}
+
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+	tx.ChannelHeader = channelHeader
+	tx.TransactionID = channelHeader.TxId
+	tx.Type = common.HeaderType(channelHeader.Type)
+
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return errors.Wrap(err, "error extracting SignatureHeader from payload")
+	}
+	creator, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator)
+	if err != nil {
+		return errors.Wrap(err, "error extracting creator from SignatureHeader")
+	}
+	tx.CreatorMSPID = creator.Mspid
+
+	if tx.Type != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil
+	}
+
+	transaction, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+
+	for _, txAction := range transaction.Actions {
+		action, err := decodeTransactionAction(txAction)
+		if err != nil {
+			return err
+		}
+		tx.Actions = append(tx.Actions, action)
+	}
+
+	return nil
+}
+
+func decodeTransactionAction(txAction *peer.TransactionAction) (*TransactionAction, error) {
+	chaincodeActionPayload, chaincodeAction, err := protoutil.GetPayloads(txAction)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting chaincode action")
+	}
+
+	proposalPayload, err := protoutil.UnmarshalChaincodeProposalPayload(chaincodeActionPayload.ChaincodeProposalPayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+	}
+
+	invocationSpec, err := protoutil.UnmarshalChaincodeInvocationSpec(proposalPayload.Input)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode invocation spec")
+	}
+
+	action := &TransactionAction{InvocationSpec: invocationSpec}
+
+	if len(chaincodeAction.Results) > 0 {
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(chaincodeAction.Results); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling read/write set")
+		}
+		action.ReadWriteSets = newNamespaceReadWriteSets(txRWSet)
+	}
+
+	if len(chaincodeAction.Events) > 0 {
+		ccEvent := &peer.ChaincodeEvent{}
+		if err := proto.Unmarshal(chaincodeAction.Events, ccEvent); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode event")
+		}
+		action.ChaincodeEvent = ccEvent
+	}
+
+	return action, nil
+}
+
+func newNamespaceReadWriteSets(txRWSet *rwsetutil.TxRwSet) []*NamespaceReadWriteSet {
+	var nsRWSets []*NamespaceReadWriteSet
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := &NamespaceReadWriteSet{
+			Namespace:      nsRWSet.NameSpace,
+			KVReadWriteSet: nsRWSet.KvRwSet,
+		}
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			ns.CollectionHashedRWSets = append(ns.CollectionHashedRWSets, &CollectionHashedReadWriteSet{
+				CollectionName: collRWSet.CollectionName,
+				HashedRWSet:    collRWSet.HashedRwSet,
+				PvtRWSetHash:   collRWSet.PvtRwSetHash,
+			})
+		}
+		nsRWSets = append(nsRWSets, ns)
+	}
+	return nsRWSets
+}
+
+// newFilteredBlock converts a filtered block
+func newFilteredBlock(fblock *peer.FilteredBlock, sourceURL string) *Block {
+	b := &Block{
+		Number:    fblock.GetNumber(),
+		SourceURL: sourceURL,
+	}
+
+	for i, ftx := range fblock.GetFilteredTransactions() {
+		tx := &BlockTransaction{
+			Index:          i,
+			TransactionID:  ftx.Txid,
+			Type:           ftx.Type,
+			ValidationCode: ftx.TxValidationCode,
+		}
+		for _, ccAction := range ftx.GetTransactionActions().GetChaincodeActions() {
+			tx.Actions = append(tx.Actions, &TransactionAction{ChaincodeEvent: ccAction.ChaincodeEvent})
+		}
+		b.Transactions = append(b.Transactions, tx)
+	}
+
+	return b
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+)
+
+func TestNewBlock(t *testing.T) {
+	block := newTestBlock(t, 7, peer.TxValidationCode_VALID, peer.TxValidationCode_MVCC_READ_CONFLICT)
+
+	b := newBlock(block, "peer1.org1.com")
+
+	if b.Number != 7 {
+		t.Fatalf("Incorrect block number: %d", b.Number)
+	}
+	if b.SourceURL != "peer1.org1.com" {
+		t.Fatalf("Incorrect source URL: %s", b.SourceURL)
+	}
+	if len(b.Transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(b.Transactions))
+	}
+
+	tx := b.Transactions[0]
+	if tx.TransactionID != "tx0" || tx.Index != 0 {
+		t.Fatalf("Incorrect transaction: %s[%d]", tx.TransactionID, tx.Index)
+	}
+	if tx.ChannelHeader.ChannelId != "mychannel" {
+		t.Fatalf("Incorrect channel ID: %s", tx.ChannelHeader.ChannelId)
+	}
+	if tx.CreatorMSPID != "Org1MSP" {
+		t.Fatalf("Incorrect creator MSP ID: %s", tx.CreatorMSPID)
+	}
+	if tx.ValidationCode != peer.TxValidationCode_VALID {
+		t.Fatalf("Incorrect validation code: %s", tx.ValidationCode)
+	}
+	if b.Transactions[1].ValidationCode != peer.TxValidationCode_MVCC_READ_CONFLICT {
+		t.Fatalf("Incorrect validation code: %s", b.Transactions[1].ValidationCode)
+	}
+
+	if len(tx.Actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(tx.Actions))
+	}
+	action := tx.Actions[0]
+
+	args := action.InvocationSpec.GetChaincodeSpec().GetInput().GetArgs()
+	if action.InvocationSpec.GetChaincodeSpec().GetChaincodeId().GetName() != "mycc" || len(args) != 2 || string(args[0]) != "move" {
+		t.Fatalf("Incorrect invocation spec: %s", action.InvocationSpec)
+	}
+
+	if len(action.ReadWriteSets) != 1 || action.ReadWriteSets[0].Namespace != "mycc" {
+		t.Fatalf("Incorrect read/write sets: %#v", action.ReadWriteSets)
+	}
+	writes := action.ReadWriteSets[0].KVReadWriteSet.Writes
+	if len(writes) != 1 || writes[0].Key != "a" || string(writes[0].Value) != "10" {
+		t.Fatalf("Incorrect writes: %v", writes)
+	}
+
+	if action.ChaincodeEvent == nil || action.ChaincodeEvent.EventName != "moved" || string(action.ChaincodeEvent.Payload) != "payload" {
+		t.Fatalf("Incorrect chaincode event: %v", action.ChaincodeEvent)
+	}
+}
+
+func TestNewBlockWithPrivateData(t *testing.T) {
+	block := newTestBlock(t, 7, peer.TxValidationCode_VALID, peer.TxValidationCode_VALID)
+	pvtData := map[uint64]*rwset.TxPvtReadWriteSet{
+		1: {
+			DataModel:  rwset.TxReadWriteSet_KV,
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{{Namespace: "mycc"}},
+		},
+	}
+
+	b := newBlockWithPrivateData(block, pvtData, "peer1.org1.com")
+
+	if len(b.Transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(b.Transactions))
+	}
+	if b.Transactions[0].PrivateData != nil {
+		t.Fatalf("Expected no private data for transaction 0")
+	}
+	if b.Transactions[1].PrivateData != pvtData[1] {
+		t.Fatalf("Incorrect private data for transaction 1: %v", b.Transactions[1].PrivateData)
+	}
+}
+
+func TestNewBlockInvalidTransaction(t *testing.T) {
+	block := newTestBlock(t, 3, peer.TxValidationCode_VALID)
+	block.Data.Data = append(block.Data.Data, []byte("invalid"))
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{0, byte(peer.TxValidationCode_BAD_PAYLOAD)}
+
+	b := newBlock(block, "")
+	if len(b.Transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(b.Transactions))
+	}
+	if b.Transactions[1].TransactionID != "" || b.Transactions[1].ValidationCode != peer.TxValidationCode_BAD_PAYLOAD {
+		t.Fatalf("Incorrect invalid transaction: %#v", b.Transactions[1])
+	}
+}
+
+func TestNewFilteredBlock(t *testing.T) {
+	fblock := &peer.FilteredBlock{
+		ChannelId: "mychannel",
+		Number:    9,
+		FilteredTransactions: []*peer.FilteredTransaction{
+			{
+				Txid:             "tx0",
+				Type:             common.HeaderType_ENDORSER_TRANSACTION,
+				TxValidationCode: peer.TxValidationCode_VALID,
+				Data: &peer.FilteredTransaction_TransactionActions{
+					TransactionActions: &peer.FilteredTransactionActions{
+						ChaincodeActions: []*peer.FilteredChaincodeAction{
+							{ChaincodeEvent: &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "moved"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b := newFilteredBlock(fblock, "peer1.org1.com")
+
+	if b.Number != 9 || len(b.Transactions) != 1 {
+		t.Fatalf("Incorrect block: %#v", b)
+	}
+	tx := b.Transactions[0]
+	if tx.TransactionID != "tx0" || tx.ChannelHeader != nil || tx.CreatorMSPID != "" {
+		t.Fatalf("Incorrect transaction: %#v", tx)
+	}
+	if len(tx.Actions) != 1 || tx.Actions[0].ChaincodeEvent.EventName != "moved" || tx.Actions[0].InvocationSpec != nil {
+		t.Fatalf("Incorrect actions: %#v", tx.Actions)
+	}
+}
+
+func newTestBlock(t *testing.T, number uint64, codes ...peer.TxValidationCode) *common.Block {
+	block := protoutil.NewBlock(number, []byte("previous"))
+
+	txFilter := make([]byte, len(codes))
+	for i, code := range codes {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(newTestEnvelope(t, "tx"+string(rune('0'+i)))))
+		txFilter[i] = byte(code)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	return block
+}
+
+func newTestEnvelope(t *testing.T, txID string) *common.Envelope {
+	cis := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: "mycc"},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte("a")}},
+		},
+	}
+
+	txRWSet := &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{
+				Namespace: "mycc",
+				Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+					Writes: []*kvrwset.KVWrite{{Key: "a", Value: []byte("10")}},
+				}),
+			},
+		},
+	}
+
+	ccAction := &peer.ChaincodeAction{
+		Results: protoutil.MarshalOrPanic(txRWSet),
+		Events: protoutil.MarshalOrPanic(&peer.ChaincodeEvent{
+			ChaincodeId: "mycc", TxId: txID, EventName: "moved", Payload: []byte("payload"),
+		}),
+	}
+
+	ccActionPayload := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: protoutil.MarshalOrPanic(&peer.ChaincodeProposalPayload{Input: protoutil.MarshalOrPanic(cis)}),
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: protoutil.MarshalOrPanic(&peer.ProposalResponsePayload{Extension: protoutil.MarshalOrPanic(ccAction)}),
+		},
+	}
+
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{{Payload: protoutil.MarshalOrPanic(ccActionPayload)}},
+	}
+
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	if err != nil {
+		t.Fatalf("Failed to marshal creator: %s", err)
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+				Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: "mychannel",
+				TxId:      txID,
+			}),
+			SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+		},
+		Data: protoutil.MarshalOrPanic(tx),
+	}
+
+	return &common.Envelope{Payload: protoutil.MarshalOrPanic(payload)}
+}
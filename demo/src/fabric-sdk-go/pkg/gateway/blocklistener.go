@@ -0,0 +1,194 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// blockListener delivers decoded blocks to a listener function, skipping blocks that
+// are at or before the last checkpoint and recording a new checkpoint after each block.
+type blockListener struct {
+	network      *Network
+	eventClient  *sharedEventClient
+	reg          fab.Registration
+	listener     func(*Block)
+	checkpointer Checkpointer
+	checkpoint   *Checkpoint
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// AddBlockListener adds a listener that receives decoded blocks as they are committed to this network.
+// Blocks are delivered to the listener function one at a time, in block number order.
+// RemoveBlockListener must be called when the listener is no longer needed.
+//  Parameters:
+//  listener is the function that is called for each block
+//  options specifies the block event type, start block and checkpointer
+//
+//  Returns:
+//  the registration that is passed to RemoveBlockListener
+func (n *Network) AddBlockListener(listener func(*Block), options ...BlockListenerOption) (fab.Registration, error) {
+	opts, err := newListenerOptions(options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to apply block listener option")
+	}
+
+	checkpoint, eventClient, err := n.listenerEventService(opts, opts.blockType == FullBlockEvents, opts.blockType == PrivateBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &blockListener{
+		network:      n,
+		eventClient:  eventClient,
+		listener:     listener,
+		checkpointer: opts.checkpointer,
+		checkpoint:   checkpoint,
+		done:         make(chan struct{}),
+	}
+
+	switch opts.blockType {
+	case FilteredBlockEvents:
+		reg, eventch, err := eventClient.RegisterFilteredBlockEvent()
+		if err != nil {
+			n.eventClients.release(eventClient)
+			return nil, errors.Wrap(err, "Failed to register for filtered block events")
+		}
+		l.reg = reg
+		if err := n.addListener(l); err != nil {
+			l.close()
+			return nil, err
+		}
+		go l.runFiltered(eventch)
+	case PrivateBlockEvents:
+		reg, eventch, err := eventClient.RegisterBlockAndPrivateDataEvent()
+		if err != nil {
+			n.eventClients.release(eventClient)
+			return nil, errors.Wrap(err, "Failed to register for block and private data events")
+		}
+		l.reg = reg
+		if err := n.addListener(l); err != nil {
+			l.close()
+			return nil, err
+		}
+		go l.runPrivate(eventch)
+	default:
+		reg, eventch, err := eventClient.RegisterBlockEvent()
+		if err != nil {
+			n.eventClients.release(eventClient)
+			return nil, errors.Wrap(err, "Failed to register for block events")
+		}
+		l.reg = reg
+		if err := n.addListener(l); err != nil {
+			l.close()
+			return nil, err
+		}
+		go l.run(eventch)
+	}
+
+	return l, nil
+}
+
+// RemoveBlockListener removes the given listener and stops the delivery of blocks to it.
+//  Parameters:
+//  registration is the registration handle that was returned from AddBlockListener method
+func (n *Network) RemoveBlockListener(registration fab.Registration) {
+	if l, ok := registration.(*blockListener); ok {
+		l.close()
+	}
+}
+
+func (l *blockListener) run(eventch <-chan *fab.BlockEvent) {
+	for {
+		select {
+		case blockEvent, ok := <-eventch:
+			if !ok {
+				return
+			}
+			if !l.processed(blockEvent.Block.GetHeader().GetNumber()) {
+				l.process(newBlock(blockEvent.Block, blockEvent.SourceURL))
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (l *blockListener) runPrivate(eventch <-chan *fab.BlockAndPrivateDataEvent) {
+	for {
+		select {
+		case blockEvent, ok := <-eventch:
+			if !ok {
+				return
+			}
+			if !l.processed(blockEvent.Block.GetHeader().GetNumber()) {
+				l.process(newBlockWithPrivateData(blockEvent.Block, blockEvent.PrivateDataMap, blockEvent.SourceURL))
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (l *blockListener) runFiltered(eventch <-chan *fab.FilteredBlockEvent) {
+	for {
+		select {
+		case blockEvent, ok := <-eventch:
+			if !ok {
+				return
+			}
+			if !l.processed(blockEvent.FilteredBlock.GetNumber()) {
+				l.process(newFilteredBlock(blockEvent.FilteredBlock, blockEvent.SourceURL))
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (l *blockListener) process(block *Block) {
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+
+	l.listener(block)
+
+	l.checkpoint = &Checkpoint{BlockNumber: block.Number}
+	for _, tx := range block.Transactions {
+		l.checkpoint.TransactionIDs = append(l.checkpoint.TransactionIDs, tx.TransactionID)
+	}
+
+	if l.checkpointer != nil {
+		if err := l.checkpointer.Save(l.checkpoint); err != nil {
+			logger.Warnf("Failed to save checkpoint for block %d: %s", block.Number, err)
+		}
+	}
+}
+
+// processed returns true if the block is at or before the current checkpoint
+func (l *blockListener) processed(blockNum uint64) bool {
+	if l.checkpoint == nil || blockNum > l.checkpoint.BlockNumber {
+		return false
+	}
+	logger.Debugf("Skipping block %d since it has already been processed", blockNum)
+	return true
+}
+
+func (l *blockListener) close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.eventClient.Unregister(l.reg)
+		l.network.removeListener(l)
+		l.network.eventClients.release(l.eventClient)
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+func TestAddBlockListener(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	reg, err := nw.AddBlockListener(func(*Block) {})
+	if err != nil {
+		t.Fatalf("Failed to add block listener: %s", err)
+	}
+	nw.RemoveBlockListener(reg)
+
+	reg, err = nw.AddBlockListener(func(*Block) {}, WithBlockEventType(FilteredBlockEvents), WithStartBlock(5))
+	if err != nil {
+		t.Fatalf("Failed to add filtered block listener: %s", err)
+	}
+	nw.RemoveBlockListener(reg)
+
+	reg, err = nw.AddBlockListener(func(*Block) {}, WithBlockEventType(PrivateBlockEvents))
+	if err != nil {
+		t.Fatalf("Failed to add private data block listener: %s", err)
+	}
+	nw.RemoveBlockListener(reg)
+}
+
+func TestBlockListenerResume(t *testing.T) {
+	checkpointer := NewInMemoryCheckpointer()
+	checkpointer.Save(&Checkpoint{BlockNumber: 2})
+	checkpoint, _ := checkpointer.Load()
+
+	var received []uint64
+	l := &blockListener{
+		listener:     func(b *Block) { received = append(received, b.Number) },
+		checkpointer: checkpointer,
+		checkpoint:   checkpoint,
+		done:         make(chan struct{}),
+	}
+
+	eventch := make(chan *fab.BlockEvent, 3)
+	eventch <- &fab.BlockEvent{Block: newTestBlock(t, 2, peer.TxValidationCode_VALID)}
+	eventch <- &fab.BlockEvent{Block: newTestBlock(t, 3, peer.TxValidationCode_VALID)}
+	eventch <- &fab.BlockEvent{Block: newTestBlock(t, 4, peer.TxValidationCode_VALID)}
+	close(eventch)
+
+	finished := make(chan struct{})
+	go func() {
+		l.run(eventch)
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for listener")
+	}
+
+	if len(received) != 2 || received[0] != 3 || received[1] != 4 {
+		t.Fatalf("Expected blocks [3 4], got %v", received)
+	}
+
+	checkpoint, _ = checkpointer.Load()
+	if checkpoint.BlockNumber != 4 || len(checkpoint.TransactionIDs) != 1 {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package gateway
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
@@ -54,6 +56,25 @@ func (c *Contract) EvaluateTransaction(name string, args ...string) ([]byte, err
 	return txn.Evaluate(args...)
 }
 
+// EvaluateTransactionContext is the same as EvaluateTransaction except that the
+// evaluation is aborted when the supplied context is cancelled or its deadline expires.
+//  Parameters:
+//  ctx is the parent context of the request.
+//  name is the name of the transaction function to be invoked in the smart contract.
+//  args are the arguments to be sent to the transaction function.
+//
+//  Returns:
+//  The return value of the transaction function in the smart contract.
+func (c *Contract) EvaluateTransactionContext(ctx reqContext.Context, name string, args ...string) ([]byte, error) {
+	txn, err := c.CreateTransaction(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return txn.EvaluateContext(ctx, args...)
+}
+
 // SubmitTransaction will submit a transaction to the ledger. The transaction function 'name'
 // will be evaluated on the endorsing peers and then submitted to the ordering service
 // for committing to the ledger.
@@ -73,6 +94,26 @@ func (c *Contract) SubmitTransaction(name string, args ...string) ([]byte, error
 	return txn.Submit(args...)
 }
 
+// SubmitTransactionContext is the same as SubmitTransaction except that endorsement,
+// ordering and the wait for the commit event are aborted when the supplied context is
+// cancelled or its deadline expires.
+//  Parameters:
+//  ctx is the parent context of the request.
+//  name is the name of the transaction function to be invoked in the smart contract.
+//  args are the arguments to be sent to the transaction function.
+//
+//  Returns:
+//  The return value of the transaction function in the smart contract.
+func (c *Contract) SubmitTransactionContext(ctx reqContext.Context, name string, args ...string) ([]byte, error) {
+	txn, err := c.CreateTransaction(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return txn.SubmitContext(ctx, args...)
+}
+
 // CreateTransaction creates an object representing a specific invocation of a transaction
 // function implemented by this contract, and provides more control over
 // the transaction invocation using the optional arguments. A new transaction object must
@@ -94,12 +135,19 @@ func (c *Contract) CreateTransaction(name string, opts ...TransactionOption) (*T
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Contract) RegisterEvent(eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
-	return c.network.event.RegisterChaincodeEvent(c.chaincodeID, eventFilter)
+	reg, eventch, err := c.network.event.RegisterChaincodeEvent(c.chaincodeID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.network.addRegistration(reg); err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // Unregister removes the given registration and closes the event channel.
 //  Parameters:
 //  registration is the registration handle that was returned from RegisterContractEvent method
 func (c *Contract) Unregister(registration fab.Registration) {
-	c.network.event.Unregister(registration)
+	c.network.Unregister(registration)
 }
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package gateway
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 func TestCreateTransaction(t *testing.T) {
@@ -116,6 +118,76 @@ func TestEvaluateTransaction(t *testing.T) {
 	}
 }
 
+func TestSubmitTransactionContext(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	contr := nw.GetContract("contract1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := contr.SubmitTransactionContext(ctx, "txn1", "arg1", "arg2")
+
+	if err != nil {
+		t.Fatalf("Failed to submit transaction: %s", err)
+	}
+
+	if string(result) != "abc" {
+		t.Fatalf("Incorrect transaction result: %s", result)
+	}
+}
+
+func TestEvaluateTransactionContext(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	contr := nw.GetContract("contract1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := contr.EvaluateTransactionContext(ctx, "txn1", "arg1", "arg2")
+
+	if err != nil {
+		t.Fatalf("Failed to evaluate transaction: %s", err)
+	}
+
+	if string(result) != "abc" {
+		t.Fatalf("Incorrect transaction result: %s", result)
+	}
+
+	cancelled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+
+	_, err = contr.EvaluateTransactionContext(cancelled, "txn1", "arg1", "arg2")
+	if err == nil {
+		t.Fatal("Expected error evaluating with cancelled context")
+	}
+}
+
 func TestContractEvent(t *testing.T) {
 	c := mockChannelProvider("mychannel")
 
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// contractListener delivers chaincode events to a listener function, skipping events that
+// are at or before the last checkpoint and recording a new checkpoint after each event.
+type contractListener struct {
+	network      *Network
+	eventClient  *sharedEventClient
+	reg          fab.Registration
+	listener     func(*fab.CCEvent)
+	checkpointer Checkpointer
+	checkpoint   *Checkpoint
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// AddContractListener adds a listener that receives the chaincode events emitted by this contract.
+// Events are delivered to the listener function one at a time, in the order in which they were committed.
+// RemoveContractListener must be called when the listener is no longer needed.
+//  Parameters:
+//  listener is the function that is called for each chaincode event
+//  options specifies the start block, checkpointer and event filter
+//
+//  Returns:
+//  the registration that is passed to RemoveContractListener
+func (c *Contract) AddContractListener(listener func(*fab.CCEvent), options ...ContractListenerOption) (fab.Registration, error) {
+	opts, err := newListenerOptions(options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to apply contract listener option")
+	}
+
+	checkpoint, eventClient, err := c.network.listenerEventService(opts, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, eventch, err := eventClient.RegisterChaincodeEvent(c.chaincodeID, opts.eventFilter)
+	if err != nil {
+		c.network.eventClients.release(eventClient)
+		return nil, errors.Wrap(err, "Failed to register for chaincode events")
+	}
+
+	l := &contractListener{
+		network:      c.network,
+		eventClient:  eventClient,
+		reg:          reg,
+		listener:     listener,
+		checkpointer: opts.checkpointer,
+		checkpoint:   checkpoint,
+		done:         make(chan struct{}),
+	}
+	if err := c.network.addListener(l); err != nil {
+		l.close()
+		return nil, err
+	}
+	go l.run(eventch)
+
+	return l, nil
+}
+
+// RemoveContractListener removes the given listener and stops the delivery of events to it.
+//  Parameters:
+//  registration is the registration handle that was returned from AddContractListener method
+func (c *Contract) RemoveContractListener(registration fab.Registration) {
+	if l, ok := registration.(*contractListener); ok {
+		l.close()
+	}
+}
+
+func (l *contractListener) run(eventch <-chan *fab.CCEvent) {
+	for {
+		select {
+		case ccEvent, ok := <-eventch:
+			if !ok {
+				return
+			}
+			l.process(ccEvent)
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (l *contractListener) process(ccEvent *fab.CCEvent) {
+	if l.processed(ccEvent) {
+		logger.Debugf("Skipping chaincode event from block %d, transaction %s since it has already been processed", ccEvent.BlockNumber, ccEvent.TxID)
+		return
+	}
+
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+
+	l.listener(ccEvent)
+
+	if l.checkpoint == nil || ccEvent.BlockNumber > l.checkpoint.BlockNumber {
+		l.checkpoint = &Checkpoint{BlockNumber: ccEvent.BlockNumber}
+	}
+	l.checkpoint.TransactionIDs = append(l.checkpoint.TransactionIDs, ccEvent.TxID)
+
+	if l.checkpointer != nil {
+		if err := l.checkpointer.Save(l.checkpoint); err != nil {
+			logger.Warnf("Failed to save checkpoint for block %d, transaction %s: %s", ccEvent.BlockNumber, ccEvent.TxID, err)
+		}
+	}
+}
+
+// processed returns true if the event is at or before the current checkpoint
+func (l *contractListener) processed(ccEvent *fab.CCEvent) bool {
+	if l.checkpoint == nil {
+		return false
+	}
+	if ccEvent.BlockNumber != l.checkpoint.BlockNumber {
+		return ccEvent.BlockNumber < l.checkpoint.BlockNumber
+	}
+	for _, txID := range l.checkpoint.TransactionIDs {
+		if txID == ccEvent.TxID {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *contractListener) close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.eventClient.Unregister(l.reg)
+		l.network.removeListener(l)
+		l.network.eventClients.release(l.eventClient)
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+func TestAddContractListener(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	contr := nw.GetContract("contract1")
+
+	reg, err := contr.AddContractListener(func(*fab.CCEvent) {})
+	if err != nil {
+		t.Fatalf("Failed to add contract listener: %s", err)
+	}
+	contr.RemoveContractListener(reg)
+
+	checkpointer := NewInMemoryCheckpointer()
+	checkpointer.Save(&Checkpoint{BlockNumber: 10, TransactionIDs: []string{"tx1"}})
+
+	reg, err = contr.AddContractListener(func(*fab.CCEvent) {}, WithStartBlock(5), WithCheckpointer(checkpointer))
+	if err != nil {
+		t.Fatalf("Failed to add contract listener: %s", err)
+	}
+
+	l := reg.(*contractListener)
+	if l.checkpoint.BlockNumber != 10 {
+		t.Fatalf("Expected listener to resume from checkpoint block 10, got %d", l.checkpoint.BlockNumber)
+	}
+	contr.RemoveContractListener(reg)
+	// removing twice is harmless
+	contr.RemoveContractListener(reg)
+}
+
+func TestContractListenerResume(t *testing.T) {
+	checkpointer := NewInMemoryCheckpointer()
+
+	var received []string
+	l := &contractListener{
+		listener:     func(ev *fab.CCEvent) { received = append(received, ev.TxID) },
+		checkpointer: checkpointer,
+		done:         make(chan struct{}),
+	}
+
+	l.process(&fab.CCEvent{TxID: "tx1", BlockNumber: 1})
+	l.process(&fab.CCEvent{TxID: "tx2", BlockNumber: 2})
+	l.process(&fab.CCEvent{TxID: "tx3", BlockNumber: 2})
+
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 events, got %v", received)
+	}
+
+	// simulate a restart: replay from the checkpointed block
+	checkpoint, err := checkpointer.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %s", err)
+	}
+	if checkpoint.BlockNumber != 2 || len(checkpoint.TransactionIDs) != 2 {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+
+	received = nil
+	l2 := &contractListener{
+		listener:     func(ev *fab.CCEvent) { received = append(received, ev.TxID) },
+		checkpointer: checkpointer,
+		checkpoint:   checkpoint,
+		done:         make(chan struct{}),
+	}
+
+	eventch := make(chan *fab.CCEvent, 4)
+	eventch <- &fab.CCEvent{TxID: "tx2", BlockNumber: 2}
+	eventch <- &fab.CCEvent{TxID: "tx3", BlockNumber: 2}
+	eventch <- &fab.CCEvent{TxID: "tx4", BlockNumber: 2}
+	eventch <- &fab.CCEvent{TxID: "tx5", BlockNumber: 3}
+	close(eventch)
+
+	finished := make(chan struct{})
+	go func() {
+		l2.run(eventch)
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for listener")
+	}
+
+	if len(received) != 2 || received[0] != "tx4" || received[1] != "tx5" {
+		t.Fatalf("Expected only unprocessed events [tx4 tx5], got %v", received)
+	}
+
+	checkpoint, _ = checkpointer.Load()
+	if checkpoint.BlockNumber != 3 || len(checkpoint.TransactionIDs) != 1 || checkpoint.TransactionIDs[0] != "tx5" {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/pkg/errors"
+)
+
+const encryptedDirPerms = 0700
+
+// encryptedWalletStore encrypts the private keys of X.509 identities with a passphrase before writing
+// them to the underlying store. Keys are stored as password-encrypted PEM, the same way the SW and SM
+// key stores protect their keys.
+type encryptedWalletStore struct {
+	store      WalletStore
+	passphrase []byte
+}
+
+// NewEncryptedFileSystemWallet creates an instance of a wallet, backed by files on the filesystem,
+// in which the private key of each X.509 identity is encrypted with the given passphrase.
+// Both ECDSA and SM2 keys are supported.
+//  Parameters:
+//  path specifies where on the filesystem to store the wallet.
+//  passphrase specifies the passphrase with which the private keys are encrypted.
+//
+//  Returns:
+//  A Wallet object.
+func NewEncryptedFileSystemWallet(path string, passphrase []byte) (*Wallet, error) {
+	store, err := newEncryptedFileSystemWalletStore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{store}, nil
+}
+
+// EncryptFileSystemWallet migrates a plaintext wallet, created using NewFileSystemWallet, to an encrypted
+// wallet by encrypting the private key of each of its identities in place. Identities whose key is already
+// encrypted, or which hold no key, are left unchanged, so the migration may safely be run again if it was
+// interrupted.
+//  Parameters:
+//  path specifies the location of the wallet on the filesystem.
+//  passphrase specifies the passphrase with which the private keys are encrypted.
+//
+//  Returns:
+//  The encrypted Wallet object.
+func EncryptFileSystemWallet(path string, passphrase []byte) (*Wallet, error) {
+	store, err := newEncryptedFileSystemWalletStore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := store.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range labels {
+		content, err := store.store.Get(label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read identity %s", label)
+		}
+		id, ok := x509IdentityFromJSON(content)
+		if !ok || isEncryptedPEM(id.Key()) {
+			continue
+		}
+		if err := store.Put(label, content); err != nil {
+			return nil, errors.Wrapf(err, "Failed to encrypt identity %s", label)
+		}
+	}
+
+	return &Wallet{store}, nil
+}
+
+func newEncryptedFileSystemWalletStore(path string, passphrase []byte) (*encryptedWalletStore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	cleanPath := filepath.Clean(path)
+	if err := os.MkdirAll(cleanPath, encryptedDirPerms); err != nil {
+		return nil, err
+	}
+
+	return &encryptedWalletStore{
+		store:      &fileSystemWalletStore{cleanPath},
+		passphrase: passphrase,
+	}, nil
+}
+
+// Put encrypts the private key of an X.509 identity and stores the identity in the underlying store.
+// Identities holding no private key are stored as they are.
+func (s *encryptedWalletStore) Put(label string, content []byte) error {
+	id, ok := x509IdentityFromJSON(content)
+	if !ok {
+		return s.store.Put(label, content)
+	}
+
+	key, err := encryptPrivateKey([]byte(id.Key()), s.passphrase)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to encrypt the private key of identity %s", label)
+	}
+	id.Credentials.Key = string(key)
+
+	content, err = id.toJSON()
+	if err != nil {
+		return err
+	}
+	return s.store.Put(label, content)
+}
+
+// Get reads an identity from the underlying store and decrypts its private key.
+func (s *encryptedWalletStore) Get(label string) ([]byte, error) {
+	content, err := s.store.Get(label)
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := x509IdentityFromJSON(content)
+	if !ok {
+		return content, nil
+	}
+	if !isEncryptedPEM(id.Key()) {
+		return nil, errors.Errorf("private key of identity %s is not encrypted", label)
+	}
+
+	key, err := decryptPrivateKey([]byte(id.Key()), s.passphrase)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to decrypt the private key of identity %s", label)
+	}
+	id.Credentials.Key = string(key)
+
+	return id.toJSON()
+}
+
+// List all of the labels in the wallet.
+func (s *encryptedWalletStore) List() ([]string, error) {
+	return s.store.List()
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *encryptedWalletStore) Exists(label string) bool {
+	return s.store.Exists(label)
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *encryptedWalletStore) Remove(label string) error {
+	return s.store.Remove(label)
+}
+
+// x509IdentityFromJSON returns the stored identity if it is an X.509 identity holding its private key
+func x509IdentityFromJSON(content []byte) (*X509Identity, bool) {
+	id := &X509Identity{}
+	if err := json.Unmarshal(content, id); err != nil || id.IDType != x509Type {
+		return nil, false
+	}
+	return id, true
+}
+
+// encryptPrivateKey encrypts a PEM encoded ECDSA or SM2 private key with the passphrase
+func encryptPrivateKey(raw []byte, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	if x509.IsEncryptedPEMBlock(block) {
+		return nil, errors.New("private key is already encrypted")
+	}
+
+	// the standard library parses ECDSA keys on NIST curves, hx509 parses SM2 keys
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	}
+	if err != nil {
+		key, err = hx509.PEMtoPrivateKey(raw, nil)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse private key")
+	}
+
+	return hx509.PrivateKeyToPEM(key, passphrase)
+}
+
+// decryptPrivateKey decrypts a private key encrypted with encryptPrivateKey and returns its PKCS#8 PEM
+func decryptPrivateKey(raw []byte, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	der, err := x509.DecryptPEMBlock(block, passphrase)
+	if err != nil {
+		return nil, errors.New("incorrect passphrase or corrupted key")
+	}
+
+	// the standard library rejects the SM2 curve, which hx509 parses
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), nil
+	}
+
+	key, err := hx509.PEMtoPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+	if err != nil {
+		return nil, errors.New("incorrect passphrase or corrupted key")
+	}
+	return hx509.PrivateKeyToPEM(key, nil)
+}
+
+func isEncryptedPEM(raw string) bool {
+	block, _ := pem.Decode([]byte(raw))
+	return block != nil && x509.IsEncryptedPEMBlock(block)
+}
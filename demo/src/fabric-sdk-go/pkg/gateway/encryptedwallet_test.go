@@ -0,0 +1,205 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+var testPassphrase = []byte("passphrase")
+
+// newTestKeyPEM returns the PEM of a new SM2 private key if sm is true, of an ECDSA P-256 private key otherwise
+func newTestKeyPEM(t *testing.T, sm bool) string {
+	if sm {
+		key, err := sm2.GenerateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate SM2 key: %s", err)
+		}
+		raw, err := hx509.PrivateKeyToPEM(&ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: key.Curve, X: key.X, Y: key.Y}, D: key.D}, nil)
+		if err != nil {
+			t.Fatalf("Failed to marshal SM2 key: %s", err)
+		}
+		return string(raw)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %s", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal ECDSA key: %s", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// privateKeyD parses a PEM encoded ECDSA or SM2 private key and returns its private value
+func privateKeyD(t *testing.T, raw string) string {
+	key, err := hx509.PEMtoPrivateKey([]byte(raw), nil)
+	if err != nil {
+		t.Fatalf("Failed to parse private key: %s", err)
+	}
+	return key.(*ecdsa.PrivateKey).D.String()
+}
+
+func TestEncryptedWallet(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		name string
+		sm   bool
+	}{
+		{name: "ECDSA"},
+		{name: "SM2", sm: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			os.RemoveAll(dir)
+			wallet, err := NewEncryptedFileSystemWallet(dir, testPassphrase)
+			if err != nil {
+				t.Fatalf("Failed to create wallet: %s", err)
+			}
+
+			key := newTestKeyPEM(t, tc.sm)
+			if err := wallet.Put("label1", NewX509Identity("msp", "testCert", key)); err != nil {
+				t.Fatalf("Failed to put identity: %s", err)
+			}
+
+			content, err := ioutil.ReadFile(filepath.Join(dir, "label1"+dataFileExtension))
+			if err != nil {
+				t.Fatalf("Failed to read wallet file: %s", err)
+			}
+			stored, ok := x509IdentityFromJSON(content)
+			if !ok {
+				t.Fatalf("Wallet file doesn't contain an X.509 identity: %s", content)
+			}
+			if !isEncryptedPEM(stored.Key()) {
+				t.Fatalf("Wallet file contains a plaintext private key: %s", content)
+			}
+			if stored.Certificate() != "testCert" || stored.MspID != "msp" {
+				t.Fatalf("Unexpected stored identity: %s", content)
+			}
+
+			id, err := wallet.Get("label1")
+			if err != nil {
+				t.Fatalf("Failed to get identity: %s", err)
+			}
+			if privateKeyD(t, id.(*X509Identity).Key()) != privateKeyD(t, key) {
+				t.Fatalf("Incorrect private key: %s", id.(*X509Identity).Key())
+			}
+		})
+	}
+}
+
+func TestEncryptedWalletIncorrectPassphrase(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	wallet, err := NewEncryptedFileSystemWallet(dir, testPassphrase)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %s", err)
+	}
+	if err := wallet.Put("label1", NewX509Identity("msp", "testCert", newTestKeyPEM(t, true))); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+
+	wallet, err = NewEncryptedFileSystemWallet(dir, []byte("incorrect"))
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %s", err)
+	}
+	if _, err := wallet.Get("label1"); err == nil || !strings.Contains(err.Error(), "incorrect passphrase") {
+		t.Fatalf("Expected incorrect passphrase error, got: %v", err)
+	}
+}
+
+func TestEncryptedWalletInvalidKey(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	wallet, err := NewEncryptedFileSystemWallet(dir, testPassphrase)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %s", err)
+	}
+	if err := wallet.Put("label1", NewX509Identity("msp", "testCert", "testPrivKey")); err == nil {
+		t.Fatal("Expected error for a private key that is not PEM encoded")
+	}
+	if wallet.Exists("label1") {
+		t.Fatal("Expected label1 to not be in wallet")
+	}
+}
+
+func TestEncryptedWalletInvalidOptions(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	defer os.RemoveAll(dir)
+
+	if _, err := NewEncryptedFileSystemWallet(dir, nil); err == nil {
+		t.Fatal("Expected error for empty passphrase")
+	}
+}
+
+func TestEncryptFileSystemWallet(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "migrate")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	plain, err := NewFileSystemWallet(dir)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %s", err)
+	}
+	key := newTestKeyPEM(t, false)
+	plain.Put("label1", NewX509Identity("msp", "testCert1", key))
+	plain.Put("label2", NewKeyRefIdentity("msp", "testCert2", []byte{1, 2, 3}))
+
+	encrypted, err := EncryptFileSystemWallet(dir, testPassphrase)
+	if err != nil {
+		t.Fatalf("Failed to migrate wallet: %s", err)
+	}
+
+	// Running the migration again leaves the encrypted identities unchanged
+	encrypted, err = EncryptFileSystemWallet(dir, testPassphrase)
+	if err != nil {
+		t.Fatalf("Failed to migrate wallet again: %s", err)
+	}
+
+	id, err := encrypted.Get("label1")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+	if privateKeyD(t, id.(*X509Identity).Key()) != privateKeyD(t, key) {
+		t.Fatalf("Incorrect private key: %s", id.(*X509Identity).Key())
+	}
+
+	id, err = encrypted.Get("label2")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+	if id.(*KeyRefIdentity).Certificate() != "testCert2" {
+		t.Fatalf("Incorrect certificate: %s", id.(*KeyRefIdentity).Certificate())
+	}
+
+	id, err = plain.Get("label1")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+	if !isEncryptedPEM(id.(*X509Identity).Key()) {
+		t.Fatal("Expected the private key to be encrypted in the wallet files")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// EndorsementError is returned by Submit when the transaction proposal could not be
+// endorsed, e.g. because no endorsers were found, the chaincode returned an error,
+// or the proposal responses did not match.
+// TransactionID is empty if the request timed out before the proposal was created.
+// Use errors.Cause(err).(*EndorsementError) to detect it.
+type EndorsementError struct {
+	TransactionID fab.TransactionID
+	Err           error
+}
+
+func (e *EndorsementError) Error() string {
+	return "endorsement failed: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *EndorsementError) Unwrap() error {
+	return e.Err
+}
+
+// OrderingError is returned by Submit when the endorsed transaction could not be
+// sent to the ordering service. The transaction has not been committed.
+// Use errors.Cause(err).(*OrderingError) to detect it.
+type OrderingError struct {
+	TransactionID fab.TransactionID
+	Err           error
+}
+
+func (e *OrderingError) Error() string {
+	return "ordering failed: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *OrderingError) Unwrap() error {
+	return e.Err
+}
+
+// CommitTimeoutError is returned by Submit when the transaction was sent to the ordering
+// service but the commit event was not received before the timeout expired or the
+// context was cancelled. The transaction may still be committed to the ledger.
+// Use errors.Cause(err).(*CommitTimeoutError) to detect it.
+type CommitTimeoutError struct {
+	TransactionID fab.TransactionID
+	Err           error
+}
+
+func (e *CommitTimeoutError) Error() string {
+	return "commit not received: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *CommitTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func isTimeout(err error) bool {
+	s, ok := status.FromError(err)
+	return ok && s.Group == status.ClientStatus && s.Code == status.Timeout.ToInt32()
+}
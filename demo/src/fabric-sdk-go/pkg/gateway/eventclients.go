@@ -0,0 +1,166 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
+
+// eventClientKey identifies an event client by the kind of events it delivers and
+// the block from which they are delivered
+type eventClientKey struct {
+	blockEvents bool
+	privateData bool
+	replay      bool
+	fromBlock   uint64
+}
+
+// liveEventClientKey identifies the event client that delivers events as they are committed
+var liveEventClientKey = eventClientKey{blockEvents: true}
+
+// sharedEventClient is an event client that is shared by the users of a network
+type sharedEventClient struct {
+	fab.EventService
+	key  eventClientKey
+	refs int
+}
+
+// eventClients holds the event clients of a network. Users that receive live events of the same kind
+// share an event client, which is reference counted and released when its last user releases it.
+// A replaying event client has already moved past its start block by the time another user could join it,
+// so every user that replays events is given its own event client.
+type eventClients struct {
+	mutex           sync.Mutex
+	channelProvider context.ChannelProvider
+	clients         map[eventClientKey]*sharedEventClient
+	replays         map[*sharedEventClient]struct{}
+}
+
+func newEventClients(channelProvider context.ChannelProvider) *eventClients {
+	return &eventClients{
+		channelProvider: channelProvider,
+		clients:         make(map[eventClientKey]*sharedEventClient),
+		replays:         make(map[*sharedEventClient]struct{}),
+	}
+}
+
+// acquire returns the event client for the given key and increments its reference count. The live
+// event client is created if necessary; a new event client is always created for a replay.
+func (c *eventClients) acquire(key eventClientKey) (*sharedEventClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if ec, ok := c.clients[key]; ok {
+		ec.refs++
+		return ec, nil
+	}
+
+	eventService, err := c.newEventService(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ec := &sharedEventClient{EventService: eventService, key: key, refs: 1}
+	if key.replay {
+		c.replays[ec] = struct{}{}
+	} else {
+		c.clients[key] = ec
+	}
+
+	return ec, nil
+}
+
+// release decrements the reference count of the event client. When the last reference is released
+// a replaying event client is closed. The live event client is left open since the SDK shares it with
+// all clients of the same channel context; its connection is closed once it has been idle for the
+// configured event service idle timeout.
+func (c *eventClients) release(ec *sharedEventClient) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if ec.refs <= 0 {
+		// Already released
+		return
+	}
+
+	ec.refs--
+	if ec.refs > 0 {
+		return
+	}
+
+	c.remove(ec)
+}
+
+// close releases all event clients regardless of their reference counts
+func (c *eventClients) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, ec := range c.clients {
+		ec.refs = 0
+		c.remove(ec)
+	}
+	for ec := range c.replays {
+		ec.refs = 0
+		c.remove(ec)
+	}
+}
+
+func (c *eventClients) remove(ec *sharedEventClient) {
+	if !ec.key.replay {
+		delete(c.clients, ec.key)
+		return
+	}
+
+	delete(c.replays, ec)
+
+	// The SDK creates a dedicated event client for each replay so closing it does not affect other users
+	if closer, ok := ec.EventService.(closable); ok {
+		logger.Debugf("Closing event client replaying from block %d", ec.key.fromBlock)
+		closer.Close()
+	}
+}
+
+func (c *eventClients) newEventService(key eventClientKey) (fab.EventService, error) {
+	ctx, err := c.channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	if ctx.ChannelService() == nil {
+		return nil, errors.New("channel service not initialized")
+	}
+
+	var opts []options.Opt
+	if key.privateData {
+		opts = append(opts, client.WithBlockAndPrivateData())
+	} else if key.blockEvents {
+		opts = append(opts, client.WithBlockEvents())
+	}
+	if key.replay {
+		opts = append(opts, deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(key.fromBlock))
+	}
+
+	eventService, err := ctx.ChannelService().EventService(opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "event service creation failed")
+	}
+
+	return eventService, nil
+}
+
+type closable interface {
+	Close()
+}
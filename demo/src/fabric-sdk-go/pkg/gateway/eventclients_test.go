@@ -0,0 +1,90 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestEventClientsShared(t *testing.T) {
+	clients := newEventClients(mockChannelProvider("mychannel"))
+
+	live1, err := clients.acquire(liveEventClientKey)
+	if err != nil {
+		t.Fatalf("Failed to acquire event client: %s", err)
+	}
+	live2, err := clients.acquire(liveEventClientKey)
+	if err != nil {
+		t.Fatalf("Failed to acquire event client: %s", err)
+	}
+	if live1 != live2 || live1.refs != 2 {
+		t.Fatalf("Expected live event client to be shared with 2 references, got %d", live1.refs)
+	}
+
+	replay, err := clients.acquire(eventClientKey{blockEvents: true, replay: true, fromBlock: 5})
+	if err != nil {
+		t.Fatalf("Failed to acquire event client: %s", err)
+	}
+	if replay == live1 {
+		t.Fatal("Expected a separate event client for replay")
+	}
+	replay2, err := clients.acquire(eventClientKey{blockEvents: true, replay: true, fromBlock: 5})
+	if err != nil {
+		t.Fatalf("Failed to acquire event client: %s", err)
+	}
+	if replay2 == replay {
+		t.Fatal("Expected each replay from the same block to have its own event client")
+	}
+
+	clients.release(live1)
+	if _, ok := clients.clients[liveEventClientKey]; !ok {
+		t.Fatal("Expected live event client to remain while it is referenced")
+	}
+	clients.release(live2)
+	if _, ok := clients.clients[liveEventClientKey]; ok {
+		t.Fatal("Expected live event client to be released")
+	}
+
+	clients.close()
+	if len(clients.clients) != 0 || len(clients.replays) != 0 || replay.refs != 0 || replay2.refs != 0 {
+		t.Fatalf("Expected all event clients to be released")
+	}
+	// releasing after close is harmless
+	clients.release(replay)
+}
+
+func TestEventClientsReplayClosed(t *testing.T) {
+	clients := newEventClients(mockChannelProvider("mychannel"))
+
+	key := eventClientKey{replay: true, fromBlock: 5}
+	replay := &closableEventService{MockEventService: mocks.NewMockEventService()}
+	live := &closableEventService{MockEventService: mocks.NewMockEventService()}
+	replayClient := &sharedEventClient{EventService: replay, key: key, refs: 1}
+	clients.replays[replayClient] = struct{}{}
+	clients.clients[liveEventClientKey] = &sharedEventClient{EventService: live, key: liveEventClientKey, refs: 1}
+
+	clients.release(replayClient)
+	if !replay.closed {
+		t.Fatal("Expected replaying event client to be closed when released")
+	}
+
+	clients.close()
+	if live.closed {
+		t.Fatal("Expected live event client to be left to the SDK")
+	}
+}
+
+type closableEventService struct {
+	*mocks.MockEventService
+	closed bool
+}
+
+func (s *closableEventService) Close() {
+	s.closed = true
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	fabcheckpoint "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/checkpoint"
+)
+
+// fileCheckpointer persists the checkpoint of a contract listener to a JSON file.
+// Instances are created using NewFileCheckpointer()
+type fileCheckpointer struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileCheckpointer creates a checkpointer that is backed by a file.
+// The checkpoint is read from the file, if it exists, when the listener starts.
+//  Parameters:
+//  path specifies the file in which to store the checkpoint.
+//
+//  Returns:
+//  A Checkpointer object.
+func NewFileCheckpointer(path string) (Checkpointer, error) {
+	cleanPath := filepath.Clean(path)
+	err := os.MkdirAll(filepath.Dir(cleanPath), os.ModePerm)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileCheckpointer{path: cleanPath}, nil
+}
+
+// Load reads the checkpoint from the file. Nil is returned if the file does not exist.
+func (c *fileCheckpointer) Load() (*Checkpoint, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	checkpoint := &Checkpoint{}
+	ok, err := fabcheckpoint.LoadFile(c.path, checkpoint)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the file.
+func (c *fileCheckpointer) Save(checkpoint *Checkpoint) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return fabcheckpoint.SaveFile(c.path, checkpoint)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCheckpointerSuite(t *testing.T) {
+	dir := filepath.Join("testdata", "checkpoint")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	checkpointer, err := NewFileCheckpointer(filepath.Join(dir, "mycontract.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file checkpointer: %s", err)
+	}
+
+	testCheckpointerSuite(t, checkpointer)
+
+	// a new checkpointer on the same file resumes from the saved checkpoint
+	checkpointer, err = NewFileCheckpointer(filepath.Join(dir, "mycontract.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file checkpointer: %s", err)
+	}
+
+	checkpoint, err := checkpointer.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %s", err)
+	}
+	if checkpoint == nil || checkpoint.BlockNumber != 6 {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+}
+
+func TestFileCheckpointerInvalidFile(t *testing.T) {
+	dir := filepath.Join("testdata", "checkpoint")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "invalid.json")
+	checkpointer, err := NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Failed to create file checkpointer: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("{invalid"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %s", err)
+	}
+
+	_, err = checkpointer.Load()
+	if err == nil {
+		t.Fatal("Expected error loading invalid checkpoint file")
+	}
+}
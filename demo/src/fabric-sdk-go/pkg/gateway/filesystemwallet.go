@@ -45,7 +45,7 @@ func NewFileSystemWallet(path string) (*Wallet, error) {
 func (fsw *fileSystemWalletStore) Put(label string, content []byte) error {
 	pathname := filepath.Join(fsw.path, label) + dataFileExtension
 
-	f, err := os.OpenFile(filepath.Clean(pathname), os.O_RDWR|os.O_CREATE, 0600)
+	f, err := os.OpenFile(filepath.Clean(pathname), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 
 	if err != nil {
 		return err
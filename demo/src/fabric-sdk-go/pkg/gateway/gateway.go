@@ -19,6 +19,7 @@ package gateway
 import (
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkinternal/pkg/util"
@@ -27,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	mspProvider "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/pkg/errors"
@@ -46,12 +48,18 @@ type Gateway struct {
 	mspid      string
 	peers      []fab.PeerConfig
 	mspfactory api.MSPProviderFactory
+	ownSDK     bool
+
+	mutex    sync.Mutex
+	networks map[string]*Network
+	closed   bool
 }
 
 type gatewayOptions struct {
-	Identity mspProvider.SigningIdentity
-	User     string
-	Timeout  time.Duration
+	Identity       mspProvider.SigningIdentity
+	User           string
+	Timeout        time.Duration
+	SecretProvider core.SecretProvider
 }
 
 // Option functional arguments can be supplied when connecting to the gateway.
@@ -85,11 +93,8 @@ func Connect(config ConfigOption, identity IdentityOption, options ...Option) (*
 		return nil, errors.Wrap(err, "Failed to apply identity option")
 	}
 
-	err = config(g)
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to apply config option")
-	}
-
+	// The gateway options are applied before the config option since the SDK that is
+	// created by WithConfig uses the secret provider
 	for _, option := range options {
 		err = option(g)
 		if err != nil {
@@ -97,9 +102,41 @@ func Connect(config ConfigOption, identity IdentityOption, options ...Option) (*
 		}
 	}
 
+	err = config(g)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to apply config option")
+	}
+
+	err = g.importIdentityKey()
+	if err != nil {
+		return nil, err
+	}
+
 	return g, nil
 }
 
+// importIdentityKey imports the private key of an X509 wallet identity, decrypting it with the
+// password supplied by the secret provider if it is encrypted
+func (gw *Gateway) importIdentityKey() error {
+	wid, ok := gw.options.Identity.(*walletIdentity)
+	if !ok || wid.privateKey != nil || wid.keyPEM == nil {
+		return nil
+	}
+
+	password, err := secret.KeyPassword(gw.options.SecretProvider, gw.org, wid.id)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to get key password for identity "+wid.id)
+	}
+
+	wid.privateKey, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytesWithPassword(wid.keyPEM, password, cryptosuite.GetDefault(), true)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to import private key for identity "+wid.id)
+	}
+	wid.keyPEM = nil
+
+	return nil
+}
+
 // WithConfig configures the gateway from a network config, such as a ccp file.
 //
 //   Parameters:
@@ -137,6 +174,9 @@ func WithConfig(config core.ConfigProvider) ConfigOption {
 		if gw.mspfactory != nil {
 			opts = append(opts, fabsdk.WithMSPPkg(gw.mspfactory))
 		}
+		if gw.options.SecretProvider != nil {
+			opts = append(opts, fabsdk.WithSecretProvider(gw.options.SecretProvider))
+		}
 
 		sdk, err := fabsdk.New(config, opts...)
 
@@ -145,6 +185,7 @@ func WithConfig(config core.ConfigProvider) ConfigOption {
 		}
 
 		gw.sdk = sdk
+		gw.ownSDK = true
 
 		//  find the 'gateway' peers
 		ctx := sdk.Context()
@@ -199,12 +240,30 @@ func WithIdentity(wallet wallet, label string) IdentityOption {
 			return err
 		}
 
-		privateKey, _ := fabricCaUtil.ImportBCCSPKeyFromPEMBytes([]byte(creds.(*X509Identity).Key()), cryptosuite.GetDefault(), true)
+		var cert string
+		var keyPEM []byte
+		var privateKey core.Key
+		switch id := creds.(type) {
+		case *X509Identity:
+			// The key is imported by Connect once the secret provider and the organization are known
+			cert = id.Certificate()
+			keyPEM = []byte(id.Key())
+		case *KeyRefIdentity:
+			cert = id.Certificate()
+			privateKey, err = id.privateKey(cryptosuite.GetDefault())
+			if err != nil {
+				return errors.WithMessage(err, "Failed to get private key for identity "+label)
+			}
+		default:
+			return errors.New("unsupported identity type: " + creds.idType())
+		}
+
 		wid := &walletIdentity{
 			id:                    label,
 			mspID:                 creds.mspID(),
-			enrollmentCertificate: []byte(creds.(*X509Identity).Certificate()),
+			enrollmentCertificate: []byte(cert),
 			privateKey:            privateKey,
+			keyPEM:                keyPEM,
 		}
 
 		gw.options.Identity = wid
@@ -231,6 +290,17 @@ func WithUser(user string) IdentityOption {
 	}
 }
 
+// WithSecretProvider is an optional argument to the Connect method which specifies the provider
+// of the password that decrypts the private key of a wallet identity (see package pkg/core/secret).
+// The SDK that is created by WithConfig also uses the secret provider. If no secret provider is
+// specified then the deprecated process-wide password set with apiKey.SetUserPassword is used.
+func WithSecretProvider(secretProvider core.SecretProvider) Option {
+	return func(gw *Gateway) error {
+		gw.options.SecretProvider = secretProvider
+		return nil
+	}
+}
+
 // WithTimeout is an optional argument to the Connect method which
 // defines the commit timeout for all transaction submissions for this gateway.
 func WithTimeout(timeout time.Duration) Option {
@@ -240,26 +310,91 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// GetNetwork returns an object representing a network channel.
+// GetNetwork returns an object representing a network channel. Networks are cached, so the
+// same Network instance is returned for each call with the same channel name until it is closed.
+// Network.Close should be called once for each call to GetNetwork when the network is no longer needed.
 //  Parameters:
 //  name is the name of the network channel
 //
 //  Returns:
 //  A Network object representing the channel
 func (gw *Gateway) GetNetwork(name string) (*Network, error) {
-	var channelProvider context.ChannelProvider
+	return gw.getNetwork(name, gw.channelProvider)
+}
+
+func (gw *Gateway) getNetwork(name string, channelProvider func(string) context.ChannelProvider) (*Network, error) {
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	if gw.closed {
+		return nil, errors.New("gateway has been closed")
+	}
+
+	if n, ok := gw.networks[name]; ok {
+		n.refs++
+		return n, nil
+	}
+
+	n, err := newNetwork(gw, channelProvider(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if gw.networks == nil {
+		gw.networks = make(map[string]*Network)
+	}
+	n.refs = 1
+	gw.networks[name] = n
+
+	return n, nil
+}
+
+func (gw *Gateway) channelProvider(name string) context.ChannelProvider {
 	if gw.options.Identity != nil {
-		channelProvider = gw.sdk.ChannelContext(name, fabsdk.WithIdentity(gw.options.Identity), fabsdk.WithOrg(gw.org))
-	} else {
-		channelProvider = gw.sdk.ChannelContext(name, fabsdk.WithUser(gw.options.User), fabsdk.WithOrg(gw.org))
+		return gw.sdk.ChannelContext(name, fabsdk.WithIdentity(gw.options.Identity), fabsdk.WithOrg(gw.org))
+	}
+	return gw.sdk.ChannelContext(name, fabsdk.WithUser(gw.options.User), fabsdk.WithOrg(gw.org))
+}
+
+// releaseNetwork releases a reference to the network and returns true if it was the last reference,
+// in which case the network is removed from the cache and must be closed
+func (gw *Gateway) releaseNetwork(n *Network) bool {
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	if n.refs > 0 {
+		n.refs--
+	}
+	if n.refs > 0 {
+		return false
 	}
-	return newNetwork(gw, channelProvider)
+
+	if gw.networks[n.name] == n {
+		delete(gw.networks, n.name)
+	}
+	return true
 }
 
 // Close the gateway connection and all associated resources, including removing listeners attached to networks and
-// contracts created by the gateway.
+// contracts created by the gateway. The SDK instance is also closed if it was created by the gateway (see WithConfig).
 func (gw *Gateway) Close() {
-	// future use
+	gw.mutex.Lock()
+	if gw.closed {
+		gw.mutex.Unlock()
+		return
+	}
+	gw.closed = true
+	networks := gw.networks
+	gw.networks = nil
+	gw.mutex.Unlock()
+
+	for _, n := range networks {
+		n.close()
+	}
+
+	if gw.ownSDK && gw.sdk != nil {
+		gw.sdk.Close()
+	}
 }
 
 func (gw *Gateway) getOrg() string {
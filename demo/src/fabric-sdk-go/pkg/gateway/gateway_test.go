@@ -0,0 +1,84 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/secret"
+)
+
+func TestImportIdentityKey(t *testing.T) {
+	keyPEM := newEncryptedKey(t, "password")
+
+	wallet := NewInMemoryWallet()
+	if err := wallet.Put("user1", NewX509Identity("Org1MSP", "testCert", keyPEM)); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+
+	tests := []struct {
+		title    string
+		password string
+		valid    bool
+	}{
+		{"Correct password", "password", true},
+		{"Wrong password", "wrong", false},
+		{"Missing password", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			gw := &Gateway{options: &gatewayOptions{}, org: "Org1"}
+			if err := WithIdentity(wallet, "user1")(gw); err != nil {
+				t.Fatalf("Failed to apply identity option: %s", err)
+			}
+
+			var org, user string
+			gw.options.SecretProvider = secret.NewCallbackProvider(func(orgID, userID string) ([]byte, error) {
+				org, user = orgID, userID
+				return []byte(test.password), nil
+			}, nil)
+
+			err := gw.importIdentityKey()
+			if org != "Org1" || user != "user1" {
+				t.Fatalf("Unexpected key password request for org [%s] and user [%s]", org, user)
+			}
+			if !test.valid {
+				if err == nil {
+					t.Fatal("Expected error importing key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to import key: %s", err)
+			}
+			if gw.options.Identity.PrivateKey() == nil {
+				t.Fatal("Expected private key to be imported")
+			}
+		})
+	}
+}
+
+func newEncryptedKey(t *testing.T, password string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %s", err)
+	}
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte(password), x509.PEMCipherAES256) //nolint
+	if err != nil {
+		t.Fatalf("Failed to encrypt key: %s", err)
+	}
+	return string(pem.EncodeToMemory(block))
+}
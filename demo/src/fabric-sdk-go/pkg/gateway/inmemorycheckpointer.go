@@ -0,0 +1,51 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import "sync"
+
+// inMemoryCheckpointer holds the checkpoint of a contract listener in memory.
+// Instances are created using NewInMemoryCheckpointer()
+type inMemoryCheckpointer struct {
+	mutex      sync.RWMutex
+	checkpoint *Checkpoint
+}
+
+// NewInMemoryCheckpointer creates a checkpointer, held in memory.
+// This implementation is not backed by a persistent store.
+//
+//  Returns:
+//  A Checkpointer object.
+func NewInMemoryCheckpointer() Checkpointer {
+	return &inMemoryCheckpointer{}
+}
+
+// Load returns the saved checkpoint, or nil if none has been saved.
+func (c *inMemoryCheckpointer) Load() (*Checkpoint, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.checkpoint == nil {
+		return nil, nil
+	}
+	return copyCheckpoint(c.checkpoint), nil
+}
+
+// Save the checkpoint.
+func (c *inMemoryCheckpointer) Save(checkpoint *Checkpoint) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.checkpoint = copyCheckpoint(checkpoint)
+	return nil
+}
+
+func copyCheckpoint(checkpoint *Checkpoint) *Checkpoint {
+	txIDs := make([]string, len(checkpoint.TransactionIDs))
+	copy(txIDs, checkpoint.TransactionIDs)
+	return &Checkpoint{BlockNumber: checkpoint.BlockNumber, TransactionIDs: txIDs}
+}
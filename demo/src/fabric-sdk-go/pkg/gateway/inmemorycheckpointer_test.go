@@ -0,0 +1,54 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+)
+
+func TestInMemoryCheckpointerSuite(t *testing.T) {
+	testCheckpointerSuite(t, NewInMemoryCheckpointer())
+}
+
+func testCheckpointerSuite(t *testing.T, checkpointer Checkpointer) {
+	checkpoint, err := checkpointer.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %s", err)
+	}
+	if checkpoint != nil {
+		t.Fatalf("Expected no checkpoint, got %#v", checkpoint)
+	}
+
+	err = checkpointer.Save(&Checkpoint{BlockNumber: 5, TransactionIDs: []string{"tx1", "tx2"}})
+	if err != nil {
+		t.Fatalf("Failed to save checkpoint: %s", err)
+	}
+
+	checkpoint, err = checkpointer.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %s", err)
+	}
+	if checkpoint == nil || checkpoint.BlockNumber != 5 {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+	if len(checkpoint.TransactionIDs) != 2 || checkpoint.TransactionIDs[1] != "tx2" {
+		t.Fatalf("Incorrect checkpoint transaction IDs: %v", checkpoint.TransactionIDs)
+	}
+
+	err = checkpointer.Save(&Checkpoint{BlockNumber: 6, TransactionIDs: []string{"tx3"}})
+	if err != nil {
+		t.Fatalf("Failed to save checkpoint: %s", err)
+	}
+
+	checkpoint, err = checkpointer.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %s", err)
+	}
+	if checkpoint.BlockNumber != 6 || len(checkpoint.TransactionIDs) != 1 {
+		t.Fatalf("Incorrect checkpoint: %#v", checkpoint)
+	}
+}
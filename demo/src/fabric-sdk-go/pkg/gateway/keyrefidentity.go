@@ -0,0 +1,96 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/pkg/errors"
+)
+
+const keyRefType = "X.509-KeyRef"
+
+// KeyRefIdentity represents an X509 identity whose private key is held by the crypto suite
+// (e.g. in a PKCS#11 token or the BCCSP keystore) rather than in the wallet.
+// Only the certificate and a reference to the key are stored.
+type KeyRefIdentity struct {
+	Version     int               `json:"version"`
+	MspID       string            `json:"mspId"`
+	IDType      string            `json:"type"`
+	Credentials keyRefCredentials `json:"credentials"`
+}
+
+type keyRefCredentials struct {
+	Certificate string `json:"certificate"`
+	SKI         string `json:"ski,omitempty"`
+}
+
+func (x *KeyRefIdentity) idType() string {
+	return keyRefType
+}
+
+func (x *KeyRefIdentity) mspID() string {
+	return x.MspID
+}
+
+// Certificate returns the X509 certificate PEM
+func (x *KeyRefIdentity) Certificate() string {
+	return x.Credentials.Certificate
+}
+
+// SKI returns the subject key identifier of the private key, or nil if the key
+// is located using the certificate's public key
+func (x *KeyRefIdentity) SKI() []byte {
+	ski, err := hex.DecodeString(x.Credentials.SKI)
+	if err != nil || len(ski) == 0 {
+		return nil
+	}
+	return ski
+}
+
+// NewKeyRefIdentity creates an identity for storage in a wallet which references a private key held by the crypto suite.
+//  Parameters:
+//  mspid specifies the MSP ID of the identity
+//  cert specifies the X509 certificate PEM
+//  ski specifies the subject key identifier of the private key. If nil, the key is located using the certificate's public key.
+func NewKeyRefIdentity(mspid string, cert string, ski []byte) *KeyRefIdentity {
+	return &KeyRefIdentity{1, mspid, keyRefType, keyRefCredentials{cert, hex.EncodeToString(ski)}}
+}
+
+// privateKey looks up the referenced private key in the crypto suite
+func (x *KeyRefIdentity) privateKey(cs core.CryptoSuite) (core.Key, error) {
+	ski := x.SKI()
+	if ski == nil {
+		return cryptoutil.GetPrivateKeyFromCert([]byte(x.Certificate()), cs)
+	}
+
+	key, err := cs.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Could not find matching key for SKI")
+	}
+	if !key.Private() {
+		return nil, errors.Errorf("Found key is not private, SKI: %s", x.Credentials.SKI)
+	}
+	return key, nil
+}
+
+func (x *KeyRefIdentity) toJSON() ([]byte, error) {
+	return json.Marshal(x)
+}
+
+func (x *KeyRefIdentity) fromJSON(data []byte) (Identity, error) {
+	err := json.Unmarshal(data, x)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
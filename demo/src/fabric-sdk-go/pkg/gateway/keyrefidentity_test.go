@@ -0,0 +1,56 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeyRefIdentity(t *testing.T) {
+	wallet := NewInMemoryWallet()
+	ski := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	if err := wallet.Put("label1", NewKeyRefIdentity("msp", "testCert", ski)); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+
+	id, err := wallet.Get("label1")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+
+	keyRef, ok := id.(*KeyRefIdentity)
+	if !ok {
+		t.Fatalf("Incorrect identity type: %T", id)
+	}
+	if keyRef.idType() != keyRefType || keyRef.mspID() != "msp" || keyRef.Version != 1 {
+		t.Fatalf("Incorrect identity: %#v", keyRef)
+	}
+	if keyRef.Certificate() != "testCert" {
+		t.Fatalf("Incorrect certificate: %s", keyRef.Certificate())
+	}
+	if !bytes.Equal(keyRef.SKI(), ski) {
+		t.Fatalf("Incorrect SKI: %x", keyRef.SKI())
+	}
+}
+
+func TestKeyRefIdentityWithoutSKI(t *testing.T) {
+	id := NewKeyRefIdentity("msp", "testCert", nil)
+
+	if id.SKI() != nil {
+		t.Fatalf("Expected nil SKI, got %x", id.SKI())
+	}
+
+	content, err := id.toJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal identity: %s", err)
+	}
+	if bytes.Contains(content, []byte("ski")) {
+		t.Fatalf("Expected SKI to be omitted: %s", content)
+	}
+}
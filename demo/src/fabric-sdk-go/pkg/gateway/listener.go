@@ -0,0 +1,129 @@
+/*
+Copyright 2020 IBM All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/gateway")
+
+const defaultEventFilter = ".*"
+
+// BlockEventType specifies the kind of block that is delivered to a block listener
+type BlockEventType int
+
+const (
+	// FullBlockEvents delivers complete blocks, including read/write sets and chaincode event payloads.
+	// The identity must have permission to receive full blocks.
+	FullBlockEvents BlockEventType = iota
+	// FilteredBlockEvents delivers filtered blocks, which contain only the transaction IDs,
+	// validation codes and chaincode event names.
+	FilteredBlockEvents
+	// PrivateBlockEvents delivers complete blocks together with the private data written by
+	// each transaction. The identity must be a member of the collections to receive their data.
+	PrivateBlockEvents
+)
+
+type listenerOptions struct {
+	startBlock    uint64
+	hasStartBlock bool
+	checkpointer  Checkpointer
+	eventFilter   string
+	blockType     BlockEventType
+}
+
+// ContractListenerOption functional arguments can be supplied when adding a contract listener
+type ContractListenerOption = func(*listenerOptions) error
+
+// BlockListenerOption functional arguments can be supplied when adding a block listener
+type BlockListenerOption = func(*listenerOptions) error
+
+func newListenerOptions(options ...func(*listenerOptions) error) (*listenerOptions, error) {
+	opts := &listenerOptions{eventFilter: defaultEventFilter}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
+
+// WithStartBlock is an optional argument to the AddContractListener and AddBlockListener methods
+// which specifies the block number from which events are replayed. It is ignored if the
+// listener's Checkpointer already holds a checkpoint.
+func WithStartBlock(block uint64) ContractListenerOption {
+	return func(o *listenerOptions) error {
+		o.startBlock = block
+		o.hasStartBlock = true
+		return nil
+	}
+}
+
+// WithCheckpointer is an optional argument to the AddContractListener and AddBlockListener methods
+// which specifies where the position of the last processed event is recorded. When the listener
+// is added again (e.g. after a restart) with the same Checkpointer, events are replayed from
+// the recorded position and events that were already processed are not delivered again.
+func WithCheckpointer(checkpointer Checkpointer) ContractListenerOption {
+	return func(o *listenerOptions) error {
+		o.checkpointer = checkpointer
+		return nil
+	}
+}
+
+// WithEventFilter is an optional argument to the AddContractListener method which
+// specifies the chaincode event filter (regular expression) for which events are delivered.
+// By default all events emitted by the contract are delivered.
+func WithEventFilter(eventFilter string) ContractListenerOption {
+	return func(o *listenerOptions) error {
+		o.eventFilter = eventFilter
+		return nil
+	}
+}
+
+// WithBlockEventType is an optional argument to the AddBlockListener method which
+// specifies the kind of block that is delivered. The default is FullBlockEvents.
+func WithBlockEventType(blockType BlockEventType) BlockListenerOption {
+	return func(o *listenerOptions) error {
+		o.blockType = blockType
+		return nil
+	}
+}
+
+// listenerEventService loads the listener's checkpoint and acquires the event client from which
+// its events are received. Listeners that replay from a start block or checkpoint are given their own
+// event client; all others share the network's live event client (or its live private data event client).
+// The event client must be released when the listener is removed.
+func (n *Network) listenerEventService(opts *listenerOptions, blockEvents bool, privateData bool) (*Checkpoint, *sharedEventClient, error) {
+	var checkpoint *Checkpoint
+	if opts.checkpointer != nil {
+		var err error
+		checkpoint, err = opts.checkpointer.Load()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to load checkpoint")
+		}
+	}
+
+	key := liveEventClientKey
+	key.privateData = privateData
+	if checkpoint != nil || opts.hasStartBlock {
+		startBlock := opts.startBlock
+		if checkpoint != nil {
+			// The checkpointed block may only have been partially processed so it is replayed
+			startBlock = checkpoint.BlockNumber
+		}
+		key = eventClientKey{blockEvents: blockEvents || privateData, privateData: privateData, replay: true, fromBlock: startBlock}
+	}
+
+	eventClient, err := n.eventClients.acquire(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create event client")
+	}
+
+	return checkpoint, eventClient, nil
+}
@@ -7,8 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package gateway
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
-	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/pkg/errors"
@@ -16,16 +17,35 @@ import (
 
 // A Network object represents the set of peers in a Fabric network (channel).
 // Applications should get a Network instance from a Gateway using the GetNetwork method.
+// Networks are cached by the Gateway, so the same instance is returned each time GetNetwork is called
+// for a channel. Close must be called once for each call to GetNetwork.
 type Network struct {
-	name    string
-	gateway *Gateway
-	client  *channel.Client
-	event   *event.Client
+	name            string
+	gateway         *Gateway
+	client          *channel.Client
+	event           *sharedEventClient
+	eventClients    *eventClients
+	channelProvider context.ChannelProvider
+
+	mutex         sync.Mutex
+	refs          int
+	closed        bool
+	listeners     map[networkListener]struct{}
+	registrations map[fab.Registration]struct{}
+}
+
+// networkListener is a listener that is removed when the network is closed
+type networkListener interface {
+	close()
 }
 
 func newNetwork(gateway *Gateway, channelProvider context.ChannelProvider) (*Network, error) {
 	n := Network{
-		gateway: gateway,
+		gateway:         gateway,
+		channelProvider: channelProvider,
+		eventClients:    newEventClients(channelProvider),
+		listeners:       make(map[networkListener]struct{}),
+		registrations:   make(map[fab.Registration]struct{}),
 	}
 
 	// Channel client is used to query and execute transactions
@@ -43,7 +63,7 @@ func newNetwork(gateway *Gateway, channelProvider context.ChannelProvider) (*Net
 
 	n.name = ctx.ChannelID()
 
-	n.event, err = event.New(channelProvider, event.WithBlockEvents())
+	n.event, err = n.eventClients.acquire(liveEventClientKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to create new event client")
 	}
@@ -92,19 +112,103 @@ func (n *Network) GetContractWithName(chaincodeID string, name string) *Contract
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (n *Network) RegisterBlockEvent() (fab.Registration, <-chan *fab.BlockEvent, error) {
-	return n.event.RegisterBlockEvent()
+	reg, eventch, err := n.event.RegisterBlockEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := n.addRegistration(reg); err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (n *Network) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
-	return n.event.RegisterFilteredBlockEvent()
+	reg, eventch, err := n.event.RegisterFilteredBlockEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := n.addRegistration(reg); err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // Unregister removes the given registration and closes the event channel.
 //  Parameters:
 //  registration is the registration handle that was returned from RegisterBlockEvent method
 func (n *Network) Unregister(registration fab.Registration) {
+	n.mutex.Lock()
+	delete(n.registrations, registration)
+	n.mutex.Unlock()
+
 	n.event.Unregister(registration)
 }
+
+// Close releases this reference to the network. When every caller of GetNetwork has closed the network,
+// its listeners and event registrations are removed and its event clients are released.
+func (n *Network) Close() {
+	if n.gateway.releaseNetwork(n) {
+		n.close()
+	}
+}
+
+// close removes all listeners and registrations and releases the event clients
+func (n *Network) close() {
+	n.mutex.Lock()
+	if n.closed {
+		n.mutex.Unlock()
+		return
+	}
+	n.closed = true
+	listeners := n.listeners
+	registrations := n.registrations
+	n.listeners = nil
+	n.registrations = nil
+	n.mutex.Unlock()
+
+	logger.Debugf("Closing network %s", n.name)
+
+	for l := range listeners {
+		l.close()
+	}
+	for reg := range registrations {
+		n.event.Unregister(reg)
+	}
+
+	n.eventClients.close()
+}
+
+// addRegistration records an event registration so that it is removed when the network is closed
+func (n *Network) addRegistration(reg fab.Registration) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.closed {
+		n.event.Unregister(reg)
+		return errors.New("network has been closed")
+	}
+	n.registrations[reg] = struct{}{}
+	return nil
+}
+
+// addListener records a listener so that it is removed when the network is closed
+func (n *Network) addListener(l networkListener) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.closed {
+		return errors.New("network has been closed")
+	}
+	n.listeners[l] = struct{}{}
+	return nil
+}
+
+func (n *Network) removeListener(l networkListener) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.listeners, l)
+}
@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package gateway
 
 import (
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 )
 
@@ -163,3 +166,109 @@ func mockBadChannelProvider(channelID string, invocations int) context.ChannelPr
 
 	return channelProvider
 }
+
+func TestGetNetworkCached(t *testing.T) {
+	gw := &Gateway{}
+
+	nw1, err := gw.getNetwork("mychannel", mockChannelProvider)
+	if err != nil {
+		t.Fatalf("Failed to get network: %s", err)
+	}
+	nw2, err := gw.getNetwork("mychannel", mockChannelProvider)
+	if err != nil {
+		t.Fatalf("Failed to get network: %s", err)
+	}
+	if nw1 != nw2 {
+		t.Fatal("Expected the same network instance to be returned for the same channel")
+	}
+
+	other, err := gw.getNetwork("otherchannel", mockChannelProvider)
+	if err != nil {
+		t.Fatalf("Failed to get network: %s", err)
+	}
+	if other == nw1 {
+		t.Fatal("Expected a different network instance for a different channel")
+	}
+
+	reg, err := nw1.AddBlockListener(func(*Block) {})
+	if err != nil {
+		t.Fatalf("Failed to add block listener: %s", err)
+	}
+
+	// The network is still referenced so the listener remains active
+	nw1.Close()
+	if _, ok := gw.networks["mychannel"]; !ok {
+		t.Fatal("Expected network to remain cached while it is referenced")
+	}
+	if _, err := nw1.AddBlockListener(func(*Block) {}); err != nil {
+		t.Fatalf("Failed to add block listener: %s", err)
+	}
+
+	nw2.Close()
+	if _, ok := gw.networks["mychannel"]; ok {
+		t.Fatal("Expected network to be removed from the cache")
+	}
+	if _, err := nw1.AddBlockListener(func(*Block) {}); err == nil {
+		t.Fatal("Expected error adding listener to closed network")
+	}
+	if _, _, err := nw1.RegisterBlockEvent(); err == nil {
+		t.Fatal("Expected error registering for events on closed network")
+	}
+	// removing a listener of a closed network is harmless
+	nw1.RemoveBlockListener(reg)
+
+	nw3, err := gw.getNetwork("mychannel", mockChannelProvider)
+	if err != nil {
+		t.Fatalf("Failed to get network: %s", err)
+	}
+	if nw3 == nw1 {
+		t.Fatal("Expected a new network instance after the network was closed")
+	}
+
+	gw.Close()
+	if _, err := gw.getNetwork("mychannel", mockChannelProvider); err == nil {
+		t.Fatal("Expected error getting network from closed gateway")
+	}
+	// closing twice is harmless
+	gw.Close()
+}
+
+func TestGatewayCloseNoGoroutineLeaks(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	gw := &Gateway{}
+
+	for _, channelID := range []string{"channel1", "channel2"} {
+		nw, err := gw.getNetwork(channelID, mockChannelProvider)
+		if err != nil {
+			t.Fatalf("Failed to get network: %s", err)
+		}
+
+		if _, err := nw.AddBlockListener(func(*Block) {}); err != nil {
+			t.Fatalf("Failed to add block listener: %s", err)
+		}
+		if _, err := nw.AddBlockListener(func(*Block) {}, WithBlockEventType(FilteredBlockEvents), WithStartBlock(3)); err != nil {
+			t.Fatalf("Failed to add filtered block listener: %s", err)
+		}
+		if _, err := nw.GetContract("contract1").AddContractListener(func(*fab.CCEvent) {}, WithStartBlock(3)); err != nil {
+			t.Fatalf("Failed to add contract listener: %s", err)
+		}
+		if _, _, err := nw.RegisterBlockEvent(); err != nil {
+			t.Fatalf("Failed to register block event: %s", err)
+		}
+	}
+
+	if runtime.NumGoroutine() <= before {
+		t.Fatal("Expected listener goroutines to be running")
+	}
+
+	gw.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Goroutines leaked after closing gateway: %d running, %d before", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
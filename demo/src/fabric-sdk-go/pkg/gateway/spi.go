@@ -24,3 +24,19 @@ type WalletStore interface {
 	Exists(label string) bool
 	Remove(label string) error
 }
+
+// Checkpointer is the interface for implementations that persist the position of the last event
+// processed by a contract listener, so that the listener can resume from that position after a restart.
+// Load returns nil if no checkpoint has been saved yet.
+type Checkpointer interface {
+	Load() (*Checkpoint, error)
+	Save(checkpoint *Checkpoint) error
+}
+
+// Checkpoint is the position of the last event processed by a contract listener.
+// TransactionIDs holds the transactions within BlockNumber that have already been processed,
+// since a block may only have been partially processed when the listener stopped.
+type Checkpoint struct {
+	BlockNumber    uint64   `json:"blockNumber"`
+	TransactionIDs []string `json:"transactionIds"`
+}
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package gateway
 
 import (
+	reqContext "context"
+	"sync"
+
 	"github.com/hyperledger/fabric-protos-go/peer"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
@@ -79,17 +82,21 @@ func WithEndorsingPeers(peers ...string) TransactionOption {
 // the responses will not be sent to the ordering service and hence will
 // not be committed to the ledger. This can be used for querying the world state.
 func (txn *Transaction) Evaluate(args ...string) ([]byte, error) {
-	bytes := make([][]byte, len(args))
-	for i, v := range args {
-		bytes[i] = []byte(v)
-	}
-	txn.request.Args = bytes
+	return txn.EvaluateContext(reqContext.Background(), args...)
+}
+
+// EvaluateContext is the same as Evaluate except that the supplied context
+// is used as the parent of the request, so the evaluation is aborted when
+// the context is cancelled or its deadline expires.
+func (txn *Transaction) EvaluateContext(ctx reqContext.Context, args ...string) ([]byte, error) {
+	txn.request.Args = toBytes(args)
 
 	var options []channel.RequestOption
 	if txn.endorsingPeers != nil {
 		options = append(options, channel.WithTargetEndpoints(txn.endorsingPeers...))
 	}
 	options = append(options, channel.WithTimeout(fab.Query, txn.contract.network.gateway.options.Timeout))
+	options = append(options, channel.WithParentContext(ctx))
 
 	response, err := txn.contract.client.Query(
 		*txn.request,
@@ -106,11 +113,16 @@ func (txn *Transaction) Evaluate(args ...string) ([]byte, error) {
 // will be evaluated on the endorsing peers and then submitted to the ordering service
 // for committing to the ledger.
 func (txn *Transaction) Submit(args ...string) ([]byte, error) {
-	bytes := make([][]byte, len(args))
-	for i, v := range args {
-		bytes[i] = []byte(v)
-	}
-	txn.request.Args = bytes
+	return txn.SubmitContext(reqContext.Background(), args...)
+}
+
+// SubmitContext is the same as Submit except that the supplied context is used
+// as the parent of the request. Endorsement, ordering and the wait for the commit
+// event are all aborted when the context is cancelled or its deadline expires.
+// The cause of a failure (see errors.Cause) is an *EndorsementError, *OrderingError
+// or *CommitTimeoutError depending on the stage at which the submission failed.
+func (txn *Transaction) SubmitContext(ctx reqContext.Context, args ...string) ([]byte, error) {
+	txn.request.Args = toBytes(args)
 
 	var options []channel.RequestOption
 	if txn.endorsingPeers != nil {
@@ -118,14 +130,16 @@ func (txn *Transaction) Submit(args ...string) ([]byte, error) {
 	}
 	options = append(options, channel.WithTimeout(fab.Execute, txn.contract.network.gateway.options.Timeout))
 	options = append(options, channel.WithRetry(retry.DefaultChannelOpts))
+	options = append(options, channel.WithParentContext(ctx))
 
+	handler := newSubmitHandler(txn.eventch)
 	response, err := txn.contract.client.InvokeHandler(
-		newSubmitHandler(txn.eventch),
+		handler,
 		*txn.request,
 		options...,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to submit")
+		return nil, errors.Wrap(handler.classify(err), "Failed to submit")
 	}
 
 	return response.Payload, nil
@@ -139,21 +153,123 @@ func (txn *Transaction) RegisterCommitEvent() <-chan *fab.TxStatusEvent {
 	return txn.eventch
 }
 
-func newSubmitHandler(eventch chan *fab.TxStatusEvent) invoke.Handler {
-	return invoke.NewSelectAndEndorseHandler(
-		invoke.NewEndorsementValidationHandler(
-			invoke.NewSignatureValidationHandler(&commitTxHandler{eventch}),
+func toBytes(args []string) [][]byte {
+	bytes := make([][]byte, len(args))
+	for i, v := range args {
+		bytes[i] = []byte(v)
+	}
+	return bytes
+}
+
+// submit phases, used to classify the error returned from a failed submission
+const (
+	phaseEndorse int32 = iota
+	phaseOrder
+	phaseCommit
+)
+
+// submitState records the phase that a submission reached and its transaction ID. The handler chain
+// keeps running in the background when the request times out, so the state is frozen once the
+// failure is classified: the submission is then neither sent for ordering nor moved to a later phase.
+type submitState struct {
+	mutex      sync.Mutex
+	phase      int32
+	txnID      fab.TransactionID
+	classified bool
+}
+
+// reset starts a new attempt (the handler chain is re-run on retry)
+func (s *submitState) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.classified {
+		s.phase = phaseEndorse
+		s.txnID = ""
+	}
+}
+
+// setTxnID records the transaction ID of the current attempt
+func (s *submitState) setTxnID(txnID fab.TransactionID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.classified {
+		s.txnID = txnID
+	}
+}
+
+// enter moves the submission to phase and returns false if the failure has already been classified
+func (s *submitState) enter(phase int32) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.classified {
+		return false
+	}
+	s.phase = phase
+	return true
+}
+
+// freeze returns the phase and transaction ID reached by the submission and prevents further changes
+func (s *submitState) freeze() (int32, fab.TransactionID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.classified = true
+	return s.phase, s.txnID
+}
+
+type submitHandler struct {
+	next   invoke.Handler
+	commit *commitTxHandler
+}
+
+func newSubmitHandler(eventch chan *fab.TxStatusEvent) *submitHandler {
+	commit := &commitTxHandler{eventch: eventch}
+	return &submitHandler{
+		next: invoke.NewSelectAndEndorseHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(commit),
+			),
 		),
-	)
+		commit: commit,
+	}
+}
+
+//Handle resets the submit state and delegates to the endorsement chain
+func (s *submitHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	s.commit.state.reset()
+	s.next.Handle(requestContext, clientContext)
+	if requestContext.Error != nil && len(requestContext.Response.TransactionID) > 0 {
+		// the proposal was created but failed endorsement or validation
+		s.commit.state.setTxnID(requestContext.Response.TransactionID)
+	}
+}
+
+// classify wraps err according to the phase that the submission had reached when it failed.
+// The transaction ID is the one recorded by the handlers, since the response is empty when
+// the request times out or is cancelled.
+func (s *submitHandler) classify(err error) error {
+	phase, txnID := s.commit.state.freeze()
+	switch phase {
+	case phaseEndorse:
+		return &EndorsementError{TransactionID: txnID, Err: err}
+	case phaseOrder:
+		return &OrderingError{TransactionID: txnID, Err: err}
+	default:
+		if isTimeout(err) {
+			return &CommitTimeoutError{TransactionID: txnID, Err: err}
+		}
+		return err
+	}
 }
 
 type commitTxHandler struct {
 	eventch chan *fab.TxStatusEvent
+	state   submitState
 }
 
 //Handle handles commit tx
 func (c *commitTxHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
 	txnID := requestContext.Response.TransactionID
+	c.state.setTxnID(txnID)
 
 	//Register Tx event
 	reg, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(string(txnID)) // TODO: Change func to use TransactionID instead of string
@@ -162,11 +278,19 @@ func (c *commitTxHandler) Handle(requestContext *invoke.RequestContext, clientCo
 		return
 	}
 	defer clientContext.EventService.Unregister(reg)
+
+	if !c.state.enter(phaseOrder) {
+		// the request timed out or was cancelled during endorsement
+		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"request timed out or been cancelled before ordering", nil)
+		return
+	}
 	_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
 	if err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
 	}
+	c.state.enter(phaseCommit)
 
 	select {
 	case txStatus := <-statusNotifier:
@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	pkgerrors "github.com/pkg/errors"
 )
 
 const (
@@ -194,6 +195,170 @@ func TestSubmitHandlerCommitError(t *testing.T) {
 
 }
 
+func TestSubmitHandlerEndorsementErrorType(t *testing.T) {
+
+	//Sample request
+	request := invoke.Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	//Prepare context objects for handler
+	requestContext := prepareRequestContext(request, invoke.Opts{}, t)
+
+	clientContext := setupChannelClientContext(nil, errors.New(mockError), nil, t)
+
+	handler := newSubmitHandler(nil)
+	handler.Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	err := handler.classify(requestContext.Error)
+	if _, ok := err.(*EndorsementError); !ok {
+		t.Fatalf("Expected EndorsementError, got %T: %s", err, err)
+	}
+	if !strings.Contains(err.Error(), mockError) {
+		t.Fatal("Expected error: ", mockError, ", Received error:", err.Error())
+	}
+}
+
+func TestSubmitHandlerOrderingErrorType(t *testing.T) {
+
+	//Sample request
+	request := invoke.Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	//Prepare context objects for handler
+	requestContext := prepareRequestContext(request, invoke.Opts{}, t)
+
+	requestContext.Response.TransactionID = "txid"
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.Transactor = &mockTransactor{}
+
+	handler := newSubmitHandler(nil)
+	handler.commit.Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	err := handler.classify(requestContext.Error)
+	oerr, ok := err.(*OrderingError)
+	if !ok {
+		t.Fatalf("Expected OrderingError, got %T: %s", err, err)
+	}
+	if oerr.TransactionID != "txid" {
+		t.Fatalf("Incorrect transaction ID: %s", oerr.TransactionID)
+	}
+}
+
+func TestSubmitHandlerCommitTimeoutErrorType(t *testing.T) {
+
+	//Sample request
+	request := invoke.Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	//Prepare context objects for handler
+	requestContext := prepareRequestContext(request, invoke.Opts{}, t)
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+	requestContext.Ctx = ctx
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.EventService.(*fcmocks.MockEventService).Timeout = true
+
+	// add reponses to request context
+	addProposalResponse(requestContext)
+
+	handler := newSubmitHandler(nil)
+	handler.commit.Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	err := handler.classify(requestContext.Error)
+	if _, ok := err.(*CommitTimeoutError); !ok {
+		t.Fatalf("Expected CommitTimeoutError, got %T: %s", err, err)
+	}
+}
+
+func TestSubmitContextCommitTimeout(t *testing.T) {
+	channel, err := fcmocks.NewMockChannel("mychannel")
+	if err != nil {
+		t.Fatalf("Failed to create mock channel: %s", err)
+	}
+	eventService := fcmocks.NewMockEventService()
+	eventService.Timeout = true
+	channelService := channel.ChannelService().(*fcmocks.MockChannelService)
+	channelService.SetEventService(eventService)
+	channelService.SetTransactor(&txnIDTransactor{MockTransactor: fcmocks.MockTransactor{ChannelID: "mychannel"}})
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, func() (cpc.Channel, error) { return channel, nil })
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	txn, err := nw.GetContract("contract1").CreateTransaction("txn1")
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %s", err)
+	}
+
+	// the commit event never arrives
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	_, err = txn.SubmitContext(ctx, "arg1", "arg2")
+	if err == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	cerr, ok := pkgerrors.Cause(err).(*CommitTimeoutError)
+	if !ok {
+		t.Fatalf("Expected CommitTimeoutError, got %T: %s", pkgerrors.Cause(err), err)
+	}
+	if cerr.TransactionID == "" {
+		t.Fatal("Expected the transaction ID of the submitted transaction")
+	}
+
+	// the handler chain doesn't change the classification after the timeout
+	reg := <-eventService.TxStatusRegCh
+	if reg.TxID != string(cerr.TransactionID) {
+		t.Fatalf("Expected transaction ID %s, got %s", reg.TxID, cerr.TransactionID)
+	}
+}
+
+func TestSubmitContextCancelled(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	gw := &Gateway{
+		options: &gatewayOptions{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	contr := nw.GetContract("contract1")
+	txn, err := contr.CreateTransaction("txn1")
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %s", err)
+	}
+
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+
+	_, err = txn.SubmitContext(ctx, "arg1", "arg2")
+	if err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func prepareRequestContext(request invoke.Request, opts invoke.Opts, t *testing.T) *invoke.RequestContext {
 	requestContext := &invoke.RequestContext{Request: request,
@@ -281,3 +446,12 @@ func (t *mockTransactor) CreateTransactionHeader(opts ...fab.TxnHeaderOpt) (fab.
 func (t *mockTransactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
 	return nil, nil
 }
+
+// txnIDTransactor creates transaction headers with a transaction ID
+type txnIDTransactor struct {
+	fcmocks.MockTransactor
+}
+
+func (t *txnIDTransactor) CreateTransactionHeader(opts ...fab.TxnHeaderOpt) (fab.TransactionHeader, error) {
+	return fcmocks.NewMockTransactionHeader(t.ChannelID)
+}
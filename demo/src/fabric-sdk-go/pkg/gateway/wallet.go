@@ -74,6 +74,8 @@ func (w *Wallet) Get(label string) (Identity, error) {
 	switch idType {
 	case x509Type:
 		id = &X509Identity{}
+	case keyRefType:
+		id = &KeyRefIdentity{}
 	default:
 		return nil, errors.New("Invalid identity format: unsupported identity type: " + idType)
 	}
@@ -123,6 +125,7 @@ type walletIdentity struct {
 	mspID                 string
 	enrollmentCertificate []byte
 	privateKey            core.Key
+	keyPEM                []byte
 }
 
 // Identifier returns walletIdentity identifier
@@ -14,6 +14,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
@@ -81,7 +82,12 @@ func NewCAClient(orgName string, ctx contextApi.Client, opts ...CAClientOption)
 	if !ok {
 		return nil, errors.Errorf("error initializing CA [%s]", caID)
 	}
-	adapter, err := newFabricCAAdapter(caID, ctx.CryptoSuite(), ctx.IdentityConfig())
+	// the password of an encrypted TLS client key is the one of the context's user
+	keyPassword, err := secret.KeyPassword(secretProviderFromConfig(ctx.EndpointConfig()), orgName, ctx.Identifier().ID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the TLS client key password")
+	}
+	adapter, err := newFabricCAAdapter(caID, ctx.CryptoSuite(), ctx.IdentityConfig(), keyPassword)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error initializing CA [%s]", caID)
 	}
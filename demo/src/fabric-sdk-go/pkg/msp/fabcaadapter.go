@@ -17,6 +17,7 @@ import (
 	caapi "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkinternal/pkg/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 )
@@ -28,9 +29,9 @@ type fabricCAAdapter struct {
 	caClient    *calib.Client
 }
 
-func newFabricCAAdapter(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*fabricCAAdapter, error) {
+func newFabricCAAdapter(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig, keyPassword []byte) (*fabricCAAdapter, error) {
 
-	caClient, err := createFabricCAClient(caID, cryptoSuite, config)
+	caClient, err := createFabricCAClient(caID, cryptoSuite, config, keyPassword)
 	if err != nil {
 		return nil, err
 	}
@@ -592,7 +593,7 @@ func getIdentityResponses(ca string, responses []caapi.IdentityInfo) []*api.Iden
 	return ret
 }
 
-func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*calib.Client, error) {
+func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig, keyPassword []byte) (*calib.Client, error) {
 
 	// Create new Fabric-ca client without configs
 	c := &calib.Client{
@@ -626,6 +627,10 @@ func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.
 	if !ok {
 		return nil, errors.Errorf("CA '%s' has no corresponding client keys in the configs", caID)
 	}
+	c.Config.TLS.Client.KeyPassword = keyPassword
+	c.Config.TLS.EncClient.CertFile = conf.TLSCAClientEncCert
+	c.Config.TLS.EncClient.KeyFile = conf.TLSCAClientEncKey
+	c.Config.TLS.EncClient.KeyPassword = keyPassword
 
 	var err error
 	c.Config.TLS.TlsCertPool, err = config.TLSCACertPool().Get()
@@ -635,6 +640,7 @@ func createFabricCAClient(caID string, cryptoSuite core.CryptoSuite, config msp.
 
 	//TLS flag enabled/disabled
 	c.Config.TLS.Enabled = endpoint.IsTLSEnabled(conf.URL)
+	c.Config.TLS.GMTLS = config.Client().TLSType == comm.TLSTypeGM
 	c.Config.MSPDir = config.CAKeyStorePath()
 
 	//Factory opts
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/secret"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -90,7 +91,7 @@ func (mgr *IdentityManager) CreateSigningIdentity(opts ...msp.SigningIdentityOpt
 		}
 	} else {
 		var err error
-		privateKey, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytes(opt.PrivateKey, mgr.cryptoSuite, true)
+		privateKey, err = mgr.importPrivateKey("", opt.PrivateKey)
 		if err != nil {
 			return nil, errors.WithMessage(err, "failed to import key")
 		}
@@ -164,7 +165,7 @@ func (mgr *IdentityManager) getEmbeddedPrivateKey(username string) (core.Key, er
 		privateKey, err = mgr.cryptoSuite.GetKey(pemBytes)
 		if err != nil || privateKey == nil {
 			// Try as a pem
-			privateKey, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytes(pemBytes, mgr.cryptoSuite, true)
+			privateKey, err = mgr.importPrivateKey(username, pemBytes)
 			if err != nil {
 				return nil, errors.Wrap(err, "import private key failed")
 			}
@@ -239,7 +240,17 @@ func (mgr *IdentityManager) getPrivateKeyFromKeyStore(username string, ski []byt
 		return nil, err
 	}
 	if pemBytes != nil {
-		return fabricCaUtil.ImportBCCSPKeyFromPEMBytes(pemBytes, mgr.cryptoSuite, true)
+		return mgr.importPrivateKey(username, pemBytes)
 	}
 	return nil, core.ErrKeyValueNotFound
 }
+
+// importPrivateKey imports the PEM encoded private key of the user, decrypting it with the
+// password supplied by the secret provider if it is encrypted
+func (mgr *IdentityManager) importPrivateKey(username string, pemBytes []byte) (core.Key, error) {
+	password, err := secret.KeyPassword(mgr.secretProvider, mgr.orgName, username)
+	if err != nil {
+		return nil, err
+	}
+	return fabricCaUtil.ImportBCCSPKeyFromPEMBytesWithPassword(pemBytes, password, mgr.cryptoSuite, true)
+}
@@ -7,12 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
-	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/plugins/huawei/hbccsp/hx509"
 	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 
 	"github.com/pkg/errors"
@@ -91,6 +91,7 @@ type ClientTLSConfig struct {
 	//Client TLS information
 	Client         endpoint.TLSKeyPair
 	SystemCertPool bool
+	Type           string
 }
 
 // CAConfig defines a CA configuration in identity config
@@ -282,7 +283,8 @@ func appendCertsFromPEM(c commtls.CertPool, pemCerts []byte) (ok bool) {
 			continue
 		}
 
-		cert, err := x509.ParseCertificate(block.Bytes)
+		// SM2 certificates are supported for GM/T TLS
+		cert, err := hx509.ParseCertificate(block.Bytes)
 		if err != nil {
 			continue
 		}
@@ -320,6 +322,7 @@ func (c *IdentityConfig) loadClientTLSConfig(configEntity *identityConfigEntity)
 		CredentialStore: configEntity.Client.CredentialStore,
 		TLSKey:          configEntity.Client.TLSCerts.Client.Key.Bytes(),
 		TLSCert:         configEntity.Client.TLSCerts.Client.Cert.Bytes(),
+		TLSType:         strings.ToLower(configEntity.Client.TLSCerts.Type),
 	}
 
 	return nil
@@ -333,6 +336,8 @@ func (c *IdentityConfig) loadCATLSConfig(configEntity *identityConfigEntity) err
 		caConfig.TLSCACerts.Path = pathvar.Subst(caConfig.TLSCACerts.Path)
 		caConfig.TLSCACerts.Client.Key.Path = pathvar.Subst(caConfig.TLSCACerts.Client.Key.Path)
 		caConfig.TLSCACerts.Client.Cert.Path = pathvar.Subst(caConfig.TLSCACerts.Client.Cert.Path)
+		caConfig.TLSCACerts.EncClient.Key.Path = pathvar.Subst(caConfig.TLSCACerts.EncClient.Key.Path)
+		caConfig.TLSCACerts.EncClient.Cert.Path = pathvar.Subst(caConfig.TLSCACerts.EncClient.Cert.Path)
 		//pre load key and cert bytes
 		err := caConfig.TLSCACerts.Client.Key.LoadBytes()
 		if err != nil {
@@ -343,6 +348,16 @@ func (c *IdentityConfig) loadCATLSConfig(configEntity *identityConfigEntity) err
 		if err != nil {
 			return errors.WithMessage(err, "failed to load ca cert")
 		}
+
+		err = caConfig.TLSCACerts.EncClient.Key.LoadBytes()
+		if err != nil {
+			return errors.WithMessage(err, "failed to load ca encryption key")
+		}
+
+		err = caConfig.TLSCACerts.EncClient.Cert.LoadBytes()
+		if err != nil {
+			return errors.WithMessage(err, "failed to load ca encryption cert")
+		}
 		configEntity.CertificateAuthorities[ca] = caConfig
 	}
 
@@ -395,6 +410,9 @@ func (c *IdentityConfig) getMSPCAConfig(caConfig *CAConfig) (*msp.CAConfig, erro
 		TLSCAClientCert:  caConfig.TLSCACerts.Client.Cert.Bytes(),
 		TLSCAClientKey:   caConfig.TLSCACerts.Client.Key.Bytes(),
 		TLSCAServerCerts: serverCerts,
+
+		TLSCAClientEncCert: caConfig.TLSCACerts.EncClient.Cert.Bytes(),
+		TLSCAClientEncKey:  caConfig.TLSCACerts.EncClient.Key.Bytes(),
 	}, nil
 }
 
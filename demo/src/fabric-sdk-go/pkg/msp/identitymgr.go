@@ -27,6 +27,12 @@ type IdentityManager struct {
 	mspPrivKeyStore core.KVStore
 	mspCertStore    core.KVStore
 	userStore       msp.UserStore
+	secretProvider  core.SecretProvider
+}
+
+// secretProviderConfig is implemented by endpoint configs that carry the secret provider of the SDK instance
+type secretProviderConfig interface {
+	SecretProvider() core.SecretProvider
 }
 
 // NewIdentityManager creates a new instance of IdentityManager
@@ -73,7 +79,15 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 		mspCertStore:    mspCertStore,
 		embeddedUsers:   orgConfig.Users,
 		userStore:       userStore,
+		secretProvider:  secretProviderFromConfig(endpointConfig),
 		// CA Client state is created lazily, when (if) needed
 	}
 	return mgr, nil
 }
+
+func secretProviderFromConfig(endpointConfig fab.EndpointConfig) core.SecretProvider {
+	if c, ok := endpointConfig.(secretProviderConfig); ok {
+		return c.SecretProvider()
+	}
+	return nil
+}